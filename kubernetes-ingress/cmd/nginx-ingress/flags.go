@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/admission"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -148,6 +149,18 @@ var (
 	serviceInsightListenPort = flag.Int("service-insight-listen-port", 9114,
 		"Set the port where the Service Insight stats are exposed. Requires -nginx-plus. [1024 - 65535]")
 
+	enableAdmissionWebhook = flag.Bool("enable-admission-webhook", false,
+		`Enable the validating admission webhook for VirtualServer, VirtualServerRoute, TransportServer and Policy resources. Requires -enable-custom-resources and -admission-webhook-tls-secret`)
+
+	admissionWebhookTLSSecretName = flag.String("admission-webhook-tls-secret", "",
+		`A Secret with a TLS certificate and key for TLS termination of the admission webhook.`)
+
+	admissionWebhookListenPort = flag.Int("admission-webhook-listen-port", 8443,
+		"Set the port where the admission webhook is exposed. [1024 - 65535]")
+
+	admissionWebhookFailurePolicy = flag.String("admission-webhook-failure-policy", admission.FailurePolicyFail,
+		`Set the response of the admission webhook to the requests it can't validate, for example, when the Ingress Controller is not ready. Allowed values: "Fail" (reject) or "Ignore" (admit)`)
+
 	enableCustomResources = flag.Bool("enable-custom-resources", true,
 		"Enable custom resources")
 
@@ -194,6 +207,9 @@ var (
 	disableIPV6 = flag.Bool("disable-ipv6", false,
 		`Disable IPV6 listeners explicitly for nodes that do not support the IPV6 stack`)
 
+	enableGatewayAPI = flag.Bool("enable-gateway-api", false,
+		`Enable support for the Gateway API Gateway, HTTPRoute and ReferenceGrant resources. The Gateway API CRDs must be installed in the cluster`)
+
 	startupCheckFn func() error
 )
 
@@ -262,6 +278,14 @@ func parseFlags() {
 		*enableServiceInsight = false
 	}
 
+	if *enableAdmissionWebhook && !*enableCustomResources {
+		glog.Fatal("enable-admission-webhook flag requires -enable-custom-resources")
+	}
+
+	if *enableAdmissionWebhook && *admissionWebhookTLSSecretName == "" {
+		glog.Fatal("enable-admission-webhook flag requires -admission-webhook-tls-secret")
+	}
+
 	if *enableCertManager && !*enableCustomResources {
 		glog.Fatal("enable-cert-manager flag requires -enable-custom-resources")
 	}
@@ -370,6 +394,16 @@ func validationChecks() {
 		glog.Fatalf("Invalid value for service-insight-listen-port: %v", metricsPortValidationError)
 	}
 
+	admissionWebhookPortValidationError := validatePort(*admissionWebhookListenPort)
+	if admissionWebhookPortValidationError != nil {
+		glog.Fatalf("Invalid value for admission-webhook-listen-port: %v", admissionWebhookPortValidationError)
+	}
+
+	admissionWebhookFailurePolicyValidationError := validateAdmissionWebhookFailurePolicy(*admissionWebhookFailurePolicy)
+	if admissionWebhookFailurePolicyValidationError != nil {
+		glog.Fatalf("Invalid value for admission-webhook-failure-policy: %v", admissionWebhookFailurePolicyValidationError)
+	}
+
 	var err error
 	allowedCIDRs, err = parseNginxStatusAllowCIDRs(*nginxStatusAllowCIDRs)
 	if err != nil {
@@ -413,6 +447,14 @@ func validateResourceName(name string) error {
 	return nil
 }
 
+// validateAdmissionWebhookFailurePolicy makes sure the failure policy is either Fail or Ignore
+func validateAdmissionWebhookFailurePolicy(policy string) error {
+	if policy != admission.FailurePolicyFail && policy != admission.FailurePolicyIgnore {
+		return fmt.Errorf("invalid failure policy %v, must be %v or %v", policy, admission.FailurePolicyFail, admission.FailurePolicyIgnore)
+	}
+	return nil
+}
+
 // validatePort makes sure a given port is inside the valid port range for its usage
 func validatePort(port int) error {
 	if port < 1024 || port > 65535 {
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/openapi"
+	"sigs.k8s.io/yaml"
+)
+
+const generateRoutesCommand = "generate-routes"
+
+// runGenerateRoutes generates a VirtualServer or a VirtualServerRoute from an OpenAPI document
+// and writes it in the YAML format to out.
+func runGenerateRoutes(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(generateRoutesCommand, flag.ContinueOnError)
+	spec := fs.String("spec", "", "Path to the OpenAPI 3 document in the YAML or JSON format. Required")
+	kind := fs.String("kind", "VirtualServer", "The kind of the generated resource: VirtualServer or VirtualServerRoute")
+	name := fs.String("name", "", "The name of the generated resource. Required")
+	namespace := fs.String("namespace", "default", "The namespace of the generated resource")
+	host := fs.String("host", "", "The host of the generated resource. Required")
+	service := fs.String("service", "", "The name of the service that implements the API. Required")
+	port := fs.Uint("port", 80, "The port of the service that implements the API")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *spec == "" || *name == "" || *host == "" || *service == "" {
+		return errors.New("the -spec, -name, -host and -service flags are required")
+	}
+	if err := validatePortNumber(*port); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*spec)
+	if err != nil {
+		return fmt.Errorf("error reading OpenAPI document: %w", err)
+	}
+
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	opts := openapi.Options{
+		Name:      *name,
+		Namespace: *namespace,
+		Host:      *host,
+		Service:   *service,
+		Port:      uint16(*port),
+	}
+
+	var resource interface{}
+	switch *kind {
+	case "VirtualServer":
+		resource, err = openapi.GenerateVirtualServer(doc, opts)
+	case "VirtualServerRoute":
+		resource, err = openapi.GenerateVirtualServerRoute(doc, opts)
+	default:
+		return fmt.Errorf("invalid kind %q, must be VirtualServer or VirtualServerRoute", *kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	output, err := marshalResource(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(output)
+	return err
+}
+
+func validatePortNumber(port uint) error {
+	if port == 0 || port > 65535 {
+		return fmt.Errorf("port %v must be in the range 1..65535", port)
+	}
+	return nil
+}
+
+// marshalResource marshals a resource to YAML without the empty fields,
+// as the fields of the custom resources are not omitted when they are empty.
+func marshalResource(resource interface{}) ([]byte, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(removeEmptyFields(obj))
+}
+
+func removeEmptyFields(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range o {
+			v = removeEmptyFields(v)
+			if isEmpty(v) {
+				delete(o, k)
+			} else {
+				o[k] = v
+			}
+		}
+		return o
+	case []interface{}:
+		for i, v := range o {
+			o[i] = removeEmptyFields(v)
+		}
+		return o
+	}
+	return obj
+}
+
+func isEmpty(obj interface{}) bool {
+	switch o := obj.(type) {
+	case nil:
+		return true
+	case string:
+		return o == ""
+	case bool:
+		return !o
+	case float64:
+		return o == 0
+	case map[string]interface{}:
+		return len(o) == 0
+	case []interface{}:
+		return len(o) == 0
+	}
+	return false
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/admission"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
@@ -37,6 +38,8 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	gateway_versioned "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gateway_scheme "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/scheme"
 )
 
 // Injected during build
@@ -48,6 +51,22 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == generateRoutesCommand {
+		if err := runGenerateRoutes(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == renderCommand {
+		if err := runRender(os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	commitHash, commitTime, dirtyBuild := getBuildInfo()
 	fmt.Printf("NGINX Ingress Controller Version=%v Commit=%v Date=%v DirtyState=%v Arch=%v/%v Go=%v\n", version, commitHash, commitTime, dirtyBuild, runtime.GOOS, runtime.GOARCH, runtime.Version())
 
@@ -63,6 +82,8 @@ func main() {
 
 	dynClient, confClient := createCustomClients(config)
 
+	gatewayClient := createGatewayClient(config)
+
 	constLabels := map[string]string{"class": *ingressClass}
 
 	managerCollector, controllerCollector, registry := createManagerAndControllerCollectors(constLabels)
@@ -137,6 +158,7 @@ func main() {
 		KubeClient:                   kubeClient,
 		ConfClient:                   confClient,
 		DynClient:                    dynClient,
+		GatewayClient:                gatewayClient,
 		RestConfig:                   config,
 		ResyncPeriod:                 30 * time.Second,
 		Namespace:                    watchNamespaces,
@@ -173,10 +195,15 @@ func main() {
 		ExternalDNSEnabled:           *enableExternalDNS,
 		IsIPV6Disabled:               *disableIPV6,
 		WatchNamespaceLabel:          *watchNamespaceLabel,
+		IsGatewayAPIEnabled:          *enableGatewayAPI,
 	}
 
 	lbc := k8s.NewLoadBalancerController(lbcInput)
 
+	if *enableAdmissionWebhook {
+		createAdmissionWebhookEndpoint(kubeClient, lbc, virtualServerValidator, transportServerValidator)
+	}
+
 	if *readyStatus {
 		go func() {
 			port := fmt.Sprintf(":%v", *readyStatusPort)
@@ -311,6 +338,25 @@ func createCustomClients(config *rest.Config) (dynamic.Interface, k8s_nginx.Inte
 	return dynClient, confClient
 }
 
+func createGatewayClient(config *rest.Config) gateway_versioned.Interface {
+	if !*enableGatewayAPI {
+		return nil
+	}
+
+	gatewayClient, err := gateway_versioned.NewForConfig(config)
+	if err != nil {
+		glog.Fatalf("Failed to create a Gateway API client: %v", err)
+	}
+
+	// required for emitting Events for HTTPRoute
+	err = gateway_scheme.AddToScheme(scheme.Scheme)
+	if err != nil {
+		glog.Fatalf("Failed to add Gateway API types to the scheme: %v", err)
+	}
+
+	return gatewayClient
+}
+
 func createPlusClient(nginxPlus bool, useFakeNginxManager bool, nginxManager nginx.Manager) *client.NginxClient {
 	var plusClient *client.NginxClient
 	var err error
@@ -465,6 +511,10 @@ func createGlobalConfigurationValidator() *cr_validation.GlobalConfigurationVali
 		forbiddenListenerPorts[*tlsPassthroughPort] = true
 	}
 
+	if *enableAdmissionWebhook {
+		forbiddenListenerPorts[*admissionWebhookListenPort] = true
+	}
+
 	return cr_validation.NewGlobalConfigurationValidator(forbiddenListenerPorts)
 }
 
@@ -705,6 +755,27 @@ func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *cli
 	go healthcheck.RunHealthCheck(*serviceInsightListenPort, plusClient, cnf, serviceInsightSecret)
 }
 
+func createAdmissionWebhookEndpoint(kubeClient *kubernetes.Clientset, lbc *k8s.LoadBalancerController, vsv *cr_validation.VirtualServerValidator, tsv *cr_validation.TransportServerValidator) {
+	if !*enableAdmissionWebhook {
+		return
+	}
+	admissionWebhookSecret, err := getAndValidateSecret(kubeClient, *admissionWebhookTLSSecretName)
+	if err != nil {
+		glog.Fatalf("Error trying to get the admission webhook TLS secret %v: %v", *admissionWebhookTLSSecretName, err)
+	}
+	cfg := admission.Config{
+		Checker:                  lbc,
+		VirtualServerValidator:   vsv,
+		TransportServerValidator: tsv,
+		IsPlus:                   *nginxPlus,
+		EnableOIDC:               *enableOIDC,
+		EnableAppProtect:         *appProtect,
+		IsTLSPassthroughEnabled:  *enableTLSPassthrough,
+		FailurePolicy:            *admissionWebhookFailurePolicy,
+	}
+	go admission.RunWebhook(*admissionWebhookListenPort, admissionWebhookSecret, cfg)
+}
+
 func processGlobalConfiguration() {
 	if *globalConfiguration != "" {
 		_, _, err := k8s.ParseNamespaceName(*globalConfiguration)
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -155,6 +158,24 @@ func TestValidateAppProtectLogLevel(t *testing.T) {
 	}
 }
 
+func TestValidateAdmissionWebhookFailurePolicy(t *testing.T) {
+	badPolicies := []string{"", "fail", "Reject"}
+	for _, badPolicy := range badPolicies {
+		err := validateAdmissionWebhookFailurePolicy(badPolicy)
+		if err == nil {
+			t.Errorf("validateAdmissionWebhookFailurePolicy(%v) returned no error when it should have returned an error", badPolicy)
+		}
+	}
+
+	goodPolicies := []string{"Fail", "Ignore"}
+	for _, goodPolicy := range goodPolicies {
+		err := validateAdmissionWebhookFailurePolicy(goodPolicy)
+		if err != nil {
+			t.Errorf("validateAdmissionWebhookFailurePolicy(%v) returned an error when it should have returned no error: %v", goodPolicy, err)
+		}
+	}
+}
+
 func TestValidateNamespaces(t *testing.T) {
 	badNamespaces := []string{"watchns1, watchns2, watchns%$", "watchns1,watchns2,watchns%$"}
 	for _, badNs := range badNamespaces {
@@ -172,3 +193,212 @@ func TestValidateNamespaces(t *testing.T) {
 		}
 	}
 }
+
+func TestRunGenerateRoutes(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "openapi.yaml")
+	err := os.WriteFile(spec, []byte(`
+openapi: 3.0.3
+info:
+  title: Jobs API
+  version: 1.0.0
+paths:
+  /jobs/{id}:
+    get:
+      operationId: getJob
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `apiVersion: k8s.nginx.org/v1
+kind: VirtualServer
+metadata:
+  name: jobs
+  namespace: default
+spec:
+  host: jobs.example.com
+  routes:
+  - action:
+      return:
+        body: Method Not Allowed
+        code: 405
+        type: text/plain
+    matches:
+    - action:
+        pass: jobs-svc
+      conditions:
+      - value: GET
+        variable: $request_method
+    path: ~ ^/jobs/[^/]+$
+  upstreams:
+  - name: jobs-svc
+    port: 8080
+    service: jobs-svc
+`
+
+	var out bytes.Buffer
+	args := []string{"-spec", spec, "-name", "jobs", "-host", "jobs.example.com", "-service", "jobs-svc", "-port", "8080"}
+	if err := runGenerateRoutes(args, &out); err != nil {
+		t.Fatalf("runGenerateRoutes() returned unexpected error %v", err)
+	}
+	if out.String() != expected {
+		t.Errorf("runGenerateRoutes() returned %q but expected %q", out.String(), expected)
+	}
+}
+
+func TestRunGenerateRoutesFails(t *testing.T) {
+	tests := []struct {
+		args []string
+		msg  string
+	}{
+		{
+			args: []string{"-name", "jobs", "-host", "jobs.example.com", "-service", "jobs-svc"},
+			msg:  "missing spec",
+		},
+		{
+			args: []string{"-spec", "not-found.yaml", "-name", "jobs", "-host", "jobs.example.com", "-service", "jobs-svc"},
+			msg:  "missing spec file",
+		},
+		{
+			args: []string{"-spec", "openapi.yaml", "-name", "jobs", "-host", "jobs.example.com", "-service", "jobs-svc", "-port", "0"},
+			msg:  "invalid port",
+		},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := runGenerateRoutes(test.args, &out); err == nil {
+			t.Errorf("runGenerateRoutes() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestRunRender(t *testing.T) {
+	dir := t.TempDir()
+	manifests := filepath.Join(dir, "manifests")
+	if err := os.Mkdir(manifests, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(manifests, "cafe.yaml"): `
+apiVersion: k8s.nginx.org/v1
+kind: VirtualServer
+metadata:
+  name: cafe
+  namespace: default
+spec:
+  host: cafe.example.com
+  upstreams:
+  - name: coffee
+    service: coffee-svc
+    port: 80
+  routes:
+  - path: /coffee
+    action:
+      pass: coffee
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: unknown
+`,
+		filepath.Join(manifests, "coffee.yaml"): `
+apiVersion: v1
+kind: Service
+metadata:
+  name: coffee-svc
+  namespace: default
+spec:
+  ports:
+  - port: 80
+    targetPort: 8080
+---
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: coffee-svc-1
+  namespace: default
+  labels:
+    kubernetes.io/service-name: coffee-svc
+addressType: IPv4
+ports:
+- port: 8080
+endpoints:
+- addresses:
+  - 10.0.0.1
+  conditions:
+    ready: true
+`,
+		filepath.Join(dir, "cm.yaml"): `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: nginx-config
+  namespace: nginx-ingress
+data:
+  worker-processes: "3"
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args := []string{
+		"-f", manifests,
+		"-configmap", filepath.Join(dir, "cm.yaml"),
+		"-main-template", "../../internal/configs/version1/nginx.tmpl",
+		"-ingress-template", "../../internal/configs/version1/nginx.ingress.tmpl",
+		"-virtualserver-template", "../../internal/configs/version2/nginx.virtualserver.tmpl",
+		"-transportserver-template", "../../internal/configs/version2/nginx.transportserver.tmpl",
+	}
+
+	var out, errOut bytes.Buffer
+	if err := runRender(args, &out, &errOut); err != nil {
+		t.Fatalf("runRender() returned unexpected error %v", err)
+	}
+
+	wantStrings := []string{
+		"# nginx.conf\n",
+		"worker_processes  3;",
+		"# conf.d/vs_default_cafe.conf\n",
+		"server 10.0.0.1:8080",
+	}
+	for _, want := range wantStrings {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("runRender() returned output without %q", want)
+		}
+	}
+	if !strings.Contains(errOut.String(), "Skipping a document") {
+		t.Errorf("runRender() didn't report the document of an unknown kind: %q", errOut.String())
+	}
+}
+
+func TestRunRenderFails(t *testing.T) {
+	tests := []struct {
+		args []string
+		msg  string
+	}{
+		{
+			args: []string{},
+			msg:  "missing manifests",
+		},
+		{
+			args: []string{"-f", "not-found"},
+			msg:  "missing manifests folder",
+		},
+		{
+			args: []string{"-f", ".", "-configmap", "not-found.yaml"},
+			msg:  "missing configmap file",
+		},
+	}
+
+	for _, test := range tests {
+		var out, errOut bytes.Buffer
+		if err := runRender(test.args, &out, &errOut); err == nil {
+			t.Errorf("runRender() returned no error for the case of %s", test.msg)
+		}
+	}
+}
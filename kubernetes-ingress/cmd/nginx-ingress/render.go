@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/k8s"
+	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
+	cr_validation "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	k8s_nginx_scheme "github.com/nginxinc/kubernetes-ingress/pkg/client/clientset/versioned/scheme"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	util_yaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const renderCommand = "render"
+
+// runRender generates the NGINX configuration for the resources from the manifests
+// and writes the main config and the files of the conf.d and stream-conf.d folders to out.
+// The problems of the resources and the warnings of the configuration are written to errOut.
+func runRender(args []string, out io.Writer, errOut io.Writer) error {
+	fs := flag.NewFlagSet(renderCommand, flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	manifests := fs.String("f", "", "Path to a YAML manifest or a folder with YAML manifests of the resources, Services, EndpointSlices, Pods, Secrets and Policies. Required")
+	configMap := fs.String("configmap", "", "Path to a YAML manifest of the ConfigMap with the NGINX configuration")
+	outputDir := fs.String("output-dir", "", "A folder to keep the generated files in. By default, the files are written to a temporary folder that is removed")
+	isPlus := fs.Bool("nginx-plus", false, "Render the configuration for NGINX Plus")
+	class := fs.String("ingress-class", "nginx", "The class of the Ingress Controller")
+	snippets := fs.Bool("enable-snippets", false, "Enable custom NGINX configuration snippets")
+	tlsPassthrough := fs.Bool("enable-tls-passthrough", false, "Enable TLS Passthrough on port 443")
+	oidc := fs.Bool("enable-oidc", false, "Enable OIDC Policies")
+	mainTemplate := fs.String("main-template", "", "Path to the main NGINX configuration template")
+	ingressTemplate := fs.String("ingress-template", "", "Path to the ingress NGINX configuration template")
+	virtualServerTemplate := fs.String("virtualserver-template", "", "Path to the VirtualServer NGINX configuration template")
+	transportServerTemplate := fs.String("transportserver-template", "", "Path to the TransportServer NGINX configuration template")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *manifests == "" {
+		return errors.New("the -f flag is required")
+	}
+
+	objects, err := readManifests(*manifests, errOut)
+	if err != nil {
+		return err
+	}
+
+	var cfm *api_v1.ConfigMap
+	if *configMap != "" {
+		cfm, err = readConfigMap(*configMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	confPath := *outputDir
+	if confPath == "" {
+		confPath, err = os.MkdirTemp("", "nginx-ingress-render")
+		if err != nil {
+			return fmt.Errorf("error creating temporary folder: %w", err)
+		}
+		defer os.RemoveAll(confPath) //nolint:errcheck
+	}
+
+	nginxManager, err := nginx.NewFileManager(confPath, "")
+	if err != nil {
+		return err
+	}
+
+	templateExecutor, templateExecutorV2, err := createRenderTemplateExecutors(*isPlus, *mainTemplate, *ingressTemplate, *virtualServerTemplate, *transportServerTemplate)
+	if err != nil {
+		return err
+	}
+
+	staticCfgParams := &configs.StaticConfigParams{
+		HealthStatusURI:       "/nginx-health",
+		NginxStatus:           true,
+		NginxStatusAllowCIDRs: []string{"127.0.0.1", "::1"},
+		NginxStatusPort:       8080,
+		TLSPassthrough:        *tlsPassthrough,
+		TLSPassthroughPort:    443,
+		EnableSnippets:        *snippets,
+		EnableOIDC:            *oidc,
+	}
+
+	cnf := configs.NewConfigurator(nginxManager, staticCfgParams, configs.NewDefaultConfigParams(*isPlus), templateExecutor,
+		templateExecutorV2, *isPlus, false, nil, false, nil, false)
+
+	forbiddenListenerPorts := map[int]bool{
+		80:                              true,
+		443:                             true,
+		staticCfgParams.NginxStatusPort: true,
+	}
+
+	problems, warnings, err := k8s.Render(k8s.RenderInput{
+		NginxConfigurator:            cnf,
+		ConfigMap:                    cfm,
+		Objects:                      objects,
+		IngressClass:                 *class,
+		IsNginxPlus:                  *isPlus,
+		EnableOIDC:                   *oidc,
+		IsTLSPassthroughEnabled:      *tlsPassthrough,
+		SnippetsEnabled:              *snippets,
+		VirtualServerValidator:       cr_validation.NewVirtualServerValidator(cr_validation.IsPlus(*isPlus)),
+		GlobalConfigurationValidator: cr_validation.NewGlobalConfigurationValidator(forbiddenListenerPorts),
+		TransportServerValidator:     cr_validation.NewTransportServerValidator(*tlsPassthrough, *snippets, *isPlus),
+	})
+	if err != nil {
+		return err
+	}
+
+	writeRenderProblems(errOut, problems, warnings)
+
+	return printRenderedFiles(out, confPath)
+}
+
+func createRenderTemplateExecutors(isPlus bool, mainTemplate, ingressTemplate, virtualServerTemplate, transportServerTemplate string) (*version1.TemplateExecutor, *version2.TemplateExecutor, error) {
+	prefix := "nginx"
+	if isPlus {
+		prefix = "nginx-plus"
+	}
+
+	if mainTemplate == "" {
+		mainTemplate = prefix + ".tmpl"
+	}
+	if ingressTemplate == "" {
+		ingressTemplate = prefix + ".ingress.tmpl"
+	}
+	if virtualServerTemplate == "" {
+		virtualServerTemplate = prefix + ".virtualserver.tmpl"
+	}
+	if transportServerTemplate == "" {
+		transportServerTemplate = prefix + ".transportserver.tmpl"
+	}
+
+	templateExecutor, err := version1.NewTemplateExecutor(mainTemplate, ingressTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating TemplateExecutor: %w", err)
+	}
+
+	templateExecutorV2, err := version2.NewTemplateExecutor(virtualServerTemplate, transportServerTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating TemplateExecutorV2: %w", err)
+	}
+
+	return templateExecutor, templateExecutorV2, nil
+}
+
+func newManifestDecoder() runtime.Decoder {
+	sch := runtime.NewScheme()
+	utilruntime.Must(scheme.AddToScheme(sch))
+	utilruntime.Must(k8s_nginx_scheme.AddToScheme(sch))
+	return serializer.NewCodecFactory(sch).UniversalDeserializer()
+}
+
+// readManifests decodes the objects from a YAML manifest or from all YAML manifests of a folder.
+// The documents of unknown kinds are skipped with a message written to errOut.
+func readManifests(path string, errOut io.Writer) ([]runtime.Object, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifests: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(p)
+			if !d.IsDir() && (ext == ".yaml" || ext == ".yml" || ext == ".json") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifests: %w", err)
+		}
+	}
+
+	decoder := newManifestDecoder()
+
+	var objects []runtime.Object
+	for _, f := range files {
+		docs, err := readDocuments(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			obj, _, err := decoder.Decode(doc, nil, nil)
+			if err != nil {
+				if runtime.IsNotRegisteredError(err) {
+					fmt.Fprintf(errOut, "Skipping a document in %s: %v\n", f, err)
+					continue
+				}
+				return nil, fmt.Errorf("error decoding %s: %w", f, err)
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
+
+func readConfigMap(path string) (*api_v1.ConfigMap, error) {
+	docs, err := readDocuments(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) != 1 {
+		return nil, fmt.Errorf("%s must contain exactly one ConfigMap", path)
+	}
+
+	obj, _, err := newManifestDecoder().Decode(docs[0], nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+
+	cfm, ok := obj.(*api_v1.ConfigMap)
+	if !ok {
+		return nil, fmt.Errorf("%s must contain a ConfigMap, got %T", path, obj)
+	}
+	return cfm, nil
+}
+
+// readDocuments reads the non-empty YAML documents of a file.
+func readDocuments(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	reader := util_yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 || isCommentOnly(doc) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+func isCommentOnly(doc []byte) bool {
+	for _, line := range strings.Split(string(doc), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func writeRenderProblems(errOut io.Writer, problems []k8s.ConfigurationProblem, warnings configs.Warnings) {
+	for _, p := range problems {
+		kind := p.Object.GetObjectKind().GroupVersionKind().Kind
+		fmt.Fprintf(errOut, "%s: %s: %s\n", kind, p.Reason, p.Message)
+	}
+
+	var messages []string
+	for obj, msgs := range warnings {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		for _, m := range msgs {
+			messages = append(messages, fmt.Sprintf("%s: Warning: %s\n", kind, m))
+		}
+	}
+	sort.Strings(messages)
+	for _, m := range messages {
+		fmt.Fprint(errOut, m)
+	}
+}
+
+// printRenderedFiles writes the main config and the files of the conf.d and stream-conf.d folders to out.
+// Every file is preceded by a comment with its path relative to confPath.
+func printRenderedFiles(out io.Writer, confPath string) error {
+	files := []string{"nginx.conf"}
+	for _, dir := range []string{"conf.d", "stream-conf.d"} {
+		entries, err := os.ReadDir(filepath.Join(confPath, dir))
+		if err != nil {
+			return fmt.Errorf("error reading rendered files: %w", err)
+		}
+		for _, e := range entries {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(confPath, "tls-passthrough-hosts.conf")); err == nil {
+		files = append(files, "tls-passthrough-hosts.conf")
+	}
+
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(confPath, f))
+		if err != nil {
+			return fmt.Errorf("error reading rendered files: %w", err)
+		}
+		if _, err := fmt.Fprintf(out, "# %s\n%s\n", f, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
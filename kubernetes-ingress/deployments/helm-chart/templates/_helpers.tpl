@@ -133,3 +133,7 @@ Expand image name.
 {{- define "nginx-ingress.prometheus.serviceName" -}}
 {{- printf "%s-%s" (include "nginx-ingress.fullname" .) "prometheus-service"  -}}
 {{- end -}}
+
+{{- define "nginx-ingress.admissionWebhook.serviceName" -}}
+{{- printf "%s-%s" (include "nginx-ingress.fullname" .) "admission-webhook"  -}}
+{{- end -}}
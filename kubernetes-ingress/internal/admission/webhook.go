@@ -0,0 +1,296 @@
+// Package admission provides the validating admission webhook for the custom resources.
+package admission
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/glog"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	admission_v1 "k8s.io/api/admission/v1"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// FailurePolicyFail rejects the requests that the webhook can't check.
+	FailurePolicyFail = "Fail"
+	// FailurePolicyIgnore admits the requests that the webhook can't check.
+	FailurePolicyIgnore = "Ignore"
+
+	// ValidatePath is the path of the validating webhook endpoint.
+	ValidatePath = "/validate"
+
+	virtualServerKind      = "VirtualServer"
+	virtualServerRouteKind = "VirtualServerRoute"
+	transportServerKind    = "TransportServer"
+	policyKind             = "Policy"
+
+	maxRequestBodySize = 3 * 1024 * 1024
+)
+
+// ResourceChecker checks the resources against the current state of the Ingress Controller.
+type ResourceChecker interface {
+	// IsNginxReady returns true once the Ingress Controller has processed the existing resources.
+	IsNginxReady() bool
+	// HasCorrectIngressClass returns true if the resource is handled by the Ingress Controller.
+	HasCorrectIngressClass(obj interface{}) bool
+	// FindHostHolder returns the key with the kind of another resource that holds the host.
+	FindHostHolder(host string, kind string, objectMeta *meta_v1.ObjectMeta) string
+	// FindListenerHolder returns the key with the kind of another resource that holds the TransportServer listener
+	// for the host.
+	FindListenerHolder(listener string, host string, kind string, objectMeta *meta_v1.ObjectMeta) string
+}
+
+// Config holds the validators and the parameters of the webhook.
+type Config struct {
+	Checker                  ResourceChecker
+	VirtualServerValidator   *validation.VirtualServerValidator
+	TransportServerValidator *validation.TransportServerValidator
+	IsPlus                   bool
+	EnableOIDC               bool
+	EnableAppProtect         bool
+	IsTLSPassthroughEnabled  bool
+	FailurePolicy            string
+}
+
+// RunWebhook starts the validating admission webhook.
+func RunWebhook(port int, secret *api_v1.Secret, cfg Config) {
+	addr := fmt.Sprintf(":%s", strconv.Itoa(port))
+	wh, err := NewWebhook(addr, secret, cfg)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	glog.Infof("Starting Admission Webhook listener on: %v%v", addr, ValidatePath)
+	glog.Fatal(wh.ListenAndServe())
+}
+
+// Webhook holds data required for running
+// the validating admission webhook server.
+type Webhook struct {
+	Server *http.Server
+	URL    string
+	Config Config
+}
+
+// NewWebhook creates the webhook server. If secret is provided,
+// the server is configured with TLS Config.
+func NewWebhook(addr string, secret *api_v1.Secret, cfg Config) (*Webhook, error) {
+	wh := Webhook{
+		Server: &http.Server{
+			Addr:         addr,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		},
+		URL:    fmt.Sprintf("http://%s/", addr),
+		Config: cfg,
+	}
+
+	if secret != nil {
+		tlsCert, err := makeCert(secret)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS cert: %w", err)
+		}
+		wh.Server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{tlsCert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		wh.URL = fmt.Sprintf("https://%s/", addr)
+	}
+	return &wh, nil
+}
+
+// ListenAndServe starts the webhook server.
+func (wh *Webhook) ListenAndServe() error {
+	mux := chi.NewRouter()
+	mux.Post(ValidatePath, wh.Validate)
+	wh.Server.Handler = mux
+	if wh.Server.TLSConfig != nil {
+		return wh.Server.ListenAndServeTLS("", "")
+	}
+	return wh.Server.ListenAndServe()
+}
+
+// Shutdown shuts down the webhook server.
+func (wh *Webhook) Shutdown(ctx context.Context) error {
+	return wh.Server.Shutdown(ctx)
+}
+
+// Validate handles an AdmissionReview request and responds with the result of the validation of the resource.
+func (wh *Webhook) Validate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		glog.Errorf("error reading admission request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var review admission_v1.AdmissionReview
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		glog.Errorf("error decoding admission review: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.review(review.Request)
+	review.Request = nil
+
+	data, err := json.Marshal(review)
+	if err != nil {
+		glog.Error("error marshaling admission review", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if _, err = w.Write(data); err != nil {
+		glog.Error("error writing admission review", err)
+	}
+}
+
+func (wh *Webhook) review(req *admission_v1.AdmissionRequest) *admission_v1.AdmissionResponse {
+	if req.Operation != admission_v1.Create && req.Operation != admission_v1.Update {
+		return allow(req.UID)
+	}
+
+	if !wh.Config.Checker.IsNginxReady() {
+		return wh.applyFailurePolicy(req.UID, errors.New("the Ingress Controller is not ready"))
+	}
+
+	var err error
+	switch req.Kind.Kind {
+	case virtualServerKind:
+		var vs conf_v1.VirtualServer
+		if err = json.Unmarshal(req.Object.Raw, &vs); err != nil {
+			return wh.applyFailurePolicy(req.UID, err)
+		}
+		err = wh.validateVirtualServer(&vs)
+	case virtualServerRouteKind:
+		var vsr conf_v1.VirtualServerRoute
+		if err = json.Unmarshal(req.Object.Raw, &vsr); err != nil {
+			return wh.applyFailurePolicy(req.UID, err)
+		}
+		err = wh.validateVirtualServerRoute(&vsr)
+	case transportServerKind:
+		var ts conf_v1alpha1.TransportServer
+		if err = json.Unmarshal(req.Object.Raw, &ts); err != nil {
+			return wh.applyFailurePolicy(req.UID, err)
+		}
+		err = wh.validateTransportServer(&ts)
+	case policyKind:
+		var pol conf_v1.Policy
+		if err = json.Unmarshal(req.Object.Raw, &pol); err != nil {
+			return wh.applyFailurePolicy(req.UID, err)
+		}
+		err = wh.validatePolicy(&pol)
+	default:
+		return wh.applyFailurePolicy(req.UID, fmt.Errorf("unsupported kind %s", req.Kind.Kind))
+	}
+
+	if err != nil {
+		return deny(req.UID, fmt.Sprintf("%s %s/%s is invalid: %v", req.Kind.Kind, req.Namespace, req.Name, err))
+	}
+	return allow(req.UID)
+}
+
+func (wh *Webhook) validateVirtualServer(vs *conf_v1.VirtualServer) error {
+	if !wh.Config.Checker.HasCorrectIngressClass(vs) {
+		return nil
+	}
+	if err := wh.Config.VirtualServerValidator.ValidateVirtualServer(vs); err != nil {
+		return err
+	}
+	if holder := wh.Config.Checker.FindHostHolder(vs.Spec.Host, virtualServerKind, &vs.ObjectMeta); holder != "" {
+		return fmt.Errorf("host %s is taken by %s", vs.Spec.Host, holder)
+	}
+	return nil
+}
+
+func (wh *Webhook) validateVirtualServerRoute(vsr *conf_v1.VirtualServerRoute) error {
+	if !wh.Config.Checker.HasCorrectIngressClass(vsr) {
+		return nil
+	}
+	return wh.Config.VirtualServerValidator.ValidateVirtualServerRoute(vsr)
+}
+
+func (wh *Webhook) validateTransportServer(ts *conf_v1alpha1.TransportServer) error {
+	if !wh.Config.Checker.HasCorrectIngressClass(ts) {
+		return nil
+	}
+	if err := wh.Config.TransportServerValidator.ValidateTransportServer(ts); err != nil {
+		return err
+	}
+
+	if ts.Spec.Listener.Name == conf_v1alpha1.TLSPassthroughListenerName {
+		if !wh.Config.IsTLSPassthroughEnabled {
+			return nil
+		}
+		if holder := wh.Config.Checker.FindHostHolder(ts.Spec.Host, transportServerKind, &ts.ObjectMeta); holder != "" {
+			return fmt.Errorf("host %s is taken by %s", ts.Spec.Host, holder)
+		}
+		return nil
+	}
+
+	if holder := wh.Config.Checker.FindListenerHolder(ts.Spec.Listener.Name, ts.Spec.Host, transportServerKind, &ts.ObjectMeta); holder != "" {
+		return fmt.Errorf("listener %s is taken by %s", ts.Spec.Listener.Name, holder)
+	}
+	return nil
+}
+
+func (wh *Webhook) validatePolicy(pol *conf_v1.Policy) error {
+	if !wh.Config.Checker.HasCorrectIngressClass(pol) {
+		return nil
+	}
+	return validation.ValidatePolicy(pol, wh.Config.IsPlus, wh.Config.EnableOIDC, wh.Config.EnableAppProtect)
+}
+
+// applyFailurePolicy responds to a request that the webhook can't check according to the failure policy.
+func (wh *Webhook) applyFailurePolicy(uid types.UID, err error) *admission_v1.AdmissionResponse {
+	if wh.Config.FailurePolicy == FailurePolicyIgnore {
+		glog.Warningf("Admitting the request %s without validation: %v", uid, err)
+		return allow(uid)
+	}
+	return deny(uid, fmt.Sprintf("unable to validate the request: %v", err))
+}
+
+func allow(uid types.UID) *admission_v1.AdmissionResponse {
+	return &admission_v1.AdmissionResponse{
+		UID:     uid,
+		Allowed: true,
+	}
+}
+
+func deny(uid types.UID, message string) *admission_v1.AdmissionResponse {
+	return &admission_v1.AdmissionResponse{
+		UID:     uid,
+		Allowed: false,
+		Result: &meta_v1.Status{
+			Status:  meta_v1.StatusFailure,
+			Message: message,
+			Reason:  meta_v1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
+
+func makeCert(s *api_v1.Secret) (tls.Certificate, error) {
+	cert, ok := s.Data[api_v1.TLSCertKey]
+	if !ok {
+		return tls.Certificate{}, errors.New("missing tls cert")
+	}
+	key, ok := s.Data[api_v1.TLSPrivateKeyKey]
+	if !ok {
+		return tls.Certificate{}, errors.New("missing tls key")
+	}
+	return tls.X509KeyPair(cert, key)
+}
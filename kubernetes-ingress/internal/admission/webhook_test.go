@@ -0,0 +1,325 @@
+package admission_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/admission"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	admission_v1 "k8s.io/api/admission/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type fakeChecker struct {
+	notReady bool
+	hosts    map[string]string
+	listener map[string]string
+}
+
+func (c *fakeChecker) IsNginxReady() bool {
+	return !c.notReady
+}
+
+func (c *fakeChecker) HasCorrectIngressClass(obj interface{}) bool {
+	switch o := obj.(type) {
+	case *conf_v1.VirtualServer:
+		return o.Spec.IngressClass == "" || o.Spec.IngressClass == "nginx"
+	case *conf_v1alpha1.TransportServer:
+		return o.Spec.IngressClass == "" || o.Spec.IngressClass == "nginx"
+	}
+	return true
+}
+
+func (c *fakeChecker) FindHostHolder(host string, kind string, objectMeta *meta_v1.ObjectMeta) string {
+	holder := c.hosts[host]
+	if holder == kind+"/"+objectMeta.Namespace+"/"+objectMeta.Name {
+		return ""
+	}
+	return holder
+}
+
+func (c *fakeChecker) FindListenerHolder(listener string, _ string, kind string, objectMeta *meta_v1.ObjectMeta) string {
+	holder := c.listener[listener]
+	if holder == kind+"/"+objectMeta.Namespace+"/"+objectMeta.Name {
+		return ""
+	}
+	return holder
+}
+
+func newTestWebhook(t *testing.T, checker admission.ResourceChecker, failurePolicy string) *admission.Webhook {
+	t.Helper()
+
+	wh, err := admission.NewWebhook(":0", nil, admission.Config{
+		Checker:                  checker,
+		VirtualServerValidator:   validation.NewVirtualServerValidator(),
+		TransportServerValidator: validation.NewTransportServerValidator(true, false, false),
+		IsTLSPassthroughEnabled:  true,
+		FailurePolicy:            failurePolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wh
+}
+
+func sendReview(t *testing.T, wh *admission.Webhook, operation admission_v1.Operation, kind string, obj interface{}) *admission_v1.AdmissionResponse {
+	t.Helper()
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	review := admission_v1.AdmissionReview{
+		TypeMeta: meta_v1.TypeMeta{
+			APIVersion: "admission.k8s.io/v1",
+			Kind:       "AdmissionReview",
+		},
+		Request: &admission_v1.AdmissionRequest{
+			UID:       "test-uid",
+			Kind:      meta_v1.GroupVersionKind{Group: "k8s.nginx.org", Version: "v1", Kind: kind},
+			Name:      "test",
+			Namespace: "default",
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	wh.Validate(rec, httptest.NewRequest(http.MethodPost, admission.ValidatePath, bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Validate() returned status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var result admission_v1.AdmissionReview
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Response == nil {
+		t.Fatal("Validate() returned no response")
+	}
+	if result.Response.UID != "test-uid" {
+		t.Errorf("Validate() returned UID %q, want %q", result.Response.UID, "test-uid")
+	}
+	return result.Response
+}
+
+func createVirtualServer(host string) *conf_v1.VirtualServer {
+	return &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: host,
+		},
+	}
+}
+
+func createTransportServer(listener string, protocol string, host string) *conf_v1alpha1.TransportServer {
+	return &conf_v1alpha1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: conf_v1alpha1.TransportServerSpec{
+			Listener: conf_v1alpha1.TransportServerListener{
+				Name:     listener,
+				Protocol: protocol,
+			},
+			Host: host,
+			Upstreams: []conf_v1alpha1.Upstream{
+				{
+					Name:    "upstream",
+					Service: "service",
+					Port:    80,
+				},
+			},
+			Action: &conf_v1alpha1.Action{
+				Pass: "upstream",
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	checker := &fakeChecker{
+		hosts: map[string]string{
+			"taken.example.com":       "VirtualServer/default/other",
+			"own.example.com":         "VirtualServer/default/test",
+			"passthrough.example.com": "TransportServer/default/other",
+		},
+		listener: map[string]string{
+			"tcp-taken": "TransportServer/default/other",
+		},
+	}
+	wh := newTestWebhook(t, checker, admission.FailurePolicyFail)
+
+	wrongClassVS := createVirtualServer("")
+	wrongClassVS.Spec.IngressClass = "other"
+
+	tests := []struct {
+		operation admission_v1.Operation
+		kind      string
+		obj       interface{}
+		allowed   bool
+		msg       string
+	}{
+		{
+			operation: admission_v1.Create,
+			kind:      "VirtualServer",
+			obj:       createVirtualServer("cafe.example.com"),
+			allowed:   true,
+			msg:       "valid VirtualServer",
+		},
+		{
+			operation: admission_v1.Update,
+			kind:      "VirtualServer",
+			obj:       createVirtualServer("own.example.com"),
+			allowed:   true,
+			msg:       "VirtualServer that holds its host",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "VirtualServer",
+			obj:       createVirtualServer(""),
+			allowed:   false,
+			msg:       "invalid VirtualServer",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "VirtualServer",
+			obj:       createVirtualServer("taken.example.com"),
+			allowed:   false,
+			msg:       "VirtualServer with a taken host",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "VirtualServer",
+			obj:       wrongClassVS,
+			allowed:   true,
+			msg:       "invalid VirtualServer of another ingress class",
+		},
+		{
+			operation: admission_v1.Delete,
+			kind:      "VirtualServer",
+			obj:       createVirtualServer(""),
+			allowed:   true,
+			msg:       "delete operation",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "VirtualServerRoute",
+			obj: &conf_v1.VirtualServerRoute{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "test", Namespace: "default"},
+			},
+			allowed: false,
+			msg:     "invalid VirtualServerRoute",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "TransportServer",
+			obj:       createTransportServer("tcp-free", "TCP", ""),
+			allowed:   true,
+			msg:       "valid TransportServer",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "TransportServer",
+			obj:       createTransportServer("tcp-taken", "TCP", ""),
+			allowed:   false,
+			msg:       "TransportServer with a taken listener",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "TransportServer",
+			obj:       createTransportServer("tls-passthrough", "TLS_PASSTHROUGH", "passthrough.example.com"),
+			allowed:   false,
+			msg:       "TLS Passthrough TransportServer with a taken host",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "Policy",
+			obj: &conf_v1.Policy{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: conf_v1.PolicySpec{
+					AccessControl: &conf_v1.AccessControl{
+						Allow: []string{"10.0.0.0/8"},
+					},
+				},
+			},
+			allowed: true,
+			msg:     "valid Policy",
+		},
+		{
+			operation: admission_v1.Create,
+			kind:      "Policy",
+			obj: &conf_v1.Policy{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "test", Namespace: "default"},
+			},
+			allowed: false,
+			msg:     "invalid Policy",
+		},
+	}
+
+	for _, test := range tests {
+		resp := sendReview(t, wh, test.operation, test.kind, test.obj)
+		if resp.Allowed != test.allowed {
+			t.Errorf("Validate() returned allowed %v but expected %v for the case of %s", resp.Allowed, test.allowed, test.msg)
+		}
+		if !resp.Allowed && (resp.Result == nil || resp.Result.Message == "") {
+			t.Errorf("Validate() returned no message for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidate_AppliesFailurePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		failurePolicy string
+		allowed       bool
+	}{
+		{
+			failurePolicy: admission.FailurePolicyFail,
+			allowed:       false,
+		},
+		{
+			failurePolicy: admission.FailurePolicyIgnore,
+			allowed:       true,
+		},
+	}
+
+	for _, test := range tests {
+		wh := newTestWebhook(t, &fakeChecker{notReady: true}, test.failurePolicy)
+
+		resp := sendReview(t, wh, admission_v1.Create, "VirtualServer", createVirtualServer("cafe.example.com"))
+		if resp.Allowed != test.allowed {
+			t.Errorf("Validate() returned allowed %v but expected %v for the failure policy %s", resp.Allowed, test.allowed, test.failurePolicy)
+		}
+	}
+}
+
+func TestValidate_ReturnsBadRequestOnInvalidReview(t *testing.T) {
+	t.Parallel()
+
+	wh := newTestWebhook(t, &fakeChecker{}, admission.FailurePolicyFail)
+
+	rec := httptest.NewRecorder()
+	wh.Validate(rec, httptest.NewRequest(http.MethodPost, admission.ValidatePath, bytes.NewReader([]byte("{"))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Validate() returned status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -17,6 +17,7 @@ type ConfigParams struct {
 	HSTSIncludeSubdomains                  bool
 	HSTSMaxAge                             int64
 	HTTP2                                  bool
+	HTTP3                                  bool
 	Keepalive                              int
 	LBMethod                               string
 	LocationSnippets                       []string
@@ -28,6 +29,8 @@ type ConfigParams struct {
 	MainLogFormat                          []string
 	MainLogFormatEscaping                  string
 	MainMainSnippets                       []string
+	MainGeoIP2LoadModule                   bool
+	MainNJSLoadModule                      bool
 	MainOpenTracingEnabled                 bool
 	MainOpenTracingLoadModule              bool
 	MainOpenTracingTracer                  string
@@ -79,6 +82,7 @@ type ConfigParams struct {
 	ServerTokens                           string
 	SlowStart                              string
 	SSLRedirect                            bool
+	StreamLogFormats                       map[string]string
 	UpstreamZoneSize                       string
 	VariablesHashBucketSize                uint64
 	VariablesHashMaxSize                   uint64
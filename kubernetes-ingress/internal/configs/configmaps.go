@@ -1,6 +1,8 @@
 package configs
 
 import (
+	"os"
+	"regexp"
 	"strings"
 
 	"github.com/golang/glog"
@@ -9,6 +11,9 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 )
 
+// geoIP2ModuleFilename is the file of the geoip2 module, which is not installed in all the images of the Ingress Controller.
+const geoIP2ModuleFilename = "/etc/nginx/modules/ngx_http_geoip2_module.so"
+
 // ParseConfigMap parses ConfigMap into ConfigParams.
 //
 //nolint:gocyclo
@@ -94,6 +99,14 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool, hasAppProtect bool, hasA
 		}
 	}
 
+	if HTTP3, exists, err := GetMapKeyAsBool(cfgm.Data, "http3", cfgm); exists {
+		if err != nil {
+			glog.Error(err)
+		} else {
+			cfgParams.HTTP3 = HTTP3
+		}
+	}
+
 	if redirectToHTTPS, exists, err := GetMapKeyAsBool(cfgm.Data, "redirect-to-https", cfgm); exists {
 		if err != nil {
 			glog.Error(err)
@@ -237,6 +250,10 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool, hasAppProtect bool, hasA
 		}
 	}
 
+	if streamLogFormats, exists := GetMapKeyAsStringSlice(cfgm.Data, "stream-log-formats", cfgm, "\n"); exists {
+		cfgParams.StreamLogFormats = parseStreamLogFormats(streamLogFormats, cfgm)
+	}
+
 	if defaultServerAccessLogOff, exists, err := GetMapKeyAsBool(cfgm.Data, "default-server-access-log-off", cfgm); exists {
 		if err != nil {
 			glog.Error(err)
@@ -437,6 +454,24 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool, hasAppProtect bool, hasA
 		}
 	}
 
+	if geoIP2, exists, err := GetMapKeyAsBool(cfgm.Data, "geoip2", cfgm); exists {
+		if err != nil {
+			glog.Error(err)
+		} else if _, err := os.Stat(geoIP2ModuleFilename); geoIP2 && err != nil {
+			glog.Errorf("ConfigMap Key 'geoip2' requires the geoip2 module %s, which is not installed in the image of the Ingress Controller: %v, the geoip2 module will not be loaded", geoIP2ModuleFilename, err)
+		} else {
+			cfgParams.MainGeoIP2LoadModule = geoIP2
+		}
+	}
+
+	if njs, exists, err := GetMapKeyAsBool(cfgm.Data, "njs", cfgm); exists {
+		if err != nil {
+			glog.Error(err)
+		} else {
+			cfgParams.MainNJSLoadModule = njs
+		}
+	}
+
 	if hasAppProtect {
 		if appProtectFailureModeAction, exists := cfgm.Data["app-protect-failure-mode-action"]; exists {
 			if appProtectFailureModeAction == "pass" || appProtectFailureModeAction == "drop" {
@@ -518,6 +553,7 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		HealthStatus:                       staticCfgParams.HealthStatus,
 		HealthStatusURI:                    staticCfgParams.HealthStatusURI,
 		HTTP2:                              config.HTTP2,
+		HTTP3:                              config.HTTP3,
 		HTTPSnippets:                       config.MainHTTPSnippets,
 		KeepaliveRequests:                  config.MainKeepaliveRequests,
 		KeepaliveTimeout:                   config.MainKeepaliveTimeout,
@@ -529,6 +565,8 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		NginxStatusPort:                    staticCfgParams.NginxStatusPort,
 		OpenTracingEnabled:                 config.MainOpenTracingEnabled,
 		OpenTracingLoadModule:              config.MainOpenTracingLoadModule,
+		GeoIP2LoadModule:                   config.MainGeoIP2LoadModule,
+		NJSLoadModule:                      config.MainNJSLoadModule,
 		OpenTracingTracer:                  config.MainOpenTracingTracer,
 		OpenTracingTracerConfig:            config.MainOpenTracingTracerConfig,
 		ProxyProtocol:                      config.ProxyProtocol,
@@ -580,3 +618,25 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 	}
 	return nginxCfg
 }
+
+var streamLogFormatNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// parseStreamLogFormats parses the named stream log formats. Every line holds the name of a format
+// followed by the format itself. Invalid lines are ignored.
+func parseStreamLogFormats(lines []string, cfgm *v1.ConfigMap) map[string]string {
+	formats := make(map[string]string)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, format, _ := strings.Cut(line, " ")
+		format = strings.TrimSpace(format)
+		if !streamLogFormatNameRegexp.MatchString(name) || format == "" {
+			glog.Errorf("Configmap %s/%s: Invalid value for the stream-log-formats key: got %q, expected a name consisting of alphanumeric characters, '-' or '_' followed by a log format", cfgm.GetNamespace(), cfgm.GetName(), line)
+			continue
+		}
+		formats[name] = format
+	}
+	return formats
+}
@@ -1,6 +1,8 @@
 package configs
 
 import (
+	"os"
+	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -203,3 +205,106 @@ func TestParseConfigMapWithoutTLSPassthroughProxyProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConfigMapWithHTTP3(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		http3 string
+		want  bool
+		msg   string
+	}{
+		{
+			http3: "true",
+			want:  true,
+			msg:   "http3 enabled",
+		},
+		{
+			http3: "false",
+			want:  false,
+			msg:   "http3 disabled",
+		},
+		{
+			http3: "invalid",
+			want:  false,
+			msg:   "invalid http3 is ignored",
+		},
+	}
+	nginxPlus := true
+	hasAppProtect := true
+	hasAppProtectDos := false
+	hasTLSPassthrough := false
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: map[string]string{
+					"http3": test.http3,
+				},
+			}
+			result := ParseConfigMap(cm, nginxPlus, hasAppProtect, hasAppProtectDos, hasTLSPassthrough)
+			if result.HTTP3 != test.want {
+				t.Errorf("want %v, got %v", test.want, result.HTTP3)
+			}
+		})
+	}
+}
+
+func TestParseConfigMapWithGeoIP2WithoutModule(t *testing.T) {
+	t.Parallel()
+	if _, err := os.Stat(geoIP2ModuleFilename); err == nil {
+		t.Skipf("the geoip2 module %s is installed", geoIP2ModuleFilename)
+	}
+
+	for _, geoIP2 := range []string{"true", "false", "invalid"} {
+		cm := &v1.ConfigMap{
+			Data: map[string]string{
+				"geoip2": geoIP2,
+			},
+		}
+		result := ParseConfigMap(cm, true, false, false, false)
+		if result.MainGeoIP2LoadModule {
+			t.Errorf("ParseConfigMap() enabled the geoip2 module that is not installed for the geoip2 key %q", geoIP2)
+		}
+	}
+}
+
+func TestParseConfigMapWithStreamLogFormats(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		formats string
+		want    map[string]string
+		msg     string
+	}{
+		{
+			formats: "tcp-audit $remote_addr [$time_local] $status\ntcp_bytes $remote_addr $bytes_sent $bytes_received\n",
+			want: map[string]string{
+				"tcp-audit": "$remote_addr [$time_local] $status",
+				"tcp_bytes": "$remote_addr $bytes_sent $bytes_received",
+			},
+			msg: "valid formats",
+		},
+		{
+			formats: "tcp-audit $remote_addr\ninvalid.name $remote_addr\nno-format\n",
+			want: map[string]string{
+				"tcp-audit": "$remote_addr",
+			},
+			msg: "invalid formats are ignored",
+		},
+	}
+	nginxPlus := true
+	hasAppProtect := false
+	hasAppProtectDos := false
+	hasTLSPassthrough := false
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: map[string]string{
+					"stream-log-formats": test.formats,
+				},
+			}
+			result := ParseConfigMap(cm, nginxPlus, hasAppProtect, hasAppProtectDos, hasTLSPassthrough)
+			if !reflect.DeepEqual(result.StreamLogFormats, test.want) {
+				t.Errorf("want %v, got %v", test.want, result.StreamLogFormats)
+			}
+		})
+	}
+}
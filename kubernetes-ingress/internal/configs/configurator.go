@@ -3,8 +3,10 @@ package configs
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/dos/v1beta1"
@@ -90,6 +92,17 @@ type tlsPassthroughPair struct {
 	UnixSocket string
 }
 
+// tlsTerminationHost holds the TLS termination configuration of a TransportServer that shares a listener with other
+// TransportServers.
+type tlsTerminationHost struct {
+	Listener    string
+	Port        int
+	IPv4        string
+	IPv6        string
+	DisableIPV6 bool
+	Host        version2.TLSTerminationHost
+}
+
 // metricLabelsIndex keeps the relations between Ingress Controller resources and NGINX configuration.
 // Used to be able to add Prometheus Metrics variable labels grouped by resource key.
 type metricLabelsIndex struct {
@@ -119,7 +132,11 @@ type Configurator struct {
 	minions                 map[string]map[string]bool
 	virtualServers          map[string]*VirtualServerEx
 	transportServers        map[string]*TransportServerEx
+	httpRoutes              map[string][]string
 	tlsPassthroughPairs     map[string]tlsPassthroughPair
+	tlsTerminationHosts     map[string]tlsTerminationHost
+	http3Ports              map[string][]int
+	njsVirtualServers       map[string]bool
 	isWildcardEnabled       bool
 	isPlus                  bool
 	labelUpdater            collector.LabelUpdater
@@ -154,10 +171,14 @@ func NewConfigurator(nginxManager nginx.Manager, staticCfgParams *StaticConfigPa
 		ingresses:               make(map[string]*IngressEx),
 		virtualServers:          make(map[string]*VirtualServerEx),
 		transportServers:        make(map[string]*TransportServerEx),
+		httpRoutes:              make(map[string][]string),
 		templateExecutor:        templateExecutor,
 		templateExecutorV2:      templateExecutorV2,
 		minions:                 make(map[string]map[string]bool),
 		tlsPassthroughPairs:     make(map[string]tlsPassthroughPair),
+		tlsTerminationHosts:     make(map[string]tlsTerminationHost),
+		http3Ports:              make(map[string][]int),
+		njsVirtualServers:       make(map[string]bool),
 		isPlus:                  isPlus,
 		isWildcardEnabled:       isWildcardEnabled,
 		labelUpdater:            labelUpdater,
@@ -325,8 +346,13 @@ func (cnf *Configurator) StreamUpstreamsForName(name string) []string {
 // Transport Server obj associated with that name.
 func (cnf *Configurator) transportServerForActionName(name string) *conf_v1alpha1.TransportServer {
 	for _, tsEx := range cnf.transportServers {
-		glog.V(3).Infof("Check ts action '%s' for requested name: '%s'", tsEx.TransportServer.Spec.Action.Pass, name)
-		if tsEx.TransportServer.Spec.Action.Pass == name {
+		// TransportServers that use splits or matches without a default action are skipped
+		action := tsEx.TransportServer.Spec.Action
+		if action == nil {
+			continue
+		}
+		glog.V(3).Infof("Check ts action '%s' for requested name: '%s'", action.Pass, name)
+		if action.Pass == name {
 			return tsEx.TransportServer
 		}
 	}
@@ -371,6 +397,10 @@ func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) (Warnings, error)
 	}
 	cnf.nginxManager.CreateConfig(name, content)
 
+	if err := cnf.updateHTTP3Ports(name, getHTTP3PortsForIngress(&nginxCfg)); err != nil {
+		return warnings, err
+	}
+
 	cnf.ingresses[name] = ingEx
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.updateIngressMetricsLabels(ingEx, nginxCfg.Upstreams)
@@ -425,6 +455,10 @@ func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 	}
 	cnf.nginxManager.CreateConfig(name, content)
 
+	if err := cnf.updateHTTP3Ports(name, getHTTP3PortsForIngress(&nginxCfg)); err != nil {
+		return warnings, err
+	}
+
 	cnf.ingresses[name] = mergeableIngs.Master
 	cnf.minions[name] = make(map[string]bool)
 	for _, minion := range mergeableIngs.Minions {
@@ -556,6 +590,14 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	}
 	cnf.nginxManager.CreateConfig(name, content)
 
+	if err := cnf.updateHTTP3Ports(name, getHTTP3PortsForVirtualServer(&vsCfg)); err != nil {
+		return warnings, err
+	}
+
+	if err := cnf.updateNJSVirtualServers(name, isNJSRequiredForVirtualServer(&vsCfg)); err != nil {
+		return warnings, err
+	}
+
 	cnf.virtualServers[name] = virtualServerEx
 
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
@@ -564,25 +606,6 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	return warnings, nil
 }
 
-// AddOrUpdateVirtualServers adds or updates NGINX configuration for multiple VirtualServer resources.
-func (cnf *Configurator) AddOrUpdateVirtualServers(virtualServerExes []*VirtualServerEx) (Warnings, error) {
-	allWarnings := newWarnings()
-
-	for _, vsEx := range virtualServerExes {
-		warnings, err := cnf.addOrUpdateVirtualServer(vsEx)
-		if err != nil {
-			return allWarnings, err
-		}
-		allWarnings.Add(warnings)
-	}
-
-	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
-		return allWarnings, fmt.Errorf("error when reloading NGINX when updating Policy: %w", err)
-	}
-
-	return allWarnings, nil
-}
-
 func (cnf *Configurator) updateTransportServerMetricsLabels(transportServerEx *TransportServerEx, upstreams []version2.StreamUpstream) {
 	labels := make(map[string][]string)
 	newUpstreams := make(map[string]bool)
@@ -665,7 +688,7 @@ func (cnf *Configurator) AddOrUpdateTransportServer(transportServerEx *Transport
 
 func (cnf *Configurator) addOrUpdateTransportServer(transportServerEx *TransportServerEx) (Warnings, error) {
 	name := getFileNameForTransportServer(transportServerEx.TransportServer)
-	tsCfg, warnings := generateTransportServerConfig(transportServerEx, transportServerEx.ListenerPort, cnf.isPlus, cnf.IsResolverConfigured())
+	tsCfg, warnings := generateTransportServerConfig(transportServerEx, transportServerEx.ListenerPort, cnf.isPlus, cnf.IsResolverConfigured(), cnf.cfgParams)
 
 	content, err := cnf.templateExecutorV2.ExecuteTransportServerTemplate(tsCfg)
 	if err != nil {
@@ -678,9 +701,13 @@ func (cnf *Configurator) addOrUpdateTransportServer(transportServerEx *Transport
 
 	cnf.transportServers[name] = transportServerEx
 
+	err = cnf.updateTLSTerminationHosts(transportServerEx, tsCfg.Server.SSL)
+	if err != nil {
+		return nil, err
+	}
+
 	// update TLS Passthrough Hosts config in case we have a TLS Passthrough TransportServer
-	// only TLS Passthrough TransportServers have non-empty hosts
-	if transportServerEx.TransportServer.Spec.Host != "" {
+	if transportServerEx.TransportServer.Spec.Listener.Name == conf_v1alpha1.TLSPassthroughListenerName {
 		key := generateNamespaceNameKey(&transportServerEx.TransportServer.ObjectMeta)
 		cnf.tlsPassthroughPairs[key] = tlsPassthroughPair{
 			Host:       transportServerEx.TransportServer.Spec.Host,
@@ -718,6 +745,113 @@ func (cnf *Configurator) updateTLSPassthroughHostsConfig() error {
 	return nil
 }
 
+// updateTLSTerminationHosts updates the TLS termination config of the listeners shared by TransportServers with hosts
+// in case the TransportServer is one of them or was one of them before the update.
+func (cnf *Configurator) updateTLSTerminationHosts(transportServerEx *TransportServerEx, ssl *version2.StreamSSL) error {
+	ts := transportServerEx.TransportServer
+	key := generateNamespaceNameKey(&ts.ObjectMeta)
+
+	oldHost, hadHost := cnf.tlsTerminationHosts[key]
+
+	if !isTLSTerminationHost(ts) {
+		if !hadHost {
+			return nil
+		}
+		delete(cnf.tlsTerminationHosts, key)
+		return cnf.updateTLSTerminationHostsConfig(oldHost.Listener)
+	}
+
+	host := tlsTerminationHost{
+		Listener:    ts.Spec.Listener.Name,
+		Port:        transportServerEx.ListenerPort,
+		IPv4:        transportServerEx.ListenerIPv4,
+		IPv6:        transportServerEx.ListenerIPv6,
+		DisableIPV6: transportServerEx.DisableIPV6,
+		Host: version2.TLSTerminationHost{
+			Host:       ts.Spec.Host,
+			UnixSocket: generateUnixSocket(transportServerEx),
+		},
+	}
+	if ssl != nil && ssl.Enabled {
+		host.Host.Certificate = ssl.Certificate
+		host.Host.CertificateKey = ssl.CertificateKey
+	}
+	cnf.tlsTerminationHosts[key] = host
+
+	if hadHost && oldHost.Listener != host.Listener {
+		err := cnf.updateTLSTerminationHostsConfig(oldHost.Listener)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cnf.updateTLSTerminationHostsConfig(host.Listener)
+}
+
+func (cnf *Configurator) updateTLSTerminationHostsConfig(listener string) error {
+	name := getFileNameForTLSTerminationHosts(listener)
+
+	cfg := generateTLSTerminationHostsConfig(listener, cnf.tlsTerminationHosts)
+	if cfg == nil {
+		cnf.nginxManager.DeleteStreamConfig(name)
+		return nil
+	}
+
+	content, err := cnf.templateExecutorV2.ExecuteTLSTerminationHostsTemplate(cfg)
+	if err != nil {
+		return fmt.Errorf("error generating TLS termination config for listener %s: %w", listener, err)
+	}
+
+	cnf.nginxManager.CreateStreamConfig(name, content)
+
+	return nil
+}
+
+// generateTLSTerminationHostsConfig generates the TLS termination config for the listener.
+// It returns nil if no TransportServer with a host uses the listener.
+// The hosts with invalid TLS secrets are not added, so that NGINX fails the TLS handshake for them.
+func generateTLSTerminationHostsConfig(listener string, tlsTerminationHosts map[string]tlsTerminationHost) *version2.TLSTerminationHostsConfig {
+	var cfg *version2.TLSTerminationHostsConfig
+
+	for _, key := range getSortedTLSTerminationHostKeys(tlsTerminationHosts) {
+		h := tlsTerminationHosts[key]
+		if h.Listener != listener {
+			continue
+		}
+
+		if cfg == nil {
+			cfg = &version2.TLSTerminationHostsConfig{
+				Listener:       h.Listener,
+				VariablePrefix: "$ts_sni_" + strings.ReplaceAll(h.Listener, "-", "_"),
+				Port:           h.Port,
+				IPv4:           h.IPv4,
+				IPv6:           h.IPv6,
+				DisableIPV6:    h.DisableIPV6,
+			}
+		}
+
+		if h.Host.Certificate == "" {
+			continue
+		}
+
+		cfg.Hosts = append(cfg.Hosts, h.Host)
+	}
+
+	return cfg
+}
+
+func getSortedTLSTerminationHostKeys(m map[string]tlsTerminationHost) []string {
+	var keys []string
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
 func generateTLSPassthroughHostsConfig(tlsPassthroughPairs map[string]tlsPassthroughPair) *version2.TLSPassthroughHostsConfig {
 	cfg := version2.TLSPassthroughHostsConfig{}
 
@@ -728,6 +862,168 @@ func generateTLSPassthroughHostsConfig(tlsPassthroughPairs map[string]tlsPassthr
 	return &cfg
 }
 
+// updateHTTP3Ports updates the ports on which the Ingress or VirtualServer with the config file name accepts HTTP/3
+// and the QUIC listeners config in case the ports changed.
+func (cnf *Configurator) updateHTTP3Ports(name string, ports []int) error {
+	if slices.Equal(cnf.http3Ports[name], ports) {
+		return nil
+	}
+
+	if len(ports) == 0 {
+		delete(cnf.http3Ports, name)
+	} else {
+		cnf.http3Ports[name] = ports
+	}
+
+	return cnf.updateHTTP3ListenersConfig()
+}
+
+// updateNJSVirtualServers updates whether the VirtualServer with the config file name requires the njs module
+// and the main config in case the module must be loaded or unloaded.
+func (cnf *Configurator) updateNJSVirtualServers(name string, required bool) error {
+	if cnf.njsVirtualServers[name] == required {
+		return nil
+	}
+
+	wasRequired := len(cnf.njsVirtualServers) > 0
+	if required {
+		cnf.njsVirtualServers[name] = true
+	} else {
+		delete(cnf.njsVirtualServers, name)
+	}
+
+	if wasRequired == (len(cnf.njsVirtualServers) > 0) {
+		return nil
+	}
+
+	return cnf.updateMainConfig()
+}
+
+// isNJSRequiredForVirtualServer checks if the VirtualServer config uses the njs module: in NGINX, the JWT policies
+// verify the tokens with njs, and the API key policies hash the keys with njs.
+func isNJSRequiredForVirtualServer(vsCfg *version2.VirtualServerConfig) bool {
+	if len(vsCfg.Server.JWTVerifierLocations) > 0 {
+		return true
+	}
+
+	for _, l := range vsCfg.Server.Locations {
+		if l.APIKey != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generateMainConfig generates the main config. The njs module is loaded if the njs ConfigMap key is enabled or
+// any VirtualServer requires it.
+func (cnf *Configurator) generateMainConfig(cfgParams *ConfigParams) *version1.MainConfig {
+	mainCfg := GenerateNginxMainConfig(cnf.staticCfgParams, cfgParams)
+	mainCfg.NJSLoadModule = mainCfg.NJSLoadModule || len(cnf.njsVirtualServers) > 0
+	return mainCfg
+}
+
+// updateMainConfig writes the main config with the current ConfigMap parameters.
+func (cnf *Configurator) updateMainConfig() error {
+	mainCfg := cnf.generateMainConfig(cnf.cfgParams)
+	mainCfgContent, err := cnf.templateExecutor.ExecuteMainConfigTemplate(mainCfg)
+	if err != nil {
+		return fmt.Errorf("error when writing main Config: %w", err)
+	}
+	cnf.nginxManager.CreateMainConfig(mainCfgContent)
+
+	return nil
+}
+
+func (cnf *Configurator) updateHTTP3ListenersConfig() error {
+	cfg := generateHTTP3ListenersConfig(cnf.http3Ports, cnf.cfgParams.HTTP3, cnf.staticCfgParams.DisableIPV6)
+	if cfg == nil {
+		cnf.nginxManager.DeleteConfig(http3ListenersFileName)
+		return nil
+	}
+
+	content, err := cnf.templateExecutorV2.ExecuteHTTP3ListenersTemplate(cfg)
+	if err != nil {
+		return fmt.Errorf("error generating config for HTTP/3 listeners: %w", err)
+	}
+
+	cnf.nginxManager.CreateConfig(http3ListenersFileName, content)
+
+	return nil
+}
+
+// generateHTTP3ListenersConfig generates the QUIC listeners for the ports on which Ingresses and VirtualServers
+// accept HTTP/3, so that the reuseport parameter is set once per port. Port 443 is skipped when the http3 ConfigMap
+// key is enabled, because the default server of the main config sets the parameter for it.
+// It returns nil if there are no such ports.
+func generateHTTP3ListenersConfig(http3Ports map[string][]int, mainHTTP3 bool, disableIPV6 bool) *version2.HTTP3ListenersConfig {
+	var ports []int
+
+	for _, resourcePorts := range http3Ports {
+		for _, p := range resourcePorts {
+			if p == 443 && mainHTTP3 {
+				continue
+			}
+			if !slices.Contains(ports, p) {
+				ports = append(ports, p)
+			}
+		}
+	}
+
+	if len(ports) == 0 {
+		return nil
+	}
+
+	slices.Sort(ports)
+
+	return &version2.HTTP3ListenersConfig{
+		Ports:       ports,
+		DisableIPV6: disableIPV6,
+	}
+}
+
+// getHTTP3PortsForIngress returns the sorted ports on which the servers of the Ingress config accept HTTP/3.
+func getHTTP3PortsForIngress(cfg *version1.IngressNginxConfig) []int {
+	var ports []int
+
+	for _, s := range cfg.Servers {
+		if !s.HTTP3 {
+			continue
+		}
+		for _, p := range s.SSLPorts {
+			if !slices.Contains(ports, p) {
+				ports = append(ports, p)
+			}
+		}
+	}
+
+	slices.Sort(ports)
+
+	return ports
+}
+
+// getHTTP3PortsForVirtualServer returns the sorted ports on which the server of the VirtualServer config accepts HTTP/3.
+func getHTTP3PortsForVirtualServer(cfg *version2.VirtualServerConfig) []int {
+	if cfg.Server.SSL == nil || !cfg.Server.SSL.HTTP3 {
+		return nil
+	}
+
+	if !cfg.Server.CustomListeners {
+		return []int{443}
+	}
+
+	var ports []int
+	for _, l := range cfg.Server.Listeners {
+		if l.SSL && l.HTTP3 && !slices.Contains(ports, l.Port) {
+			ports = append(ports, l.Port)
+		}
+	}
+
+	slices.Sort(ports)
+
+	return ports
+}
+
 func (cnf *Configurator) addOrUpdateCASecret(secret *api_v1.Secret) string {
 	name := objectMetaToFileName(&secret.ObjectMeta)
 	crtData, crlData := GenerateCAFileContent(secret)
@@ -843,6 +1139,10 @@ func (cnf *Configurator) DeleteIngress(key string, skipReload bool) error {
 	delete(cnf.ingresses, name)
 	delete(cnf.minions, name)
 
+	if err := cnf.updateHTTP3Ports(name, nil); err != nil {
+		return fmt.Errorf("error when removing ingress %v: %w", key, err)
+	}
+
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteIngressMetricsLabels(key)
 	}
@@ -862,6 +1162,13 @@ func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error
 	cnf.nginxManager.DeleteConfig(name)
 
 	delete(cnf.virtualServers, name)
+
+	if err := cnf.updateHTTP3Ports(name, nil); err != nil {
+		return fmt.Errorf("error when removing VirtualServer %v: %w", key, err)
+	}
+	if err := cnf.updateNJSVirtualServers(name, false); err != nil {
+		return fmt.Errorf("error when removing VirtualServer %v: %w", key, err)
+	}
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteVirtualServerMetricsLabels(key)
 	}
@@ -875,6 +1182,50 @@ func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error
 	return nil
 }
 
+// AddOrUpdateHTTPRoute adds or updates NGINX configuration for the VirtualServers generated for the HTTPRoute resource.
+// The configuration of the VirtualServers that were generated for the HTTPRoute before but are not in virtualServerExes is deleted.
+func (cnf *Configurator) AddOrUpdateHTTPRoute(key string, virtualServerExes []*VirtualServerEx) (Warnings, error) {
+	allWarnings := newWarnings()
+	var vsKeys []string
+
+	for _, vsEx := range virtualServerExes {
+		warnings, err := cnf.addOrUpdateVirtualServer(vsEx)
+		if err != nil {
+			return allWarnings, fmt.Errorf("error adding or updating HTTPRoute %v: %w", key, err)
+		}
+		allWarnings.Add(warnings)
+		vsKeys = append(vsKeys, vsEx.VirtualServer.Namespace+"/"+vsEx.VirtualServer.Name)
+	}
+
+	for _, oldKey := range cnf.httpRoutes[key] {
+		if !slices.Contains(vsKeys, oldKey) {
+			// DeleteVirtualServer doesn't return an error when it skips the reload
+			_ = cnf.DeleteVirtualServer(oldKey, true)
+		}
+	}
+	cnf.httpRoutes[key] = vsKeys
+
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
+		return allWarnings, fmt.Errorf("error reloading NGINX for HTTPRoute %v: %w", key, err)
+	}
+
+	return allWarnings, nil
+}
+
+// DeleteHTTPRoute deletes NGINX configuration for the VirtualServers generated for the HTTPRoute resource.
+func (cnf *Configurator) DeleteHTTPRoute(key string) error {
+	for _, vsKey := range cnf.httpRoutes[key] {
+		_ = cnf.DeleteVirtualServer(vsKey, true)
+	}
+	delete(cnf.httpRoutes, key)
+
+	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
+		return fmt.Errorf("error when removing HTTPRoute %v: %w", key, err)
+	}
+
+	return nil
+}
+
 // DeleteTransportServer deletes NGINX configuration for the TransportServer resource.
 func (cnf *Configurator) DeleteTransportServer(key string) error {
 	if cnf.isPlus && cnf.isPrometheusEnabled {
@@ -898,6 +1249,14 @@ func (cnf *Configurator) deleteTransportServer(key string) error {
 	name := getFileNameForTransportServerFromKey(key)
 	cnf.nginxManager.DeleteStreamConfig(name)
 
+	delete(cnf.transportServers, name)
+
+	if host, exists := cnf.tlsTerminationHosts[key]; exists {
+		delete(cnf.tlsTerminationHosts, key)
+
+		return cnf.updateTLSTerminationHostsConfig(host.Listener)
+	}
+
 	// update TLS Passthrough Hosts config in case we have a TLS Passthrough TransportServer
 	if _, exists := cnf.tlsPassthroughPairs[key]; exists {
 		delete(cnf.tlsPassthroughPairs, key)
@@ -1135,7 +1494,112 @@ func (cnf *Configurator) reload(isEndpointsUpdate bool) error {
 		return nil
 	}
 
-	return cnf.nginxManager.Reload(isEndpointsUpdate)
+	err := cnf.nginxManager.Reload(isEndpointsUpdate)
+
+	var rollbackErr *nginx.ConfigRollbackError
+	if !errors.As(err, &rollbackErr) {
+		return err
+	}
+
+	return cnf.removeInvalidResources(rollbackErr, isEndpointsUpdate)
+}
+
+// InvalidResourcesError is returned when NGINX rejects the configuration of some of the resources.
+// The configuration of those resources is removed, while the configuration of the other resources is applied.
+type InvalidResourcesError struct {
+	// Resources maps the keys with the kinds of the invalid resources, like VirtualServer/my-namespace/my-name,
+	// to the errors of NGINX.
+	Resources map[string]error
+	Err       error
+}
+
+func (e *InvalidResourcesError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidResourcesError) Unwrap() error {
+	return e.Err
+}
+
+// removeInvalidResources removes the configuration of the resources whose configs NGINX rejected, so that
+// the state of the Configurator matches the configuration of NGINX, and reloads NGINX.
+// If NGINX rejected a file that doesn't belong to a resource, like the main config, the rollback error is returned,
+// because the invalid resource is not known.
+func (cnf *Configurator) removeInvalidResources(rollbackErr *nginx.ConfigRollbackError, isEndpointsUpdate bool) error {
+	invalidResources := make(map[string]error)
+	var removeFuncs []func() error
+
+	for name, nginxErr := range rollbackErr.InvalidConfigs {
+		switch {
+		case cnf.ingresses[name] != nil:
+			ing := cnf.ingresses[name].Ingress
+			key := ing.Namespace + "/" + ing.Name
+			invalidResources["Ingress/"+key] = nginxErr
+			removeFuncs = append(removeFuncs, func() error {
+				return cnf.DeleteIngress(key, true)
+			})
+		case cnf.virtualServers[name] != nil:
+			vs := cnf.virtualServers[name].VirtualServer
+			key := vs.Namespace + "/" + vs.Name
+			if hrKey, exists := cnf.findHTTPRouteForVirtualServer(name); exists {
+				invalidResources["HTTPRoute/"+hrKey] = nginxErr
+				removeFuncs = append(removeFuncs, func() error {
+					for _, vsKey := range cnf.httpRoutes[hrKey] {
+						if err := cnf.DeleteVirtualServer(vsKey, true); err != nil {
+							return err
+						}
+					}
+					delete(cnf.httpRoutes, hrKey)
+					return nil
+				})
+				continue
+			}
+			invalidResources["VirtualServer/"+key] = nginxErr
+			removeFuncs = append(removeFuncs, func() error {
+				return cnf.DeleteVirtualServer(key, true)
+			})
+		case cnf.transportServers[name] != nil:
+			ts := cnf.transportServers[name].TransportServer
+			key := ts.Namespace + "/" + ts.Name
+			invalidResources["TransportServer/"+key] = nginxErr
+			removeFuncs = append(removeFuncs, func() error {
+				if cnf.isPlus && cnf.isPrometheusEnabled {
+					cnf.deleteTransportServerMetricsLabels(key)
+				}
+				return cnf.deleteTransportServer(key)
+			})
+		default:
+			return rollbackErr
+		}
+	}
+
+	for _, remove := range removeFuncs {
+		if err := remove(); err != nil {
+			return fmt.Errorf("%w; removing the invalid resources failed: %w", rollbackErr, err)
+		}
+	}
+
+	if err := cnf.nginxManager.Reload(isEndpointsUpdate); err != nil {
+		return fmt.Errorf("%w; removing the invalid resources failed: %w", rollbackErr, err)
+	}
+
+	return &InvalidResourcesError{
+		Resources: invalidResources,
+		Err:       rollbackErr,
+	}
+}
+
+// findHTTPRouteForVirtualServer returns the key of the HTTPRoute that the VirtualServer with the config name
+// was generated for.
+func (cnf *Configurator) findHTTPRouteForVirtualServer(name string) (string, bool) {
+	for hrKey, vsKeys := range cnf.httpRoutes {
+		for _, vsKey := range vsKeys {
+			if getFileNameForVirtualServerFromKey(vsKey) == name {
+				return hrKey, true
+			}
+		}
+	}
+	return "", false
 }
 
 func (cnf *Configurator) updateServersInPlus(upstream string, servers []string, config nginx.ServerConfig) error {
@@ -1190,7 +1654,7 @@ func (cnf *Configurator) UpdateConfig(cfgParams *ConfigParams, resources Extende
 		}
 	}
 
-	mainCfg := GenerateNginxMainConfig(cnf.staticCfgParams, cfgParams)
+	mainCfg := cnf.generateMainConfig(cfgParams)
 	mainCfgContent, err := cnf.templateExecutor.ExecuteMainConfigTemplate(mainCfg)
 	if err != nil {
 		return allWarnings, fmt.Errorf("error when writing main Config")
@@ -1227,6 +1691,13 @@ func (cnf *Configurator) UpdateConfig(cfgParams *ConfigParams, resources Extende
 		allWarnings.Add(warnings)
 	}
 
+	// The default server of the main config sets the reuseport parameter for port 443 when the http3 key is enabled.
+	if len(cnf.http3Ports) > 0 {
+		if err := cnf.updateHTTP3ListenersConfig(); err != nil {
+			return allWarnings, err
+		}
+	}
+
 	if mainCfg.OpenTracingLoadModule {
 		if err := cnf.addOrUpdateOpenTracingTracerConfig(mainCfg.OpenTracingTracerConfig); err != nil {
 			return allWarnings, fmt.Errorf("error when updating OpenTracing tracer config: %w", err)
@@ -1354,6 +1825,14 @@ func getFileNameForTransportServer(transportServer *conf_v1alpha1.TransportServe
 	return fmt.Sprintf("ts_%s_%s", transportServer.Namespace, transportServer.Name)
 }
 
+// http3ListenersFileName is the name of the config file with the QUIC listeners. The underscore
+// prevents conflicts with the config files of the Ingresses, which are named after the namespace and name.
+const http3ListenersFileName = "http3_listeners"
+
+func getFileNameForTLSTerminationHosts(listener string) string {
+	return fmt.Sprintf("tls-termination_%s", listener)
+}
+
 func getFileNameForVirtualServerFromKey(key string) string {
 	replaced := strings.Replace(key, "/", "_", -1)
 	return fmt.Sprintf("vs_%s", replaced)
@@ -1414,6 +1893,10 @@ func (cnf *Configurator) GetIngressCounts() map[string]int {
 // GetVirtualServerCounts returns the total count of VS/VSR resources that are handled by the Ingress Controller
 func (cnf *Configurator) GetVirtualServerCounts() (vsCount int, vsrCount int) {
 	vsCount = len(cnf.virtualServers)
+	// the VirtualServers generated for HTTPRoutes are not counted
+	for _, vsKeys := range cnf.httpRoutes {
+		vsCount -= len(vsKeys)
+	}
 	for _, vs := range cnf.virtualServers {
 		vsrCount += len(vs.VirtualServerRoutes)
 	}
@@ -1664,12 +2147,9 @@ func (cnf *Configurator) DeleteAppProtectDosLogConf(resource *unstructured.Unstr
 func (cnf *Configurator) AddInternalRouteConfig() error {
 	cnf.staticCfgParams.EnableInternalRoutes = true
 	cnf.staticCfgParams.InternalRouteServerName = fmt.Sprintf("%s.%s.svc", os.Getenv("POD_SERVICEACCOUNT"), os.Getenv("POD_NAMESPACE"))
-	mainCfg := GenerateNginxMainConfig(cnf.staticCfgParams, cnf.cfgParams)
-	mainCfgContent, err := cnf.templateExecutor.ExecuteMainConfigTemplate(mainCfg)
-	if err != nil {
-		return fmt.Errorf("error when writing main Config: %w", err)
+	if err := cnf.updateMainConfig(); err != nil {
+		return err
 	}
-	cnf.nginxManager.CreateMainConfig(mainCfgContent)
 	if err := cnf.reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("error when reloading nginx: %w", err)
 	}
@@ -1688,6 +2168,9 @@ func (cnf *Configurator) AddOrUpdateSecret(secret *api_v1.Secret) string {
 	case secrets.SecretTypeOIDC:
 		// OIDC ClientSecret is not required on the filesystem, it is written directly to the config file.
 		return ""
+	case secrets.SecretTypeAPIKey:
+		// API keys are not required on the filesystem, their hashes are written directly to the config file.
+		return ""
 	default:
 		return cnf.addOrUpdateTLSSecret(secret)
 	}
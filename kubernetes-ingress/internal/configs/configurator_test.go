@@ -1,18 +1,24 @@
 package configs
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/prometheus/client_golang/prometheus"
+	api_v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
@@ -300,6 +306,342 @@ func TestGenerateTLSPassthroughHostsConfig(t *testing.T) {
 	}
 }
 
+func TestGenerateTLSTerminationHostsConfig(t *testing.T) {
+	t.Parallel()
+	tlsTerminationHosts := map[string]tlsTerminationHost{
+		"default/ts-1": {
+			Listener: "tcp-8443",
+			Port:     8443,
+			Host: version2.TLSTerminationHost{
+				Host:           "one.example.com",
+				UnixSocket:     "socket1.sock",
+				Certificate:    "/etc/nginx/secrets/default-secret-one",
+				CertificateKey: "/etc/nginx/secrets/default-secret-one",
+			},
+		},
+		"default/ts-2": {
+			Listener: "tcp-8443",
+			Port:     8443,
+			Host: version2.TLSTerminationHost{
+				Host:       "two.example.com",
+				UnixSocket: "socket2.sock",
+			},
+		},
+		"default/ts-3": {
+			Listener: "tcp-9443",
+			Port:     9443,
+			Host: version2.TLSTerminationHost{
+				Host:           "three.example.com",
+				UnixSocket:     "socket3.sock",
+				Certificate:    "/etc/nginx/secrets/default-secret-three",
+				CertificateKey: "/etc/nginx/secrets/default-secret-three",
+			},
+		},
+	}
+
+	tests := []struct {
+		listener string
+		expected *version2.TLSTerminationHostsConfig
+		msg      string
+	}{
+		{
+			listener: "tcp-8443",
+			expected: &version2.TLSTerminationHostsConfig{
+				Listener:       "tcp-8443",
+				VariablePrefix: "$ts_sni_tcp_8443",
+				Port:           8443,
+				Hosts: []version2.TLSTerminationHost{
+					{
+						Host:           "one.example.com",
+						UnixSocket:     "socket1.sock",
+						Certificate:    "/etc/nginx/secrets/default-secret-one",
+						CertificateKey: "/etc/nginx/secrets/default-secret-one",
+					},
+				},
+			},
+			msg: "host without a valid certificate is not added",
+		},
+		{
+			listener: "tcp-9443",
+			expected: &version2.TLSTerminationHostsConfig{
+				Listener:       "tcp-9443",
+				VariablePrefix: "$ts_sni_tcp_9443",
+				Port:           9443,
+				Hosts: []version2.TLSTerminationHost{
+					{
+						Host:           "three.example.com",
+						UnixSocket:     "socket3.sock",
+						Certificate:    "/etc/nginx/secrets/default-secret-three",
+						CertificateKey: "/etc/nginx/secrets/default-secret-three",
+					},
+				},
+			},
+			msg: "only hosts of the listener are added",
+		},
+		{
+			listener: "tcp-7777",
+			expected: nil,
+			msg:      "listener without hosts",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateTLSTerminationHostsConfig(test.listener, tlsTerminationHosts)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateTLSTerminationHostsConfig() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateHTTP3ListenersConfig(t *testing.T) {
+	t.Parallel()
+	http3Ports := map[string][]int{
+		"vs_default_cafe":   {443},
+		"vs_default_tea":    {443, 8443},
+		"default-ingress-1": {9443},
+	}
+
+	tests := []struct {
+		http3Ports  map[string][]int
+		mainHTTP3   bool
+		disableIPV6 bool
+		expected    *version2.HTTP3ListenersConfig
+		msg         string
+	}{
+		{
+			http3Ports: http3Ports,
+			mainHTTP3:  false,
+			expected: &version2.HTTP3ListenersConfig{
+				Ports: []int{443, 8443, 9443},
+			},
+			msg: "ports shared by resources are added once",
+		},
+		{
+			http3Ports:  http3Ports,
+			mainHTTP3:   true,
+			disableIPV6: true,
+			expected: &version2.HTTP3ListenersConfig{
+				Ports:       []int{8443, 9443},
+				DisableIPV6: true,
+			},
+			msg: "port 443 is skipped with the http3 ConfigMap key",
+		},
+		{
+			http3Ports: map[string][]int{
+				"vs_default_cafe": {443},
+			},
+			mainHTTP3: true,
+			expected:  nil,
+			msg:       "only port 443 with the http3 ConfigMap key",
+		},
+		{
+			http3Ports: map[string][]int{},
+			expected:   nil,
+			msg:        "no ports",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateHTTP3ListenersConfig(test.http3Ports, test.mainHTTP3, test.disableIPV6)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateHTTP3ListenersConfig() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestHTTP3ListenersSetReusePortOncePerPort(t *testing.T) {
+	t.Parallel()
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/cafe-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+			Path: "/etc/nginx/secrets/default-cafe-secret",
+		},
+	}
+
+	tests := []struct {
+		vsEx      *VirtualServerEx
+		wantPorts []int
+		msg       string
+	}{
+		{
+			vsEx: &VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						TLS: &conf_v1.TLS{
+							Secret: "cafe-secret",
+							HTTP3:  createPointerFromBool(true),
+						},
+					},
+				},
+				SecretRefs: secretRefs,
+			},
+			wantPorts: []int{443},
+			msg:       "tls.http3 without the http3 ConfigMap key",
+		},
+		{
+			vsEx: &VirtualServerEx{
+				Listeners: []conf_v1alpha1.Listener{
+					{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true, HTTP3: createPointerFromBool(true)},
+				},
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						TLS: &conf_v1.TLS{
+							Secret: "cafe-secret",
+						},
+						Listener: &conf_v1.Listener{
+							HTTPS: "https-8443",
+						},
+					},
+				},
+				SecretRefs: secretRefs,
+			},
+			wantPorts: []int{8443},
+			msg:       "custom listener with HTTP/3",
+		},
+	}
+
+	executor, err := version2.NewTemplateExecutor("version2/nginx.virtualserver.tmpl", "version2/nginx.transportserver.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+		vsCfg, _ := vsc.GenerateVirtualServerConfig(test.vsEx, nil, nil)
+
+		vsConf, err := executor.ExecuteVirtualServerTemplate(&vsCfg)
+		if err != nil {
+			t.Fatalf("Failed to execute the VirtualServer template for the case of %s: %v", test.msg, err)
+		}
+
+		ports := getHTTP3PortsForVirtualServer(&vsCfg)
+		if diff := cmp.Diff(test.wantPorts, ports); diff != "" {
+			t.Errorf("getHTTP3PortsForVirtualServer() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+
+		cfg := generateHTTP3ListenersConfig(map[string][]int{"vs_default_cafe": ports}, false, false)
+		if cfg == nil {
+			t.Fatalf("generateHTTP3ListenersConfig() returned nil for the case of %s", test.msg)
+		}
+		listenersConf, err := executor.ExecuteHTTP3ListenersTemplate(cfg)
+		if err != nil {
+			t.Fatalf("Failed to execute the HTTP/3 listeners template for the case of %s: %v", test.msg, err)
+		}
+
+		conf := string(vsConf) + string(listenersConf)
+		for _, p := range test.wantPorts {
+			for _, want := range []string{
+				fmt.Sprintf("listen %d quic reuseport;", p),
+				fmt.Sprintf("listen [::]:%d quic reuseport;", p),
+			} {
+				if count := strings.Count(conf, want); count != 1 {
+					t.Errorf("want %q once in the generated config for the case of %s, got %d", want, test.msg, count)
+				}
+			}
+			if !strings.Contains(string(vsConf), fmt.Sprintf("listen %d quic;", p)) {
+				t.Errorf("want the QUIC listener for port %d in the VirtualServer config for the case of %s", p, test.msg)
+			}
+		}
+		if count := strings.Count(conf, "quic reuseport"); count != 2*len(test.wantPorts) {
+			t.Errorf("want quic reuseport %d times in the generated config for the case of %s, got %d", 2*len(test.wantPorts), test.msg, count)
+		}
+		t.Log(conf)
+	}
+}
+
+func TestIsNJSRequiredForVirtualServer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		vsCfg    *version2.VirtualServerConfig
+		expected bool
+		msg      string
+	}{
+		{
+			vsCfg: &version2.VirtualServerConfig{
+				Server: version2.Server{
+					JWTVerifierLocations: []version2.JWTAuth{
+						{
+							Key:      "default/jwt-policy",
+							Verifier: &version2.JWTVerifier{Path: "/internal_location_jwtauth_default_jwt-policy"},
+						},
+					},
+				},
+			},
+			expected: true,
+			msg:      "jwt verifier",
+		},
+		{
+			vsCfg: &version2.VirtualServerConfig{
+				Server: version2.Server{
+					Locations: []version2.Location{
+						{Path: "/coffee"},
+						{Path: "/tea", APIKey: &version2.APIKey{Sources: []string{"$http_x_api_key"}}},
+					},
+				},
+			},
+			expected: true,
+			msg:      "api key in a location",
+		},
+		{
+			vsCfg: &version2.VirtualServerConfig{
+				Server: version2.Server{
+					Locations: []version2.Location{
+						{Path: "/coffee"},
+					},
+				},
+			},
+			expected: false,
+			msg:      "no jwt verifiers and api keys",
+		},
+	}
+
+	for _, test := range tests {
+		result := isNJSRequiredForVirtualServer(test.vsCfg)
+		if result != test.expected {
+			t.Errorf("isNJSRequiredForVirtualServer() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestUpdateNJSVirtualServers(t *testing.T) {
+	t.Parallel()
+	cnf := createTestConfigurator(t)
+
+	steps := []struct {
+		name     string
+		required bool
+		expected bool
+		msg      string
+	}{
+		{name: "vs_default_cafe", required: true, expected: true, msg: "first VirtualServer requires njs"},
+		{name: "vs_default_tea", required: true, expected: true, msg: "second VirtualServer requires njs"},
+		{name: "vs_default_cafe", required: false, expected: true, msg: "first VirtualServer no longer requires njs"},
+		{name: "vs_default_tea", required: false, expected: false, msg: "no VirtualServers require njs"},
+	}
+
+	for _, step := range steps {
+		if err := cnf.updateNJSVirtualServers(step.name, step.required); err != nil {
+			t.Fatalf("updateNJSVirtualServers() returned an unexpected error for the step %s: %v", step.msg, err)
+		}
+		mainCfg := cnf.generateMainConfig(cnf.cfgParams)
+		if mainCfg.NJSLoadModule != step.expected {
+			t.Errorf("generateMainConfig() returned NJSLoadModule %v but expected %v for the step %s", mainCfg.NJSLoadModule, step.expected, step.msg)
+		}
+	}
+}
+
 func TestAddInternalRouteConfig(t *testing.T) {
 	t.Parallel()
 	cnf := createTestConfigurator(t)
@@ -328,6 +670,180 @@ func TestAddInternalRouteConfig(t *testing.T) {
 	}
 }
 
+// reloadErrorsFakeManager is a FakeManager that returns the errors of reloadErrs from Reload one by one.
+type reloadErrorsFakeManager struct {
+	*nginx.FakeManager
+	reloadErrs []error
+	reloads    int
+}
+
+func (m *reloadErrorsFakeManager) Reload(_ bool) error {
+	m.reloads++
+	if len(m.reloadErrs) == 0 {
+		return nil
+	}
+	err := m.reloadErrs[0]
+	m.reloadErrs = m.reloadErrs[1:]
+	return err
+}
+
+func TestReloadRemovesInvalidResources(t *testing.T) {
+	t.Parallel()
+
+	reloadErr := errors.New("nginx reload failed")
+	nginxErr := errors.New("nginx: [emerg] unknown directive \"broken\"")
+
+	tests := []struct {
+		invalidConfigs          map[string]error
+		expectedResources       map[string]error
+		expectedVirtualServer   bool
+		expectedTransportServer bool
+		msg                     string
+	}{
+		{
+			invalidConfigs:          map[string]error{"vs_default_cafe": nginxErr},
+			expectedResources:       map[string]error{"VirtualServer/default/cafe": nginxErr},
+			expectedVirtualServer:   false,
+			expectedTransportServer: true,
+			msg:                     "invalid VirtualServer",
+		},
+		{
+			invalidConfigs:          map[string]error{"ts_default_secure-app": nginxErr},
+			expectedResources:       map[string]error{"TransportServer/default/secure-app": nginxErr},
+			expectedVirtualServer:   true,
+			expectedTransportServer: false,
+			msg:                     "invalid TransportServer",
+		},
+		{
+			invalidConfigs:          map[string]error{"vs_default_cafe": nginxErr, "/etc/nginx/nginx.conf": nginxErr},
+			expectedResources:       nil,
+			expectedVirtualServer:   true,
+			expectedTransportServer: true,
+			msg:                     "invalid main config",
+		},
+	}
+
+	for _, test := range tests {
+		cnf := createTestConfigurator(t)
+		manager := &reloadErrorsFakeManager{FakeManager: nginx.NewFakeManager("/etc/nginx")}
+		cnf.nginxManager = manager
+
+		vsEx := &VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe",
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host: "cafe.example.com",
+				},
+			},
+		}
+		if _, err := cnf.AddOrUpdateVirtualServer(vsEx); err != nil {
+			t.Fatalf("AddOrUpdateVirtualServer() returned an unexpected error for the case of %s: %v", test.msg, err)
+		}
+
+		manager.reloadErrs = []error{&nginx.ConfigRollbackError{InvalidConfigs: test.invalidConfigs, Err: reloadErr}}
+		tsEx := &TransportServerEx{
+			TransportServer: &conf_v1alpha1.TransportServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "secure-app",
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					Listener: conf_v1alpha1.TransportServerListener{
+						Name:     "tcp-listener",
+						Protocol: "TCP",
+					},
+					Upstreams: []conf_v1alpha1.Upstream{
+						{
+							Name:    "secure-app",
+							Service: "secure-app",
+							Port:    8443,
+						},
+					},
+					Action: &conf_v1alpha1.Action{
+						Pass: "secure-app",
+					},
+				},
+			},
+		}
+		_, err := cnf.AddOrUpdateTransportServer(tsEx)
+		if !errors.Is(err, reloadErr) {
+			t.Errorf("AddOrUpdateTransportServer() returned %v that doesn't wrap the error of the failed reload for the case of %s", err, test.msg)
+		}
+
+		var invalidErr *InvalidResourcesError
+		if test.expectedResources == nil {
+			if errors.As(err, &invalidErr) {
+				t.Errorf("AddOrUpdateTransportServer() returned an InvalidResourcesError %v for the case of %s", err, test.msg)
+			}
+			if manager.reloads != 2 {
+				t.Errorf("Configurator reloaded NGINX %d times, want 2 for the case of %s", manager.reloads, test.msg)
+			}
+		} else {
+			if !errors.As(err, &invalidErr) {
+				t.Fatalf("AddOrUpdateTransportServer() returned %v, want an InvalidResourcesError for the case of %s", err, test.msg)
+			}
+			if !cmp.Equal(test.expectedResources, invalidErr.Resources, cmpopts.EquateErrors()) {
+				t.Errorf("AddOrUpdateTransportServer() returned the invalid resources %v, want %v for the case of %s", invalidErr.Resources, test.expectedResources, test.msg)
+			}
+			if manager.reloads != 3 {
+				t.Errorf("Configurator reloaded NGINX %d times, want 3 for the case of %s", manager.reloads, test.msg)
+			}
+		}
+
+		if _, exists := cnf.virtualServers["vs_default_cafe"]; exists != test.expectedVirtualServer {
+			t.Errorf("Configurator has the VirtualServer %v, want %v for the case of %s", exists, test.expectedVirtualServer, test.msg)
+		}
+		if _, exists := cnf.transportServers["ts_default_secure-app"]; exists != test.expectedTransportServer {
+			t.Errorf("Configurator has the TransportServer %v, want %v for the case of %s", exists, test.expectedTransportServer, test.msg)
+		}
+	}
+}
+
+func TestReloadRemovesInvalidHTTPRoute(t *testing.T) {
+	t.Parallel()
+	cnf := createTestConfigurator(t)
+	manager := &reloadErrorsFakeManager{FakeManager: nginx.NewFakeManager("/etc/nginx")}
+	cnf.nginxManager = manager
+
+	nginxErr := errors.New("nginx: [emerg] unknown directive \"broken\"")
+	manager.reloadErrs = []error{&nginx.ConfigRollbackError{
+		InvalidConfigs: map[string]error{"vs_default_cafe-route-cafe.example.com": nginxErr},
+		Err:            errors.New("nginx reload failed"),
+	}}
+
+	var vsExes []*VirtualServerEx
+	for _, host := range []string{"cafe.example.com", "tea.example.com"} {
+		vsExes = append(vsExes, &VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe-route-" + host,
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host: host,
+				},
+			},
+		})
+	}
+
+	_, err := cnf.AddOrUpdateHTTPRoute("default/cafe-route", vsExes)
+
+	var invalidErr *InvalidResourcesError
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("AddOrUpdateHTTPRoute() returned %v, want an InvalidResourcesError", err)
+	}
+	expectedResources := map[string]error{"HTTPRoute/default/cafe-route": nginxErr}
+	if !cmp.Equal(expectedResources, invalidErr.Resources, cmpopts.EquateErrors()) {
+		t.Errorf("AddOrUpdateHTTPRoute() returned the invalid resources %v, want %v", invalidErr.Resources, expectedResources)
+	}
+	if len(cnf.virtualServers) != 0 || len(cnf.httpRoutes) != 0 {
+		t.Errorf("Configurator kept the VirtualServers %v and the HTTPRoutes %v of the invalid HTTPRoute", cnf.virtualServers, cnf.httpRoutes)
+	}
+}
+
 func TestFindRemovedKeys(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -136,6 +136,7 @@ func generateNginxCfg(ingEx *IngressEx, apResources *AppProtectResources, dosRes
 			Name:                  serverName,
 			ServerTokens:          cfgParams.ServerTokens,
 			HTTP2:                 cfgParams.HTTP2,
+			HTTP3:                 cfgParams.HTTP3,
 			RedirectToHTTPS:       cfgParams.RedirectToHTTPS,
 			SSLRedirect:           cfgParams.SSLRedirect,
 			ProxyProtocol:         cfgParams.ProxyProtocol,
@@ -0,0 +1,13 @@
+const c = require('crypto');
+
+// hash returns the SHA-256 hash of the API key supplied in the request, so that it can be
+// compared to the hashes of the keys of the clients in the config.
+function hash(r) {
+    const key = r.variables['apikey_auth_token'];
+    if (!key) {
+        return '';
+    }
+    return c.createHash('sha256').update(key).digest('hex');
+}
+
+export default { hash };
@@ -0,0 +1,195 @@
+const fs = require('fs');
+const qs = require('querystring');
+
+// algorithms maps the JWS algorithms to the parameters of the Web Crypto API.
+const algorithms = {
+    RS256: rsa('RSASSA-PKCS1-v1_5', 'SHA-256'),
+    RS384: rsa('RSASSA-PKCS1-v1_5', 'SHA-384'),
+    RS512: rsa('RSASSA-PKCS1-v1_5', 'SHA-512'),
+    PS256: rsa('RSA-PSS', 'SHA-256', 32),
+    PS384: rsa('RSA-PSS', 'SHA-384', 48),
+    PS512: rsa('RSA-PSS', 'SHA-512', 64),
+    ES256: ec('P-256', 'SHA-256'),
+    ES384: ec('P-384', 'SHA-384'),
+    ES512: ec('P-521', 'SHA-512'),
+    HS256: hmac('SHA-256'),
+    HS384: hmac('SHA-384'),
+    HS512: hmac('SHA-512'),
+};
+
+function rsa(name, hash, saltLength) {
+    return {
+        kty: 'RSA',
+        importParams: { name: name, hash: hash },
+        verifyParams: { name: name, saltLength: saltLength },
+    };
+}
+
+function ec(namedCurve, hash) {
+    return {
+        kty: 'EC',
+        importParams: { name: 'ECDSA', namedCurve: namedCurve },
+        verifyParams: { name: 'ECDSA', hash: hash },
+    };
+}
+
+function hmac(hash) {
+    return {
+        kty: 'oct',
+        importParams: { name: 'HMAC', hash: hash },
+        verifyParams: { name: 'HMAC' },
+    };
+}
+
+// verify is the handler of the internal location of a JWT policy in NGINX OSS, which is called by auth_request.
+// It verifies the signature, the expiration and the claims of the token of the request and returns
+// the claims as X-JWT-Claim-* headers. The status codes match the ones of auth_jwt of NGINX Plus.
+async function verify(r) {
+    const token = getToken(r);
+    if (!token) {
+        return unauthorized(r);
+    }
+
+    const parts = token.split('.');
+    if (parts.length != 3) {
+        r.warn('jwt_auth: the token is not a JWS in the compact serialization');
+        return unauthorized(r);
+    }
+
+    let header, payload;
+    try {
+        header = JSON.parse(Buffer.from(parts[0], 'base64url').toString());
+        payload = JSON.parse(Buffer.from(parts[1], 'base64url').toString());
+    } catch (e) {
+        r.warn(`jwt_auth: failed to decode the token: ${e.message}`);
+        return unauthorized(r);
+    }
+
+    const alg = algorithms[header.alg];
+    if (!alg) {
+        r.warn(`jwt_auth: unsupported algorithm ${header.alg}`);
+        return unauthorized(r);
+    }
+
+    let keys;
+    try {
+        keys = await getKeys(r);
+    } catch (e) {
+        r.error(`jwt_auth: failed to load the keys: ${e.message}`);
+        return r.return(500);
+    }
+
+    const data = Buffer.from(parts[0] + '.' + parts[1]);
+    const signature = Buffer.from(parts[2], 'base64url');
+    let verified = false;
+    for (let i = 0; i < keys.length && !verified; i++) {
+        const jwk = keys[i];
+        if (jwk.kty != alg.kty || (header.kid && jwk.kid && header.kid != jwk.kid)) {
+            continue;
+        }
+        try {
+            const key = await crypto.subtle.importKey('jwk', jwk, alg.importParams, false, ['verify']);
+            verified = await crypto.subtle.verify(alg.verifyParams, key, signature, data);
+        } catch (e) {
+            r.warn(`jwt_auth: failed to verify the token with the key ${jwk.kid}: ${e.message}`);
+        }
+    }
+    if (!verified) {
+        return unauthorized(r);
+    }
+
+    const now = Date.now() / 1000;
+    if ((payload.exp !== undefined && now >= payload.exp) || (payload.nbf !== undefined && now < payload.nbf)) {
+        r.warn('jwt_auth: the token is expired or not yet valid');
+        return unauthorized(r);
+    }
+
+    if (!checkClaims(r, payload)) {
+        return r.return(403);
+    }
+
+    Object.keys(payload).forEach(function (name) {
+        const value = claimValue(payload[name]);
+        if (/^[a-zA-Z0-9_-]+$/.test(name) && !/[\r\n]/.test(value)) {
+            r.headersOut['X-JWT-Claim-' + name] = value;
+        }
+    });
+    r.return(200);
+}
+
+// getToken returns the token from the variable of the policy, or the bearer token of the Authorization header.
+function getToken(r) {
+    const source = r.variables.jwt_auth_token_source;
+    if (!source) {
+        const m = (r.headersIn['Authorization'] || '').match(/^Bearer\s+(\S+)$/i);
+        return m ? m[1] : '';
+    }
+
+    // The arguments of the original request aren't passed to the auth subrequest.
+    if (source.startsWith('arg_')) {
+        const args = qs.parse(r.variables.request_uri.split('?')[1] || '');
+        const value = args[source.substring(4)];
+        return Array.isArray(value) ? value[0] : value || '';
+    }
+
+    return r.variables[source] || '';
+}
+
+// getKeys returns the JWKs of the policy from the file of the JWK secret or the JWKS URI.
+async function getKeys(r) {
+    let jwks;
+    if (r.variables.jwt_auth_key_file) {
+        jwks = JSON.parse(fs.readFileSync(r.variables.jwt_auth_key_file));
+    } else {
+        const reply = await r.subrequest(r.variables.jwt_auth_jwks_location);
+        if (reply.status != 200) {
+            throw new Error(`the JWKS URI returned the status ${reply.status}`);
+        }
+        jwks = JSON.parse(reply.responseText);
+    }
+    return jwks.keys || [jwks];
+}
+
+// checkClaims checks that every claim requirement of the policy is met, which is the case if the claim
+// matches any of the regular expressions of the requirement. A regular expression that can't be compiled
+// doesn't match, so that the request is rejected with 403 rather than failing with 500.
+function checkClaims(r, payload) {
+    if (!r.variables.jwt_auth_claims) {
+        return true;
+    }
+
+    const requirements = JSON.parse(Buffer.from(r.variables.jwt_auth_claims, 'base64').toString());
+    return requirements.every(function (requirement) {
+        const value = claimValue(payload[requirement.claim]);
+        return requirement.regexes.some(function (regex) {
+            try {
+                return new RegExp(regex).test(value);
+            } catch (e) {
+                r.error(`jwt_auth: invalid regex ${regex} for the claim ${requirement.claim}: ${e.message}`);
+                return false;
+            }
+        });
+    });
+}
+
+// claimValue returns the value of a claim like the $jwt_claim_ variables of NGINX Plus:
+// the elements of an array are joined with commas.
+function claimValue(claim) {
+    if (claim === undefined || claim === null) {
+        return '';
+    }
+    if (Array.isArray(claim)) {
+        return claim.join(',');
+    }
+    if (typeof claim == 'object') {
+        return JSON.stringify(claim);
+    }
+    return String(claim);
+}
+
+function unauthorized(r) {
+    r.headersOut['WWW-Authenticate'] = `Bearer realm="${r.variables.jwt_auth_realm}"`;
+    r.return(401);
+}
+
+export default { verify };
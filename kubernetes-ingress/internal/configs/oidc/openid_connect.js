@@ -265,10 +265,29 @@ function validateIdToken(r) {
 
 function logout(r) {
     r.log("OIDC logout for " + r.variables.cookie_auth_token);
+    var idToken = r.variables.session_jwt;
     r.variables.session_jwt   = "-";
     r.variables.access_token  = "-";
     r.variables.refresh_token = "-";
-    r.return(302, r.variables.oidc_logout_redirect);
+
+    // Without an end_session_endpoint the session is only terminated locally
+    if (!r.variables.oidc_end_session_endpoint) {
+        r.return(302, r.variables.oidc_logout_redirect);
+        return;
+    }
+
+    // RP-initiated logout: the IdP requires an absolute post_logout_redirect_uri
+    var logoutRedirect = r.variables.oidc_logout_redirect;
+    if (logoutRedirect.charAt(0) == "/") {
+        logoutRedirect = r.variables.redirect_base + logoutRedirect;
+    }
+    // The end_session_endpoint may already include query parameters
+    var logoutArgs = (r.variables.oidc_end_session_endpoint.indexOf("?") == -1) ? "?" : "&";
+    logoutArgs += "post_logout_redirect_uri=" + encodeURIComponent(logoutRedirect) + "&client_id=" + r.variables.oidc_client;
+    if (idToken && idToken != "-") {
+        logoutArgs += "&id_token_hint=" + idToken;
+    }
+    r.return(302, r.variables.oidc_end_session_endpoint + logoutArgs);
 }
 
 function getAuthZArgs(r) {
@@ -302,10 +321,10 @@ function getAuthZArgs(r) {
 }
 
 function idpClientAuth(r) {
-    // If PKCE is enabled we have to use the code_verifier
+    // If PKCE is enabled we have to send the code_verifier as well
     if ( r.variables.oidc_pkce_enable == 1 ) {
         r.variables.pkce_id = r.variables.arg_state;
-        return "code=" + r.variables.arg_code + "&code_verifier=" + r.variables.pkce_code_verifier;
+        return "code=" + r.variables.arg_code + "&code_verifier=" + r.variables.pkce_code_verifier + "&client_secret=" + r.variables.oidc_client_secret;
     } else {
         return "code=" + r.variables.arg_code + "&client_secret=" + r.variables.oidc_client_secret;
     }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 )
 
@@ -16,12 +17,15 @@ const nginxNonExistingUnixSocket = "unix:/var/lib/nginx/non-existing-unix-socket
 // TransportServerEx holds a TransportServer along with the resources referenced by it.
 type TransportServerEx struct {
 	ListenerPort     int
+	ListenerIPv4     string
+	ListenerIPv6     string
 	TransportServer  *conf_v1alpha1.TransportServer
 	Endpoints        map[string][]string
 	PodsByIP         map[string]string
 	ExternalNameSvcs map[string]bool
 	DisableIPV6      bool
 	SecretRefs       map[string]*secrets.SecretReference
+	Policies         map[string]*conf_v1.Policy
 }
 
 func (tsEx *TransportServerEx) String() string {
@@ -40,8 +44,16 @@ func newUpstreamNamerForTransportServer(transportServer *conf_v1alpha1.Transport
 	}
 }
 
+func newVariableNamerForTransportServer(transportServer *conf_v1alpha1.TransportServer) *variableNamer {
+	safeNsName := strings.ReplaceAll(fmt.Sprintf("%s_%s", transportServer.Namespace, transportServer.Name), "-", "_")
+	return &variableNamer{
+		prefix:     "ts",
+		safeNsName: safeNsName,
+	}
+}
+
 // generateTransportServerConfig generates a full configuration for a TransportServer.
-func generateTransportServerConfig(transportServerEx *TransportServerEx, listenerPort int, isPlus bool, isResolverConfigured bool) (*version2.TransportServerConfig, Warnings) {
+func generateTransportServerConfig(transportServerEx *TransportServerEx, listenerPort int, isPlus bool, isResolverConfigured bool, cfgParams *ConfigParams) (*version2.TransportServerConfig, Warnings) {
 	warnings := newWarnings()
 
 	upstreamNamer := newUpstreamNamerForTransportServer(transportServerEx.TransportServer)
@@ -49,13 +61,31 @@ func generateTransportServerConfig(transportServerEx *TransportServerEx, listene
 	upstreams, w := generateStreamUpstreams(transportServerEx, upstreamNamer, isPlus, isResolverConfigured)
 	warnings.Add(w)
 
-	healthCheck, match := generateTransportServerHealthCheck(transportServerEx.TransportServer.Spec.Action.Pass,
-		upstreamNamer.GetNameForUpstream(transportServerEx.TransportServer.Spec.Action.Pass),
-		transportServerEx.TransportServer.Spec.Upstreams)
+	routingCfg := generateStreamRoutingConfig(transportServerEx.TransportServer, upstreamNamer, newVariableNamerForTransportServer(transportServerEx.TransportServer))
+
+	var healthCheck *version2.StreamHealthCheck
+	var match *version2.Match
+	if routingCfg.Maps == nil && routingCfg.SplitClients == nil {
+		healthCheck, match = generateTransportServerHealthCheck(transportServerEx.TransportServer.Spec.Action.Pass,
+			upstreamNamer.GetNameForUpstream(transportServerEx.TransportServer.Spec.Action.Pass),
+			transportServerEx.TransportServer.Spec.Upstreams)
+	} else {
+		for _, u := range transportServerEx.TransportServer.Spec.Upstreams {
+			if u.HealthCheck != nil && u.HealthCheck.Enabled {
+				warnings.AddWarningf(transportServerEx.TransportServer, "Health checks are not supported with splits or matches, the health check of upstream %s is ignored", u.Name)
+			}
+		}
+	}
 
 	sslConfig, w := generateSSLConfig(transportServerEx.TransportServer, transportServerEx.TransportServer.Spec.TLS, transportServerEx.TransportServer.Namespace, transportServerEx.SecretRefs)
 	warnings.Add(w)
 
+	policiesCfg, w := generateTransportServerPolicies(transportServerEx)
+	warnings.Add(w)
+
+	accessLog, logFormat, w := generateStreamAccessLog(transportServerEx.TransportServer, cfgParams)
+	warnings.Add(w)
+
 	var proxyRequests, proxyResponses *int
 	var connectTimeout, nextUpstreamTimeout string
 	var nextUpstream bool
@@ -83,7 +113,7 @@ func generateTransportServerConfig(transportServerEx *TransportServerEx, listene
 	streamSnippets := generateSnippets(true, transportServerEx.TransportServer.Spec.StreamSnippets, []string{})
 
 	statusZone := transportServerEx.TransportServer.Spec.Listener.Name
-	if transportServerEx.TransportServer.Spec.Listener.Name == conf_v1alpha1.TLSPassthroughListenerName {
+	if transportServerEx.TransportServer.Spec.Host != "" {
 		statusZone = transportServerEx.TransportServer.Spec.Host
 	}
 
@@ -92,11 +122,15 @@ func generateTransportServerConfig(transportServerEx *TransportServerEx, listene
 			TLSPassthrough:           transportServerEx.TransportServer.Spec.Listener.Name == conf_v1alpha1.TLSPassthroughListenerName,
 			UnixSocket:               generateUnixSocket(transportServerEx),
 			Port:                     listenerPort,
+			IPv4:                     transportServerEx.ListenerIPv4,
+			IPv6:                     transportServerEx.ListenerIPv6,
 			UDP:                      transportServerEx.TransportServer.Spec.Listener.Protocol == "UDP",
 			StatusZone:               statusZone,
 			ProxyRequests:            proxyRequests,
 			ProxyResponses:           proxyResponses,
-			ProxyPass:                upstreamNamer.GetNameForUpstream(transportServerEx.TransportServer.Spec.Action.Pass),
+			ProxyPass:                routingCfg.ProxyPass,
+			ProxyProtocol:            generateStreamProxyProtocol(transportServerEx.TransportServer.Spec.Upstreams),
+			SSLPreread:               routingCfg.SSLPreread,
 			Name:                     transportServerEx.TransportServer.Name,
 			Namespace:                transportServerEx.TransportServer.Namespace,
 			ProxyConnectTimeout:      generateTimeWithDefault(connectTimeout, "60s"),
@@ -108,21 +142,324 @@ func generateTransportServerConfig(transportServerEx *TransportServerEx, listene
 			ServerSnippets:           serverSnippets,
 			DisableIPV6:              transportServerEx.DisableIPV6,
 			SSL:                      sslConfig,
+			Allow:                    policiesCfg.Allow,
+			Deny:                     policiesCfg.Deny,
+			LimitConnOptions:         policiesCfg.LimitConnOptions,
+			LimitConns:               policiesCfg.LimitConns,
+			IngressMTLS:              policiesCfg.IngressMTLS,
+			EgressMTLS:               policiesCfg.EgressMTLS,
+			PoliciesErrorDeny:        policiesCfg.ErrorDeny,
+			AccessLog:                accessLog,
 		},
 		Match:          match,
 		Upstreams:      upstreams,
 		StreamSnippets: streamSnippets,
+		LimitConnZones: policiesCfg.LimitConnZones,
+		SplitClients:   routingCfg.SplitClients,
+		Maps:           routingCfg.Maps,
+		LogFormat:      logFormat,
 	}
 	return tsConfig, warnings
 }
 
+// generateStreamAccessLog generates the access log of a TransportServer. A log format from the stream-log-formats
+// ConfigMap key is generated with a name unique to the TransportServer, so that TransportServers referencing
+// the same format don't define it twice.
+func generateStreamAccessLog(ts *conf_v1alpha1.TransportServer, cfgParams *ConfigParams) (*version2.StreamAccessLog, *version2.StreamLogFormat, Warnings) {
+	tsLog := ts.Spec.Log
+	if tsLog == nil {
+		return nil, nil, nil
+	}
+	if tsLog.Destination == "off" {
+		return &version2.StreamAccessLog{Off: true}, nil, nil
+	}
+
+	warnings := newWarnings()
+
+	accessLog := &version2.StreamAccessLog{
+		Destination: "/dev/stdout",
+		Format:      "stream-main",
+		Condition:   tsLog.Condition,
+	}
+	if tsLog.Destination != "" && tsLog.Destination != "stdout" {
+		accessLog.Destination = tsLog.Destination
+	}
+
+	if tsLog.Format == "" {
+		return accessLog, nil, warnings
+	}
+
+	format, exists := cfgParams.StreamLogFormats[tsLog.Format]
+	if !exists {
+		warnings.AddWarningf(ts, "Log format %s is not defined in the stream-log-formats ConfigMap key, the stream-main log format is used", tsLog.Format)
+		return accessLog, nil, warnings
+	}
+
+	logFormat := &version2.StreamLogFormat{
+		Name:     fmt.Sprintf("ts_%s_%s_%s", ts.Namespace, ts.Name, tsLog.Format),
+		Escaping: cfgParams.MainStreamLogFormatEscaping,
+		Format:   format,
+	}
+	accessLog.Format = logFormat.Name
+
+	return accessLog, logFormat, warnings
+}
+
+// streamRoutingCfg holds the configuration that passes the connections of a TransportServer to its upstreams.
+type streamRoutingCfg struct {
+	ProxyPass    string
+	SplitClients []version2.SplitClient
+	Maps         []version2.Map
+	SSLPreread   bool
+}
+
+// generateStreamRoutingConfig generates the configuration for the action, splits and matches of a TransportServer.
+// With splits or matches, the proxy_pass directive refers to a variable that a split_clients or a map block
+// resolves to the name of an upstream.
+func generateStreamRoutingConfig(ts *conf_v1alpha1.TransportServer, upstreamNamer *upstreamNamer, variableNamer *variableNamer) streamRoutingCfg {
+	var cfg streamRoutingCfg
+
+	defaultProxyPass := ""
+	if ts.Spec.Action != nil {
+		defaultProxyPass = upstreamNamer.GetNameForUpstream(ts.Spec.Action.Pass)
+	}
+
+	scIndex := 0
+	if len(ts.Spec.Splits) > 0 {
+		cfg.SplitClients = append(cfg.SplitClients, generateStreamSplitClient(ts.Spec.Splits, ts.Spec.SplitKey, upstreamNamer, variableNamer, scIndex))
+		defaultProxyPass = variableNamer.GetNameForSplitClientVariable(scIndex)
+		scIndex++
+	}
+
+	cfg.SSLPreread = strings.Contains(ts.Spec.SplitKey, "${ssl_preread_server_name}")
+
+	if len(ts.Spec.Matches) == 0 {
+		cfg.ProxyPass = defaultProxyPass
+		return cfg
+	}
+
+	var params []version2.Parameter
+	source := ""
+
+	for i, m := range ts.Spec.Matches {
+		for j, c := range m.Conditions {
+			successfulResult := "1"
+			if j < len(m.Conditions)-1 {
+				successfulResult = variableNamer.GetNameForVariableForMatchesRouteMap(0, i, j+1)
+			}
+
+			cfg.Maps = append(cfg.Maps, version2.Map{
+				Source:     c.Variable,
+				Variable:   variableNamer.GetNameForVariableForMatchesRouteMap(0, i, j),
+				Parameters: generateParametersForMatchesRouteMap(c.Value, successfulResult),
+			})
+
+			if c.Variable == "$ssl_preread_server_name" {
+				cfg.SSLPreread = true
+			}
+		}
+
+		source += variableNamer.GetNameForVariableForMatchesRouteMap(0, i, 0)
+
+		result := ""
+		if len(m.Splits) > 0 {
+			// the split key of the TransportServer is used if the match does not define its own
+			splitKey := m.SplitKey
+			if splitKey == "" {
+				splitKey = ts.Spec.SplitKey
+			}
+			if strings.Contains(splitKey, "${ssl_preread_server_name}") {
+				cfg.SSLPreread = true
+			}
+
+			cfg.SplitClients = append(cfg.SplitClients, generateStreamSplitClient(m.Splits, splitKey, upstreamNamer, variableNamer, scIndex))
+			result = variableNamer.GetNameForSplitClientVariable(scIndex)
+			scIndex++
+		} else if m.Action != nil {
+			result = upstreamNamer.GetNameForUpstream(m.Action.Pass)
+		}
+
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf("~^%s1", strings.Repeat("0", i)),
+			Result: result,
+		})
+	}
+
+	params = append(params, version2.Parameter{
+		Value:  "default",
+		Result: defaultProxyPass,
+	})
+
+	mainMapVariable := variableNamer.GetNameForVariableForMatchesRouteMainMap(0)
+	cfg.Maps = append(cfg.Maps, version2.Map{
+		Source:     source,
+		Variable:   mainMapVariable,
+		Parameters: params,
+	})
+	cfg.ProxyPass = mainMapVariable
+
+	return cfg
+}
+
+func generateStreamSplitClient(splits []conf_v1alpha1.Split, splitKey string, upstreamNamer *upstreamNamer, variableNamer *variableNamer, index int) version2.SplitClient {
+	var distributions []version2.Distribution
+	for _, s := range splits {
+		distributions = append(distributions, version2.Distribution{
+			Weight: fmt.Sprintf("%d%%", s.Weight),
+			Value:  upstreamNamer.GetNameForUpstream(s.Action.Pass),
+		})
+	}
+
+	source := "$remote_addr"
+	if splitKey != "" {
+		source = fmt.Sprintf(`"%s"`, splitKey)
+	}
+
+	return version2.SplitClient{
+		Source:        source,
+		Variable:      variableNamer.GetNameForSplitClientVariable(index),
+		Distributions: distributions,
+	}
+}
+
+// generateStreamProxyProtocol returns true if the PROXY protocol is enabled for the upstreams. The validation
+// ensures that all upstreams of a TransportServer use the same value.
+func generateStreamProxyProtocol(upstreams []conf_v1alpha1.Upstream) bool {
+	for _, u := range upstreams {
+		if u.ProxyProtocol {
+			return true
+		}
+	}
+	return false
+}
+
+// streamPoliciesCfg holds the configuration generated from the policies of a TransportServer.
+type streamPoliciesCfg struct {
+	Allow            []string
+	Deny             []string
+	LimitConnZones   []version2.LimitConnZone
+	LimitConns       []version2.LimitConn
+	LimitConnOptions version2.LimitConnOptions
+	IngressMTLS      *version2.IngressMTLS
+	EgressMTLS       *version2.EgressMTLS
+	ErrorDeny        bool
+}
+
+// generateTransportServerPolicies generates the configuration for the policies referenced by a TransportServer.
+// Only accessControl, connectionLimit, ingressMTLS and egressMTLS policies are supported in the stream context. If a referenced policy is
+// missing, invalid or not supported, the server denies all connections.
+func generateTransportServerPolicies(transportServerEx *TransportServerEx) (streamPoliciesCfg, Warnings) {
+	warnings := newWarnings()
+	ts := transportServerEx.TransportServer
+
+	var cfg streamPoliciesCfg
+
+	for _, p := range ts.Spec.Policies {
+		polNamespace := p.Namespace
+		if polNamespace == "" {
+			polNamespace = ts.Namespace
+		}
+
+		key := fmt.Sprintf("%s/%s", polNamespace, p.Name)
+
+		pol, exists := transportServerEx.Policies[key]
+		if !exists {
+			warnings.AddWarningf(ts, "Policy %s is missing or invalid", key)
+			return streamPoliciesCfg{ErrorDeny: true}, warnings
+		}
+
+		switch {
+		case pol.Spec.AccessControl != nil:
+			cfg.Allow = append(cfg.Allow, pol.Spec.AccessControl.Allow...)
+			cfg.Deny = append(cfg.Deny, pol.Spec.AccessControl.Deny...)
+			if len(cfg.Allow) > 0 && len(cfg.Deny) > 0 {
+				warnings.AddWarning(ts, "AccessControl policy (or policies) with deny rules is overridden by policy (or policies) with allow rules")
+			}
+		case pol.Spec.ConnectionLimit != nil:
+			zoneName := fmt.Sprintf("pol_cl_%v_%v_%v_%v", polNamespace, p.Name, ts.Namespace, ts.Name)
+			cfg.LimitConnZones = append(cfg.LimitConnZones, version2.LimitConnZone{
+				Key:      pol.Spec.ConnectionLimit.Key,
+				ZoneName: zoneName,
+				ZoneSize: pol.Spec.ConnectionLimit.ZoneSize,
+			})
+			cfg.LimitConns = append(cfg.LimitConns, version2.LimitConn{
+				ZoneName:    zoneName,
+				Connections: pol.Spec.ConnectionLimit.Connections,
+			})
+
+			options := generateLimitConnOptions(pol.Spec.ConnectionLimit)
+			if len(cfg.LimitConns) == 1 {
+				cfg.LimitConnOptions = options
+				continue
+			}
+			if options.DryRun != cfg.LimitConnOptions.DryRun {
+				warnings.AddWarningf(ts, "ConnectionLimit policy %s with limit connection option dryRun='%v' is overridden to dryRun='%v' by the first policy reference in this context", key, options.DryRun, cfg.LimitConnOptions.DryRun)
+			}
+			if options.LogLevel != cfg.LimitConnOptions.LogLevel {
+				warnings.AddWarningf(ts, "ConnectionLimit policy %s with limit connection option logLevel='%v' is overridden to logLevel='%v' by the first policy reference in this context", key, options.LogLevel, cfg.LimitConnOptions.LogLevel)
+			}
+		case pol.Spec.IngressMTLS != nil:
+			if ts.Spec.TLS == nil || isTLSTerminationHost(ts) {
+				warnings.AddWarningf(ts, "TLS termination on a listener not shared by hosts must be configured in TransportServer for IngressMTLS policy %s", key)
+				return streamPoliciesCfg{ErrorDeny: true}, warnings
+			}
+
+			mtlsCfg := policiesCfg{IngressMTLS: cfg.IngressMTLS}
+			res := mtlsCfg.addIngressMTLSConfig(pol.Spec.IngressMTLS, key, polNamespace, specContext, true, transportServerEx.SecretRefs)
+			for _, msg := range res.warnings {
+				warnings.AddWarning(ts, msg)
+			}
+			if res.isError {
+				return streamPoliciesCfg{ErrorDeny: true}, warnings
+			}
+			cfg.IngressMTLS = mtlsCfg.IngressMTLS
+		case pol.Spec.EgressMTLS != nil:
+			mtlsCfg := policiesCfg{EgressMTLS: cfg.EgressMTLS}
+			res := mtlsCfg.addEgressMTLSConfig(pol.Spec.EgressMTLS, key, polNamespace, transportServerEx.SecretRefs)
+			for _, msg := range res.warnings {
+				warnings.AddWarning(ts, msg)
+			}
+			if res.isError {
+				return streamPoliciesCfg{ErrorDeny: true}, warnings
+			}
+			if cfg.EgressMTLS == nil && pol.Spec.EgressMTLS.SSLName == "" {
+				// the $proxy_host variable doesn't exist in the stream module,
+				// NGINX uses the address from the proxy_pass directive by default.
+				mtlsCfg.EgressMTLS.SSLName = ""
+			}
+			cfg.EgressMTLS = mtlsCfg.EgressMTLS
+		default:
+			warnings.AddWarningf(ts, "Policy %s is not supported in TransportServer. Only accessControl, connectionLimit, ingressMTLS and egressMTLS policies are supported", key)
+			return streamPoliciesCfg{ErrorDeny: true}, warnings
+		}
+	}
+
+	return cfg, warnings
+}
+
+func generateLimitConnOptions(connectionLimitPol *conf_v1.ConnectionLimit) version2.LimitConnOptions {
+	return version2.LimitConnOptions{
+		DryRun:   generateBool(connectionLimitPol.DryRun, false),
+		LogLevel: generateString(connectionLimitPol.LogLevel, "error"),
+	}
+}
+
 func generateUnixSocket(transportServerEx *TransportServerEx) string {
 	if transportServerEx.TransportServer.Spec.Listener.Name == conf_v1alpha1.TLSPassthroughListenerName {
 		return fmt.Sprintf("unix:/var/lib/nginx/passthrough-%s_%s.sock", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name)
 	}
+	if isTLSTerminationHost(transportServerEx.TransportServer) {
+		return fmt.Sprintf("unix:/var/lib/nginx/tls-termination-%s_%s.sock", transportServerEx.TransportServer.Namespace, transportServerEx.TransportServer.Name)
+	}
 	return ""
 }
 
+// isTLSTerminationHost returns true if the TransportServer terminates TLS for its host on a listener
+// that can be shared with other TransportServers.
+func isTLSTerminationHost(ts *conf_v1alpha1.TransportServer) bool {
+	return ts.Spec.Host != "" && ts.Spec.Listener.Name != conf_v1alpha1.TLSPassthroughListenerName
+}
+
 func generateSSLConfig(ts *conf_v1alpha1.TransportServer, tls *conf_v1alpha1.TLS, namespace string, secretRefs map[string]*secrets.SecretReference) (*version2.StreamSSL, Warnings) {
 	if tls == nil {
 		return &version2.StreamSSL{Enabled: false}, nil
@@ -8,6 +8,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -144,7 +145,7 @@ func TestGenerateTransportServerConfigForTCPSnippets(t *testing.T) {
 		StreamSnippets: []string{"limit_conn_zone $binary_remote_addr zone=addr:10m;"},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -228,7 +229,7 @@ func TestGenerateTransportServerConfigForIPV6Disabled(t *testing.T) {
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -320,7 +321,7 @@ func TestGenerateTransportServerConfigForTCP(t *testing.T) {
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -415,7 +416,7 @@ func TestGenerateTransportServerConfigForTCPMaxConnections(t *testing.T) {
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -508,7 +509,7 @@ func TestGenerateTransportServerConfigForTLSPassthrough(t *testing.T) {
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -606,7 +607,7 @@ func TestGenerateTransportServerConfigForUDP(t *testing.T) {
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -696,7 +697,7 @@ func TestGenerateTransportServerConfig_ProducesValidConfigOnValidInputForExterna
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, 2020, true, true)
+	result, warnings := generateTransportServerConfig(&transportServerEx, 2020, true, true, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -780,7 +781,7 @@ func TestGenerateTransportServerConfig_GeneratesWarningOnNotConfiguredResolver(t
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, 2020, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, 2020, true, false, &ConfigParams{})
 	if len(warnings) == 0 {
 		t.Errorf("want warnings, got %v", warnings)
 	}
@@ -866,7 +867,7 @@ func TestGenerateTransportServerConfig_UsesNotExistignSocketOnNotPlusAndNoEndpoi
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, 2020, false, true)
+	result, warnings := generateTransportServerConfig(&transportServerEx, 2020, false, true, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -973,7 +974,7 @@ func TestGenerateTransportServerConfigForTCPWithTLS(t *testing.T) {
 		StreamSnippets: []string{},
 	}
 
-	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false)
+	result, warnings := generateTransportServerConfig(&transportServerEx, listenerPort, true, false, &ConfigParams{})
 	if len(warnings) != 0 {
 		t.Errorf("want no warnings, got %v", warnings)
 	}
@@ -1012,6 +1013,14 @@ func TestGenerateUnixSocket(t *testing.T) {
 	if result != expected {
 		t.Errorf("generateUnixSocket() returned %q but expected %q", result, expected)
 	}
+
+	transportServerEx.TransportServer.Spec.Host = "example.com"
+	expected = "unix:/var/lib/nginx/tls-termination-default_tcp-server.sock"
+
+	result = generateUnixSocket(transportServerEx)
+	if result != expected {
+		t.Errorf("generateUnixSocket() returned %q but expected %q", result, expected)
+	}
 }
 
 func TestGenerateTransportServerHealthChecks(t *testing.T) {
@@ -1361,3 +1370,754 @@ func TestGenerateTsSSLConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateTransportServerPolicies(t *testing.T) {
+	t.Parallel()
+	dryRun := true
+	policies := map[string]*conf_v1.Policy{
+		"default/allow-policy": {
+			Spec: conf_v1.PolicySpec{
+				AccessControl: &conf_v1.AccessControl{
+					Allow: []string{"10.0.0.0/8"},
+				},
+			},
+		},
+		"default/deny-policy": {
+			Spec: conf_v1.PolicySpec{
+				AccessControl: &conf_v1.AccessControl{
+					Deny: []string{"127.0.0.1"},
+				},
+			},
+		},
+		"nginx-ingress/conn-limit": {
+			Spec: conf_v1.PolicySpec{
+				ConnectionLimit: &conf_v1.ConnectionLimit{
+					Key:         "${binary_remote_addr}",
+					Connections: 10,
+					ZoneSize:    "10m",
+					DryRun:      &dryRun,
+				},
+			},
+		},
+		"default/conn-limit": {
+			Spec: conf_v1.PolicySpec{
+				ConnectionLimit: &conf_v1.ConnectionLimit{
+					Key:         "${server_port}",
+					Connections: 100,
+					ZoneSize:    "1m",
+					LogLevel:    "warn",
+				},
+			},
+		},
+		"default/rate-limit": {
+			Spec: conf_v1.PolicySpec{
+				RateLimit: &conf_v1.RateLimit{
+					Key:      "${binary_remote_addr}",
+					Rate:     "10r/s",
+					ZoneSize: "10m",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		policyRefs       []conf_v1alpha1.PolicyReference
+		expected         streamPoliciesCfg
+		expectedWarnings int
+		msg              string
+	}{
+		{
+			policyRefs: nil,
+			expected:   streamPoliciesCfg{},
+			msg:        "no policies",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+			},
+			expected: streamPoliciesCfg{
+				Allow: []string{"10.0.0.0/8"},
+			},
+			msg: "access control policy",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+				{
+					Name: "deny-policy",
+				},
+			},
+			expected: streamPoliciesCfg{
+				Allow: []string{"10.0.0.0/8"},
+				Deny:  []string{"127.0.0.1"},
+			},
+			expectedWarnings: 1,
+			msg:              "access control policies with allow and deny rules",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name:      "conn-limit",
+					Namespace: "nginx-ingress",
+				},
+				{
+					Name: "conn-limit",
+				},
+			},
+			expected: streamPoliciesCfg{
+				LimitConnZones: []version2.LimitConnZone{
+					{
+						Key:      "${binary_remote_addr}",
+						ZoneName: "pol_cl_nginx-ingress_conn-limit_default_tcp-server",
+						ZoneSize: "10m",
+					},
+					{
+						Key:      "${server_port}",
+						ZoneName: "pol_cl_default_conn-limit_default_tcp-server",
+						ZoneSize: "1m",
+					},
+				},
+				LimitConns: []version2.LimitConn{
+					{
+						ZoneName:    "pol_cl_nginx-ingress_conn-limit_default_tcp-server",
+						Connections: 10,
+					},
+					{
+						ZoneName:    "pol_cl_default_conn-limit_default_tcp-server",
+						Connections: 100,
+					},
+				},
+				LimitConnOptions: version2.LimitConnOptions{
+					DryRun:   true,
+					LogLevel: "error",
+				},
+			},
+			expectedWarnings: 2,
+			msg:              "connection limit policies with overridden options",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+				{
+					Name: "missing-policy",
+				},
+			},
+			expected: streamPoliciesCfg{
+				ErrorDeny: true,
+			},
+			expectedWarnings: 1,
+			msg:              "missing policy",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "rate-limit",
+				},
+			},
+			expected: streamPoliciesCfg{
+				ErrorDeny: true,
+			},
+			expectedWarnings: 1,
+			msg:              "unsupported policy",
+		},
+	}
+
+	for _, test := range tests {
+		transportServerEx := &TransportServerEx{
+			TransportServer: &conf_v1alpha1.TransportServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "tcp-server",
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					Policies: test.policyRefs,
+				},
+			},
+			Policies: policies,
+		}
+
+		result, warnings := generateTransportServerPolicies(transportServerEx)
+		if diff := cmp.Diff(test.expected, result, cmp.AllowUnexported(streamPoliciesCfg{})); diff != "" {
+			t.Errorf("generateTransportServerPolicies() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(warnings[transportServerEx.TransportServer]) != test.expectedWarnings {
+			t.Errorf("generateTransportServerPolicies() returned warnings %v but expected %d for the case of %s", warnings, test.expectedWarnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateTransportServerPoliciesWithMTLS(t *testing.T) {
+	t.Parallel()
+	policies := map[string]*conf_v1.Policy{
+		"default/ingress-mtls": {
+			Spec: conf_v1.PolicySpec{
+				IngressMTLS: &conf_v1.IngressMTLS{
+					ClientCertSecret: "ingress-mtls-secret",
+					VerifyClient:     "optional",
+				},
+			},
+		},
+		"default/ingress-mtls-2": {
+			Spec: conf_v1.PolicySpec{
+				IngressMTLS: &conf_v1.IngressMTLS{
+					ClientCertSecret: "ingress-mtls-secret",
+				},
+			},
+		},
+		"default/ingress-mtls-invalid": {
+			Spec: conf_v1.PolicySpec{
+				IngressMTLS: &conf_v1.IngressMTLS{
+					ClientCertSecret: "tls-secret",
+				},
+			},
+		},
+		"default/egress-mtls": {
+			Spec: conf_v1.PolicySpec{
+				EgressMTLS: &conf_v1.EgressMTLS{
+					TLSSecret:         "tls-secret",
+					TrustedCertSecret: "ingress-mtls-secret",
+					VerifyServer:      true,
+				},
+			},
+		},
+		"default/egress-mtls-ssl-name": {
+			Spec: conf_v1.PolicySpec{
+				EgressMTLS: &conf_v1.EgressMTLS{
+					ServerName: true,
+					SSLName:    "backend.example.com",
+				},
+			},
+		},
+	}
+
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/ingress-mtls-secret": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeCA,
+			},
+			Path: "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+		},
+		"default/tls-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+			Path: "/etc/nginx/secrets/default-tls-secret",
+		},
+	}
+
+	tests := []struct {
+		policyRefs       []conf_v1alpha1.PolicyReference
+		tls              *conf_v1alpha1.TLS
+		host             string
+		expected         streamPoliciesCfg
+		expectedWarnings int
+		msg              string
+	}{
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "ingress-mtls",
+				},
+			},
+			tls: &conf_v1alpha1.TLS{
+				Secret: "tls-secret",
+			},
+			expected: streamPoliciesCfg{
+				IngressMTLS: &version2.IngressMTLS{
+					ClientCert:   "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+					VerifyClient: "optional",
+					VerifyDepth:  1,
+				},
+			},
+			msg: "ingressMTLS policy",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "ingress-mtls",
+				},
+				{
+					Name: "ingress-mtls-2",
+				},
+			},
+			tls: &conf_v1alpha1.TLS{
+				Secret: "tls-secret",
+			},
+			expected: streamPoliciesCfg{
+				IngressMTLS: &version2.IngressMTLS{
+					ClientCert:   "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+					VerifyClient: "optional",
+					VerifyDepth:  1,
+				},
+			},
+			expectedWarnings: 1,
+			msg:              "multiple ingressMTLS policies",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "ingress-mtls",
+				},
+			},
+			expected: streamPoliciesCfg{
+				ErrorDeny: true,
+			},
+			expectedWarnings: 1,
+			msg:              "ingressMTLS policy without tls",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "ingress-mtls",
+				},
+			},
+			tls: &conf_v1alpha1.TLS{
+				Secret: "tls-secret",
+			},
+			host: "example.com",
+			expected: streamPoliciesCfg{
+				ErrorDeny: true,
+			},
+			expectedWarnings: 1,
+			msg:              "ingressMTLS policy with tls termination on a shared listener",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "ingress-mtls-invalid",
+				},
+			},
+			tls: &conf_v1alpha1.TLS{
+				Secret: "tls-secret",
+			},
+			expected: streamPoliciesCfg{
+				ErrorDeny: true,
+			},
+			expectedWarnings: 1,
+			msg:              "ingressMTLS policy with a secret of a wrong type",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "egress-mtls",
+				},
+			},
+			expected: streamPoliciesCfg{
+				EgressMTLS: &version2.EgressMTLS{
+					Certificate:    "/etc/nginx/secrets/default-tls-secret",
+					CertificateKey: "/etc/nginx/secrets/default-tls-secret",
+					Ciphers:        "DEFAULT",
+					Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
+					VerifyServer:   true,
+					VerifyDepth:    1,
+					SessionReuse:   true,
+					TrustedCert:    "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+				},
+			},
+			msg: "egressMTLS policy",
+		},
+		{
+			policyRefs: []conf_v1alpha1.PolicyReference{
+				{
+					Name: "egress-mtls-ssl-name",
+				},
+				{
+					Name: "egress-mtls",
+				},
+			},
+			expected: streamPoliciesCfg{
+				EgressMTLS: &version2.EgressMTLS{
+					Ciphers:      "DEFAULT",
+					Protocols:    "TLSv1 TLSv1.1 TLSv1.2",
+					VerifyDepth:  1,
+					SessionReuse: true,
+					ServerName:   true,
+					SSLName:      "backend.example.com",
+				},
+			},
+			expectedWarnings: 1,
+			msg:              "multiple egressMTLS policies",
+		},
+	}
+
+	for _, test := range tests {
+		transportServerEx := &TransportServerEx{
+			TransportServer: &conf_v1alpha1.TransportServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "tcp-server",
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					Listener: conf_v1alpha1.TransportServerListener{
+						Name:     "tcp-listener",
+						Protocol: "TCP",
+					},
+					Host:     test.host,
+					TLS:      test.tls,
+					Policies: test.policyRefs,
+				},
+			},
+			Policies:   policies,
+			SecretRefs: secretRefs,
+		}
+
+		result, warnings := generateTransportServerPolicies(transportServerEx)
+		if diff := cmp.Diff(test.expected, result, cmp.AllowUnexported(streamPoliciesCfg{})); diff != "" {
+			t.Errorf("generateTransportServerPolicies() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(warnings[transportServerEx.TransportServer]) != test.expectedWarnings {
+			t.Errorf("generateTransportServerPolicies() returned warnings %v but expected %d for the case of %s", warnings, test.expectedWarnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateStreamAccessLog(t *testing.T) {
+	t.Parallel()
+	cfgParams := &ConfigParams{
+		MainStreamLogFormatEscaping: "json",
+		StreamLogFormats: map[string]string{
+			"tcp-audit": "$remote_addr [$time_local] $status",
+		},
+	}
+
+	tests := []struct {
+		log               *conf_v1alpha1.TransportServerLog
+		expectedAccessLog *version2.StreamAccessLog
+		expectedLogFormat *version2.StreamLogFormat
+		expectedWarnings  int
+		msg               string
+	}{
+		{
+			log: nil,
+			msg: "no log",
+		},
+		{
+			log: &conf_v1alpha1.TransportServerLog{
+				Destination: "off",
+			},
+			expectedAccessLog: &version2.StreamAccessLog{
+				Off: true,
+			},
+			msg: "log off",
+		},
+		{
+			log: &conf_v1alpha1.TransportServerLog{},
+			expectedAccessLog: &version2.StreamAccessLog{
+				Destination: "/dev/stdout",
+				Format:      "stream-main",
+			},
+			msg: "default log",
+		},
+		{
+			log: &conf_v1alpha1.TransportServerLog{
+				Format:      "tcp-audit",
+				Destination: "syslog:server=10.0.0.1:514",
+				Condition:   "$loggable",
+			},
+			expectedAccessLog: &version2.StreamAccessLog{
+				Destination: "syslog:server=10.0.0.1:514",
+				Format:      "ts_default_tcp-server_tcp-audit",
+				Condition:   "$loggable",
+			},
+			expectedLogFormat: &version2.StreamLogFormat{
+				Name:     "ts_default_tcp-server_tcp-audit",
+				Escaping: "json",
+				Format:   "$remote_addr [$time_local] $status",
+			},
+			msg: "log with format, syslog destination and condition",
+		},
+		{
+			log: &conf_v1alpha1.TransportServerLog{
+				Format:      "missing",
+				Destination: "stdout",
+			},
+			expectedAccessLog: &version2.StreamAccessLog{
+				Destination: "/dev/stdout",
+				Format:      "stream-main",
+			},
+			expectedWarnings: 1,
+			msg:              "log with a missing format",
+		},
+	}
+
+	for _, test := range tests {
+		ts := &conf_v1alpha1.TransportServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tcp-server",
+				Namespace: "default",
+			},
+			Spec: conf_v1alpha1.TransportServerSpec{
+				Log: test.log,
+			},
+		}
+
+		accessLog, logFormat, warnings := generateStreamAccessLog(ts, cfgParams)
+		if diff := cmp.Diff(test.expectedAccessLog, accessLog); diff != "" {
+			t.Errorf("generateStreamAccessLog() access log mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff(test.expectedLogFormat, logFormat); diff != "" {
+			t.Errorf("generateStreamAccessLog() log format mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(warnings[ts]) != test.expectedWarnings {
+			t.Errorf("generateStreamAccessLog() returned warnings %v but expected %d for the case of %s", warnings, test.expectedWarnings, test.msg)
+		}
+	}
+}
+
+func TestVariableNamerForTransportServer(t *testing.T) {
+	t.Parallel()
+	transportServer := conf_v1alpha1.TransportServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tcp-app",
+			Namespace: "default",
+		},
+	}
+	variableNamer := newVariableNamerForTransportServer(&transportServer)
+
+	expected := "$ts_default_tcp_app_splits_1"
+
+	result := variableNamer.GetNameForSplitClientVariable(1)
+	if result != expected {
+		t.Errorf("GetNameForSplitClientVariable() returned %s but expected %v", result, expected)
+	}
+}
+
+func TestGenerateStreamRoutingConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		spec     conf_v1alpha1.TransportServerSpec
+		expected streamRoutingCfg
+		msg      string
+	}{
+		{
+			spec: conf_v1alpha1.TransportServerSpec{
+				Action: &conf_v1alpha1.Action{Pass: "app"},
+			},
+			expected: streamRoutingCfg{
+				ProxyPass: "ts_default_tcp-server_app",
+			},
+			msg: "action",
+		},
+		{
+			spec: conf_v1alpha1.TransportServerSpec{
+				Splits: []conf_v1alpha1.Split{
+					{Weight: 90, Action: &conf_v1alpha1.Action{Pass: "app"}},
+					{Weight: 10, Action: &conf_v1alpha1.Action{Pass: "app-v2"}},
+				},
+			},
+			expected: streamRoutingCfg{
+				ProxyPass: "$ts_default_tcp_server_splits_0",
+				SplitClients: []version2.SplitClient{
+					{
+						Source:   "$remote_addr",
+						Variable: "$ts_default_tcp_server_splits_0",
+						Distributions: []version2.Distribution{
+							{Weight: "90%", Value: "ts_default_tcp-server_app"},
+							{Weight: "10%", Value: "ts_default_tcp-server_app-v2"},
+						},
+					},
+				},
+			},
+			msg: "splits",
+		},
+		{
+			spec: conf_v1alpha1.TransportServerSpec{
+				Splits: []conf_v1alpha1.Split{
+					{Weight: 50, Action: &conf_v1alpha1.Action{Pass: "app"}},
+					{Weight: 50, Action: &conf_v1alpha1.Action{Pass: "app-v2"}},
+				},
+				SplitKey: "${ssl_preread_server_name}",
+			},
+			expected: streamRoutingCfg{
+				ProxyPass: "$ts_default_tcp_server_splits_0",
+				SplitClients: []version2.SplitClient{
+					{
+						Source:   `"${ssl_preread_server_name}"`,
+						Variable: "$ts_default_tcp_server_splits_0",
+						Distributions: []version2.Distribution{
+							{Weight: "50%", Value: "ts_default_tcp-server_app"},
+							{Weight: "50%", Value: "ts_default_tcp-server_app-v2"},
+						},
+					},
+				},
+				SSLPreread: true,
+			},
+			msg: "splits with a split key",
+		},
+		{
+			spec: conf_v1alpha1.TransportServerSpec{
+				Action: &conf_v1alpha1.Action{Pass: "app"},
+				Matches: []conf_v1alpha1.TransportServerMatch{
+					{
+						Conditions: []conf_v1alpha1.Condition{
+							{Variable: "$ssl_preread_server_name", Value: "v2.example.com"},
+							{Variable: "$remote_addr", Value: "!10.0.0.1"},
+						},
+						Action: &conf_v1alpha1.Action{Pass: "app-v2"},
+					},
+					{
+						Conditions: []conf_v1alpha1.Condition{
+							{Variable: "$remote_addr", Value: "10.0.0.2"},
+						},
+						Splits: []conf_v1alpha1.Split{
+							{Weight: 90, Action: &conf_v1alpha1.Action{Pass: "app"}},
+							{Weight: 10, Action: &conf_v1alpha1.Action{Pass: "app-v2"}},
+						},
+					},
+				},
+			},
+			expected: streamRoutingCfg{
+				ProxyPass: "$ts_default_tcp_server_matches_0",
+				SplitClients: []version2.SplitClient{
+					{
+						Source:   "$remote_addr",
+						Variable: "$ts_default_tcp_server_splits_0",
+						Distributions: []version2.Distribution{
+							{Weight: "90%", Value: "ts_default_tcp-server_app"},
+							{Weight: "10%", Value: "ts_default_tcp-server_app-v2"},
+						},
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$ssl_preread_server_name",
+						Variable: "$ts_default_tcp_server_matches_0_match_0_cond_0",
+						Parameters: []version2.Parameter{
+							{Value: `"v2.example.com"`, Result: "$ts_default_tcp_server_matches_0_match_0_cond_1"},
+							{Value: "default", Result: "0"},
+						},
+					},
+					{
+						Source:   "$remote_addr",
+						Variable: "$ts_default_tcp_server_matches_0_match_0_cond_1",
+						Parameters: []version2.Parameter{
+							{Value: `"10.0.0.1"`, Result: "0"},
+							{Value: "default", Result: "1"},
+						},
+					},
+					{
+						Source:   "$remote_addr",
+						Variable: "$ts_default_tcp_server_matches_0_match_1_cond_0",
+						Parameters: []version2.Parameter{
+							{Value: `"10.0.0.2"`, Result: "1"},
+							{Value: "default", Result: "0"},
+						},
+					},
+					{
+						Source:   "$ts_default_tcp_server_matches_0_match_0_cond_0$ts_default_tcp_server_matches_0_match_1_cond_0",
+						Variable: "$ts_default_tcp_server_matches_0",
+						Parameters: []version2.Parameter{
+							{Value: "~^1", Result: "ts_default_tcp-server_app-v2"},
+							{Value: "~^01", Result: "$ts_default_tcp_server_splits_0"},
+							{Value: "default", Result: "ts_default_tcp-server_app"},
+						},
+					},
+				},
+				SSLPreread: true,
+			},
+			msg: "matches with an action and splits",
+		},
+		{
+			spec: conf_v1alpha1.TransportServerSpec{
+				Action: &conf_v1alpha1.Action{Pass: "app"},
+				Matches: []conf_v1alpha1.TransportServerMatch{
+					{
+						Conditions: []conf_v1alpha1.Condition{
+							{Variable: "$remote_addr", Value: "10.0.0.2"},
+						},
+						Splits: []conf_v1alpha1.Split{
+							{Weight: 90, Action: &conf_v1alpha1.Action{Pass: "app"}},
+							{Weight: 10, Action: &conf_v1alpha1.Action{Pass: "app-v2"}},
+						},
+					},
+				},
+				SplitKey: "${remote_addr}${remote_port}",
+			},
+			expected: streamRoutingCfg{
+				ProxyPass: "$ts_default_tcp_server_matches_0",
+				SplitClients: []version2.SplitClient{
+					{
+						Source:   `"${remote_addr}${remote_port}"`,
+						Variable: "$ts_default_tcp_server_splits_0",
+						Distributions: []version2.Distribution{
+							{Weight: "90%", Value: "ts_default_tcp-server_app"},
+							{Weight: "10%", Value: "ts_default_tcp-server_app-v2"},
+						},
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$remote_addr",
+						Variable: "$ts_default_tcp_server_matches_0_match_0_cond_0",
+						Parameters: []version2.Parameter{
+							{Value: `"10.0.0.2"`, Result: "1"},
+							{Value: "default", Result: "0"},
+						},
+					},
+					{
+						Source:   "$ts_default_tcp_server_matches_0_match_0_cond_0",
+						Variable: "$ts_default_tcp_server_matches_0",
+						Parameters: []version2.Parameter{
+							{Value: "~^1", Result: "$ts_default_tcp_server_splits_0"},
+							{Value: "default", Result: "ts_default_tcp-server_app"},
+						},
+					},
+				},
+			},
+			msg: "matches with splits that use the split key of the TransportServer",
+		},
+	}
+
+	for _, test := range tests {
+		ts := &conf_v1alpha1.TransportServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tcp-server",
+				Namespace: "default",
+			},
+			Spec: test.spec,
+		}
+
+		result := generateStreamRoutingConfig(ts, newUpstreamNamerForTransportServer(ts), newVariableNamerForTransportServer(ts))
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateStreamRoutingConfig() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateStreamProxyProtocol(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		upstreams []conf_v1alpha1.Upstream
+		expected  bool
+		msg       string
+	}{
+		{
+			upstreams: nil,
+			expected:  false,
+			msg:       "no upstreams",
+		},
+		{
+			upstreams: []conf_v1alpha1.Upstream{{Name: "app"}},
+			expected:  false,
+			msg:       "proxy protocol disabled",
+		},
+		{
+			upstreams: []conf_v1alpha1.Upstream{{Name: "app", ProxyProtocol: true}, {Name: "app-v2", ProxyProtocol: true}},
+			expected:  true,
+			msg:       "proxy protocol enabled",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateStreamProxyProtocol(test.upstreams)
+		if result != test.expected {
+			t.Errorf("generateStreamProxyProtocol() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
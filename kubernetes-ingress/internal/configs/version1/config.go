@@ -73,6 +73,7 @@ type Server struct {
 	GRPCOnly              bool
 	StatusZone            string
 	HTTP2                 bool
+	HTTP3                 bool
 	RedirectToHTTPS       bool
 	SSLRedirect           bool
 	ProxyProtocol         bool
@@ -169,6 +170,7 @@ type MainConfig struct {
 	HealthStatus                       bool
 	HealthStatusURI                    string
 	HTTP2                              bool
+	HTTP3                              bool
 	HTTPSnippets                       []string
 	KeepaliveRequests                  int64
 	KeepaliveTimeout                   string
@@ -180,6 +182,8 @@ type MainConfig struct {
 	NginxStatusPort                    int
 	OpenTracingEnabled                 bool
 	OpenTracingLoadModule              bool
+	GeoIP2LoadModule                   bool
+	NJSLoadModule                      bool
 	OpenTracingTracer                  string
 	OpenTracingTracerConfig            string
 	ProxyProtocol                      bool
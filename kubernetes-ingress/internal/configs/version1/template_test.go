@@ -285,6 +285,166 @@ func TestExecuteTemplate_ForMergeableIngressForNGINXPlusWithPathRegexAnnotationO
 	}
 }
 
+func TestExecuteTemplate_ForMainWithHTTP3(t *testing.T) {
+	t.Parallel()
+
+	cfg := mainCfg
+	cfg.HTTP3 = true
+
+	for _, tmpl := range []*template.Template{newNGINXMainTmpl(t), newNGINXPlusMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		wantDirectives := []string{
+			"listen 443 quic reuseport default_server;",
+			"listen [::]:443 quic reuseport default_server;",
+		}
+
+		mainConf := buf.String()
+		for _, want := range wantDirectives {
+			if !strings.Contains(mainConf, want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+	}
+}
+
+func TestExecuteTemplate_ForMainWithGeoIP2Module(t *testing.T) {
+	t.Parallel()
+
+	cfg := mainCfg
+	cfg.GeoIP2LoadModule = true
+
+	for _, tmpl := range []*template.Template{newNGINXMainTmpl(t), newNGINXPlusMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		want := "load_module modules/ngx_http_geoip2_module.so;"
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+}
+
+func TestExecuteTemplate_ForMainWithNJSModule(t *testing.T) {
+	t.Parallel()
+
+	cfg := mainCfg
+	cfg.NJSLoadModule = true
+
+	tests := []struct {
+		tmpl           *template.Template
+		wantDirectives []string
+	}{
+		{
+			tmpl: newNGINXMainTmpl(t),
+			wantDirectives: []string{
+				"load_module modules/ngx_http_js_module.so;",
+				"js_import /etc/nginx/njs/apikey_auth.js;",
+				"js_set $apikey_auth_hash apikey_auth.hash;",
+				"js_import /etc/nginx/njs/jwt_auth.js;",
+			},
+		},
+		{
+			tmpl: newNGINXPlusMainTmpl(t),
+			wantDirectives: []string{
+				"load_module modules/ngx_http_js_module.so;",
+				"js_import /etc/nginx/njs/apikey_auth.js;",
+				"js_set $apikey_auth_hash apikey_auth.hash;",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+
+		err := test.tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		for _, want := range test.wantDirectives {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+	}
+}
+
+func TestExecuteTemplate_ForMainWithoutNJSModule(t *testing.T) {
+	t.Parallel()
+
+	cfg := mainCfg
+	cfg.NJSLoadModule = false
+	cfg.OIDC = false
+
+	for _, tmpl := range []*template.Template{newNGINXMainTmpl(t), newNGINXPlusMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		unwantDirectives := []string{
+			"load_module modules/ngx_http_js_module.so;",
+			"js_import /etc/nginx/njs/apikey_auth.js;",
+			"js_import /etc/nginx/njs/jwt_auth.js;",
+		}
+		for _, unwant := range unwantDirectives {
+			if strings.Contains(buf.String(), unwant) {
+				t.Errorf("unwanted %q in generated config", unwant)
+			}
+		}
+	}
+}
+
+func TestExecuteTemplate_ForIngressWithHTTP3(t *testing.T) {
+	t.Parallel()
+
+	cfg := ingressCfg
+	cfg.Servers = []Server{ingressCfg.Servers[0]}
+	cfg.Servers[0].SSL = true
+	cfg.Servers[0].SSLPorts = []int{443}
+	cfg.Servers[0].HTTP3 = true
+
+	for _, tmpl := range []*template.Template{newNGINXIngressTmpl(t), newNGINXPlusIngressTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, cfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		wantDirectives := []string{
+			"listen 443 ssl;",
+			"listen 443 quic;",
+			"listen [::]:443 quic;",
+			`add_header Alt-Svc 'h3=":$server_port"; ma=86400' always;`,
+		}
+
+		ingressConf := buf.String()
+		for _, want := range wantDirectives {
+			if !strings.Contains(ingressConf, want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+	}
+}
+
 func TestExecuteTemplate_ForMainForNGINXWithCustomTLSPassthroughPort(t *testing.T) {
 	t.Parallel()
 
@@ -12,8 +12,10 @@ type UpstreamLabels struct {
 
 // VirtualServerConfig holds NGINX configuration for a VirtualServer.
 type VirtualServerConfig struct {
+	GeoIP2            []GeoIP2
 	HTTPSnippets      []string
 	LimitReqZones     []LimitReqZone
+	LimitConnZones    []LimitConnZone
 	Maps              []Map
 	Server            Server
 	SpiffeCerts       bool
@@ -51,8 +53,7 @@ type Server struct {
 	ServerName                string
 	StatusZone                string
 	CustomListeners           bool
-	HTTPPort                  int
-	HTTPSPort                 int
+	Listeners                 []Listener
 	ProxyProtocol             bool
 	SSL                       *SSL
 	ServerTokens              string
@@ -64,6 +65,10 @@ type Server struct {
 	Locations                 []Location
 	ErrorPageLocations        []ErrorPageLocation
 	ReturnLocations           []ReturnLocation
+	CORSPreflightLocations    []CORS
+	ExternalAuthLocations     []ExternalAuth
+	JWTVerifierLocations      []JWTAuth
+	JWTClaimNames             []string
 	HealthChecks              []HealthCheck
 	TLSRedirect               *TLSRedirect
 	TLSPassthrough            bool
@@ -71,6 +76,9 @@ type Server struct {
 	Deny                      []string
 	LimitReqOptions           LimitReqOptions
 	LimitReqs                 []LimitReq
+	LimitConnOptions          LimitConnOptions
+	LimitConns                []LimitConn
+	LimitRate                 *LimitRate
 	JWTAuth                   *JWTAuth
 	JWTAuthList               map[string]*JWTAuth
 	JWKSAuthEnabled           bool
@@ -87,9 +95,19 @@ type Server struct {
 	Gunzip                    bool
 }
 
+// Listener defines a custom listener of a server.
+type Listener struct {
+	Port          int
+	SSL           bool
+	HTTP2         bool
+	HTTP3         bool
+	ProxyProtocol bool
+}
+
 // SSL defines SSL configuration for a server.
 type SSL struct {
 	HTTP2           bool
+	HTTP3           bool
 	Certificate     string
 	CertificateKey  string
 	RejectHandshake bool
@@ -119,16 +137,19 @@ type EgressMTLS struct {
 
 // OIDC holds OIDC configuration data.
 type OIDC struct {
-	AuthEndpoint      string
-	ClientID          string
-	ClientSecret      string
-	JwksURI           string
-	Scope             string
-	TokenEndpoint     string
-	RedirectURI       string
-	ZoneSyncLeeway    int
-	AuthExtraArgs     string
-	AccessTokenEnable bool
+	AuthEndpoint          string
+	ClientID              string
+	ClientSecret          string
+	JwksURI               string
+	Scope                 string
+	TokenEndpoint         string
+	RedirectURI           string
+	ZoneSyncLeeway        int
+	AuthExtraArgs         string
+	AccessTokenEnable     bool
+	EndSessionEndpoint    string
+	PostLogoutRedirectURI string
+	PKCEEnable            bool
 }
 
 // WAF defines WAF configuration.
@@ -173,6 +194,7 @@ type Location struct {
 	ProxyInterceptErrors     bool
 	ProxyPassRequestHeaders  bool
 	ProxySetHeaders          []Header
+	ForwardedHeaders         bool
 	ProxyHideHeaders         []string
 	ProxyPassHeaders         []string
 	ProxyIgnoreHeaders       string
@@ -187,11 +209,18 @@ type Location struct {
 	Deny                     []string
 	LimitReqOptions          LimitReqOptions
 	LimitReqs                []LimitReq
+	LimitConnOptions         LimitConnOptions
+	LimitConns               []LimitConn
+	LimitRate                *LimitRate
 	JWTAuth                  *JWTAuth
 	BasicAuth                *BasicAuth
 	EgressMTLS               *EgressMTLS
 	OIDC                     bool
 	WAF                      *WAF
+	CORS                     *CORS
+	ExternalAuth             *ExternalAuth
+	APIKey                   *APIKey
+	Geo                      *Geo
 	Dos                      *Dos
 	PoliciesErrorReturn      *Return
 	ServiceName              string
@@ -199,6 +228,18 @@ type Location struct {
 	VSRName                  string
 	VSRNamespace             string
 	GRPCPass                 string
+	Mirror                   *Mirror
+}
+
+// Mirror defines the mirroring of the requests of a location to an upstream through an internal location.
+type Mirror struct {
+	Path           string
+	ProxyPass      string
+	Rewrites       []string
+	ProxySSLName   string
+	ServiceName    string
+	Percentage     int
+	SampleVariable string
 }
 
 // ReturnLocation defines a location for returning a fixed response.
@@ -361,6 +402,12 @@ func (rl LimitReqOptions) String() string {
 	return fmt.Sprintf("{DryRun %v, LogLevel %q, RejectCode %q}", rl.DryRun, rl.LogLevel, rl.RejectCode)
 }
 
+// LimitRate defines the bandwidth limit of a response.
+type LimitRate struct {
+	Rate  string
+	After string
+}
+
 // JWTAuth holds JWT authentication configuration.
 type JWTAuth struct {
 	Key      string
@@ -369,6 +416,25 @@ type JWTAuth struct {
 	Token    string
 	KeyCache string
 	JwksURI  JwksURI
+	Claims   *JWTClaims
+	Verifier *JWTVerifier
+}
+
+// JWTVerifier defines the verification of a JWT by the njs module in NGINX OSS, which doesn't support auth_jwt.
+// The token is verified by a subrequest to the internal location at Path.
+// TokenSource is the name of the variable of the token, the bearer token of the Authorization header is used if it is empty.
+// Claims holds the base64 encoded claim requirements of the policy.
+type JWTVerifier struct {
+	Path        string
+	TokenSource string
+	Claims      string
+}
+
+// JWTClaims defines the claims required by a JWT policy.
+type JWTClaims struct {
+	RequiredVariables []string
+	RejectLocation    string
+	RejectBody        string
 }
 
 // JwksURI defines the components of a JwksURI
@@ -384,3 +450,68 @@ type BasicAuth struct {
 	Secret string
 	Realm  string
 }
+
+// CORS defines the Cross-Origin Resource Sharing headers of a location.
+// Preflight requests are answered by the internal location at PreflightPath.
+type CORS struct {
+	PreflightPath    string
+	AllowOrigin      string
+	AllowMethods     string
+	AllowHeaders     string
+	ExposeHeaders    string
+	AllowCredentials bool
+	MaxAge           int
+	VaryOrigin       bool
+}
+
+// ExternalAuth defines the authorization of the requests of a location by an auth service.
+// The auth service is called by a subrequest to the internal location at Path.
+type ExternalAuth struct {
+	Path            string
+	ProxyPass       string
+	ServiceName     string
+	RequestHeaders  []Header
+	ResponseHeaders []ExternalAuthResponseHeader
+}
+
+// APIKey defines the API key authentication of a location.
+// The key is read from the first non-empty variable of Sources and its hash is looked up in the map of ClientVariable.
+type APIKey struct {
+	Sources        []string
+	ClientVariable string
+	RejectCode     int
+}
+
+// GeoIP2 defines a geoip2 block that sets variables from the data of a GeoIP2 database.
+type GeoIP2 struct {
+	Database  string
+	Variables []GeoIP2Variable
+}
+
+// GeoIP2Variable defines a variable set from the data of a GeoIP2 database.
+type GeoIP2Variable struct {
+	Name string
+	Path string
+}
+
+// Geo defines the config for the geo policy.
+type Geo struct {
+	AllowedVariables []string
+	RejectCode       int
+	Headers          []Header
+}
+
+// ExternalAuthResponseHeader defines a header of the auth service response that is passed to the upstream.
+type ExternalAuthResponseHeader struct {
+	Name             string
+	Variable         string
+	UpstreamVariable string
+}
+
+// HTTP3ListenersConfig defines a server that sets the reuseport parameter of the QUIC listeners for the ports on
+// which Ingresses and VirtualServers accept HTTP/3. NGINX accepts the parameter only once per address:port,
+// while without it QUIC packets of a connection can be routed to different worker processes.
+type HTTP3ListenersConfig struct {
+	Ports       []int
+	DisableIPV6 bool
+}
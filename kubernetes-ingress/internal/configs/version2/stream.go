@@ -7,6 +7,10 @@ type TransportServerConfig struct {
 	StreamSnippets []string
 	Match          *Match
 	DisableIPV6    bool
+	LimitConnZones []LimitConnZone
+	SplitClients   []SplitClient
+	Maps           []Map
+	LogFormat      *StreamLogFormat
 }
 
 // StreamUpstream defines a stream upstream.
@@ -31,11 +35,15 @@ type StreamServer struct {
 	TLSPassthrough           bool
 	UnixSocket               string
 	Port                     int
+	IPv4                     string
+	IPv6                     string
 	UDP                      bool
 	StatusZone               string
 	ProxyRequests            *int
 	ProxyResponses           *int
 	ProxyPass                string
+	ProxyProtocol            bool
+	SSLPreread               bool
 	Name                     string
 	Namespace                string
 	ProxyTimeout             string
@@ -47,6 +55,49 @@ type StreamServer struct {
 	ServerSnippets           []string
 	DisableIPV6              bool
 	SSL                      *StreamSSL
+	Allow                    []string
+	Deny                     []string
+	LimitConnOptions         LimitConnOptions
+	LimitConns               []LimitConn
+	IngressMTLS              *IngressMTLS
+	EgressMTLS               *EgressMTLS
+	PoliciesErrorDeny        bool
+	AccessLog                *StreamAccessLog
+}
+
+// StreamLogFormat defines a log format of a TransportServer.
+type StreamLogFormat struct {
+	Name     string
+	Escaping string
+	Format   string
+}
+
+// StreamAccessLog defines the access log of a TransportServer.
+type StreamAccessLog struct {
+	Off         bool
+	Destination string
+	Format      string
+	Condition   string
+}
+
+// LimitConnZone defines a connection limit shared memory zone.
+type LimitConnZone struct {
+	Key      string
+	ZoneName string
+	ZoneSize string
+}
+
+// LimitConn defines a connection limit.
+type LimitConn struct {
+	ZoneName    string
+	Connections int
+}
+
+// LimitConnOptions defines connection limit options.
+type LimitConnOptions struct {
+	DryRun     bool
+	LogLevel   string
+	RejectCode int
 }
 
 // StreamSSL defines SSL configuration for a server.
@@ -78,3 +129,25 @@ type Match struct {
 
 // TLSPassthroughHostsConfig defines a mapping between TLS Passthrough hosts and the corresponding unix sockets.
 type TLSPassthroughHostsConfig map[string]string
+
+// TLSTerminationHostsConfig defines a server that terminates TLS on a listener shared by TransportServers with hosts
+// and passes the connections to the unix sockets of the TransportServers based on the SNI.
+// VariablePrefix is the prefix of the variables of the SNI maps, which is unique for the listener, because
+// listeners with different IPs can share the port.
+type TLSTerminationHostsConfig struct {
+	Listener       string
+	VariablePrefix string
+	Port           int
+	IPv4           string
+	IPv6           string
+	DisableIPV6    bool
+	Hosts          []TLSTerminationHost
+}
+
+// TLSTerminationHost defines the certificate and the unix socket of a TransportServer host.
+type TLSTerminationHost struct {
+	Host           string
+	UnixSocket     string
+	Certificate    string
+	CertificateKey string
+}
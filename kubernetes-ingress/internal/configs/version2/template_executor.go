@@ -13,11 +13,65 @@ const tlsPassthroughHostsTemplateString = `# mapping between TLS Passthrough hos
 {{ end }}
 `
 
+// #nosec G101
+const tlsTerminationHostsTemplateString = `# TLS termination for the TransportServer hosts of the listener {{ .Listener }}
+map $ssl_server_name {{ .VariablePrefix }}_certificate {
+    hostnames;
+    {{ range $h := .Hosts }}
+    {{ $h.Host }} {{ $h.Certificate }};
+    {{ end }}
+}
+
+map $ssl_server_name {{ .VariablePrefix }}_certificate_key {
+    hostnames;
+    {{ range $h := .Hosts }}
+    {{ $h.Host }} {{ $h.CertificateKey }};
+    {{ end }}
+}
+
+map $ssl_server_name {{ .VariablePrefix }}_destination {
+    hostnames;
+    default unix:/var/lib/nginx/non-existing-unix-socket.sock;
+    {{ range $h := .Hosts }}
+    {{ $h.Host }} {{ $h.UnixSocket }};
+    {{ end }}
+}
+
+server {
+    {{ if or .IPv4 .IPv6 }}
+    {{ with .IPv4 }}listen {{ . }}:{{ $.Port }} ssl;{{ end }}
+    {{ with .IPv6 }}listen [{{ . }}]:{{ $.Port }} ssl;{{ end }}
+    {{ else }}
+    listen {{ .Port }} ssl;
+    {{ if not .DisableIPV6 }}listen [::]:{{ .Port }} ssl;{{ end }}
+    {{ end }}
+
+    ssl_certificate {{ .VariablePrefix }}_certificate;
+    ssl_certificate_key {{ .VariablePrefix }}_certificate_key;
+
+    proxy_pass {{ .VariablePrefix }}_destination;
+    proxy_protocol on;
+}
+`
+
+const http3ListenersTemplateString = `# QUIC listeners with the reuseport parameter for the ports of the Ingresses and VirtualServers with HTTP/3
+server {
+    {{ range $p := .Ports }}
+    listen {{ $p }} quic reuseport;
+    {{ if not $.DisableIPV6 }}listen [::]:{{ $p }} quic reuseport;{{ end }}
+    {{ end }}
+
+    ssl_reject_handshake on;
+}
+`
+
 // TemplateExecutor executes NGINX configuration templates.
 type TemplateExecutor struct {
 	virtualServerTemplate       *template.Template
 	transportServerTemplate     *template.Template
 	tlsPassthroughHostsTemplate *template.Template
+	tlsTerminationHostsTemplate *template.Template
+	http3ListenersTemplate      *template.Template
 }
 
 // NewTemplateExecutor creates a TemplateExecutor.
@@ -39,10 +93,22 @@ func NewTemplateExecutor(virtualServerTemplatePath string, transportServerTempla
 		return nil, err
 	}
 
+	tlsTerminationHostsTemplate, err := template.New("tlsTerminationHosts").Parse(tlsTerminationHostsTemplateString)
+	if err != nil {
+		return nil, err
+	}
+
+	http3ListenersTemplate, err := template.New("http3Listeners").Parse(http3ListenersTemplateString)
+	if err != nil {
+		return nil, err
+	}
+
 	return &TemplateExecutor{
 		virtualServerTemplate:       vsTemplate,
 		transportServerTemplate:     tsTemplate,
 		tlsPassthroughHostsTemplate: tlsPassthroughHostsTemplate,
+		tlsTerminationHostsTemplate: tlsTerminationHostsTemplate,
+		http3ListenersTemplate:      http3ListenersTemplate,
 	}, nil
 }
 
@@ -81,3 +147,21 @@ func (te *TemplateExecutor) ExecuteTLSPassthroughHostsTemplate(cfg *TLSPassthrou
 
 	return configBuffer.Bytes(), err
 }
+
+// ExecuteTLSTerminationHostsTemplate generates the content of an NGINX configuration file for the server that
+// terminates TLS for the TransportServer hosts of a listener.
+func (te *TemplateExecutor) ExecuteTLSTerminationHostsTemplate(cfg *TLSTerminationHostsConfig) ([]byte, error) {
+	var configBuffer bytes.Buffer
+	err := te.tlsTerminationHostsTemplate.Execute(&configBuffer, cfg)
+
+	return configBuffer.Bytes(), err
+}
+
+// ExecuteHTTP3ListenersTemplate generates the content of an NGINX configuration file for the server that sets
+// the reuseport parameter of the QUIC listeners.
+func (te *TemplateExecutor) ExecuteHTTP3ListenersTemplate(cfg *HTTP3ListenersConfig) ([]byte, error) {
+	var configBuffer bytes.Buffer
+	err := te.http3ListenersTemplate.Execute(&configBuffer, cfg)
+
+	return configBuffer.Bytes(), err
+}
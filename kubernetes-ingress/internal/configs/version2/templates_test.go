@@ -2,6 +2,7 @@ package version2
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -110,6 +111,160 @@ func TestExecuteVirtualServerTemplate_RendersTemplateWithCustomListener(t *testi
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplate_RendersTemplateWithMultipleCustomListeners(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := virtualServerCfgWithCustomListener
+	cfg.Server.Listeners = []Listener{
+		{Port: 8082, ProxyProtocol: true},
+		{Port: 8443, SSL: true, ProxyProtocol: true},
+		{Port: 9443, SSL: true, HTTP2: true},
+	}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		wantStrings := []string{
+			"listen 8082 proxy_protocol;",
+			"listen 8443 ssl proxy_protocol;",
+			"listen 9443 ssl http2;",
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithHTTP3(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := virtualServerCfg
+	ssl := *cfg.Server.SSL
+	ssl.HTTP3 = true
+	cfg.Server.SSL = &ssl
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		wantStrings := []string{
+			"listen 443 quic;",
+			"listen [::]:443 quic;",
+			`add_header Alt-Svc 'h3=":$server_port"; ma=86400' always;`,
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersAltSvcHeaderInLocationsWithHeaders(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cors := CORS{
+		PreflightPath: "/internal_location_cors_default_cors-policy",
+		AllowOrigin:   "https://example.com",
+		AllowMethods:  "GET, POST",
+	}
+
+	cfg := virtualServerCfg
+	ssl := *cfg.Server.SSL
+	ssl.HTTP3 = true
+	cfg.Server.SSL = &ssl
+	cfg.Server.CORSPreflightLocations = []CORS{cors}
+	cfg.Server.ErrorPageLocations = []ErrorPageLocation{
+		{
+			Name:        "@error_page_0",
+			DefaultType: "application/json",
+			Return:      &Return{Text: "Hello World"},
+			Headers: []Header{
+				{Name: "Set-Cookie", Value: "cookie1=test"},
+			},
+		},
+	}
+	cfg.Server.Locations = []Location{
+		{
+			Path:      "/headers",
+			ProxyPass: "http://test-upstream",
+			AddHeaders: []AddHeader{
+				{
+					Header: Header{Name: "X-Header", Value: "value"},
+				},
+			},
+		},
+		{
+			Path:      "/cors",
+			ProxyPass: "http://test-upstream",
+			CORS:      &cors,
+		},
+		{
+			Path:      "/plain",
+			ProxyPass: "http://test-upstream",
+		},
+	}
+
+	altSvc := []byte(`add_header Alt-Svc 'h3=":$server_port"; ma=86400' always;`)
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the server, the error page, the CORS preflight location and the 2 locations with headers
+		if count := bytes.Count(got, altSvc); count != 5 {
+			t.Errorf("want `%s` 5 times in generated template, got %d", altSvc, count)
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithHTTP3CustomListener(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := virtualServerCfgWithCustomListener
+	ssl := *cfg.Server.SSL
+	ssl.HTTP3 = true
+	cfg.Server.SSL = &ssl
+	cfg.Server.Listeners = []Listener{
+		{Port: 8443, SSL: true},
+		{Port: 9443, SSL: true, HTTP3: true},
+	}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		if !bytes.Contains(got, []byte("listen 9443 quic;")) {
+			t.Error("want `listen 9443 quic;` in generated template")
+		}
+		unwantStrings := []string{
+			"listen 443 quic;",
+			"listen 8443 quic;",
+		}
+		for _, unwant := range unwantStrings {
+			if bytes.Contains(got, []byte(unwant)) {
+				t.Errorf("unwant `%s` in generated template", unwant)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
 func TestExecuteVirtualServerTemplate_RendersTemplateWithCustomListenerHTTPOnly(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINXPlus(t)
@@ -166,6 +321,556 @@ func TestExecuteVirtualServerTemplate_RendersTemplateWithCustomListenerHTTPSOnly
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplate_RendersTemplateWithMirror(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"mirror /internal_location_mirror_0;",
+		"location = /internal_location_mirror_0 {",
+		"if ($vs_default_cafe_mirror_0 = \"\") {",
+		"proxy_pass http://vs_default_cafe_coffee-v2$request_uri;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithMirror)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithTLSMirrorAndRewrite(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"location = /internal_location_mirror_0 {",
+		"rewrite ^ $request_uri_no_args;",
+		`rewrite "^/coffee(.*)$" "/beans$1" break;`,
+		"proxy_ssl_server_name on;",
+		"proxy_ssl_name coffee-v2.default.svc;",
+		"proxy_pass https://vs_default_cafe_coffee-v2;",
+	}
+
+	cfg := virtualServerCfgWithMirror
+	cfg.Server.Locations = []Location{cfg.Server.Locations[0]}
+	cfg.Server.Locations[0].Mirror = &Mirror{
+		Path:         "/internal_location_mirror_0",
+		ProxyPass:    "https://vs_default_cafe_coffee-v2",
+		Rewrites:     []string{"^ $request_uri_no_args", `"^/coffee(.*)$" "/beans$1" break`},
+		ProxySSLName: "coffee-v2.default.svc",
+		ServiceName:  "coffee-v2",
+		Percentage:   100,
+	}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithMirrorRequestHeaders(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"proxy_pass_request_headers off;",
+		`proxy_set_header Host "$host";`,
+		`proxy_set_header X-Real-IP "$http_x_client_ip";`,
+		`proxy_set_header X-Tenant "cafe";`,
+		"proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;",
+		"proxy_set_header Forwarded $vs_add_forwarded;",
+	}
+	unwantStrings := []string{
+		"proxy_set_header X-Real-IP $remote_addr;",
+	}
+
+	cfg := virtualServerCfgWithMirror
+	cfg.Server.Locations = []Location{cfg.Server.Locations[0]}
+	cfg.Server.Locations[0].ProxyPassRequestHeaders = false
+	cfg.Server.Locations[0].ForwardedHeaders = true
+	cfg.Server.Locations[0].ProxySetHeaders = []Header{
+		{Name: "X-Real-IP", Value: "$http_x_client_ip"},
+		{Name: "X-Tenant", Value: "cafe"},
+		{Name: "Host", Value: "$host"},
+	}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		_, mirrorLocation, found := bytes.Cut(got, []byte("location = /internal_location_mirror_0 {"))
+		if !found {
+			t.Fatal("want the mirror location in generated template")
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(mirrorLocation, []byte(want)) {
+				t.Errorf("want `%s` in the mirror location of generated template", want)
+			}
+		}
+		for _, unwant := range unwantStrings {
+			if bytes.Contains(mirrorLocation, []byte(unwant)) {
+				t.Errorf("unwant `%s` in the mirror location of generated template", unwant)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithForwardedHeaders(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := virtualServerCfg
+	cfg.Server.Locations = []Location{
+		{
+			Path:             "/",
+			ProxyPass:        "http://test-upstream",
+			ForwardedHeaders: true,
+		},
+	}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		wantStrings := []string{
+			"proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;",
+			"proxy_set_header Forwarded $vs_add_forwarded;",
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithBandwidthLimit(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := virtualServerCfg
+	cfg.LimitConnZones = []LimitConnZone{
+		{
+			Key:      "$binary_remote_addr",
+			ZoneName: "pol_bl_default_bandwidth_limit_policy_default_cafe",
+			ZoneSize: "10m",
+		},
+	}
+	cfg.Server.Locations = []Location{
+		{
+			Path:      "/downloads",
+			ProxyPass: "http://test-upstream",
+			LimitConnOptions: LimitConnOptions{
+				DryRun:     true,
+				LogLevel:   "warn",
+				RejectCode: 429,
+			},
+			LimitConns: []LimitConn{
+				{
+					ZoneName:    "pol_bl_default_bandwidth_limit_policy_default_cafe",
+					Connections: 2,
+				},
+			},
+			LimitRate: &LimitRate{
+				Rate:  "1m",
+				After: "10m",
+			},
+		},
+	}
+
+	wantStrings := []string{
+		"limit_conn_zone $binary_remote_addr zone=pol_bl_default_bandwidth_limit_policy_default_cafe:10m;",
+		"limit_conn_dry_run on;",
+		"limit_conn_log_level warn;",
+		"limit_conn_status 429;",
+		"limit_conn pol_bl_default_bandwidth_limit_policy_default_cafe 2;",
+		"limit_rate 1m;",
+		"limit_rate_after 10m;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithGeo(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := virtualServerCfg
+	cfg.GeoIP2 = []GeoIP2{
+		{
+			Database: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+			Variables: []GeoIP2Variable{
+				{
+					Name: "$pol_geo_default_geo_policy_default_cafe_country",
+					Path: "country iso_code",
+				},
+			},
+		},
+	}
+	cfg.Server.Locations = []Location{
+		{
+			Path:      "/",
+			ProxyPass: "http://test-upstream",
+			Geo: &Geo{
+				AllowedVariables: []string{"$pol_geo_default_geo_policy_default_cafe_country_allowed"},
+				RejectCode:       451,
+				Headers: []Header{
+					{
+						Name:  "X-Country",
+						Value: "$pol_geo_default_geo_policy_default_cafe_country",
+					},
+				},
+			},
+		},
+	}
+
+	wantStrings := []string{
+		"geoip2 /etc/nginx/geoip/GeoLite2-Country.mmdb {",
+		"$pol_geo_default_geo_policy_default_cafe_country country iso_code;",
+		"if ($pol_geo_default_geo_policy_default_cafe_country_allowed = 0) {",
+		"return 451;",
+		"proxy_set_header X-Country $pol_geo_default_geo_policy_default_cafe_country;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithJWTClaims(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+
+	cfg := virtualServerCfg
+	cfg.Server.JWTAuth = &JWTAuth{
+		Realm:  "My Test API",
+		Secret: "/etc/nginx/secrets/default-jwt-secret",
+		Claims: &JWTClaims{
+			RequiredVariables: []string{"$pol_jwt_default_jwt_policy_default_cafe_iss"},
+		},
+	}
+	cfg.Server.Locations = []Location{
+		{
+			Path:      "/add-job",
+			ProxyPass: "http://test-upstream",
+			JWTAuth: &JWTAuth{
+				Realm:  "My Test API",
+				Secret: "/etc/nginx/secrets/default-jwt-secret",
+				Claims: &JWTClaims{
+					RequiredVariables: []string{
+						"$pol_jwt_default_jwt_role_policy_default_cafe_iss",
+						"$pol_jwt_default_jwt_role_policy_default_cafe_roles",
+					},
+					RejectLocation: "@jwt_claims_reject_default_jwt_role_policy_default_cafe",
+					RejectBody:     "Access denied",
+				},
+			},
+			ErrorPages: []ErrorPage{
+				{
+					Name:         "@jwt_claims_reject_default_jwt_role_policy_default_cafe",
+					Codes:        "403",
+					ResponseCode: 403,
+				},
+				{
+					Name:         "@error_page_0_0",
+					Codes:        "404",
+					ResponseCode: 200,
+				},
+			},
+		},
+	}
+	cfg.Server.ReturnLocations = []ReturnLocation{
+		{
+			Name:        "@jwt_claims_reject_default_jwt_role_policy_default_cafe",
+			DefaultType: "text/plain",
+			Return: Return{
+				Text: "Access denied",
+			},
+		},
+	}
+
+	wantStrings := []string{
+		"auth_jwt_require $pol_jwt_default_jwt_policy_default_cafe_iss error=403;",
+		"auth_jwt_require $pol_jwt_default_jwt_role_policy_default_cafe_iss $pol_jwt_default_jwt_role_policy_default_cafe_roles error=403;",
+		`error_page 403 =403 "@jwt_claims_reject_default_jwt_role_policy_default_cafe";`,
+		`error_page 404 =200 "@error_page_0_0";`,
+		"location @jwt_claims_reject_default_jwt_role_policy_default_cafe {",
+		`return 0 "Access denied";`,
+	}
+	got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range wantStrings {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want `%s` in generated template", want)
+		}
+	}
+	if count := bytes.Count(got, []byte("error_page 403 =403")); count != 1 {
+		t.Errorf("want the error page of the JWT claims only in the location, got %d", count)
+	}
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithJWTVerifier(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINX(t)
+
+	serverJWTAuth := JWTAuth{
+		Realm:  "My Test API",
+		Secret: "/etc/nginx/secrets/default-jwt-secret",
+		Verifier: &JWTVerifier{
+			Path:        "/internal_location_jwtauth_default_jwt-policy",
+			TokenSource: "http_token",
+		},
+	}
+	locationJWTAuth := JWTAuth{
+		Key:   "default/jwt-policy-jwks",
+		Realm: "My Test API",
+		JwksURI: JwksURI{
+			JwksScheme: "https",
+			JwksHost:   "idp.example.com",
+			JwksPath:   "/keys",
+		},
+		KeyCache: "1h",
+		Claims: &JWTClaims{
+			RejectLocation: "@jwt_claims_reject_default_jwt_policy_jwks_default_cafe",
+			RejectBody:     "Access denied",
+		},
+		Verifier: &JWTVerifier{
+			Path:   "/internal_location_jwtauth_default_jwt-policy-jwks",
+			Claims: "W3siY2xhaW0iOiJpc3MiLCJyZWdleGVzIjpbIl5jYWZlJCJdfV0=",
+		},
+	}
+
+	cfg := virtualServerCfg
+	cfg.Server.VSName = "cafe"
+	cfg.Server.JWTAuth = &serverJWTAuth
+	cfg.Server.JWTAuthList = map[string]*JWTAuth{
+		locationJWTAuth.Key: &locationJWTAuth,
+	}
+	cfg.Server.JWKSAuthEnabled = true
+	cfg.Server.JWTVerifierLocations = []JWTAuth{serverJWTAuth, locationJWTAuth}
+	cfg.Server.JWTClaimNames = []string{"sub", "tenantId"}
+	cfg.Server.Locations = []Location{
+		{
+			Path:      "/add-job",
+			ProxyPass: "http://test-upstream",
+			JWTAuth:   &locationJWTAuth,
+			ErrorPages: []ErrorPage{
+				{
+					Name:         "@jwt_claims_reject_default_jwt_policy_jwks_default_cafe",
+					Codes:        "403",
+					ResponseCode: 403,
+				},
+			},
+		},
+	}
+
+	wantStrings := []string{
+		"proxy_cache_path /var/cache/nginx/jwks_uri_cafe levels=1 keys_zone=jwks_uri_cafe:1m max_size=10m;",
+		"auth_request /internal_location_jwtauth_default_jwt-policy;",
+		"auth_request /internal_location_jwtauth_default_jwt-policy-jwks;",
+		"auth_request_set $jwt_claim_sub $upstream_http_x_jwt_claim_sub;",
+		"auth_request_set $jwt_claim_tenantId $upstream_http_x_jwt_claim_tenantid;",
+		`error_page 403 =403 "@jwt_claims_reject_default_jwt_policy_jwks_default_cafe";`,
+		"location = /internal_location_jwtauth_default_jwt-policy {",
+		`set $jwt_auth_token_source "http_token";`,
+		"set $jwt_auth_key_file /etc/nginx/secrets/default-jwt-secret;",
+		"set $jwt_auth_jwks_location /_jwks_uri_server_default/jwt-policy-jwks;",
+		`set $jwt_auth_claims "W3siY2xhaW0iOiJpc3MiLCJyZWdleGVzIjpbIl5jYWZlJCJdfV0=";`,
+		"js_content jwt_auth.verify;",
+		"location = /_jwks_uri_server_default/jwt-policy-jwks {",
+		"proxy_cache_valid 200 1h;",
+	}
+	got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range wantStrings {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want `%s` in generated template", want)
+		}
+	}
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithJWTClaimNamesWithoutVerifier(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINX(t)
+
+	cfg := virtualServerCfg
+	cfg.Server.JWTClaimNames = []string{"tenantId"}
+
+	got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+	if err != nil {
+		t.Error(err)
+	}
+	want := `set $jwt_claim_tenantId "";`
+	if !bytes.Contains(got, []byte(want)) {
+		t.Errorf("want `%s` in generated template", want)
+	}
+	if bytes.Contains(got, []byte("auth_request_set $jwt_claim_")) {
+		t.Errorf("unexpected `auth_request_set` in generated template")
+	}
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithOIDCLogoutAndPKCE(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+
+	cfg := virtualServerCfg
+	cfg.Server.OIDC = &OIDC{
+		AuthEndpoint:          "https://idp.example.com/auth",
+		TokenEndpoint:         "https://idp.example.com/token",
+		JwksURI:               "https://idp.example.com/certs",
+		ClientID:              "nginx-plus",
+		ClientSecret:          "super_secret_123",
+		Scope:                 "openid",
+		RedirectURI:           "/_codexch",
+		ZoneSyncLeeway:        200,
+		EndSessionEndpoint:    "https://idp.example.com/logout",
+		PostLogoutRedirectURI: "/signed-out",
+		PKCEEnable:            true,
+	}
+
+	wantStrings := []string{
+		"set $oidc_pkce_enable 1;",
+		`set $oidc_end_session_endpoint "https://idp.example.com/logout";`,
+		`set $oidc_logout_redirect "/signed-out";`,
+		"location = /logout {",
+		"js_content oidc.logout;",
+		"location = /_logout {",
+	}
+	got, err := executor.ExecuteVirtualServerTemplate(&cfg)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range wantStrings {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want `%s` in generated template", want)
+		}
+	}
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithCORS(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"map $http_origin $pol_cors_default_cors_policy_default_cafe_origin {",
+		"location = /internal_location_cors_default_cors-policy {",
+		`add_header Access-Control-Allow-Methods "GET, PUT" always;`,
+		"add_header Access-Control-Allow-Headers $http_access_control_request_headers always;",
+		"add_header Access-Control-Max-Age 3600 always;",
+		"return 204;",
+		"rewrite ^ /internal_location_cors_default_cors-policy last;",
+		`add_header Access-Control-Allow-Origin "$pol_cors_default_cors_policy_default_cafe_origin" always;`,
+		`add_header Access-Control-Allow-Credentials "true" always;`,
+		`add_header Access-Control-Expose-Headers "X-Request-Id" always;`,
+		"add_header Vary Origin always;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithCORS)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithExternalAuth(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"location = /internal_location_extauth_default_ext-auth-policy {",
+		"proxy_pass_request_body off;",
+		"proxy_pass_request_headers off;",
+		"proxy_set_header Authorization $http_authorization;",
+		"proxy_pass http://vs_default_cafe_extauth_default_ext-auth-policy/auth;",
+		"auth_request /internal_location_extauth_default_ext-auth-policy;",
+		"auth_request_set $extauth_x_user_id $upstream_http_x_user_id;",
+		"proxy_set_header X-User-Id $extauth_x_user_id;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithExternalAuth)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithAPIKey(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"map $apikey_auth_hash $apikey_auth_client_name_default_api_key_policy_default_cafe {",
+		`"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8" "client1";`,
+		"set $apikey_auth_token $http_x_api_key;",
+		"set $apikey_auth_token $arg_apikey;",
+		`if ($apikey_auth_client_name_default_api_key_policy_default_cafe = "") {`,
+		"return 403;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithAPIKey)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
 func TestVirtualServerForNginxPlusWithWAFApBundle(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINXPlus(t)
@@ -173,61 +878,480 @@ func TestVirtualServerForNginxPlusWithWAFApBundle(t *testing.T) {
 	if err != nil {
 		t.Errorf("Failed to execute template: %v", err)
 	}
-	t.Log(string(data))
+	t.Log(string(data))
+}
+
+func TestVirtualServerForNginx(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINX(t)
+	data, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfg)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+	t.Log(string(data))
+}
+
+func TestTransportServerForNginxPlus(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	data, err := executor.ExecuteTransportServerTemplate(&transportServerCfg)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+	t.Log(string(data))
+}
+
+func TestExecuteTemplateForTransportServerWithResolver(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	_, err := executor.ExecuteTransportServerTemplate(&transportServerCfgWithResolver)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+}
+
+func TestTransportServerForNginx(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINX(t)
+	data, err := executor.ExecuteTransportServerTemplate(&transportServerCfg)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+	t.Log(string(data))
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithPolicies(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	wantStrings := []string{
+		"limit_conn_zone $binary_remote_addr zone=pol_cl_default_conn-limit_default_tcp-app:10m;",
+		"allow 10.0.0.0/8;",
+		"deny all;",
+		"limit_conn_dry_run on;",
+		"limit_conn_log_level warn;",
+		"limit_conn pol_cl_default_conn-limit_default_tcp-app 10;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&transportServerCfgWithPolicies)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithTLSTerminationHost(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.Server.UnixSocket = "unix:/var/lib/nginx/tls-termination-default_tcp-server.sock"
+	cfg.Server.SSL = &StreamSSL{
+		Enabled:        true,
+		Certificate:    "/etc/nginx/secrets/default-secret",
+		CertificateKey: "/etc/nginx/secrets/default-secret",
+	}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		if !bytes.Contains(got, []byte("listen unix:/var/lib/nginx/tls-termination-default_tcp-server.sock proxy_protocol;")) {
+			t.Error("want `listen unix:/var/lib/nginx/tls-termination-default_tcp-server.sock proxy_protocol;` in generated template")
+		}
+		if bytes.Contains(got, []byte("ssl_certificate")) {
+			t.Error("want no `ssl_certificate` in generated template")
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithListenerAddresses(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.Server.IPv4 = "10.0.0.1"
+	cfg.Server.IPv6 = "2001:db8::1"
+	cfg.Server.SSL = &StreamSSL{}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		wantStrings := []string{
+			fmt.Sprintf("listen 10.0.0.1:%d udp;", cfg.Server.Port),
+			fmt.Sprintf("listen [2001:db8::1]:%d udp;", cfg.Server.Port),
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		if bytes.Contains(got, []byte(fmt.Sprintf("listen [::]:%d", cfg.Server.Port))) {
+			t.Errorf("want no `listen [::]:%d` in generated template", cfg.Server.Port)
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithSplitsAndMatches(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.SplitClients = []SplitClient{
+		{
+			Source:   "$remote_addr",
+			Variable: "$ts_default_tcp_server_splits_0",
+			Distributions: []Distribution{
+				{Weight: "90%", Value: "ts_default_tcp-server_tcp-app"},
+				{Weight: "10%", Value: "ts_default_tcp-server_tcp-app-v2"},
+			},
+		},
+	}
+	cfg.Maps = []Map{
+		{
+			Source:   "$ssl_preread_server_name",
+			Variable: "$ts_default_tcp_server_matches_0_match_0_cond_0",
+			Parameters: []Parameter{
+				{Value: `"app.example.com"`, Result: "1"},
+				{Value: "default", Result: "0"},
+			},
+		},
+		{
+			Source:   "$ts_default_tcp_server_matches_0_match_0_cond_0",
+			Variable: "$ts_default_tcp_server_matches_0",
+			Parameters: []Parameter{
+				{Value: "~^1", Result: "ts_default_tcp-server_tcp-app-v2"},
+				{Value: "default", Result: "$ts_default_tcp_server_splits_0"},
+			},
+		},
+	}
+	cfg.Server.ProxyPass = "$ts_default_tcp_server_matches_0"
+	cfg.Server.SSLPreread = true
+
+	wantStrings := []string{
+		"split_clients $remote_addr $ts_default_tcp_server_splits_0 {",
+		"90% ts_default_tcp-server_tcp-app;",
+		"10% ts_default_tcp-server_tcp-app-v2;",
+		"map $ssl_preread_server_name $ts_default_tcp_server_matches_0_match_0_cond_0 {",
+		`"app.example.com" 1;`,
+		"map $ts_default_tcp_server_matches_0_match_0_cond_0 $ts_default_tcp_server_matches_0 {",
+		"~^1 ts_default_tcp-server_tcp-app-v2;",
+		"default $ts_default_tcp_server_splits_0;",
+		"ssl_preread on;",
+		"proxy_pass $ts_default_tcp_server_matches_0;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithProxyProtocol(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.Server.ProxyProtocol = true
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		if !bytes.Contains(got, []byte("proxy_protocol on;")) {
+			t.Error("want `proxy_protocol on;` in generated template")
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithMTLS(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.Server.SSL = &StreamSSL{
+		Enabled:        true,
+		Certificate:    "cafe-secret.pem",
+		CertificateKey: "cafe-secret.pem",
+	}
+	cfg.Server.IngressMTLS = &IngressMTLS{
+		ClientCert:   "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+		VerifyClient: "on",
+		VerifyDepth:  2,
+	}
+	cfg.Server.EgressMTLS = &EgressMTLS{
+		Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
+		CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
+		TrustedCert:    "/etc/nginx/secrets/default-egress-trusted-ca-secret-ca.crt",
+		VerifyServer:   true,
+		VerifyDepth:    1,
+		Protocols:      "TLSv1.2",
+		Ciphers:        "DEFAULT",
+		SessionReuse:   true,
+	}
+
+	want := []string{
+		"ssl_client_certificate /etc/nginx/secrets/default-ingress-mtls-secret-ca.crt;",
+		"ssl_verify_client on;",
+		"ssl_verify_depth 2;",
+		"proxy_ssl on;",
+		"proxy_ssl_certificate /etc/nginx/secrets/default-egress-mtls-secret;",
+		"proxy_ssl_trusted_certificate /etc/nginx/secrets/default-egress-trusted-ca-secret-ca.crt;",
+		"proxy_ssl_verify on;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, directive := range want {
+			if !bytes.Contains(got, []byte(directive)) {
+				t.Errorf("want %q in generated template", directive)
+			}
+		}
+		if bytes.Contains(got, []byte("proxy_ssl_name")) {
+			t.Error("want no `proxy_ssl_name` in generated template")
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithAccessLog(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.LogFormat = &StreamLogFormat{
+		Name:     "ts_default_tcp-server_tcp-audit",
+		Escaping: "json",
+		Format:   "$remote_addr [$time_local] $status",
+	}
+	cfg.Server.AccessLog = &StreamAccessLog{
+		Destination: "syslog:server=10.0.0.1:514",
+		Format:      "ts_default_tcp-server_tcp-audit",
+		Condition:   "$loggable",
+	}
+
+	want := []string{
+		"log_format ts_default_tcp-server_tcp-audit escape=json '$remote_addr [$time_local] $status';",
+		"access_log syslog:server=10.0.0.1:514 ts_default_tcp-server_tcp-audit if=$loggable;",
+	}
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		for _, directive := range want {
+			if !bytes.Contains(got, []byte(directive)) {
+				t.Errorf("want %q in generated template", directive)
+			}
+		}
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTransportServerTemplate_RendersTemplateWithAccessLogOff(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+
+	cfg := transportServerCfg
+	cfg.Server.AccessLog = &StreamAccessLog{Off: true}
+
+	for _, executor := range executors {
+		got, err := executor.ExecuteTransportServerTemplate(&cfg)
+		if err != nil {
+			t.Error(err)
+		}
+		if !bytes.Contains(got, []byte("access_log off;")) {
+			t.Error("want `access_log off;` in generated template")
+		}
+		t.Log(string(got))
+	}
 }
 
-func TestVirtualServerForNginx(t *testing.T) {
+func TestTLSPassthroughHosts(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINX(t)
-	data, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfg)
+
+	unixSocketsCfg := TLSPassthroughHostsConfig{
+		"app.example.com": "unix:/var/lib/nginx/passthrough-default_secure-app.sock",
+	}
+
+	data, err := executor.ExecuteTLSPassthroughHostsTemplate(&unixSocketsCfg)
 	if err != nil {
 		t.Errorf("Failed to execute template: %v", err)
 	}
 	t.Log(string(data))
 }
 
-func TestTransportServerForNginxPlus(t *testing.T) {
+func TestTLSTerminationHosts(t *testing.T) {
 	t.Parallel()
-	executor := newTmplExecutorNGINXPlus(t)
-	data, err := executor.ExecuteTransportServerTemplate(&transportServerCfg)
+	executor := newTmplExecutorNGINX(t)
+
+	tlsTerminationHostsCfg := TLSTerminationHostsConfig{
+		Listener:       "tcp-8443",
+		VariablePrefix: "$ts_sni_tcp_8443",
+		Port:           8443,
+		Hosts: []TLSTerminationHost{
+			{
+				Host:           "app.example.com",
+				UnixSocket:     "unix:/var/lib/nginx/tls-termination-default_secure-app.sock",
+				Certificate:    "/etc/nginx/secrets/default-app-secret",
+				CertificateKey: "/etc/nginx/secrets/default-app-secret",
+			},
+		},
+	}
+
+	got, err := executor.ExecuteTLSTerminationHostsTemplate(&tlsTerminationHostsCfg)
 	if err != nil {
 		t.Errorf("Failed to execute template: %v", err)
 	}
-	t.Log(string(data))
+
+	wantDirectives := []string{
+		"map $ssl_server_name $ts_sni_tcp_8443_certificate {",
+		"app.example.com /etc/nginx/secrets/default-app-secret;",
+		"app.example.com unix:/var/lib/nginx/tls-termination-default_secure-app.sock;",
+		"listen 8443 ssl;",
+		"listen [::]:8443 ssl;",
+		"ssl_certificate $ts_sni_tcp_8443_certificate;",
+		"proxy_pass $ts_sni_tcp_8443_destination;",
+		"proxy_protocol on;",
+	}
+
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want `%s` in generated template", want)
+		}
+	}
+	t.Log(string(got))
 }
 
-func TestExecuteTemplateForTransportServerWithResolver(t *testing.T) {
+func TestExecuteHTTP3ListenersTemplate(t *testing.T) {
 	t.Parallel()
-	executor := newTmplExecutorNGINXPlus(t)
-	_, err := executor.ExecuteTransportServerTemplate(&transportServerCfgWithResolver)
+	executor := newTmplExecutorNGINX(t)
+
+	got, err := executor.ExecuteHTTP3ListenersTemplate(&HTTP3ListenersConfig{Ports: []int{443, 8443}})
 	if err != nil {
-		t.Errorf("Failed to execute template: %v", err)
+		t.Fatalf("Failed to execute template: %v", err)
+	}
+
+	wantDirectives := []string{
+		"listen 443 quic reuseport;",
+		"listen [::]:443 quic reuseport;",
+		"listen 8443 quic reuseport;",
+		"listen [::]:8443 quic reuseport;",
+		"ssl_reject_handshake on;",
+	}
+	for _, want := range wantDirectives {
+		if count := bytes.Count(got, []byte(want)); count != 1 {
+			t.Errorf("want `%s` once in generated template, got %d", want, count)
+		}
 	}
+	t.Log(string(got))
 }
 
-func TestTransportServerForNginx(t *testing.T) {
+func TestExecuteHTTP3ListenersTemplateWithDisabledIPV6(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINX(t)
-	data, err := executor.ExecuteTransportServerTemplate(&transportServerCfg)
+
+	got, err := executor.ExecuteHTTP3ListenersTemplate(&HTTP3ListenersConfig{Ports: []int{8443}, DisableIPV6: true})
 	if err != nil {
-		t.Errorf("Failed to execute template: %v", err)
+		t.Fatalf("Failed to execute template: %v", err)
 	}
-	t.Log(string(data))
+
+	if !bytes.Contains(got, []byte("listen 8443 quic reuseport;")) {
+		t.Error("want `listen 8443 quic reuseport;` in generated template")
+	}
+	if bytes.Contains(got, []byte("[::]")) {
+		t.Error("want no IPv6 listeners in generated template")
+	}
+	t.Log(string(got))
 }
 
-func TestTLSPassthroughHosts(t *testing.T) {
+func TestTLSTerminationHostsWithListenersOnSamePort(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINX(t)
 
-	unixSocketsCfg := TLSPassthroughHostsConfig{
-		"app.example.com": "unix:/var/lib/nginx/passthrough-default_secure-app.sock",
+	tlsTerminationHostsCfgs := []TLSTerminationHostsConfig{
+		{
+			Listener:       "tcp-internal",
+			VariablePrefix: "$ts_sni_tcp_internal",
+			Port:           8443,
+			IPv4:           "10.0.0.1",
+			Hosts: []TLSTerminationHost{
+				{
+					Host:           "internal.example.com",
+					UnixSocket:     "unix:/var/lib/nginx/tls-termination-default_internal-app.sock",
+					Certificate:    "/etc/nginx/secrets/default-internal-secret",
+					CertificateKey: "/etc/nginx/secrets/default-internal-secret",
+				},
+			},
+		},
+		{
+			Listener:       "tcp-external",
+			VariablePrefix: "$ts_sni_tcp_external",
+			Port:           8443,
+			IPv4:           "10.0.0.2",
+			Hosts: []TLSTerminationHost{
+				{
+					Host:           "external.example.com",
+					UnixSocket:     "unix:/var/lib/nginx/tls-termination-default_external-app.sock",
+					Certificate:    "/etc/nginx/secrets/default-external-secret",
+					CertificateKey: "/etc/nginx/secrets/default-external-secret",
+				},
+			},
+		},
 	}
 
-	data, err := executor.ExecuteTLSPassthroughHostsTemplate(&unixSocketsCfg)
-	if err != nil {
-		t.Errorf("Failed to execute template: %v", err)
+	var got []byte
+	for _, cfg := range tlsTerminationHostsCfgs {
+		data, err := executor.ExecuteTLSTerminationHostsTemplate(&cfg)
+		if err != nil {
+			t.Fatalf("Failed to execute template: %v", err)
+		}
+		got = append(got, data...)
 	}
-	t.Log(string(data))
+
+	wantDirectives := []string{
+		"map $ssl_server_name $ts_sni_tcp_internal_certificate {",
+		"map $ssl_server_name $ts_sni_tcp_internal_certificate_key {",
+		"map $ssl_server_name $ts_sni_tcp_internal_destination {",
+		"listen 10.0.0.1:8443 ssl;",
+		"proxy_pass $ts_sni_tcp_internal_destination;",
+		"map $ssl_server_name $ts_sni_tcp_external_certificate {",
+		"map $ssl_server_name $ts_sni_tcp_external_certificate_key {",
+		"map $ssl_server_name $ts_sni_tcp_external_destination {",
+		"listen 10.0.0.2:8443 ssl;",
+		"proxy_pass $ts_sni_tcp_external_destination;",
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want `%s` in generated template", want)
+		}
+	}
+	if bytes.Contains(got, []byte("$ts_sni_8443")) {
+		t.Error("want the SNI map variables keyed by the listener rather than the port")
+	}
+	t.Log(string(got))
 }
 
 func TestExecuteVirtualServerTemplateWithJWKSWithToken(t *testing.T) {
@@ -2734,8 +3858,10 @@ var (
 				Code:    301,
 			},
 			CustomListeners: true,
-			HTTPPort:        8082,
-			HTTPSPort:       8443,
+			Listeners: []Listener{
+				{Port: 8082},
+				{Port: 8443, SSL: true},
+			},
 			ServerTokens:    "off",
 			SetRealIPFrom:   []string{"0.0.0.0/0"},
 			RealIPHeader:    "X-Real-IP",
@@ -3082,8 +4208,9 @@ var (
 				Code:    301,
 			},
 			CustomListeners: true,
-			HTTPPort:        8082,
-			HTTPSPort:       0,
+			Listeners: []Listener{
+				{Port: 8082},
+			},
 			ServerTokens:    "off",
 			SetRealIPFrom:   []string{"0.0.0.0/0"},
 			RealIPHeader:    "X-Real-IP",
@@ -3430,8 +4557,9 @@ var (
 				Code:    301,
 			},
 			CustomListeners: true,
-			HTTPPort:        0,
-			HTTPSPort:       8443,
+			Listeners: []Listener{
+				{Port: 8443, SSL: true},
+			},
 			ServerTokens:    "off",
 			SetRealIPFrom:   []string{"0.0.0.0/0"},
 			RealIPHeader:    "X-Real-IP",
@@ -3667,6 +4795,255 @@ var (
 		},
 	}
 
+	virtualServerCfgWithMirror = VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "vs_default_cafe_coffee-v1",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.31:8001",
+					},
+				},
+			},
+			{
+				Name: "vs_default_cafe_coffee-v2",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.32:8001",
+					},
+				},
+			},
+		},
+		SplitClients: []SplitClient{
+			{
+				Source:   "$request_id",
+				Variable: "$vs_default_cafe_mirror_0",
+				Distributions: []Distribution{
+					{
+						Weight: "10%",
+						Value:  "1",
+					},
+					{
+						Weight: "*",
+						Value:  `""`,
+					},
+				},
+			},
+		},
+		Server: Server{
+			ServerName:  "cafe.example.com",
+			StatusZone:  "cafe.example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Locations: []Location{
+				{
+					Path:                     "/coffee",
+					ProxyConnectTimeout:      "30s",
+					ProxyReadTimeout:         "31s",
+					ProxySendTimeout:         "32s",
+					ClientMaxBodySize:        "1m",
+					ProxyPass:                "http://vs_default_cafe_coffee-v1",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxyNextUpstreamTries:   0,
+					ProxyPassRequestHeaders:  true,
+					ServiceName:              "coffee-v1",
+					Mirror: &Mirror{
+						Path:           "/internal_location_mirror_0",
+						ProxyPass:      "http://vs_default_cafe_coffee-v2$request_uri",
+						ServiceName:    "coffee-v2",
+						Percentage:     10,
+						SampleVariable: "$vs_default_cafe_mirror_0",
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithCORS = VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "vs_default_cafe_tea",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.20:8001",
+					},
+				},
+			},
+		},
+		Maps: []Map{
+			{
+				Source:   "$http_origin",
+				Variable: "$pol_cors_default_cors_policy_default_cafe_origin",
+				Parameters: []Parameter{
+					{
+						Value:  `"https://app.example.com"`,
+						Result: "$http_origin",
+					},
+					{
+						Value:  "default",
+						Result: `""`,
+					},
+				},
+			},
+		},
+		Server: Server{
+			ServerName:  "cafe.example.com",
+			StatusZone:  "cafe.example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			CORSPreflightLocations: []CORS{
+				{
+					PreflightPath:    "/internal_location_cors_default_cors-policy",
+					AllowOrigin:      "$pol_cors_default_cors_policy_default_cafe_origin",
+					AllowMethods:     "GET, PUT",
+					AllowCredentials: true,
+					ExposeHeaders:    "X-Request-Id",
+					MaxAge:           3600,
+					VaryOrigin:       true,
+				},
+			},
+			Locations: []Location{
+				{
+					Path:                     "/tea",
+					ProxyPass:                "http://vs_default_cafe_tea",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxyPassRequestHeaders:  true,
+					ServiceName:              "tea",
+					CORS: &CORS{
+						PreflightPath:    "/internal_location_cors_default_cors-policy",
+						AllowOrigin:      "$pol_cors_default_cors_policy_default_cafe_origin",
+						AllowMethods:     "GET, PUT",
+						AllowCredentials: true,
+						ExposeHeaders:    "X-Request-Id",
+						MaxAge:           3600,
+						VaryOrigin:       true,
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithExternalAuth = VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "vs_default_cafe_tea",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.20:8001",
+					},
+				},
+			},
+			{
+				Name: "vs_default_cafe_extauth_default_ext-auth-policy",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.40:8080",
+					},
+				},
+			},
+		},
+		Server: Server{
+			ServerName:  "cafe.example.com",
+			StatusZone:  "cafe.example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			ExternalAuthLocations: []ExternalAuth{
+				{
+					Path:        "/internal_location_extauth_default_ext-auth-policy",
+					ProxyPass:   "http://vs_default_cafe_extauth_default_ext-auth-policy/auth",
+					ServiceName: "auth-svc",
+					RequestHeaders: []Header{
+						{
+							Name:  "Authorization",
+							Value: "$http_authorization",
+						},
+					},
+					ResponseHeaders: []ExternalAuthResponseHeader{
+						{
+							Name:             "X-User-Id",
+							Variable:         "$extauth_x_user_id",
+							UpstreamVariable: "$upstream_http_x_user_id",
+						},
+					},
+				},
+			},
+			Locations: []Location{
+				{
+					Path:                     "/tea",
+					ProxyPass:                "http://vs_default_cafe_tea",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxyPassRequestHeaders:  true,
+					ServiceName:              "tea",
+					ExternalAuth: &ExternalAuth{
+						Path:        "/internal_location_extauth_default_ext-auth-policy",
+						ProxyPass:   "http://vs_default_cafe_extauth_default_ext-auth-policy/auth",
+						ServiceName: "auth-svc",
+						ResponseHeaders: []ExternalAuthResponseHeader{
+							{
+								Name:             "X-User-Id",
+								Variable:         "$extauth_x_user_id",
+								UpstreamVariable: "$upstream_http_x_user_id",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithAPIKey = VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "vs_default_cafe_tea",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.20:8001",
+					},
+				},
+			},
+		},
+		Maps: []Map{
+			{
+				Source:   "$apikey_auth_hash",
+				Variable: "$apikey_auth_client_name_default_api_key_policy_default_cafe",
+				Parameters: []Parameter{
+					{
+						Value:  `"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`,
+						Result: `"client1"`,
+					},
+					{
+						Value:  "default",
+						Result: `""`,
+					},
+				},
+			},
+		},
+		Server: Server{
+			ServerName:  "cafe.example.com",
+			StatusZone:  "cafe.example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Locations: []Location{
+				{
+					Path:                     "/tea",
+					ProxyPass:                "http://vs_default_cafe_tea",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxyPassRequestHeaders:  true,
+					ServiceName:              "tea",
+					APIKey: &APIKey{
+						Sources:        []string{"$http_x_api_key", "$arg_apikey"},
+						ClientVariable: "$apikey_auth_client_name_default_api_key_policy_default_cafe",
+						RejectCode:     403,
+					},
+				},
+			},
+		},
+	}
+
 	transportServerCfg = TransportServerConfig{
 		Upstreams: []StreamUpstream{
 			{
@@ -3709,6 +5086,44 @@ var (
 		},
 	}
 
+	transportServerCfgWithPolicies = TransportServerConfig{
+		Upstreams: []StreamUpstream{
+			{
+				Name: "tcp-upstream",
+				Servers: []StreamUpstreamServer{
+					{
+						Address: "10.0.0.20:5001",
+					},
+				},
+			},
+		},
+		Server: StreamServer{
+			Port:                1234,
+			StatusZone:          "tcp-app",
+			ProxyPass:           "tcp-upstream",
+			ProxyTimeout:        "10s",
+			ProxyConnectTimeout: "10s",
+			Allow:               []string{"10.0.0.0/8"},
+			LimitConnOptions: LimitConnOptions{
+				DryRun:   true,
+				LogLevel: "warn",
+			},
+			LimitConns: []LimitConn{
+				{
+					ZoneName:    "pol_cl_default_conn-limit_default_tcp-app",
+					Connections: 10,
+				},
+			},
+		},
+		LimitConnZones: []LimitConnZone{
+			{
+				Key:      "$binary_remote_addr",
+				ZoneName: "pol_cl_default_conn-limit_default_tcp-app",
+				ZoneSize: "10m",
+			},
+		},
+	}
+
 	transportServerCfgWithResolver = TransportServerConfig{
 		Upstreams: []StreamUpstream{
 			{
@@ -1,8 +1,13 @@
 package configs
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -11,6 +16,7 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -76,8 +82,7 @@ type PodInfo struct {
 // VirtualServerEx holds a VirtualServer along with the resources that are referenced in this VirtualServer.
 type VirtualServerEx struct {
 	VirtualServer       *conf_v1.VirtualServer
-	HTTPPort            int
-	HTTPSPort           int
+	Listeners           []conf_v1alpha1.Listener
 	Endpoints           map[string][]string
 	VirtualServerRoutes []*conf_v1.VirtualServerRoute
 	ExternalNameSvcs    map[string]bool
@@ -175,18 +180,24 @@ func (namer *upstreamNamer) GetNameForUpstream(upstream string) string {
 }
 
 type variableNamer struct {
+	prefix     string
 	safeNsName string
 }
 
 func newVariableNamer(virtualServer *conf_v1.VirtualServer) *variableNamer {
 	safeNsName := strings.ReplaceAll(fmt.Sprintf("%s_%s", virtualServer.Namespace, virtualServer.Name), "-", "_")
 	return &variableNamer{
+		prefix:     "vs",
 		safeNsName: safeNsName,
 	}
 }
 
 func (namer *variableNamer) GetNameForSplitClientVariable(index int) string {
-	return fmt.Sprintf("$vs_%s_splits_%d", namer.safeNsName, index)
+	return fmt.Sprintf("$%s_%s_splits_%d", namer.prefix, namer.safeNsName, index)
+}
+
+func (namer *variableNamer) GetNameForMirrorVariable(index int) string {
+	return fmt.Sprintf("$%s_%s_mirror_%d", namer.prefix, namer.safeNsName, index)
 }
 
 func (namer *variableNamer) GetNameForVariableForMatchesRouteMap(
@@ -194,11 +205,11 @@ func (namer *variableNamer) GetNameForVariableForMatchesRouteMap(
 	matchIndex int,
 	conditionIndex int,
 ) string {
-	return fmt.Sprintf("$vs_%s_matches_%d_match_%d_cond_%d", namer.safeNsName, matchesIndex, matchIndex, conditionIndex)
+	return fmt.Sprintf("$%s_%s_matches_%d_match_%d_cond_%d", namer.prefix, namer.safeNsName, matchesIndex, matchIndex, conditionIndex)
 }
 
 func (namer *variableNamer) GetNameForVariableForMatchesRouteMainMap(matchesIndex int) string {
-	return fmt.Sprintf("$vs_%s_matches_%d", namer.safeNsName, matchesIndex)
+	return fmt.Sprintf("$%s_%s_matches_%d", namer.prefix, namer.safeNsName, matchesIndex)
 }
 
 func newHealthCheckWithDefaults(upstream conf_v1.Upstream, upstreamName string, cfgParams *ConfigParams) *version2.HealthCheck {
@@ -319,6 +330,18 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	}
 
 	sslConfig := vsc.generateSSLConfig(vsEx.VirtualServer, vsEx.VirtualServer.Spec.TLS, vsEx.VirtualServer.Namespace, vsEx.SecretRefs, vsc.cfgParams)
+	if tls := vsEx.VirtualServer.Spec.TLS; sslConfig == nil && tls != nil && tls.HTTP3 != nil && *tls.HTTP3 {
+		vsc.addWarningf(vsEx.VirtualServer, "HTTP/3 requires TLS termination, tls.http3 is ignored")
+	}
+
+	listeners := generateListeners(vsEx.Listeners, vsc.cfgParams, generateHTTP3(vsEx.VirtualServer.Spec.TLS, vsc.cfgParams))
+	if sslConfig != nil && useCustomListeners {
+		// With custom listeners, the server accepts HTTP/3 only on the listeners that enable it.
+		sslConfig.HTTP3 = false
+		for _, l := range listeners {
+			sslConfig.HTTP3 = sslConfig.HTTP3 || l.HTTP3
+		}
+	}
 	tlsRedirectConfig := generateTLSRedirectConfig(vsEx.VirtualServer.Spec.TLS)
 
 	policyOpts := policyOptions{
@@ -362,6 +385,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 	limitReqZones = append(limitReqZones, policiesCfg.LimitReqZones...)
 
+	var limitConnZones []version2.LimitConnZone
+	limitConnZones = append(limitConnZones, policiesCfg.LimitConnZones...)
+
+	var maps []version2.Map
+	maps = append(maps, policiesCfg.Maps...)
+
+	var geoIP2 []version2.GeoIP2
+	geoIP2 = append(geoIP2, policiesCfg.GeoIP2...)
+
 	// generate upstreams for VirtualServer
 	for _, u := range vsEx.VirtualServer.Spec.Upstreams {
 
@@ -422,11 +454,12 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		}
 	}
 
+	upstreams = append(upstreams, vsc.generateExternalAuthUpstreams(vsEx)...)
+
 	var locations []version2.Location
 	var internalRedirectLocations []version2.InternalRedirectLocation
 	var returnLocations []version2.ReturnLocation
 	var splitClients []version2.SplitClient
-	var maps []version2.Map
 	var errorPageLocations []version2.ErrorPageLocation
 	vsrErrorPagesFromVs := make(map[string][]conf_v1.ErrorPage)
 	vsrErrorPagesRouteIndex := make(map[string]int)
@@ -483,6 +516,21 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		if policiesCfg.OIDC {
 			routePoliciesCfg.OIDC = policiesCfg.OIDC
 		}
+		if routePoliciesCfg.CORS == nil {
+			routePoliciesCfg.CORS = policiesCfg.CORS
+		}
+		if routePoliciesCfg.ExternalAuth == nil {
+			routePoliciesCfg.ExternalAuth = policiesCfg.ExternalAuth
+		}
+		if routePoliciesCfg.APIKey == nil {
+			routePoliciesCfg.APIKey = policiesCfg.APIKey
+		}
+		if routePoliciesCfg.Geo == nil {
+			routePoliciesCfg.Geo = policiesCfg.Geo
+		}
+		if !vsc.isPlus {
+			routePoliciesCfg = vsc.checkAuthRequestPolicies(ownerDetails.owner, r.Path, routePoliciesCfg, policiesCfg.JWTAuth)
+		}
 		if routePoliciesCfg.JWKSAuthEnabled {
 			policiesCfg.JWKSAuthEnabled = routePoliciesCfg.JWKSAuthEnabled
 
@@ -496,6 +544,9 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			}
 		}
 		limitReqZones = append(limitReqZones, routePoliciesCfg.LimitReqZones...)
+		limitConnZones = append(limitConnZones, routePoliciesCfg.LimitConnZones...)
+		maps = append(maps, routePoliciesCfg.Maps...)
+		geoIP2 = append(geoIP2, routePoliciesCfg.GeoIP2...)
 
 		dosRouteCfg := generateDosCfg(dosResources[r.Path])
 
@@ -542,6 +593,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 			loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
 				proxySSLName, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings)
+			loc.Mirror = generateMirror(r.Path, r.Action, virtualServerUpstreamNamer, crUpstreams)
 			addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 			loc.Dos = dosRouteCfg
 
@@ -605,6 +657,21 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			if policiesCfg.OIDC {
 				routePoliciesCfg.OIDC = policiesCfg.OIDC
 			}
+			if routePoliciesCfg.CORS == nil {
+				routePoliciesCfg.CORS = policiesCfg.CORS
+			}
+			if routePoliciesCfg.ExternalAuth == nil {
+				routePoliciesCfg.ExternalAuth = policiesCfg.ExternalAuth
+			}
+			if routePoliciesCfg.APIKey == nil {
+				routePoliciesCfg.APIKey = policiesCfg.APIKey
+			}
+			if routePoliciesCfg.Geo == nil {
+				routePoliciesCfg.Geo = policiesCfg.Geo
+			}
+			if !vsc.isPlus {
+				routePoliciesCfg = vsc.checkAuthRequestPolicies(ownerDetails.owner, r.Path, routePoliciesCfg, policiesCfg.JWTAuth)
+			}
 			if routePoliciesCfg.JWKSAuthEnabled {
 				policiesCfg.JWKSAuthEnabled = routePoliciesCfg.JWKSAuthEnabled
 
@@ -618,6 +685,9 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				}
 			}
 			limitReqZones = append(limitReqZones, routePoliciesCfg.LimitReqZones...)
+			limitConnZones = append(limitConnZones, routePoliciesCfg.LimitConnZones...)
+			maps = append(maps, routePoliciesCfg.Maps...)
+			geoIP2 = append(geoIP2, routePoliciesCfg.GeoIP2...)
 
 			dosRouteCfg := generateDosCfg(dosResources[r.Path])
 
@@ -665,6 +735,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 				loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
 					proxySSLName, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings)
+				loc.Mirror = generateMirror(r.Path, r.Action, upstreamNamer, crUpstreams)
 				addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 				loc.Dos = dosRouteCfg
 
@@ -676,6 +747,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		}
 	}
 
+	splitClients = append(splitClients, generateMirrorLocations(locations, variableNamer)...)
+
 	httpSnippets := generateSnippets(vsc.enableSnippets, vsEx.VirtualServer.Spec.HTTPSnippets, []string{})
 	serverSnippets := generateSnippets(
 		vsc.enableSnippets,
@@ -683,20 +756,29 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		vsc.cfgParams.ServerSnippets,
 	)
 
+	addJWTClaimsErrorPages(policiesCfg.JWTAuth, locations)
+
+	jwtVerifierLocations := generateJWTVerifierLocations(policiesCfg.JWTAuth, locations)
+	var jwtClaimNames []string
+	if !vsc.isPlus {
+		jwtClaimNames = generateJWTClaimNames(vsEx, len(jwtVerifierLocations) > 0)
+	}
+
 	vsCfg := version2.VirtualServerConfig{
-		Upstreams:     upstreams,
-		SplitClients:  splitClients,
-		Maps:          maps,
-		StatusMatches: statusMatches,
-		LimitReqZones: removeDuplicateLimitReqZones(limitReqZones),
-		HTTPSnippets:  httpSnippets,
+		Upstreams:      upstreams,
+		SplitClients:   splitClients,
+		Maps:           removeDuplicateMaps(maps),
+		GeoIP2:         removeDuplicateGeoIP2(geoIP2),
+		StatusMatches:  statusMatches,
+		LimitReqZones:  removeDuplicateLimitReqZones(limitReqZones),
+		LimitConnZones: removeDuplicateLimitConnZones(limitConnZones),
+		HTTPSnippets:   httpSnippets,
 		Server: version2.Server{
 			ServerName:                vsEx.VirtualServer.Spec.Host,
 			Gunzip:                    vsEx.VirtualServer.Spec.Gunzip,
 			StatusZone:                vsEx.VirtualServer.Spec.Host,
-			HTTPPort:                  vsEx.HTTPPort,
-			HTTPSPort:                 vsEx.HTTPSPort,
 			CustomListeners:           useCustomListeners,
+			Listeners:                 listeners,
 			ProxyProtocol:             vsc.cfgParams.ProxyProtocol,
 			SSL:                       sslConfig,
 			ServerTokens:              vsc.cfgParams.ServerTokens,
@@ -706,7 +788,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			Snippets:                  serverSnippets,
 			InternalRedirectLocations: internalRedirectLocations,
 			Locations:                 locations,
-			ReturnLocations:           returnLocations,
+			ReturnLocations:           append(returnLocations, generateJWTClaimsRejectLocations(policiesCfg.JWTAuth, locations)...),
+			CORSPreflightLocations:    generateCORSPreflightLocations(locations),
+			ExternalAuthLocations:     generateExternalAuthLocations(locations),
+			JWTVerifierLocations:      jwtVerifierLocations,
+			JWTClaimNames:             jwtClaimNames,
 			HealthChecks:              healthChecks,
 			TLSRedirect:               tlsRedirectConfig,
 			ErrorPageLocations:        errorPageLocations,
@@ -715,6 +801,9 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			Deny:                      policiesCfg.Deny,
 			LimitReqOptions:           policiesCfg.LimitReqOptions,
 			LimitReqs:                 policiesCfg.LimitReqs,
+			LimitConnOptions:          policiesCfg.LimitConnOptions,
+			LimitConns:                policiesCfg.LimitConns,
+			LimitRate:                 policiesCfg.LimitRate,
 			JWTAuth:                   policiesCfg.JWTAuth,
 			BasicAuth:                 policiesCfg.BasicAuth,
 			JWTAuthList:               policiesCfg.JWTAuthList,
@@ -737,20 +826,30 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 }
 
 type policiesCfg struct {
-	Allow           []string
-	Deny            []string
-	LimitReqOptions version2.LimitReqOptions
-	LimitReqZones   []version2.LimitReqZone
-	LimitReqs       []version2.LimitReq
-	JWTAuth         *version2.JWTAuth
-	JWTAuthList     map[string]*version2.JWTAuth
-	JWKSAuthEnabled bool
-	BasicAuth       *version2.BasicAuth
-	IngressMTLS     *version2.IngressMTLS
-	EgressMTLS      *version2.EgressMTLS
-	OIDC            bool
-	WAF             *version2.WAF
-	ErrorReturn     *version2.Return
+	Allow            []string
+	Deny             []string
+	LimitReqOptions  version2.LimitReqOptions
+	LimitReqZones    []version2.LimitReqZone
+	LimitReqs        []version2.LimitReq
+	LimitConnOptions version2.LimitConnOptions
+	LimitConnZones   []version2.LimitConnZone
+	LimitConns       []version2.LimitConn
+	LimitRate        *version2.LimitRate
+	JWTAuth          *version2.JWTAuth
+	JWTAuthList      map[string]*version2.JWTAuth
+	JWKSAuthEnabled  bool
+	BasicAuth        *version2.BasicAuth
+	IngressMTLS      *version2.IngressMTLS
+	EgressMTLS       *version2.EgressMTLS
+	OIDC             bool
+	WAF              *version2.WAF
+	CORS             *version2.CORS
+	ExternalAuth     *version2.ExternalAuth
+	APIKey           *version2.APIKey
+	Geo              *version2.Geo
+	GeoIP2           []version2.GeoIP2
+	Maps             []version2.Map
+	ErrorReturn      *version2.Return
 }
 
 func newPoliciesConfig() *policiesCfg {
@@ -805,9 +904,14 @@ func (p *policiesCfg) addRateLimitConfig(
 ) *validationResults {
 	res := newValidationResults()
 	rlZoneName := fmt.Sprintf("pol_rl_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
-	p.LimitReqs = append(p.LimitReqs, generateLimitReq(rlZoneName, rateLimit))
-	p.LimitReqZones = append(p.LimitReqZones, generateLimitReqZone(rlZoneName, rateLimit))
-	if len(p.LimitReqs) == 1 {
+	isFirstPolicy := len(p.LimitReqs) == 0
+	if len(rateLimit.Tiers) > 0 {
+		p.addRateLimitTiers(rlZoneName, rateLimit)
+	} else {
+		p.LimitReqs = append(p.LimitReqs, generateLimitReq(rlZoneName, rateLimit))
+		p.LimitReqZones = append(p.LimitReqZones, generateLimitReqZone(rlZoneName, rateLimit))
+	}
+	if isFirstPolicy {
 		p.LimitReqOptions = generateLimitReqOptions(rateLimit)
 	} else {
 		curOptions := generateLimitReqOptions(rateLimit)
@@ -824,6 +928,124 @@ func (p *policiesCfg) addRateLimitConfig(
 	return res
 }
 
+// addRateLimitTiers adds a zone for every tier of the rate limit policy. The key of a zone is a map
+// that is resolved to the policy key for the requests of the tier and to an empty value, which is not
+// accounted, for the requests of the other tiers.
+func (p *policiesCfg) addRateLimitTiers(rlZoneName string, rateLimit *conf_v1.RateLimit) {
+	selector := "$" + strings.TrimSuffix(strings.TrimPrefix(rateLimit.TierSelector, "${"), "}")
+	key := fmt.Sprintf(`"%s"`, rateLimit.Key)
+
+	for _, tier := range rateLimit.Tiers {
+		zoneName := fmt.Sprintf("%s_%s", rlZoneName, tier.Name)
+		keyVariable := "$" + strings.NewReplacer("-", "_", ".", "_").Replace(zoneName)
+
+		var params []version2.Parameter
+		if tier.Name == rateLimit.DefaultTier {
+			for _, t := range rateLimit.Tiers {
+				if t.Name != tier.Name {
+					params = append(params, version2.Parameter{
+						Value:  generateValueForRateLimitTierMap(t.Name),
+						Result: `""`,
+					})
+				}
+			}
+			params = append(params, version2.Parameter{
+				Value:  "default",
+				Result: key,
+			})
+		} else {
+			params = append(params, version2.Parameter{
+				Value:  generateValueForRateLimitTierMap(tier.Name),
+				Result: key,
+			}, version2.Parameter{
+				Value:  "default",
+				Result: `""`,
+			})
+		}
+
+		p.Maps = append(p.Maps, version2.Map{
+			Source:     selector,
+			Variable:   keyVariable,
+			Parameters: params,
+		})
+
+		limitReq := generateLimitReq(zoneName, rateLimit)
+		if tier.Burst != nil {
+			limitReq.Burst = *tier.Burst
+		}
+		p.LimitReqs = append(p.LimitReqs, limitReq)
+		p.LimitReqZones = append(p.LimitReqZones, version2.LimitReqZone{
+			ZoneName: zoneName,
+			Key:      keyVariable,
+			ZoneSize: rateLimit.ZoneSize,
+			Rate:     tier.Rate,
+		})
+	}
+}
+
+// generateValueForRateLimitTierMap escapes the tier names that are special parameters of the map block,
+// because NGINX strips the quotes of the values.
+func generateValueForRateLimitTierMap(tierName string) string {
+	if _, exists := specialMapParameters[tierName]; exists {
+		return `\` + tierName
+	}
+
+	return fmt.Sprintf(`"%s"`, tierName)
+}
+
+func (p *policiesCfg) addBandwidthLimitConfig(
+	bandwidthLimit *conf_v1.BandwidthLimit,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+
+	if bandwidthLimit.Rate != "" {
+		if p.LimitRate != nil {
+			res.addWarningf("BandwidthLimit policy %s with rate='%v' is overridden to rate='%v' by the first policy reference in this context", polKey, bandwidthLimit.Rate, p.LimitRate.Rate)
+		} else {
+			p.LimitRate = &version2.LimitRate{
+				Rate:  bandwidthLimit.Rate,
+				After: bandwidthLimit.RateAfter,
+			}
+		}
+	}
+
+	if bandwidthLimit.Connections == 0 {
+		return res
+	}
+
+	zoneName := fmt.Sprintf("pol_bl_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+	p.LimitConnZones = append(p.LimitConnZones, version2.LimitConnZone{
+		Key:      bandwidthLimit.Key,
+		ZoneName: zoneName,
+		ZoneSize: bandwidthLimit.ZoneSize,
+	})
+	p.LimitConns = append(p.LimitConns, version2.LimitConn{
+		ZoneName:    zoneName,
+		Connections: bandwidthLimit.Connections,
+	})
+
+	options := generateBandwidthLimitConnOptions(bandwidthLimit)
+	if len(p.LimitConns) == 1 {
+		p.LimitConnOptions = options
+		return res
+	}
+	if options.DryRun != p.LimitConnOptions.DryRun {
+		res.addWarningf("BandwidthLimit policy %s with limit connection option dryRun='%v' is overridden to dryRun='%v' by the first policy reference in this context", polKey, options.DryRun, p.LimitConnOptions.DryRun)
+	}
+	if options.LogLevel != p.LimitConnOptions.LogLevel {
+		res.addWarningf("BandwidthLimit policy %s with limit connection option logLevel='%v' is overridden to logLevel='%v' by the first policy reference in this context", polKey, options.LogLevel, p.LimitConnOptions.LogLevel)
+	}
+	if options.RejectCode != p.LimitConnOptions.RejectCode {
+		res.addWarningf("BandwidthLimit policy %s with limit connection option rejectCode='%v' is overridden to rejectCode='%v' by the first policy reference in this context", polKey, options.RejectCode, p.LimitConnOptions.RejectCode)
+	}
+	return res
+}
+
 func (p *policiesCfg) addBasicAuthConfig(
 	basicAuth *conf_v1.BasicAuth,
 	polKey string,
@@ -863,13 +1085,39 @@ func (p *policiesCfg) addJWTAuthConfig(
 	jwtAuth *conf_v1.JWTAuth,
 	polKey string,
 	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
 	secretRefs map[string]*secrets.SecretReference,
+	isPlus bool,
 ) *validationResults {
 	res := newValidationResults()
 	if p.JWTAuth != nil {
 		res.addWarningf("Multiple jwt policies in the same context is not valid. JWT policy %s will be ignored", polKey)
 		return res
 	}
+	if !isPlus && p.ExternalAuth != nil {
+		res.addWarningf("JWT policy %s can't be combined with an external auth policy in the same context in NGINX and will be ignored", polKey)
+		return res
+	}
+
+	// NGINX doesn't support auth_jwt, so the token is verified by the njs module in a subrequest.
+	var verifier *version2.JWTVerifier
+	if !isPlus {
+		if jwtAuth.Claims != nil {
+			for _, m := range jwtAuth.Claims.Match {
+				if err := validateNJSRegex(m.Regex); err != nil {
+					res.addWarningf("JWT policy %s has a regex for the claim %s that is not supported in NGINX: %v", polKey, m.Name, err)
+					res.isError = true
+					return res
+				}
+			}
+		}
+		verifier = &version2.JWTVerifier{
+			Path:        fmt.Sprintf("/%vjwtauth_%v_%v", internalLocationPrefix, polNamespace, polName),
+			TokenSource: strings.TrimPrefix(jwtAuth.Token, "$"),
+		}
+	}
 	if jwtAuth.Secret != "" {
 		jwtSecretKey := fmt.Sprintf("%v/%v", polNamespace, jwtAuth.Secret)
 		secretRef := secretRefs[jwtSecretKey]
@@ -888,10 +1136,12 @@ func (p *policiesCfg) addJWTAuthConfig(
 		}
 
 		p.JWTAuth = &version2.JWTAuth{
-			Secret: secretRef.Path,
-			Realm:  jwtAuth.Realm,
-			Token:  jwtAuth.Token,
+			Secret:   secretRef.Path,
+			Realm:    jwtAuth.Realm,
+			Token:    jwtAuth.Token,
+			Verifier: verifier,
 		}
+		p.addJWTClaims(jwtAuth.Claims, polNamespace, polName, vsNamespace, vsName)
 		return res
 	} else if jwtAuth.JwksURI != "" {
 		uri, _ := url.Parse(jwtAuth.JwksURI)
@@ -909,13 +1159,150 @@ func (p *policiesCfg) addJWTAuthConfig(
 			Realm:    jwtAuth.Realm,
 			Token:    jwtAuth.Token,
 			KeyCache: jwtAuth.KeyCache,
+			Verifier: verifier,
 		}
 		p.JWKSAuthEnabled = true
+		p.addJWTClaims(jwtAuth.Claims, polNamespace, polName, vsNamespace, vsName)
 		return res
 	}
 	return res
 }
 
+// addJWTClaims adds the maps that check the claims of the JWT. Every map is resolved to 1 if the claim
+// meets the requirement, so that the request is authorized only if all the variables are set to 1.
+// In NGINX the requirements are passed to the njs module of the verifier instead.
+func (p *policiesCfg) addJWTClaims(claims *conf_v1.JWTClaims, polNamespace, polName, vsNamespace, vsName string) {
+	if claims == nil {
+		return
+	}
+
+	variablePrefix := fmt.Sprintf("$pol_jwt_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+	variablePrefix = strings.NewReplacer("-", "_", ".", "_").Replace(variablePrefix)
+
+	p.JWTAuth.Claims = &version2.JWTClaims{
+		RejectBody: claims.RejectBody,
+	}
+	if claims.RejectBody != "" {
+		p.JWTAuth.Claims.RejectLocation = "@jwt_claims_reject" + strings.TrimPrefix(variablePrefix, "$pol_jwt")
+	}
+
+	var requirements []jwtClaimRequirement
+	if claims.Issuer != "" {
+		requirements = append(requirements, jwtClaimRequirement{
+			Claim:    "iss",
+			Regexes:  []string{"^" + regexp.QuoteMeta(claims.Issuer) + "$"},
+			variable: variablePrefix + "_iss",
+		})
+	}
+	if claims.Audience != "" {
+		requirements = append(requirements, jwtClaimRequirement{
+			Claim:    "aud",
+			Regexes:  []string{jwtClaimListElementRegex(claims.Audience)},
+			variable: variablePrefix + "_aud",
+		})
+	}
+	for i, scope := range claims.Scopes {
+		requirements = append(requirements, jwtClaimRequirement{
+			Claim:    "scope",
+			Regexes:  []string{jwtClaimListElementRegex(scope)},
+			variable: fmt.Sprintf("%s_scope_%d", variablePrefix, i),
+		})
+	}
+	if len(claims.Roles) > 0 {
+		rolesClaim := claims.RolesClaim
+		if rolesClaim == "" {
+			rolesClaim = "roles"
+		}
+		var regexes []string
+		for _, role := range claims.Roles {
+			regexes = append(regexes, jwtClaimListElementRegex(role))
+		}
+		requirements = append(requirements, jwtClaimRequirement{
+			Claim:    rolesClaim,
+			Regexes:  regexes,
+			variable: variablePrefix + "_roles",
+		})
+	}
+	for i, m := range claims.Match {
+		regex := m.Regex
+		if m.Value != "" {
+			regex = "^" + regexp.QuoteMeta(m.Value) + "$"
+		}
+		requirements = append(requirements, jwtClaimRequirement{
+			Claim:    m.Name,
+			Regexes:  []string{regex},
+			variable: fmt.Sprintf("%s_claim_%d", variablePrefix, i),
+		})
+	}
+
+	// In NGINX the claims are checked by the njs module, which gets the requirements from the verifier location.
+	if p.JWTAuth.Verifier != nil {
+		data, _ := json.Marshal(requirements)
+		p.JWTAuth.Verifier.Claims = base64.StdEncoding.EncodeToString(data)
+		return
+	}
+
+	for _, r := range requirements {
+		p.addJWTClaimMap(r)
+	}
+}
+
+// jwtClaimRequirement defines a requirement of a JWT policy for a claim, which is met if the claim matches any of the regular expressions.
+// In NGINX Plus the requirement is checked by a map that sets variable.
+type jwtClaimRequirement struct {
+	Claim    string   `json:"claim"`
+	Regexes  []string `json:"regexes"`
+	variable string
+}
+
+// addJWTClaimMap adds a map that is resolved to 1 if the claim matches any of the regular expressions.
+func (p *policiesCfg) addJWTClaimMap(requirement jwtClaimRequirement) {
+	var params []version2.Parameter
+	for _, r := range requirement.Regexes {
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf(`"~%s"`, r),
+			Result: "1",
+		})
+	}
+	params = append(params, version2.Parameter{
+		Value:  "default",
+		Result: "0",
+	})
+
+	p.Maps = append(p.Maps, version2.Map{
+		Source:     "$jwt_claim_" + requirement.Claim,
+		Variable:   requirement.variable,
+		Parameters: params,
+	})
+	p.JWTAuth.Claims.RequiredVariables = append(p.JWTAuth.Claims.RequiredVariables, requirement.variable)
+}
+
+// jwtClaimListElementRegex returns a regular expression that matches an element of a claim.
+// NGINX Plus joins the elements of an array claim with commas, while the scope claim is a space-delimited string.
+func jwtClaimListElementRegex(value string) string {
+	return fmt.Sprintf(`(^|[\s,])%s([\s,]|$)`, regexp.QuoteMeta(value))
+}
+
+// validateNJSRegex checks that a regular expression of a claim requirement can be compiled by the RegExp of njs,
+// which doesn't support the inline flags, the POSIX character classes and some of the escape sequences of the
+// syntax of the regular expressions of the policy.
+func validateNJSRegex(regex string) error {
+	for i := 0; i < len(regex); i++ {
+		switch {
+		case regex[i] == '\\' && i+1 < len(regex):
+			i++
+			if strings.IndexByte("AzQEpP", regex[i]) >= 0 {
+				return fmt.Errorf("%q is not supported", regex[i-1:i+1])
+			}
+		case strings.HasPrefix(regex[i:], "[[:"):
+			return fmt.Errorf("POSIX character classes are not supported")
+		case strings.HasPrefix(regex[i:], "(?") && i+2 < len(regex) && strings.IndexByte(":=!<", regex[i+2]) < 0:
+			return fmt.Errorf("%q is not supported", regex[i:i+3])
+		}
+	}
+	return nil
+}
+
 func (p *policiesCfg) addIngressMTLSConfig(
 	ingressMTLS *conf_v1.IngressMTLS,
 	polKey string,
@@ -1134,18 +1521,25 @@ func (p *policiesCfg) addOIDCConfig(
 		if oidc.AuthExtraArgs != nil {
 			authExtraArgs = strings.Join(oidc.AuthExtraArgs, "&")
 		}
+		postLogoutRedirectURI := oidc.PostLogoutRedirectURI
+		if postLogoutRedirectURI == "" {
+			postLogoutRedirectURI = "/_logout"
+		}
 
 		oidcPolCfg.oidc = &version2.OIDC{
-			AuthEndpoint:      oidc.AuthEndpoint,
-			AuthExtraArgs:     authExtraArgs,
-			TokenEndpoint:     oidc.TokenEndpoint,
-			JwksURI:           oidc.JWKSURI,
-			ClientID:          oidc.ClientID,
-			ClientSecret:      string(clientSecret),
-			Scope:             scope,
-			RedirectURI:       redirectURI,
-			ZoneSyncLeeway:    generateIntFromPointer(oidc.ZoneSyncLeeway, 200),
-			AccessTokenEnable: oidc.AccessTokenEnable,
+			AuthEndpoint:          oidc.AuthEndpoint,
+			AuthExtraArgs:         authExtraArgs,
+			TokenEndpoint:         oidc.TokenEndpoint,
+			JwksURI:               oidc.JWKSURI,
+			ClientID:              oidc.ClientID,
+			ClientSecret:          string(clientSecret),
+			Scope:                 scope,
+			RedirectURI:           redirectURI,
+			ZoneSyncLeeway:        generateIntFromPointer(oidc.ZoneSyncLeeway, 200),
+			AccessTokenEnable:     oidc.AccessTokenEnable,
+			EndSessionEndpoint:    oidc.EndSessionEndpoint,
+			PostLogoutRedirectURI: postLogoutRedirectURI,
+			PKCEEnable:            oidc.PKCEEnable,
 		}
 		oidcPolCfg.key = polKey
 	}
@@ -1233,6 +1627,340 @@ func (p *policiesCfg) addWAFConfig(
 	return res
 }
 
+func (p *policiesCfg) addCORSConfig(
+	cors *conf_v1.CORS,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.CORS != nil {
+		res.addWarningf("Multiple CORS policies in the same context is not valid. CORS policy %s will be ignored", polKey)
+		return res
+	}
+
+	p.CORS = &version2.CORS{
+		PreflightPath:    fmt.Sprintf("/%vcors_%v_%v", internalLocationPrefix, polNamespace, polName),
+		AllowOrigin:      "*",
+		AllowMethods:     generateString(strings.Join(cors.AllowMethods, ", "), "GET, HEAD, POST"),
+		AllowHeaders:     strings.Join(cors.AllowHeaders, ", "),
+		ExposeHeaders:    strings.Join(cors.ExposeHeaders, ", "),
+		AllowCredentials: generateBool(cors.AllowCredentials, false),
+		MaxAge:           generateIntFromPointer(cors.MaxAge, 0),
+	}
+
+	if len(cors.AllowOrigins) == 1 && cors.AllowOrigins[0] == "*" {
+		return res
+	}
+
+	// the allowed origins are matched by a map, so that the origin of a request is
+	// reflected in the response only when it is allowed.
+	originVariable := fmt.Sprintf("$pol_cors_%v_%v_%v_%v_origin", polNamespace, polName, vsNamespace, vsName)
+	originVariable = strings.NewReplacer("-", "_", ".", "_").Replace(originVariable)
+
+	var params []version2.Parameter
+	for _, origin := range cors.AllowOrigins {
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf(`"%s"`, origin),
+			Result: "$http_origin",
+		})
+	}
+	params = append(params, version2.Parameter{
+		Value:  "default",
+		Result: `""`,
+	})
+
+	p.Maps = append(p.Maps, version2.Map{
+		Source:     "$http_origin",
+		Variable:   originVariable,
+		Parameters: params,
+	})
+	p.CORS.AllowOrigin = originVariable
+	p.CORS.VaryOrigin = true
+
+	return res
+}
+
+func (p *policiesCfg) addGeoConfig(
+	geo *conf_v1.Geo,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+	geoIP2Enabled bool,
+) *validationResults {
+	res := newValidationResults()
+	if p.Geo != nil {
+		res.addWarningf("Multiple geo policies in the same context is not valid. Geo policy %s will be ignored", polKey)
+		return res
+	}
+	if !geoIP2Enabled {
+		res.addWarningf("Geo policy %s requires the geoip2 module, which is loaded with the geoip2 ConfigMap key", polKey)
+		res.isError = true
+		return res
+	}
+
+	variablePrefix := fmt.Sprintf("$pol_geo_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+	variablePrefix = strings.NewReplacer("-", "_", ".", "_").Replace(variablePrefix)
+
+	// an IP address that is not found in the database is looked up as an empty value.
+	unknownResult := "1"
+	if geo.UnknownAction == "deny" {
+		unknownResult = "0"
+	}
+
+	p.Geo = &version2.Geo{
+		RejectCode: generateIntFromPointer(geo.RejectCode, 403),
+	}
+
+	p.addGeoLookup(geo.CountryDatabase, "country iso_code", variablePrefix+"_country", geo.AllowCountries, geo.DenyCountries, geo.CountryHeader, unknownResult)
+	p.addGeoLookup(geo.ASNDatabase, "autonomous_system_number", variablePrefix+"_asn", formatASNs(geo.AllowASNs), formatASNs(geo.DenyASNs), geo.ASNHeader, unknownResult)
+
+	return res
+}
+
+// addGeoLookup adds the lookup of a value in a GeoIP2 database. The value is passed to the upstream in the header
+// and, if the allowed or denied values are set, is checked by a map that is resolved to 0 for a rejected request.
+func (p *policiesCfg) addGeoLookup(database, path, variable string, allow, deny []string, header, unknownResult string) {
+	if allow == nil && deny == nil && header == "" {
+		return
+	}
+
+	p.GeoIP2 = append(p.GeoIP2, version2.GeoIP2{
+		Database: database,
+		Variables: []version2.GeoIP2Variable{
+			{
+				Name: variable,
+				Path: path,
+			},
+		},
+	})
+
+	if header != "" {
+		p.Geo.Headers = append(p.Geo.Headers, version2.Header{
+			Name:  header,
+			Value: variable,
+		})
+	}
+
+	if allow == nil && deny == nil {
+		return
+	}
+
+	values, matchResult, defaultResult := allow, "1", "0"
+	if deny != nil {
+		values, matchResult, defaultResult = deny, "0", "1"
+	}
+
+	params := []version2.Parameter{
+		{
+			Value:  `""`,
+			Result: unknownResult,
+		},
+	}
+	for _, v := range values {
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf(`"%s"`, v),
+			Result: matchResult,
+		})
+	}
+	params = append(params, version2.Parameter{
+		Value:  "default",
+		Result: defaultResult,
+	})
+
+	allowedVariable := variable + "_allowed"
+	p.Maps = append(p.Maps, version2.Map{
+		Source:     variable,
+		Variable:   allowedVariable,
+		Parameters: params,
+	})
+	p.Geo.AllowedVariables = append(p.Geo.AllowedVariables, allowedVariable)
+}
+
+func formatASNs(asns []int) []string {
+	if asns == nil {
+		return nil
+	}
+	result := make([]string, 0, len(asns))
+	for _, asn := range asns {
+		result = append(result, strconv.Itoa(asn))
+	}
+	return result
+}
+
+func (p *policiesCfg) addAPIKeyConfig(
+	apiKey *conf_v1.APIKey,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+	secretRefs map[string]*secrets.SecretReference,
+) *validationResults {
+	res := newValidationResults()
+	if p.APIKey != nil {
+		res.addWarningf("Multiple API key policies in the same context is not valid. API key policy %s will be ignored", polKey)
+		return res
+	}
+
+	apiKeySecretKey := fmt.Sprintf("%v/%v", polNamespace, apiKey.ClientSecret)
+	secretRef := secretRefs[apiKeySecretKey]
+	var secretType api_v1.SecretType
+	if secretRef.Secret != nil {
+		secretType = secretRef.Secret.Type
+	}
+	if secretType != "" && secretType != secrets.SecretTypeAPIKey {
+		res.addWarningf("API key policy %s references a secret %s of a wrong type '%s', must be '%s'", polKey, apiKeySecretKey, secretType, secrets.SecretTypeAPIKey)
+		res.isError = true
+		return res
+	} else if secretRef.Error != nil {
+		res.addWarningf("API key policy %s references an invalid secret %s: %v", polKey, apiKeySecretKey, secretRef.Error)
+		res.isError = true
+		return res
+	}
+
+	var sources []string
+	if apiKey.SuppliedIn != nil {
+		for _, h := range apiKey.SuppliedIn.Header {
+			sources = append(sources, "$http_"+generateVariableNameForHeader(h))
+		}
+		for _, q := range apiKey.SuppliedIn.Query {
+			sources = append(sources, "$arg_"+q)
+		}
+	}
+
+	// the keys are matched by their hashes, so that the keys themselves are not written to the config.
+	clientVariable := fmt.Sprintf("$apikey_auth_client_name_%v_%v_%v_%v", polNamespace, polName, vsNamespace, vsName)
+	clientVariable = strings.NewReplacer("-", "_", ".", "_").Replace(clientVariable)
+
+	clientIDs := make([]string, 0, len(secretRef.Secret.Data))
+	for clientID := range secretRef.Secret.Data {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	var params []version2.Parameter
+	for _, clientID := range clientIDs {
+		hash := sha256.Sum256(secretRef.Secret.Data[clientID])
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf(`"%x"`, hash),
+			Result: fmt.Sprintf(`"%s"`, clientID),
+		})
+	}
+	params = append(params, version2.Parameter{
+		Value:  "default",
+		Result: `""`,
+	})
+
+	p.Maps = append(p.Maps, version2.Map{
+		Source:     "$apikey_auth_hash",
+		Variable:   clientVariable,
+		Parameters: params,
+	})
+	p.APIKey = &version2.APIKey{
+		Sources:        sources,
+		ClientVariable: clientVariable,
+		RejectCode:     generateIntFromPointer(apiKey.RejectCode, 401),
+	}
+
+	return res
+}
+
+func (p *policiesCfg) addExternalAuthConfig(
+	externalAuth *conf_v1.ExternalAuth,
+	polKey string,
+	polNamespace string,
+	polName string,
+	vsNamespace string,
+	vsName string,
+) *validationResults {
+	res := newValidationResults()
+	if p.ExternalAuth != nil {
+		res.addWarningf("Multiple external auth policies in the same context is not valid. External auth policy %s will be ignored", polKey)
+		return res
+	}
+	if p.JWTAuth != nil && p.JWTAuth.Verifier != nil {
+		res.addWarningf("External auth policy %s can't be combined with a JWT policy in the same context in NGINX and will be ignored", polKey)
+		return res
+	}
+
+	var requestHeaders []version2.Header
+	for _, h := range externalAuth.RequestHeaders {
+		requestHeaders = append(requestHeaders, version2.Header{
+			Name:  h,
+			Value: "$http_" + generateVariableNameForHeader(h),
+		})
+	}
+
+	var responseHeaders []version2.ExternalAuthResponseHeader
+	for _, h := range externalAuth.ResponseHeaders {
+		responseHeaders = append(responseHeaders, version2.ExternalAuthResponseHeader{
+			Name:             h,
+			Variable:         "$extauth_" + generateVariableNameForHeader(h),
+			UpstreamVariable: "$upstream_http_" + generateVariableNameForHeader(h),
+		})
+	}
+
+	upstreamName := getNameForExternalAuthUpstream(vsNamespace, vsName, polNamespace, polName)
+	p.ExternalAuth = &version2.ExternalAuth{
+		Path:            fmt.Sprintf("/%vextauth_%v_%v", internalLocationPrefix, polNamespace, polName),
+		ProxyPass:       fmt.Sprintf("http://%v%v", upstreamName, externalAuth.Path),
+		ServiceName:     externalAuth.Service,
+		RequestHeaders:  requestHeaders,
+		ResponseHeaders: responseHeaders,
+	}
+	return res
+}
+
+// generateVariableNameForHeader returns the suffix of the NGINX variables of a header, like x_user_id for X-User-Id.
+func generateVariableNameForHeader(header string) string {
+	return strings.ToLower(strings.ReplaceAll(header, "-", "_"))
+}
+
+func getNameForExternalAuthUpstream(vsNamespace string, vsName string, polNamespace string, polName string) string {
+	return fmt.Sprintf("vs_%s_%s_extauth_%s_%s", vsNamespace, vsName, polNamespace, polName)
+}
+
+// generateExternalAuthUpstreams generates the upstreams of the auth services of the external auth policies
+// referenced by a VirtualServer and its VirtualServerRoutes.
+func (vsc *virtualServerConfigurator) generateExternalAuthUpstreams(vsEx *VirtualServerEx) []version2.Upstream {
+	var upstreams []version2.Upstream
+
+	for _, key := range getSortedPolicyKeys(vsEx.Policies) {
+		pol := vsEx.Policies[key]
+		if pol.Spec.ExternalAuth == nil {
+			continue
+		}
+
+		u := conf_v1.Upstream{
+			Name:    pol.Name,
+			Service: pol.Spec.ExternalAuth.Service,
+			Port:    pol.Spec.ExternalAuth.Port,
+		}
+		upstreamName := getNameForExternalAuthUpstream(vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name, pol.Namespace, pol.Name)
+		endpoints := vsc.generateEndpointsForUpstream(vsEx.VirtualServer, pol.Namespace, u, vsEx)
+
+		// isExternalNameSvc is always false for OSS
+		_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(pol.Namespace, u.Service)]
+		upstreams = append(upstreams, vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints))
+	}
+
+	return upstreams
+}
+
+func getSortedPolicyKeys(policies map[string]*conf_v1.Policy) []string {
+	keys := make([]string, 0, len(policies))
+	for k := range policies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (vsc *virtualServerConfigurator) generatePolicies(
 	ownerDetails policyOwnerDetails,
 	policyRefs []conf_v1.PolicyReference,
@@ -1265,7 +1993,16 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 					ownerDetails.vsName,
 				)
 			case pol.Spec.JWTAuth != nil:
-				res = config.addJWTAuthConfig(pol.Spec.JWTAuth, key, polNamespace, policyOpts.secretRefs)
+				res = config.addJWTAuthConfig(
+					pol.Spec.JWTAuth,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+					policyOpts.secretRefs,
+					vsc.isPlus,
+				)
 			case pol.Spec.BasicAuth != nil:
 				res = config.addBasicAuthConfig(pol.Spec.BasicAuth, key, polNamespace, policyOpts.secretRefs)
 			case pol.Spec.IngressMTLS != nil:
@@ -1283,6 +2020,56 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 				res = config.addOIDCConfig(pol.Spec.OIDC, key, polNamespace, policyOpts.secretRefs, vsc.oidcPolCfg)
 			case pol.Spec.WAF != nil:
 				res = config.addWAFConfig(pol.Spec.WAF, key, polNamespace, policyOpts.apResources)
+			case pol.Spec.CORS != nil:
+				res = config.addCORSConfig(
+					pol.Spec.CORS,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
+			case pol.Spec.ExternalAuth != nil:
+				res = config.addExternalAuthConfig(
+					pol.Spec.ExternalAuth,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
+			case pol.Spec.APIKey != nil:
+				res = config.addAPIKeyConfig(
+					pol.Spec.APIKey,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+					policyOpts.secretRefs,
+				)
+			case pol.Spec.Geo != nil:
+				res = config.addGeoConfig(
+					pol.Spec.Geo,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+					vsc.cfgParams.MainGeoIP2LoadModule,
+				)
+			case pol.Spec.BandwidthLimit != nil:
+				res = config.addBandwidthLimitConfig(
+					pol.Spec.BandwidthLimit,
+					key,
+					polNamespace,
+					p.Name,
+					ownerDetails.vsNamespace,
+					ownerDetails.vsName,
+				)
+			case pol.Spec.ConnectionLimit != nil:
+				res = newValidationResults()
+				res.addWarningf("ConnectionLimit policy %s is only supported in TransportServer and is ignored", key)
 			default:
 				res = newValidationResults()
 			}
@@ -1303,6 +2090,25 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 	return *config
 }
 
+// checkAuthRequestPolicies checks the policies of a route after the policies of the VirtualServer are inherited.
+// In NGINX, both the JWT and the external auth policies are implemented with auth_request, which can't be repeated
+// in a location, and the auth_request of a location overrides the one of the server. So if a route ends up with
+// both policies, the route returns 500 rather than failing the reload or silently skipping the JWT policy.
+func (vsc *virtualServerConfigurator) checkAuthRequestPolicies(owner runtime.Object, path string, routePoliciesCfg policiesCfg, serverJWTAuth *version2.JWTAuth) policiesCfg {
+	jwtAuth := routePoliciesCfg.JWTAuth
+	if jwtAuth == nil {
+		jwtAuth = serverJWTAuth
+	}
+	if jwtAuth == nil || jwtAuth.Verifier == nil || routePoliciesCfg.ExternalAuth == nil {
+		return routePoliciesCfg
+	}
+
+	vsc.addWarningf(owner, "Route %s combines a JWT policy with an external auth policy, which is not supported in NGINX", path)
+	return policiesCfg{
+		ErrorReturn: &version2.Return{Code: 500},
+	}
+}
+
 func generateLimitReq(zoneName string, rateLimitPol *conf_v1.RateLimit) version2.LimitReq {
 	var limitReq version2.LimitReq
 
@@ -1354,16 +2160,247 @@ func removeDuplicateLimitReqZones(rlz []version2.LimitReqZone) []version2.LimitR
 	return result
 }
 
+func generateBandwidthLimitConnOptions(bandwidthLimitPol *conf_v1.BandwidthLimit) version2.LimitConnOptions {
+	return version2.LimitConnOptions{
+		DryRun:     generateBool(bandwidthLimitPol.DryRun, false),
+		LogLevel:   generateString(bandwidthLimitPol.LogLevel, "error"),
+		RejectCode: generateIntFromPointer(bandwidthLimitPol.RejectCode, 503),
+	}
+}
+
+func removeDuplicateLimitConnZones(lcz []version2.LimitConnZone) []version2.LimitConnZone {
+	encountered := make(map[string]bool)
+	var result []version2.LimitConnZone
+
+	for _, v := range lcz {
+		if !encountered[v.ZoneName] {
+			encountered[v.ZoneName] = true
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func removeDuplicateMaps(maps []version2.Map) []version2.Map {
+	encountered := make(map[string]bool)
+	var result []version2.Map
+
+	for _, m := range maps {
+		if !encountered[m.Variable] {
+			encountered[m.Variable] = true
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+func removeDuplicateGeoIP2(geoIP2 []version2.GeoIP2) []version2.GeoIP2 {
+	encountered := make(map[string]bool)
+	var result []version2.GeoIP2
+
+	for _, g := range geoIP2 {
+		if !encountered[g.Variables[0].Name] {
+			encountered[g.Variables[0].Name] = true
+			result = append(result, g)
+		}
+	}
+
+	return result
+}
+
+// addJWTClaimsErrorPages adds the error page that returns the reject body of the JWT policy to the locations with
+// the claim requirements of the policy. The error page is not added to the server, so that the other 403 responses
+// of the server keep their own error pages. It comes before the error pages of the route, so that NGINX, which uses
+// the first error page for a code, returns the reject body when the claim requirements are not met.
+func addJWTClaimsErrorPages(serverJWTAuth *version2.JWTAuth, locations []version2.Location) {
+	for i := range locations {
+		jwtAuth := locations[i].JWTAuth
+		if jwtAuth == nil {
+			jwtAuth = serverJWTAuth
+		}
+		if jwtAuth == nil || jwtAuth.Claims == nil || jwtAuth.Claims.RejectLocation == "" {
+			continue
+		}
+
+		errorPage := version2.ErrorPage{
+			Name:         jwtAuth.Claims.RejectLocation,
+			Codes:        "403",
+			ResponseCode: 403,
+		}
+		locations[i].ErrorPages = append([]version2.ErrorPage{errorPage}, locations[i].ErrorPages...)
+	}
+}
+
+// generateJWTClaimsRejectLocations generates the locations that return the reject body of the JWT policies
+// whose claim requirements are not met.
+func generateJWTClaimsRejectLocations(serverJWTAuth *version2.JWTAuth, locations []version2.Location) []version2.ReturnLocation {
+	jwtAuths := []*version2.JWTAuth{serverJWTAuth}
+	for _, l := range locations {
+		jwtAuths = append(jwtAuths, l.JWTAuth)
+	}
+
+	encountered := make(map[string]bool)
+	var result []version2.ReturnLocation
+
+	for _, j := range jwtAuths {
+		if j == nil || j.Claims == nil || j.Claims.RejectLocation == "" || encountered[j.Claims.RejectLocation] {
+			continue
+		}
+		encountered[j.Claims.RejectLocation] = true
+		result = append(result, version2.ReturnLocation{
+			Name:        j.Claims.RejectLocation,
+			DefaultType: "text/plain",
+			Return: version2.Return{
+				Text: j.Claims.RejectBody,
+			},
+		})
+	}
+
+	return result
+}
+
+// generateJWTVerifierLocations generates the internal locations that verify the JWTs in NGINX OSS
+// for the JWT policies of the server and the locations.
+func generateJWTVerifierLocations(serverJWTAuth *version2.JWTAuth, locations []version2.Location) []version2.JWTAuth {
+	jwtAuths := []*version2.JWTAuth{serverJWTAuth}
+	for _, l := range locations {
+		jwtAuths = append(jwtAuths, l.JWTAuth)
+	}
+
+	encountered := make(map[string]bool)
+	var result []version2.JWTAuth
+
+	for _, j := range jwtAuths {
+		if j == nil || j.Verifier == nil || encountered[j.Verifier.Path] {
+			continue
+		}
+		encountered[j.Verifier.Path] = true
+		result = append(result, *j)
+	}
+
+	return result
+}
+
+var jwtClaimVariableRegexp = regexp.MustCompile(`\$\{jwt_claim_([a-zA-Z0-9_]+)\}`)
+
+// generateJWTClaimNames returns the names of the claims that the routes of the VirtualServer and its
+// VirtualServerRoutes reference with the ${jwt_claim_} variables in the headers of the proxy actions and in the
+// split keys, the fields in which NGINX evaluates the variables after the verifier of a JWT policy sets them.
+// The sub claim is always included if the VirtualServer has verifiers.
+func generateJWTClaimNames(vsEx *VirtualServerEx, hasVerifiers bool) []string {
+	names := make(map[string]bool)
+	if hasVerifiers {
+		names["sub"] = true
+	}
+
+	addNames := func(value string) {
+		for _, m := range jwtClaimVariableRegexp.FindAllStringSubmatch(value, -1) {
+			names[m[1]] = true
+		}
+	}
+
+	addActionNames := func(action *conf_v1.Action) {
+		if action == nil || action.Proxy == nil {
+			return
+		}
+		if action.Proxy.RequestHeaders != nil {
+			for _, h := range action.Proxy.RequestHeaders.Set {
+				addNames(h.Value)
+			}
+		}
+		if action.Proxy.ResponseHeaders != nil {
+			for _, h := range action.Proxy.ResponseHeaders.Add {
+				addNames(h.Value)
+			}
+		}
+	}
+
+	addRouteNames := func(routes []conf_v1.Route) {
+		for _, r := range routes {
+			addNames(r.SplitKey)
+			addActionNames(r.Action)
+			for _, s := range r.Splits {
+				addActionNames(s.Action)
+			}
+			for _, m := range r.Matches {
+				addNames(m.SplitKey)
+				addActionNames(m.Action)
+				for _, s := range m.Splits {
+					addActionNames(s.Action)
+				}
+			}
+		}
+	}
+
+	addRouteNames(vsEx.VirtualServer.Spec.Routes)
+	for _, vsr := range vsEx.VirtualServerRoutes {
+		addRouteNames(vsr.Spec.Subroutes)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// generateCORSPreflightLocations generates the internal locations that answer the preflight requests
+// for the CORS policies of the locations.
+func generateCORSPreflightLocations(locations []version2.Location) []version2.CORS {
+	encountered := make(map[string]bool)
+	var result []version2.CORS
+
+	for _, l := range locations {
+		if l.CORS != nil && !encountered[l.CORS.PreflightPath] {
+			encountered[l.CORS.PreflightPath] = true
+			result = append(result, *l.CORS)
+		}
+	}
+
+	return result
+}
+
+// generateExternalAuthLocations generates the internal locations that call the auth services
+// of the external auth policies of the locations.
+func generateExternalAuthLocations(locations []version2.Location) []version2.ExternalAuth {
+	encountered := make(map[string]bool)
+	var result []version2.ExternalAuth
+
+	for _, l := range locations {
+		if l.ExternalAuth != nil && !encountered[l.ExternalAuth.Path] {
+			encountered[l.ExternalAuth.Path] = true
+			result = append(result, *l.ExternalAuth)
+		}
+	}
+
+	return result
+}
+
 func addPoliciesCfgToLocation(cfg policiesCfg, location *version2.Location) {
 	location.Allow = cfg.Allow
 	location.Deny = cfg.Deny
 	location.LimitReqOptions = cfg.LimitReqOptions
 	location.LimitReqs = cfg.LimitReqs
+	location.LimitConnOptions = cfg.LimitConnOptions
+	location.LimitConns = cfg.LimitConns
+	location.LimitRate = cfg.LimitRate
 	location.JWTAuth = cfg.JWTAuth
 	location.BasicAuth = cfg.BasicAuth
 	location.EgressMTLS = cfg.EgressMTLS
 	location.OIDC = cfg.OIDC
 	location.WAF = cfg.WAF
+	location.CORS = cfg.CORS
+	location.ExternalAuth = cfg.ExternalAuth
+	location.APIKey = cfg.APIKey
+	location.Geo = cfg.Geo
 	location.PoliciesErrorReturn = cfg.ErrorReturn
 }
 
@@ -1875,6 +2912,7 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 		ProxyInterceptErrors:     generateProxyInterceptErrors(errorPages),
 		ProxyPassRequestHeaders:  generateProxyPassRequestHeaders(proxy),
 		ProxySetHeaders:          generateProxySetHeaders(proxy),
+		ForwardedHeaders:         upstream.ForwardedHeaders,
 		ProxyHideHeaders:         generateProxyHideHeaders(proxy),
 		ProxyPassHeaders:         generateProxyPassHeaders(proxy),
 		ProxyIgnoreHeaders:       generateProxyIgnoreHeaders(proxy),
@@ -1957,6 +2995,69 @@ func generateLocationForReturn(path string, locationSnippets []string, actionRet
 		}
 }
 
+// generateMirror generates the mirror of a location for the proxy action, if the action mirrors requests.
+// The path of the internal mirror location is set by generateMirrorLocations. Like the internal locations of
+// splits and matches, the mirror location rewrites the original URI of the request for the route path.
+func generateMirror(routePath string, action *conf_v1.Action, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1.Upstream) *version2.Mirror {
+	if action == nil || action.Proxy == nil || action.Proxy.Mirror == nil {
+		return nil
+	}
+
+	upstreamName := upstreamNamer.GetNameForUpstream(action.Proxy.Mirror.Upstream)
+	upstream := crUpstreams[upstreamName]
+
+	var proxySSLName string
+	if upstream.TLS.Enable {
+		proxySSLName = generateProxySSLName(upstream.Service, upstreamNamer.namespace)
+	}
+
+	return &version2.Mirror{
+		ProxyPass:    generateProxyPass(upstream.TLS.Enable, upstreamName, true, action.Proxy),
+		Rewrites:     generateRewrites(routePath, action.Proxy, true, "", false),
+		ProxySSLName: proxySSLName,
+		ServiceName:  upstream.Service,
+		Percentage:   generateIntFromPointer(action.Proxy.Mirror.Percentage, 100),
+	}
+}
+
+// generateMirrorLocations names the internal mirror locations of the locations that mirror requests.
+// It returns the split clients used to sample the mirrored requests.
+func generateMirrorLocations(locations []version2.Location, variableNamer *variableNamer) []version2.SplitClient {
+	var splitClients []version2.SplitClient
+	index := 0
+
+	for i := range locations {
+		m := locations[i].Mirror
+		if m == nil {
+			continue
+		}
+
+		m.Path = fmt.Sprintf("/%vmirror_%d", internalLocationPrefix, index)
+
+		if m.Percentage < 100 {
+			m.SampleVariable = variableNamer.GetNameForMirrorVariable(index)
+			splitClients = append(splitClients, version2.SplitClient{
+				Source:   "$request_id",
+				Variable: m.SampleVariable,
+				Distributions: []version2.Distribution{
+					{
+						Weight: fmt.Sprintf("%d%%", m.Percentage),
+						Value:  "1",
+					},
+					{
+						Weight: "*",
+						Value:  `""`,
+					},
+				},
+			})
+		}
+
+		index++
+	}
+
+	return splitClients
+}
+
 type routingCfg struct {
 	Maps                     []version2.Map
 	SplitClients             []version2.SplitClient
@@ -1965,8 +3066,18 @@ type routingCfg struct {
 	ReturnLocations          []version2.ReturnLocation
 }
 
+// generateSplitClientSource returns the source of a split_clients block.
+// If no split key is set, a random source is used, so every request is split independently.
+func generateSplitClientSource(splitKey string) string {
+	if splitKey == "" {
+		return "$request_id"
+	}
+	return fmt.Sprintf(`"%s"`, splitKey)
+}
+
 func generateSplits(
 	splits []conf_v1.Split,
+	splitKey string,
 	upstreamNamer *upstreamNamer,
 	crUpstreams map[string]conf_v1.Upstream,
 	variableNamer *variableNamer,
@@ -1993,7 +3104,7 @@ func generateSplits(
 	}
 
 	splitClient := version2.SplitClient{
-		Source:        "$request_id",
+		Source:        generateSplitClientSource(splitKey),
 		Variable:      variableNamer.GetNameForSplitClientVariable(scIndex),
 		Distributions: distributions,
 	}
@@ -2009,6 +3120,7 @@ func generateSplits(
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, s.Action, cfgParams, errorPages, true,
 			proxySSLName, originalPath, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+		loc.Mirror = generateMirror(originalPath, s.Action, upstreamNamer, crUpstreams)
 		locations = append(locations, loc)
 		if returnLoc != nil {
 			returnLocations = append(returnLocations, *returnLoc)
@@ -2035,7 +3147,7 @@ func generateDefaultSplitsConfig(
 	vsrNamespace string,
 	vscWarnings Warnings,
 ) routingCfg {
-	sc, locs, returnLocs := generateSplits(route.Splits, upstreamNamer, crUpstreams, variableNamer, scIndex, cfgParams,
+	sc, locs, returnLocs := generateSplits(route.Splits, route.SplitKey, upstreamNamer, crUpstreams, variableNamer, scIndex, cfgParams,
 		errorPages, originalPath, locSnippets, enableSnippets, retLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
 
 	splitClientVarName := variableNamer.GetNameForSplitClientVariable(scIndex)
@@ -2130,9 +3242,16 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 
 	for i, m := range route.Matches {
 		if len(m.Splits) > 0 {
+			// the split key of the route is used if the match does not define its own
+			splitKey := m.SplitKey
+			if splitKey == "" {
+				splitKey = route.SplitKey
+			}
+
 			newRetLocIndex := retLocIndex + len(returnLocations)
 			sc, locs, returnLocs := generateSplits(
 				m.Splits,
+				splitKey,
 				upstreamNamer,
 				crUpstreams,
 				variableNamer,
@@ -2160,6 +3279,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 			newRetLocIndex := retLocIndex + len(returnLocations)
 			loc, returnLoc := generateLocation(path, upstreamName, upstream, m.Action, cfgParams, errorPages, true,
 				proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+			loc.Mirror = generateMirror(route.Path, m.Action, upstreamNamer, crUpstreams)
 			locations = append(locations, loc)
 			if returnLoc != nil {
 				returnLocations = append(returnLocations, *returnLoc)
@@ -2172,6 +3292,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		sc, locs, returnLocs := generateSplits(
 			route.Splits,
+			route.SplitKey,
 			upstreamNamer,
 			crUpstreams,
 			variableNamer,
@@ -2198,6 +3319,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, route.Action, cfgParams, errorPages, true,
 			proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+		loc.Mirror = generateMirror(route.Path, route.Action, upstreamNamer, crUpstreams)
 		locations = append(locations, loc)
 		if returnLoc != nil {
 			returnLocations = append(returnLocations, *returnLoc)
@@ -2294,6 +3416,7 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 		if vsc.isWildcardEnabled {
 			ssl := version2.SSL{
 				HTTP2:           cfgParams.HTTP2,
+				HTTP3:           generateHTTP3(tls, cfgParams),
 				Certificate:     pemFileNameForWildcardTLSSecret,
 				CertificateKey:  pemFileNameForWildcardTLSSecret,
 				RejectHandshake: false,
@@ -2322,6 +3445,7 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 
 	ssl := version2.SSL{
 		HTTP2:           cfgParams.HTTP2,
+		HTTP3:           generateHTTP3(tls, cfgParams),
 		Certificate:     name,
 		CertificateKey:  name,
 		RejectHandshake: rejectHandshake,
@@ -2330,6 +3454,45 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 	return &ssl
 }
 
+// generateHTTP3 returns true if HTTP/3 is enabled for a VirtualServer. The http3 field of the TLS takes precedence
+// over the http3 ConfigMap key.
+func generateHTTP3(tls *conf_v1.TLS, cfgParams *ConfigParams) bool {
+	if tls != nil && tls.HTTP3 != nil {
+		return *tls.HTTP3
+	}
+	return cfgParams.HTTP3
+}
+
+// generateListeners generates the custom listeners of a server. The proxy_protocol, http2 and http3 settings of
+// a listener take precedence over the ones from the VirtualServer and the ConfigMap.
+func generateListeners(listeners []conf_v1alpha1.Listener, cfgParams *ConfigParams, http3 bool) []version2.Listener {
+	var result []version2.Listener
+
+	for _, l := range listeners {
+		listener := version2.Listener{
+			Port:          l.Port,
+			SSL:           l.Ssl,
+			ProxyProtocol: cfgParams.ProxyProtocol,
+		}
+		if l.ProxyProtocol != nil {
+			listener.ProxyProtocol = *l.ProxyProtocol
+		}
+		if l.Ssl {
+			listener.HTTP2 = cfgParams.HTTP2
+			if l.HTTP2 != nil {
+				listener.HTTP2 = *l.HTTP2
+			}
+			listener.HTTP3 = http3
+			if l.HTTP3 != nil {
+				listener.HTTP3 = *l.HTTP3
+			}
+		}
+		result = append(result, listener)
+	}
+
+	return result
+}
+
 func generateTLSRedirectConfig(tls *conf_v1.TLS) *version2.TLSRedirect {
 	if tls == nil || tls.Redirect == nil || !tls.Redirect.Enable {
 		return nil
@@ -2413,6 +3576,14 @@ func createUpstreamsForPlus(
 		}
 	}
 
+	for _, ups := range vsc.generateExternalAuthUpstreams(virtualServerEx) {
+		if ups.Resolve {
+			glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", ups.UpstreamLabels.Service)
+			continue
+		}
+		upstreams = append(upstreams, ups)
+	}
+
 	return upstreams
 }
 
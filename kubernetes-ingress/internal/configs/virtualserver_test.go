@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"reflect"
@@ -11,6 +12,7 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -1272,8 +1274,6 @@ func TestGenerateVirtualServerConfig(t *testing.T) {
 		Server: version2.Server{
 			ServerName:      "cafe.example.com",
 			StatusZone:      "cafe.example.com",
-			HTTPPort:        0,
-			HTTPSPort:       0,
 			CustomListeners: false,
 			VSNamespace:     "default",
 			VSName:          "cafe",
@@ -1454,9 +1454,11 @@ func TestGenerateVirtualServerConfigWithCustomHttpAndHttpsListeners(t *testing.T
 			VSNamespace:     virtualServerExWithCustomHTTPAndHTTPSListeners.VirtualServer.ObjectMeta.Namespace,
 			VSName:          virtualServerExWithCustomHTTPAndHTTPSListeners.VirtualServer.ObjectMeta.Name,
 			DisableIPV6:     true,
-			HTTPPort:        virtualServerExWithCustomHTTPAndHTTPSListeners.HTTPPort,
-			HTTPSPort:       virtualServerExWithCustomHTTPAndHTTPSListeners.HTTPSPort,
 			CustomListeners: true,
+			Listeners: []version2.Listener{
+				{Port: 8083, ProxyProtocol: true},
+				{Port: 8443, SSL: true, ProxyProtocol: true},
+			},
 			ProxyProtocol:   true,
 			ServerTokens:    "off",
 			SetRealIPFrom:   []string{"0.0.0.0/0"},
@@ -1502,9 +1504,10 @@ func TestGenerateVirtualServerConfigWithCustomHttpListener(t *testing.T) {
 			VSNamespace:     virtualServerExWithCustomHTTPListener.VirtualServer.ObjectMeta.Namespace,
 			VSName:          virtualServerExWithCustomHTTPListener.VirtualServer.ObjectMeta.Name,
 			DisableIPV6:     true,
-			HTTPPort:        virtualServerExWithCustomHTTPListener.HTTPPort,
-			HTTPSPort:       virtualServerExWithCustomHTTPListener.HTTPSPort,
 			CustomListeners: true,
+			Listeners: []version2.Listener{
+				{Port: 8083, ProxyProtocol: true},
+			},
 			ProxyProtocol:   true,
 			ServerTokens:    "off",
 			SetRealIPFrom:   []string{"0.0.0.0/0"},
@@ -1550,9 +1553,10 @@ func TestGenerateVirtualServerConfigWithCustomHttpsListener(t *testing.T) {
 			VSNamespace:     virtualServerExWithCustomHTTPSListener.VirtualServer.ObjectMeta.Namespace,
 			VSName:          virtualServerExWithCustomHTTPSListener.VirtualServer.ObjectMeta.Name,
 			DisableIPV6:     true,
-			HTTPPort:        virtualServerExWithCustomHTTPSListener.HTTPPort,
-			HTTPSPort:       virtualServerExWithCustomHTTPSListener.HTTPSPort,
 			CustomListeners: true,
+			Listeners: []version2.Listener{
+				{Port: 8443, SSL: true, ProxyProtocol: true},
+			},
 			ProxyProtocol:   true,
 			ServerTokens:    "off",
 			SetRealIPFrom:   []string{"0.0.0.0/0"},
@@ -1585,6 +1589,119 @@ func TestGenerateVirtualServerConfigWithCustomHttpsListener(t *testing.T) {
 	}
 }
 
+func TestGenerateHTTP3(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tls       *conf_v1.TLS
+		cfgParams *ConfigParams
+		expected  bool
+		msg       string
+	}{
+		{
+			tls:       nil,
+			cfgParams: &ConfigParams{HTTP3: true},
+			expected:  true,
+			msg:       "http3 enabled in the ConfigMap",
+		},
+		{
+			tls:       &conf_v1.TLS{Secret: "secret", HTTP3: createPointerFromBool(true)},
+			cfgParams: &ConfigParams{},
+			expected:  true,
+			msg:       "http3 enabled in the VirtualServer",
+		},
+		{
+			tls:       &conf_v1.TLS{Secret: "secret", HTTP3: createPointerFromBool(false)},
+			cfgParams: &ConfigParams{HTTP3: true},
+			expected:  false,
+			msg:       "http3 disabled in the VirtualServer",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateHTTP3(test.tls, test.cfgParams)
+		if result != test.expected {
+			t.Errorf("generateHTTP3() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateListeners(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		listeners []conf_v1alpha1.Listener
+		cfgParams *ConfigParams
+		http3     bool
+		expected  []version2.Listener
+		msg       string
+	}{
+		{
+			listeners: nil,
+			cfgParams: &ConfigParams{},
+			expected:  nil,
+			msg:       "no listeners",
+		},
+		{
+			listeners: []conf_v1alpha1.Listener{
+				{Name: "http-8083", Port: 8083, Protocol: "HTTP"},
+				{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+			},
+			cfgParams: &ConfigParams{ProxyProtocol: true, HTTP2: true},
+			expected: []version2.Listener{
+				{Port: 8083, ProxyProtocol: true},
+				{Port: 8443, SSL: true, HTTP2: true, ProxyProtocol: true},
+			},
+			msg: "listeners use the ConfigMap settings",
+		},
+		{
+			listeners: []conf_v1alpha1.Listener{
+				{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+				{
+					Name:          "https-9443",
+					Port:          9443,
+					Protocol:      "HTTP",
+					Ssl:           true,
+					ProxyProtocol: createPointerFromBool(false),
+					HTTP2:         createPointerFromBool(true),
+				},
+			},
+			cfgParams: &ConfigParams{ProxyProtocol: true},
+			expected: []version2.Listener{
+				{Port: 8443, SSL: true, ProxyProtocol: true},
+				{Port: 9443, SSL: true, HTTP2: true},
+			},
+			msg: "listener settings override the ConfigMap settings",
+		},
+		{
+			listeners: []conf_v1alpha1.Listener{
+				{Name: "http-8083", Port: 8083, Protocol: "HTTP"},
+				{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+				{
+					Name:     "https-9443",
+					Port:     9443,
+					Protocol: "HTTP",
+					Ssl:      true,
+					HTTP3:    createPointerFromBool(false),
+				},
+			},
+			cfgParams: &ConfigParams{},
+			http3:     true,
+			expected: []version2.Listener{
+				{Port: 8083},
+				{Port: 8443, SSL: true, HTTP3: true},
+				{Port: 9443, SSL: true},
+			},
+			msg: "http3 is enabled only for the https listeners that don't disable it",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateListeners(test.listeners, test.cfgParams, test.http3)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateListeners() '%s' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func TestGenerateVirtualServerConfigWithNilListener(t *testing.T) {
 	t.Parallel()
 
@@ -1598,8 +1715,6 @@ func TestGenerateVirtualServerConfigWithNilListener(t *testing.T) {
 			VSNamespace:     virtualServerExWithNilListener.VirtualServer.ObjectMeta.Namespace,
 			VSName:          virtualServerExWithNilListener.VirtualServer.ObjectMeta.Name,
 			DisableIPV6:     true,
-			HTTPPort:        virtualServerExWithNilListener.HTTPPort,
-			HTTPSPort:       virtualServerExWithNilListener.HTTPSPort,
 			CustomListeners: false,
 			ProxyProtocol:   true,
 			ServerTokens:    baseCfgParams.ServerTokens,
@@ -4116,7 +4231,7 @@ func TestGenerateVirtualServerConfigJWKSPolicy(t *testing.T) {
 
 	vsc := newVirtualServerConfigurator(
 		&baseCfgParams,
-		false,
+		true,
 		false,
 		&StaticConfigParams{TLSPassthrough: true},
 		false,
@@ -4133,693 +4248,2420 @@ func TestGenerateVirtualServerConfigJWKSPolicy(t *testing.T) {
 	}
 }
 
-func TestGeneratePolicies(t *testing.T) {
+func TestGenerateVirtualServerConfigJWTAndExternalAuthPoliciesForOSS(t *testing.T) {
 	t.Parallel()
-	ownerDetails := policyOwnerDetails{
-		owner:          nil, // nil is OK for the unit test
-		ownerNamespace: "default",
-		vsNamespace:    "default",
-		vsName:         "test",
-	}
-	mTLSCertPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt"
-	mTLSCrlPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crl"
-	mTLSCertAndCrlPath := fmt.Sprintf("%s %s", mTLSCertPath, mTLSCrlPath)
-	policyOpts := policyOptions{
-		tls: true,
-		secretRefs: map[string]*secrets.SecretReference{
-			"default/ingress-mtls-secret": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeCA,
-				},
-				Path: mTLSCertPath,
-			},
-			"default/ingress-mtls-secret-crl": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeCA,
-					Data: map[string][]byte{
-						"ca.crl": []byte("base64crl"),
-					},
-				},
-				Path: mTLSCertAndCrlPath,
-			},
-			"default/egress-mtls-secret": {
-				Secret: &api_v1.Secret{
-					Type: api_v1.SecretTypeTLS,
-				},
-				Path: "/etc/nginx/secrets/default-egress-mtls-secret",
-			},
-			"default/egress-trusted-ca-secret": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeCA,
-				},
-				Path: "/etc/nginx/secrets/default-egress-trusted-ca-secret",
-			},
-			"default/egress-trusted-ca-secret-crl": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeCA,
-				},
-				Path: mTLSCertAndCrlPath,
-			},
-			"default/jwt-secret": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeJWK,
-				},
-				Path: "/etc/nginx/secrets/default-jwt-secret",
-			},
-			"default/htpasswd-secret": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeHtpasswd,
-				},
-				Path: "/etc/nginx/secrets/default-htpasswd-secret",
+
+	policies := map[string]*conf_v1.Policy{
+		"default/jwt-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "jwt-policy",
+				Namespace: "default",
 			},
-			"default/oidc-secret": {
-				Secret: &api_v1.Secret{
-					Type: secrets.SecretTypeOIDC,
-					Data: map[string][]byte{
-						"client-secret": []byte("super_secret_123"),
-					},
+			Spec: conf_v1.PolicySpec{
+				JWTAuth: &conf_v1.JWTAuth{
+					Realm:   "My API",
+					JwksURI: "https://idp.example.com:443/keys",
 				},
 			},
 		},
-		apResources: &appProtectResourcesForVS{
-			Policies: map[string]string{
-				"default/dataguard-alarm": "/etc/nginx/waf/nac-policies/default-dataguard-alarm",
+		"default/external-auth-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "external-auth-policy",
+				Namespace: "default",
 			},
-			LogConfs: map[string]string{
-				"default/logconf": "/etc/nginx/waf/nac-logconfs/default-logconf",
+			Spec: conf_v1.PolicySpec{
+				ExternalAuth: &conf_v1.ExternalAuth{
+					Service: "auth-svc",
+					Port:    8080,
+					Path:    "/check",
+				},
 			},
 		},
 	}
 
 	tests := []struct {
-		policyRefs []conf_v1.PolicyReference
-		policies   map[string]*conf_v1.Policy
-		context    string
-		expected   policiesCfg
-		msg        string
+		specPolicies     []conf_v1.PolicyReference
+		routePolicies    []conf_v1.PolicyReference
+		expectedWarnings []string
+		msg              string
 	}{
 		{
-			policyRefs: []conf_v1.PolicyReference{
+			specPolicies: []conf_v1.PolicyReference{
 				{
-					Name:      "allow-policy",
-					Namespace: "default",
+					Name: "external-auth-policy",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/allow-policy": {
-					Spec: conf_v1.PolicySpec{
-						AccessControl: &conf_v1.AccessControl{
-							Allow: []string{"127.0.0.1"},
-						},
-					},
+			routePolicies: []conf_v1.PolicyReference{
+				{
+					Name: "jwt-policy",
 				},
 			},
-			expected: policiesCfg{
-				Allow: []string{"127.0.0.1"},
+			expectedWarnings: []string{
+				"Route /tea combines a JWT policy with an external auth policy, which is not supported in NGINX",
 			},
-			msg: "explicit reference",
+			msg: "external auth policy in spec, jwt policy in route",
 		},
 		{
-			policyRefs: []conf_v1.PolicyReference{
+			specPolicies: []conf_v1.PolicyReference{
 				{
-					Name: "allow-policy",
+					Name: "jwt-policy",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/allow-policy": {
-					Spec: conf_v1.PolicySpec{
-						AccessControl: &conf_v1.AccessControl{
-							Allow: []string{"127.0.0.1"},
-						},
-					},
+			routePolicies: []conf_v1.PolicyReference{
+				{
+					Name: "external-auth-policy",
 				},
 			},
-			expected: policiesCfg{
-				Allow: []string{"127.0.0.1"},
+			expectedWarnings: []string{
+				"Route /tea combines a JWT policy with an external auth policy, which is not supported in NGINX",
 			},
-			msg: "implicit reference",
+			msg: "jwt policy in spec, external auth policy in route",
 		},
-		{
-			policyRefs: []conf_v1.PolicyReference{
-				{
-					Name: "allow-policy-1",
-				},
-				{
-					Name: "allow-policy-2",
+	}
+
+	for _, test := range tests {
+		virtualServerEx := VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe",
+					Namespace: "default",
 				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/allow-policy-1": {
-					Spec: conf_v1.PolicySpec{
-						AccessControl: &conf_v1.AccessControl{
-							Allow: []string{"127.0.0.1"},
+				Spec: conf_v1.VirtualServerSpec{
+					Host:     "cafe.example.com",
+					Policies: test.specPolicies,
+					Upstreams: []conf_v1.Upstream{
+						{
+							Name:    "tea",
+							Service: "tea-svc",
+							Port:    80,
+						},
+						{
+							Name:    "coffee",
+							Service: "coffee-svc",
+							Port:    80,
 						},
 					},
-				},
-				"default/allow-policy-2": {
-					Spec: conf_v1.PolicySpec{
-						AccessControl: &conf_v1.AccessControl{
-							Allow: []string{"127.0.0.2"},
+					Routes: []conf_v1.Route{
+						{
+							Path: "/tea",
+							Action: &conf_v1.Action{
+								Pass: "tea",
+							},
+							Policies: test.routePolicies,
+						},
+						{
+							Path: "/coffee",
+							Action: &conf_v1.Action{
+								Pass: "coffee",
+							},
 						},
 					},
 				},
 			},
-			expected: policiesCfg{
-				Allow: []string{"127.0.0.1", "127.0.0.2"},
-			},
-			msg: "merging",
-		},
-		{
-			policyRefs: []conf_v1.PolicyReference{
-				{
-					Name:      "rateLimit-policy",
-					Namespace: "default",
+			Policies: policies,
+			Endpoints: map[string][]string{
+				"default/tea-svc:80": {
+					"10.0.0.20:80",
 				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/rateLimit-policy": {
-					Spec: conf_v1.PolicySpec{
-						RateLimit: &conf_v1.RateLimit{
-							Key:      "test",
-							ZoneSize: "10M",
-							Rate:     "10r/s",
-							LogLevel: "notice",
-						},
-					},
+				"default/coffee-svc:80": {
+					"10.0.0.30:80",
 				},
 			},
-			expected: policiesCfg{
-				LimitReqZones: []version2.LimitReqZone{
+		}
+
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+
+		result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+		for _, l := range result.Server.Locations {
+			switch l.Path {
+			case "/tea":
+				if diff := cmp.Diff(&version2.Return{Code: 500}, l.PoliciesErrorReturn); diff != "" {
+					t.Errorf("GenerateVirtualServerConfig() '%v' location %v mismatch (-want +got):\n%s", test.msg, l.Path, diff)
+				}
+				if l.JWTAuth != nil || l.ExternalAuth != nil {
+					t.Errorf("GenerateVirtualServerConfig() '%v' location %v still combines JWT and external auth policies", test.msg, l.Path)
+				}
+			case "/coffee":
+				if l.PoliciesErrorReturn != nil {
+					t.Errorf("GenerateVirtualServerConfig() '%v' location %v returned %v", test.msg, l.Path, l.PoliciesErrorReturn)
+				}
+			}
+		}
+		if diff := cmp.Diff(test.expectedWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() '%v' warnings mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateVirtualServerConfigCORSPolicy(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Policies: []conf_v1.PolicyReference{
 					{
-						Key:      "test",
-						ZoneSize: "10M",
-						Rate:     "10r/s",
-						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+						Name: "cors-policy",
 					},
 				},
-				LimitReqOptions: version2.LimitReqOptions{
-					LogLevel:   "notice",
-					RejectCode: 503,
-				},
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+					{
+						Name:    "coffee",
+						Service: "coffee-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+						Policies: []conf_v1.PolicyReference{
+							{
+								Name: "cors-policy-route",
+							},
+						},
+					},
+					{
+						Path: "/coffee",
+						Action: &conf_v1.Action{
+							Pass: "coffee",
+						},
+					},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/cors-policy": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					CORS: &conf_v1.CORS{
+						AllowOrigins:     []string{"https://app.example.com"},
+						AllowCredentials: createPointerFromBool(true),
+					},
+				},
+			},
+			"default/cors-policy-route": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cors-policy-route",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					CORS: &conf_v1.CORS{
+						AllowOrigins: []string{"*"},
+						AllowMethods: []string{"GET"},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+			"default/coffee-svc:80": {
+				"10.0.0.30:80",
+			},
+		},
+	}
+
+	specCORS := &version2.CORS{
+		PreflightPath:    "/internal_location_cors_default_cors-policy",
+		AllowOrigin:      "$pol_cors_default_cors_policy_default_cafe_origin",
+		AllowMethods:     "GET, HEAD, POST",
+		AllowCredentials: true,
+		VaryOrigin:       true,
+	}
+	routeCORS := &version2.CORS{
+		PreflightPath: "/internal_location_cors_default_cors-policy-route",
+		AllowOrigin:   "*",
+		AllowMethods:  "GET",
+	}
+
+	expected := version2.VirtualServerConfig{
+		Upstreams: []version2.Upstream{
+			{
+				UpstreamLabels: version2.UpstreamLabels{
+					Service:           "tea-svc",
+					ResourceType:      "virtualserver",
+					ResourceName:      "cafe",
+					ResourceNamespace: "default",
+				},
+				Name: "vs_default_cafe_tea",
+				Servers: []version2.UpstreamServer{
+					{
+						Address: "10.0.0.20:80",
+					},
+				},
+			},
+			{
+				UpstreamLabels: version2.UpstreamLabels{
+					Service:           "coffee-svc",
+					ResourceType:      "virtualserver",
+					ResourceName:      "cafe",
+					ResourceNamespace: "default",
+				},
+				Name: "vs_default_cafe_coffee",
+				Servers: []version2.UpstreamServer{
+					{
+						Address: "10.0.0.30:80",
+					},
+				},
+			},
+		},
+		Maps: []version2.Map{
+			{
+				Source:   "$http_origin",
+				Variable: "$pol_cors_default_cors_policy_default_cafe_origin",
+				Parameters: []version2.Parameter{
+					{
+						Value:  `"https://app.example.com"`,
+						Result: "$http_origin",
+					},
+					{
+						Value:  "default",
+						Result: `""`,
+					},
+				},
+			},
+		},
+		HTTPSnippets:  []string{},
+		LimitReqZones: []version2.LimitReqZone{},
+		Server: version2.Server{
+			ServerName:  "cafe.example.com",
+			StatusZone:  "cafe.example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Locations: []version2.Location{
+				{
+					Path:                     "/tea",
+					ProxyPass:                "http://vs_default_cafe_tea",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxySSLName:             "tea-svc.default.svc",
+					ProxyPassRequestHeaders:  true,
+					ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
+					ServiceName:              "tea-svc",
+					CORS:                     routeCORS,
+				},
+				{
+					Path:                     "/coffee",
+					ProxyPass:                "http://vs_default_cafe_coffee",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxySSLName:             "coffee-svc.default.svc",
+					ProxyPassRequestHeaders:  true,
+					ProxySetHeaders:          []version2.Header{{Name: "Host", Value: "$host"}},
+					ServiceName:              "coffee-svc",
+					CORS:                     specCORS,
+				},
+			},
+			CORSPreflightLocations: []version2.CORS{*routeCORS, *specCORS},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", vsc.warnings)
+	}
+}
+
+func TestGeneratePolicies(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+	mTLSCertPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt"
+	mTLSCrlPath := "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crl"
+	mTLSCertAndCrlPath := fmt.Sprintf("%s %s", mTLSCertPath, mTLSCrlPath)
+	policyOpts := policyOptions{
+		tls: true,
+		secretRefs: map[string]*secrets.SecretReference{
+			"default/ingress-mtls-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeCA,
+				},
+				Path: mTLSCertPath,
+			},
+			"default/ingress-mtls-secret-crl": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeCA,
+					Data: map[string][]byte{
+						"ca.crl": []byte("base64crl"),
+					},
+				},
+				Path: mTLSCertAndCrlPath,
+			},
+			"default/egress-mtls-secret": {
+				Secret: &api_v1.Secret{
+					Type: api_v1.SecretTypeTLS,
+				},
+				Path: "/etc/nginx/secrets/default-egress-mtls-secret",
+			},
+			"default/egress-trusted-ca-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeCA,
+				},
+				Path: "/etc/nginx/secrets/default-egress-trusted-ca-secret",
+			},
+			"default/egress-trusted-ca-secret-crl": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeCA,
+				},
+				Path: mTLSCertAndCrlPath,
+			},
+			"default/jwt-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeJWK,
+				},
+				Path: "/etc/nginx/secrets/default-jwt-secret",
+			},
+			"default/htpasswd-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeHtpasswd,
+				},
+				Path: "/etc/nginx/secrets/default-htpasswd-secret",
+			},
+			"default/oidc-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeOIDC,
+					Data: map[string][]byte{
+						"client-secret": []byte("super_secret_123"),
+					},
+				},
+			},
+			"default/api-key-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeAPIKey,
+					Data: map[string][]byte{
+						"client1": []byte("password"),
+						"client2": []byte("another-password"),
+					},
+				},
+			},
+		},
+		apResources: &appProtectResourcesForVS{
+			Policies: map[string]string{
+				"default/dataguard-alarm": "/etc/nginx/waf/nac-policies/default-dataguard-alarm",
+			},
+			LogConfs: map[string]string{
+				"default/logconf": "/etc/nginx/waf/nac-logconfs/default-logconf",
+			},
+		},
+	}
+
+	tests := []struct {
+		policyRefs   []conf_v1.PolicyReference
+		policies     map[string]*conf_v1.Policy
+		context      string
+		expected     policiesCfg
+		expectedOidc *version2.OIDC
+		msg          string
+	}{
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "allow-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"127.0.0.1"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Allow: []string{"127.0.0.1"},
+			},
+			msg: "explicit reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "allow-policy",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"127.0.0.1"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Allow: []string{"127.0.0.1"},
+			},
+			msg: "implicit reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "allow-policy-1",
+				},
+				{
+					Name: "allow-policy-2",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy-1": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"127.0.0.1"},
+						},
+					},
+				},
+				"default/allow-policy-2": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"127.0.0.2"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Allow: []string{"127.0.0.1", "127.0.0.2"},
+			},
+			msg: "merging",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rateLimit-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "test",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+							LogLevel: "notice",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				LimitReqZones: []version2.LimitReqZone{
+					{
+						Key:      "test",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+				},
+				LimitReqOptions: version2.LimitReqOptions{
+					LogLevel:   "notice",
+					RejectCode: 503,
+				},
+				LimitReqs: []version2.LimitReq{
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+				},
+			},
+			msg: "rate limit reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rateLimit-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "rateLimit-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "test",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+						},
+					},
+				},
+				"default/rateLimit-policy2": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "test2",
+							ZoneSize: "20M",
+							Rate:     "20r/s",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				LimitReqZones: []version2.LimitReqZone{
+					{
+						Key:      "test",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+					{
+						Key:      "test2",
+						ZoneSize: "20M",
+						Rate:     "20r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+					},
+				},
+				LimitReqOptions: version2.LimitReqOptions{
+					LogLevel:   "error",
+					RejectCode: 503,
+				},
+				LimitReqs: []version2.LimitReq{
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+					},
+				},
+			},
+			msg: "multi rate limit reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rateLimit-policy-tiers",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy-tiers": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "${jwt_claim_sub}",
+							ZoneSize: "10M",
+							Burst:    createPointerFromInt(5),
+							Tiers: []conf_v1.RateLimitTier{
+								{
+									Name: "free",
+									Rate: "10r/s",
+								},
+								{
+									Name:  "gold",
+									Rate:  "200r/s",
+									Burst: createPointerFromInt(50),
+								},
+							},
+							TierSelector: "${jwt_claim_plan}",
+							DefaultTier:  "free",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				LimitReqZones: []version2.LimitReqZone{
+					{
+						Key:      "$pol_rl_default_rateLimit_policy_tiers_default_test_free",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy-tiers_default_test_free",
+					},
+					{
+						Key:      "$pol_rl_default_rateLimit_policy_tiers_default_test_gold",
+						ZoneSize: "10M",
+						Rate:     "200r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy-tiers_default_test_gold",
+					},
+				},
+				LimitReqOptions: version2.LimitReqOptions{
+					LogLevel:   "error",
+					RejectCode: 503,
+				},
+				LimitReqs: []version2.LimitReq{
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy-tiers_default_test_free",
+						Burst:    5,
+					},
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy-tiers_default_test_gold",
+						Burst:    50,
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$jwt_claim_plan",
+						Variable: "$pol_rl_default_rateLimit_policy_tiers_default_test_free",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"gold"`,
+								Result: `""`,
+							},
+							{
+								Value:  "default",
+								Result: `"${jwt_claim_sub}"`,
+							},
+						},
+					},
+					{
+						Source:   "$jwt_claim_plan",
+						Variable: "$pol_rl_default_rateLimit_policy_tiers_default_test_gold",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"gold"`,
+								Result: `"${jwt_claim_sub}"`,
+							},
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+						},
+					},
+				},
+			},
+			msg: "rate limit reference with tiers",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rateLimit-policy-special-tiers",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy-special-tiers": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "${jwt_claim_sub}",
+							ZoneSize: "10M",
+							Burst:    createPointerFromInt(5),
+							Tiers: []conf_v1.RateLimitTier{
+								{
+									Name: "default",
+									Rate: "10r/s",
+								},
+								{
+									Name:  "include",
+									Rate:  "200r/s",
+									Burst: createPointerFromInt(50),
+								},
+							},
+							TierSelector: "${jwt_claim_plan}",
+							DefaultTier:  "include",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				LimitReqZones: []version2.LimitReqZone{
+					{
+						Key:      "$pol_rl_default_rateLimit_policy_special_tiers_default_test_default",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy-special-tiers_default_test_default",
+					},
+					{
+						Key:      "$pol_rl_default_rateLimit_policy_special_tiers_default_test_include",
+						ZoneSize: "10M",
+						Rate:     "200r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy-special-tiers_default_test_include",
+					},
+				},
+				LimitReqOptions: version2.LimitReqOptions{
+					LogLevel:   "error",
+					RejectCode: 503,
+				},
 				LimitReqs: []version2.LimitReq{
 					{
-						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+						ZoneName: "pol_rl_default_rateLimit-policy-special-tiers_default_test_default",
+						Burst:    5,
+					},
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy-special-tiers_default_test_include",
+						Burst:    50,
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$jwt_claim_plan",
+						Variable: "$pol_rl_default_rateLimit_policy_special_tiers_default_test_default",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `\default`,
+								Result: `"${jwt_claim_sub}"`,
+							},
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+						},
+					},
+					{
+						Source:   "$jwt_claim_plan",
+						Variable: "$pol_rl_default_rateLimit_policy_special_tiers_default_test_include",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `\default`,
+								Result: `""`,
+							},
+							{
+								Value:  "default",
+								Result: `"${jwt_claim_sub}"`,
+							},
+						},
+					},
+				},
+			},
+			msg: "rate limit reference with tiers named as special map parameters",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "bandwidthLimit-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/bandwidthLimit-policy": {
+					Spec: conf_v1.PolicySpec{
+						BandwidthLimit: &conf_v1.BandwidthLimit{
+							Key:         "${binary_remote_addr}",
+							Connections: 2,
+							ZoneSize:    "10M",
+							LogLevel:    "warn",
+							RejectCode:  createPointerFromInt(429),
+							Rate:        "1m",
+							RateAfter:   "10m",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				LimitConnZones: []version2.LimitConnZone{
+					{
+						Key:      "${binary_remote_addr}",
+						ZoneName: "pol_bl_default_bandwidthLimit-policy_default_test",
+						ZoneSize: "10M",
+					},
+				},
+				LimitConnOptions: version2.LimitConnOptions{
+					LogLevel:   "warn",
+					RejectCode: 429,
+				},
+				LimitConns: []version2.LimitConn{
+					{
+						ZoneName:    "pol_bl_default_bandwidthLimit-policy_default_test",
+						Connections: 2,
+					},
+				},
+				LimitRate: &version2.LimitRate{
+					Rate:  "1m",
+					After: "10m",
+				},
+			},
+			msg: "bandwidth limit reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "jwt-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/jwt-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "jwt-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						JWTAuth: &conf_v1.JWTAuth{
+							Realm:  "My Test API",
+							Secret: "jwt-secret",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				JWTAuth: &version2.JWTAuth{
+					Secret: "/etc/nginx/secrets/default-jwt-secret",
+					Realm:  "My Test API",
+				},
+				JWKSAuthEnabled: false,
+			},
+			msg: "jwt reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "jwt-policy-2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/jwt-policy-2": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "jwt-policy-2",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						JWTAuth: &conf_v1.JWTAuth{
+							Realm:    "My Test API",
+							JwksURI:  "https://idp.example.com:443/keys",
+							KeyCache: "1h",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				JWTAuth: &version2.JWTAuth{
+					Key:   "default/jwt-policy-2",
+					Realm: "My Test API",
+					JwksURI: version2.JwksURI{
+						JwksScheme: "https",
+						JwksHost:   "idp.example.com",
+						JwksPort:   "443",
+						JwksPath:   "/keys",
+					},
+					KeyCache: "1h",
+				},
+				JWKSAuthEnabled: true,
+			},
+			msg: "Basic jwks example",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "jwt-policy-2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/jwt-policy-2": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "jwt-policy-2",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						JWTAuth: &conf_v1.JWTAuth{
+							Realm:    "My Test API",
+							JwksURI:  "https://idp.example.com/keys",
+							KeyCache: "1h",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				JWTAuth: &version2.JWTAuth{
+					Key:   "default/jwt-policy-2",
+					Realm: "My Test API",
+					JwksURI: version2.JwksURI{
+						JwksScheme: "https",
+						JwksHost:   "idp.example.com",
+						JwksPort:   "",
+						JwksPath:   "/keys",
+					},
+					KeyCache: "1h",
+				},
+				JWKSAuthEnabled: true,
+			},
+			msg: "Basic jwks example, no port in JwksURI",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "basic-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/basic-auth-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic-auth-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						BasicAuth: &conf_v1.BasicAuth{
+							Realm:  "My Test API",
+							Secret: "htpasswd-secret",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				BasicAuth: &version2.BasicAuth{
+					Secret: "/etc/nginx/secrets/default-htpasswd-secret",
+					Realm:  "My Test API",
+				},
+			},
+			msg: "basic auth reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ingress-mtls-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ingress-mtls-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ingress-mtls-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret",
+							VerifyClient:     "off",
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				IngressMTLS: &version2.IngressMTLS{
+					ClientCert:   mTLSCertPath,
+					VerifyClient: "off",
+					VerifyDepth:  1,
+				},
+			},
+			msg: "ingressMTLS reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ingress-mtls-policy-crl",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ingress-mtls-policy-crl": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ingress-mtls-policy-crl",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret-crl",
+							VerifyClient:     "off",
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				IngressMTLS: &version2.IngressMTLS{
+					ClientCert:   mTLSCertPath,
+					ClientCrl:    mTLSCrlPath,
+					VerifyClient: "off",
+					VerifyDepth:  1,
+				},
+			},
+			msg: "ingressMTLS reference with ca.crl field in secret",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ingress-mtls-policy-crl",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ingress-mtls-policy-crl": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ingress-mtls-policy-crl",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret",
+							CrlFileName:      "default-ingress-mtls-secret-ca.crl",
+							VerifyClient:     "off",
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				IngressMTLS: &version2.IngressMTLS{
+					ClientCert:   mTLSCertPath,
+					ClientCrl:    mTLSCrlPath,
+					VerifyClient: "off",
+					VerifyDepth:  1,
+				},
+			},
+			msg: "ingressMTLS reference with crl field in policy",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "egress-mtls-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/egress-mtls-policy": {
+					Spec: conf_v1.PolicySpec{
+						EgressMTLS: &conf_v1.EgressMTLS{
+							TLSSecret:         "egress-mtls-secret",
+							ServerName:        true,
+							SessionReuse:      createPointerFromBool(false),
+							TrustedCertSecret: "egress-trusted-ca-secret",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				EgressMTLS: &version2.EgressMTLS{
+					Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
+					CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
+					Ciphers:        "DEFAULT",
+					Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
+					ServerName:     true,
+					SessionReuse:   false,
+					VerifyDepth:    1,
+					VerifyServer:   false,
+					TrustedCert:    "/etc/nginx/secrets/default-egress-trusted-ca-secret",
+					SSLName:        "$proxy_host",
+				},
+			},
+			msg: "egressMTLS reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "egress-mtls-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/egress-mtls-policy": {
+					Spec: conf_v1.PolicySpec{
+						EgressMTLS: &conf_v1.EgressMTLS{
+							TLSSecret:         "egress-mtls-secret",
+							ServerName:        true,
+							SessionReuse:      createPointerFromBool(false),
+							TrustedCertSecret: "egress-trusted-ca-secret-crl",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				EgressMTLS: &version2.EgressMTLS{
+					Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
+					CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
+					Ciphers:        "DEFAULT",
+					Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
+					ServerName:     true,
+					SessionReuse:   false,
+					VerifyDepth:    1,
+					VerifyServer:   false,
+					TrustedCert:    mTLSCertPath,
+					SSLName:        "$proxy_host",
+				},
+			},
+			msg: "egressMTLS with crt and crl",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "oidc-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/oidc-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "oidc-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						OIDC: &conf_v1.OIDC{
+							AuthEndpoint:          "http://example.com/auth",
+							TokenEndpoint:         "http://example.com/token",
+							JWKSURI:               "http://example.com/jwks",
+							ClientID:              "client-id",
+							ClientSecret:          "oidc-secret",
+							Scope:                 "scope",
+							RedirectURI:           "/redirect",
+							ZoneSyncLeeway:        createPointerFromInt(20),
+							AccessTokenEnable:     true,
+							EndSessionEndpoint:    "http://example.com/logout",
+							PostLogoutRedirectURI: "/signed-out",
+							PKCEEnable:            true,
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				OIDC: true,
+			},
+			expectedOidc: &version2.OIDC{
+				AuthEndpoint:          "http://example.com/auth",
+				TokenEndpoint:         "http://example.com/token",
+				JwksURI:               "http://example.com/jwks",
+				ClientID:              "client-id",
+				ClientSecret:          "super_secret_123",
+				Scope:                 "scope",
+				RedirectURI:           "/redirect",
+				ZoneSyncLeeway:        20,
+				AccessTokenEnable:     true,
+				EndSessionEndpoint:    "http://example.com/logout",
+				PostLogoutRedirectURI: "/signed-out",
+				PKCEEnable:            true,
+			},
+			msg: "oidc reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "waf-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/waf-policy": {
+					Spec: conf_v1.PolicySpec{
+						WAF: &conf_v1.WAF{
+							Enable:   true,
+							ApPolicy: "default/dataguard-alarm",
+							SecurityLog: &conf_v1.SecurityLog{
+								Enable:    true,
+								ApLogConf: "default/logconf",
+								LogDest:   "syslog:server=127.0.0.1:514",
+							},
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				WAF: &version2.WAF{
+					Enable:              "on",
+					ApPolicy:            "/etc/nginx/waf/nac-policies/default-dataguard-alarm",
+					ApSecurityLogEnable: true,
+					ApLogConf:           []string{"/etc/nginx/waf/nac-logconfs/default-logconf syslog:server=127.0.0.1:514"},
+				},
+			},
+			msg: "WAF reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cors-policy": {
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins: []string{"*"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				CORS: &version2.CORS{
+					PreflightPath: "/internal_location_cors_default_cors-policy",
+					AllowOrigin:   "*",
+					AllowMethods:  "GET, HEAD, POST",
+				},
+			},
+			msg: "cors reference with any origin",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cors-policy": {
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins:     []string{"https://example.com", `~^https://.*\.example\.org$`},
+							AllowMethods:     []string{"GET", "PUT"},
+							AllowHeaders:     []string{"Content-Type", "Authorization"},
+							ExposeHeaders:    []string{"X-Request-Id"},
+							AllowCredentials: createPointerFromBool(true),
+							MaxAge:           createPointerFromInt(3600),
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				CORS: &version2.CORS{
+					PreflightPath:    "/internal_location_cors_default_cors-policy",
+					AllowOrigin:      "$pol_cors_default_cors_policy_default_test_origin",
+					AllowMethods:     "GET, PUT",
+					AllowHeaders:     "Content-Type, Authorization",
+					ExposeHeaders:    "X-Request-Id",
+					AllowCredentials: true,
+					MaxAge:           3600,
+					VaryOrigin:       true,
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$http_origin",
+						Variable: "$pol_cors_default_cors_policy_default_test_origin",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"https://example.com"`,
+								Result: "$http_origin",
+							},
+							{
+								Value:  `"~^https://.*\.example\.org$"`,
+								Result: "$http_origin",
+							},
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+						},
+					},
+				},
+			},
+			msg: "cors reference with exact and regex origins",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ext-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ext-auth-policy": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							Service:         "auth-svc",
+							Port:            8080,
+							Path:            "/auth",
+							RequestHeaders:  []string{"Authorization"},
+							ResponseHeaders: []string{"X-User-Id"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				ExternalAuth: &version2.ExternalAuth{
+					Path:        "/internal_location_extauth_default_ext-auth-policy",
+					ProxyPass:   "http://vs_default_test_extauth_default_ext-auth-policy/auth",
+					ServiceName: "auth-svc",
+					RequestHeaders: []version2.Header{
+						{
+							Name:  "Authorization",
+							Value: "$http_authorization",
+						},
+					},
+					ResponseHeaders: []version2.ExternalAuthResponseHeader{
+						{
+							Name:             "X-User-Id",
+							Variable:         "$extauth_x_user_id",
+							UpstreamVariable: "$upstream_http_x_user_id",
+						},
+					},
+				},
+			},
+			msg: "external auth reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "api-key-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/api-key-policy": {
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Header: []string{"X-API-Key"},
+								Query:  []string{"apikey"},
+							},
+							ClientSecret: "api-key-secret",
+							RejectCode:   createPointerFromInt(403),
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				APIKey: &version2.APIKey{
+					Sources:        []string{"$http_x_api_key", "$arg_apikey"},
+					ClientVariable: "$apikey_auth_client_name_default_api_key_policy_default_test",
+					RejectCode:     403,
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$apikey_auth_hash",
+						Variable: "$apikey_auth_client_name_default_api_key_policy_default_test",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`,
+								Result: `"client1"`,
+							},
+							{
+								Value:  `"5b6cb866b79cfaffe4162718f97eacafa6732e3d340622fcbda84582840eb9ec"`,
+								Result: `"client2"`,
+							},
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+						},
 					},
 				},
 			},
-			msg: "rate limit reference",
+			msg: "api key reference",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false, &StaticConfigParams{}, false)
+
+	for _, test := range tests {
+		result := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, policyOpts)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+		if test.expectedOidc != nil {
+			if diff := cmp.Diff(test.expectedOidc, vsc.oidcPolCfg.oidc); diff != "" {
+				t.Errorf("generatePolicies() '%v' oidc mismatch (-want +got):\n%s", test.msg, diff)
+			}
+		}
+		if len(vsc.warnings) > 0 {
+			t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
+func TestGeneratePolicies_GeneratesWAFPolicyOnValidApBundle(t *testing.T) {
+	t.Parallel()
+
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+
+	test := struct {
+		policyRefs []conf_v1.PolicyReference
+		policies   map[string]*conf_v1.Policy
+		policyOpts policyOptions
+		context    string
+		want       policiesCfg
+	}{
+		policyRefs: []conf_v1.PolicyReference{
+			{
+				Name:      "waf-bundle",
+				Namespace: "default",
+			},
+		},
+		policies: map[string]*conf_v1.Policy{
+			"default/waf-bundle": {
+				Spec: conf_v1.PolicySpec{
+					WAF: &conf_v1.WAF{
+						Enable:   true,
+						ApBundle: "testWAFPolicyBundle.tgz",
+					},
+				},
+			},
+		},
+		context: "route",
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+	want := policiesCfg{
+		WAF: &version2.WAF{
+			Enable:   "on",
+			ApBundle: "/etc/nginx/waf/bundles/testWAFPolicyBundle.tgz",
+		},
+	}
+	got := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, policyOptions{})
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestGeneratePoliciesWithGeo(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+
+	policies := map[string]*conf_v1.Policy{
+		"default/geo-policy": {
+			Spec: conf_v1.PolicySpec{
+				Geo: &conf_v1.Geo{
+					CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+					ASNDatabase:     "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+					AllowCountries:  []string{"US", "CA"},
+					DenyASNs:        []int{64496},
+					UnknownAction:   "deny",
+					RejectCode:      createPointerFromInt(451),
+					CountryHeader:   "X-Country",
+				},
+			},
+		},
+		"default/geo-policy2": {
+			Spec: conf_v1.PolicySpec{
+				Geo: &conf_v1.Geo{
+					CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+					DenyCountries:   []string{"DE"},
+				},
+			},
 		},
+	}
+
+	tests := []struct {
+		policyRefs       []conf_v1.PolicyReference
+		expected         policiesCfg
+		expectedWarnings Warnings
+		msg              string
+	}{
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "rateLimit-policy",
-					Namespace: "default",
-				},
-				{
-					Name:      "rateLimit-policy2",
-					Namespace: "default",
+					Name: "geo-policy",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/rateLimit-policy": {
-					Spec: conf_v1.PolicySpec{
-						RateLimit: &conf_v1.RateLimit{
-							Key:      "test",
-							ZoneSize: "10M",
-							Rate:     "10r/s",
-						},
+			expected: policiesCfg{
+				Geo: &version2.Geo{
+					AllowedVariables: []string{
+						"$pol_geo_default_geo_policy_default_test_country_allowed",
+						"$pol_geo_default_geo_policy_default_test_asn_allowed",
 					},
-				},
-				"default/rateLimit-policy2": {
-					Spec: conf_v1.PolicySpec{
-						RateLimit: &conf_v1.RateLimit{
-							Key:      "test2",
-							ZoneSize: "20M",
-							Rate:     "20r/s",
+					RejectCode: 451,
+					Headers: []version2.Header{
+						{
+							Name:  "X-Country",
+							Value: "$pol_geo_default_geo_policy_default_test_country",
 						},
 					},
 				},
-			},
-			expected: policiesCfg{
-				LimitReqZones: []version2.LimitReqZone{
+				GeoIP2: []version2.GeoIP2{
 					{
-						Key:      "test",
-						ZoneSize: "10M",
-						Rate:     "10r/s",
-						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+						Database: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+						Variables: []version2.GeoIP2Variable{
+							{
+								Name: "$pol_geo_default_geo_policy_default_test_country",
+								Path: "country iso_code",
+							},
+						},
 					},
 					{
-						Key:      "test2",
-						ZoneSize: "20M",
-						Rate:     "20r/s",
-						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+						Database: "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+						Variables: []version2.GeoIP2Variable{
+							{
+								Name: "$pol_geo_default_geo_policy_default_test_asn",
+								Path: "autonomous_system_number",
+							},
+						},
 					},
 				},
-				LimitReqOptions: version2.LimitReqOptions{
-					LogLevel:   "error",
-					RejectCode: 503,
-				},
-				LimitReqs: []version2.LimitReq{
+				Maps: []version2.Map{
 					{
-						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+						Source:   "$pol_geo_default_geo_policy_default_test_country",
+						Variable: "$pol_geo_default_geo_policy_default_test_country_allowed",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `""`,
+								Result: "0",
+							},
+							{
+								Value:  `"US"`,
+								Result: "1",
+							},
+							{
+								Value:  `"CA"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
 					},
 					{
-						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+						Source:   "$pol_geo_default_geo_policy_default_test_asn",
+						Variable: "$pol_geo_default_geo_policy_default_test_asn_allowed",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `""`,
+								Result: "0",
+							},
+							{
+								Value:  `"64496"`,
+								Result: "0",
+							},
+							{
+								Value:  "default",
+								Result: "1",
+							},
+						},
 					},
 				},
 			},
-			msg: "multi rate limit reference",
+			expectedWarnings: Warnings{},
+			msg:              "geo reference",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "jwt-policy",
-					Namespace: "default",
+					Name: "geo-policy2",
+				},
+				{
+					Name: "geo-policy",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/jwt-policy": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "jwt-policy",
-						Namespace: "default",
+			expected: policiesCfg{
+				Geo: &version2.Geo{
+					AllowedVariables: []string{
+						"$pol_geo_default_geo_policy2_default_test_country_allowed",
 					},
-					Spec: conf_v1.PolicySpec{
-						JWTAuth: &conf_v1.JWTAuth{
-							Realm:  "My Test API",
-							Secret: "jwt-secret",
+					RejectCode: 403,
+				},
+				GeoIP2: []version2.GeoIP2{
+					{
+						Database: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+						Variables: []version2.GeoIP2Variable{
+							{
+								Name: "$pol_geo_default_geo_policy2_default_test_country",
+								Path: "country iso_code",
+							},
+						},
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$pol_geo_default_geo_policy2_default_test_country",
+						Variable: "$pol_geo_default_geo_policy2_default_test_country_allowed",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `""`,
+								Result: "1",
+							},
+							{
+								Value:  `"DE"`,
+								Result: "0",
+							},
+							{
+								Value:  "default",
+								Result: "1",
+							},
 						},
 					},
 				},
 			},
-			expected: policiesCfg{
-				JWTAuth: &version2.JWTAuth{
-					Secret: "/etc/nginx/secrets/default-jwt-secret",
-					Realm:  "My Test API",
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple geo policies in the same context is not valid. Geo policy default/geo-policy will be ignored`,
 				},
-				JWKSAuthEnabled: false,
 			},
-			msg: "jwt reference",
+			msg: "multiple geo references",
 		},
-		{
-			policyRefs: []conf_v1.PolicyReference{
-				{
-					Name:      "jwt-policy-2",
-					Namespace: "default",
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{MainGeoIP2LoadModule: true}, false, false, &StaticConfigParams{}, false)
+
+		result := vsc.generatePolicies(ownerDetails, test.policyRefs, policies, specContext, policyOptions{})
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+		if !reflect.DeepEqual(vsc.warnings, test.expectedWarnings) {
+			t.Errorf("generatePolicies() returned warnings of \n%v but expected \n%v for the case of %s", vsc.warnings, test.expectedWarnings, test.msg)
+		}
+	}
+}
+
+func TestGeneratePoliciesWithJWTClaims(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+	policyOpts := policyOptions{
+		secretRefs: map[string]*secrets.SecretReference{
+			"default/jwt-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeJWK,
 				},
+				Path: "/etc/nginx/secrets/default-jwt-secret",
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/jwt-policy-2": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "jwt-policy-2",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						JWTAuth: &conf_v1.JWTAuth{
-							Realm:    "My Test API",
-							JwksURI:  "https://idp.example.com:443/keys",
-							KeyCache: "1h",
+		},
+	}
+
+	policies := map[string]*conf_v1.Policy{
+		"default/jwt-policy": {
+			Spec: conf_v1.PolicySpec{
+				JWTAuth: &conf_v1.JWTAuth{
+					Realm:  "My Test API",
+					Secret: "jwt-secret",
+					Claims: &conf_v1.JWTClaims{
+						Issuer: "https://idp.example.com",
+						Scopes: []string{"jobs:write"},
+						Roles:  []string{"admin", "operator"},
+						Match: []conf_v1.JWTClaimMatch{
+							{
+								Name:  "email",
+								Regex: `@example\.com$`,
+							},
 						},
+						RejectBody: "Access denied",
 					},
 				},
 			},
-			expected: policiesCfg{
-				JWTAuth: &version2.JWTAuth{
-					Key:   "default/jwt-policy-2",
-					Realm: "My Test API",
-					JwksURI: version2.JwksURI{
-						JwksScheme: "https",
-						JwksHost:   "idp.example.com",
-						JwksPort:   "443",
-						JwksPath:   "/keys",
+		},
+		"default/jwt-policy-audience": {
+			Spec: conf_v1.PolicySpec{
+				JWTAuth: &conf_v1.JWTAuth{
+					Realm:  "My Test API",
+					Secret: "jwt-secret",
+					Claims: &conf_v1.JWTClaims{
+						Audience:   "jobs-api",
+						Roles:      []string{"admin"},
+						RolesClaim: "groups",
+						Match: []conf_v1.JWTClaimMatch{
+							{
+								Name:  "tenant_id",
+								Value: "cafe",
+							},
+						},
 					},
-					KeyCache: "1h",
 				},
-				JWKSAuthEnabled: true,
 			},
-			msg: "Basic jwks example",
 		},
+	}
+
+	tests := []struct {
+		policyRefs []conf_v1.PolicyReference
+		expected   policiesCfg
+		msg        string
+	}{
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "jwt-policy-2",
-					Namespace: "default",
+					Name: "jwt-policy",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/jwt-policy-2": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "jwt-policy-2",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						JWTAuth: &conf_v1.JWTAuth{
-							Realm:    "My Test API",
-							JwksURI:  "https://idp.example.com/keys",
-							KeyCache: "1h",
+			expected: policiesCfg{
+				JWTAuth: &version2.JWTAuth{
+					Secret: "/etc/nginx/secrets/default-jwt-secret",
+					Realm:  "My Test API",
+					Claims: &version2.JWTClaims{
+						RequiredVariables: []string{
+							"$pol_jwt_default_jwt_policy_default_test_iss",
+							"$pol_jwt_default_jwt_policy_default_test_scope_0",
+							"$pol_jwt_default_jwt_policy_default_test_roles",
+							"$pol_jwt_default_jwt_policy_default_test_claim_0",
 						},
+						RejectLocation: "@jwt_claims_reject_default_jwt_policy_default_test",
+						RejectBody:     "Access denied",
 					},
 				},
-			},
-			expected: policiesCfg{
-				JWTAuth: &version2.JWTAuth{
-					Key:   "default/jwt-policy-2",
-					Realm: "My Test API",
-					JwksURI: version2.JwksURI{
-						JwksScheme: "https",
-						JwksHost:   "idp.example.com",
-						JwksPort:   "",
-						JwksPath:   "/keys",
+				Maps: []version2.Map{
+					{
+						Source:   "$jwt_claim_iss",
+						Variable: "$pol_jwt_default_jwt_policy_default_test_iss",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~^https://idp\.example\.com$"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
+					},
+					{
+						Source:   "$jwt_claim_scope",
+						Variable: "$pol_jwt_default_jwt_policy_default_test_scope_0",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~(^|[\s,])jobs:write([\s,]|$)"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
+					},
+					{
+						Source:   "$jwt_claim_roles",
+						Variable: "$pol_jwt_default_jwt_policy_default_test_roles",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~(^|[\s,])admin([\s,]|$)"`,
+								Result: "1",
+							},
+							{
+								Value:  `"~(^|[\s,])operator([\s,]|$)"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
+					},
+					{
+						Source:   "$jwt_claim_email",
+						Variable: "$pol_jwt_default_jwt_policy_default_test_claim_0",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~@example\.com$"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
 					},
-					KeyCache: "1h",
 				},
-				JWKSAuthEnabled: true,
 			},
-			msg: "Basic jwks example, no port in JwksURI",
+			msg: "jwt reference with issuer, scopes, roles and claim regex",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "basic-auth-policy",
-					Namespace: "default",
+					Name: "jwt-policy-audience",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/basic-auth-policy": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "basic-auth-policy",
-						Namespace: "default",
+			expected: policiesCfg{
+				JWTAuth: &version2.JWTAuth{
+					Secret: "/etc/nginx/secrets/default-jwt-secret",
+					Realm:  "My Test API",
+					Claims: &version2.JWTClaims{
+						RequiredVariables: []string{
+							"$pol_jwt_default_jwt_policy_audience_default_test_aud",
+							"$pol_jwt_default_jwt_policy_audience_default_test_roles",
+							"$pol_jwt_default_jwt_policy_audience_default_test_claim_0",
+						},
+					},
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$jwt_claim_aud",
+						Variable: "$pol_jwt_default_jwt_policy_audience_default_test_aud",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~(^|[\s,])jobs-api([\s,]|$)"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
+					},
+					{
+						Source:   "$jwt_claim_groups",
+						Variable: "$pol_jwt_default_jwt_policy_audience_default_test_roles",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~(^|[\s,])admin([\s,]|$)"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
+						},
 					},
-					Spec: conf_v1.PolicySpec{
-						BasicAuth: &conf_v1.BasicAuth{
-							Realm:  "My Test API",
-							Secret: "htpasswd-secret",
+					{
+						Source:   "$jwt_claim_tenant_id",
+						Variable: "$pol_jwt_default_jwt_policy_audience_default_test_claim_0",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"~^cafe$"`,
+								Result: "1",
+							},
+							{
+								Value:  "default",
+								Result: "0",
+							},
 						},
 					},
 				},
 			},
-			expected: policiesCfg{
-				BasicAuth: &version2.BasicAuth{
-					Secret: "/etc/nginx/secrets/default-htpasswd-secret",
-					Realm:  "My Test API",
+			msg: "jwt reference with audience, roles claim and claim value",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false, &StaticConfigParams{}, false)
+
+		result := vsc.generatePolicies(ownerDetails, test.policyRefs, policies, specContext, policyOpts)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+		if len(vsc.warnings) > 0 {
+			t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateJWTClaimsRejectLocations(t *testing.T) {
+	t.Parallel()
+	jwtAuth := &version2.JWTAuth{
+		Claims: &version2.JWTClaims{
+			RequiredVariables: []string{"$pol_jwt_default_jwt_policy_default_test_iss"},
+			RejectLocation:    "@jwt_claims_reject_default_jwt_policy_default_test",
+			RejectBody:        "Access denied",
+		},
+	}
+	locations := []version2.Location{
+		{
+			Path:    "/add-job",
+			JWTAuth: jwtAuth,
+		},
+		{
+			Path:    "/jobs",
+			JWTAuth: jwtAuth,
+		},
+		{
+			Path: "/",
+			JWTAuth: &version2.JWTAuth{
+				Claims: &version2.JWTClaims{
+					RequiredVariables: []string{"$pol_jwt_default_jwt_policy2_default_test_iss"},
 				},
 			},
-			msg: "basic auth reference",
 		},
+	}
+	expected := []version2.ReturnLocation{
 		{
-			policyRefs: []conf_v1.PolicyReference{
+			Name:        "@jwt_claims_reject_default_jwt_policy_default_test",
+			DefaultType: "text/plain",
+			Return: version2.Return{
+				Text: "Access denied",
+			},
+		},
+	}
+
+	result := generateJWTClaimsRejectLocations(nil, locations)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateJWTClaimsRejectLocations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddJWTClaimsErrorPages(t *testing.T) {
+	t.Parallel()
+	serverJWTAuth := &version2.JWTAuth{
+		Claims: &version2.JWTClaims{
+			RequiredVariables: []string{"$pol_jwt_default_jwt_policy_default_test_iss"},
+			RejectLocation:    "@jwt_claims_reject_default_jwt_policy_default_test",
+			RejectBody:        "Access denied",
+		},
+	}
+	locations := []version2.Location{
+		{
+			Path: "/",
+		},
+		{
+			Path: "/jobs",
+			ErrorPages: []version2.ErrorPage{
 				{
-					Name:      "ingress-mtls-policy",
-					Namespace: "default",
+					Name:         "@error_page_0_0",
+					Codes:        "403 404",
+					ResponseCode: 200,
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/ingress-mtls-policy": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "ingress-mtls-policy",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						IngressMTLS: &conf_v1.IngressMTLS{
-							ClientCertSecret: "ingress-mtls-secret",
-							VerifyClient:     "off",
-						},
-					},
+		},
+		{
+			Path: "/add-job",
+			JWTAuth: &version2.JWTAuth{
+				Claims: &version2.JWTClaims{
+					RequiredVariables: []string{"$pol_jwt_default_jwt_policy2_default_test_iss"},
+					RejectLocation:    "@jwt_claims_reject_default_jwt_policy2_default_test",
+					RejectBody:        "Access denied",
 				},
 			},
-			context: "spec",
-			expected: policiesCfg{
-				IngressMTLS: &version2.IngressMTLS{
-					ClientCert:   mTLSCertPath,
-					VerifyClient: "off",
-					VerifyDepth:  1,
+		},
+		{
+			Path: "/status",
+			JWTAuth: &version2.JWTAuth{
+				Claims: &version2.JWTClaims{
+					RequiredVariables: []string{"$pol_jwt_default_jwt_policy3_default_test_iss"},
 				},
 			},
-			msg: "ingressMTLS reference",
 		},
+	}
+	expected := []version2.ErrorPage{
+		{
+			Name:         "@jwt_claims_reject_default_jwt_policy_default_test",
+			Codes:        "403",
+			ResponseCode: 403,
+		},
+	}
+	expectedWithRouteErrorPages := []version2.ErrorPage{
 		{
-			policyRefs: []conf_v1.PolicyReference{
-				{
-					Name:      "ingress-mtls-policy-crl",
-					Namespace: "default",
+			Name:         "@jwt_claims_reject_default_jwt_policy_default_test",
+			Codes:        "403",
+			ResponseCode: 403,
+		},
+		{
+			Name:         "@error_page_0_0",
+			Codes:        "403 404",
+			ResponseCode: 200,
+		},
+	}
+	expectedForLocationPolicy := []version2.ErrorPage{
+		{
+			Name:         "@jwt_claims_reject_default_jwt_policy2_default_test",
+			Codes:        "403",
+			ResponseCode: 403,
+		},
+	}
+
+	addJWTClaimsErrorPages(serverJWTAuth, locations)
+
+	if diff := cmp.Diff(expected, locations[0].ErrorPages); diff != "" {
+		t.Errorf("addJWTClaimsErrorPages() mismatch for the location without error pages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedWithRouteErrorPages, locations[1].ErrorPages); diff != "" {
+		t.Errorf("addJWTClaimsErrorPages() mismatch for the location with the error pages of the route (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedForLocationPolicy, locations[2].ErrorPages); diff != "" {
+		t.Errorf("addJWTClaimsErrorPages() mismatch for the location with a JWT policy (-want +got):\n%s", diff)
+	}
+	if locations[3].ErrorPages != nil {
+		t.Errorf("addJWTClaimsErrorPages() added %v to the location with a JWT policy without a reject body", locations[3].ErrorPages)
+	}
+}
+
+func TestGeneratePoliciesWithJWTForOSS(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "test",
+	}
+	policyOpts := policyOptions{
+		secretRefs: map[string]*secrets.SecretReference{
+			"default/jwt-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeJWK,
 				},
+				Path: "/etc/nginx/secrets/default-jwt-secret",
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/ingress-mtls-policy-crl": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "ingress-mtls-policy-crl",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						IngressMTLS: &conf_v1.IngressMTLS{
-							ClientCertSecret: "ingress-mtls-secret-crl",
-							VerifyClient:     "off",
-						},
+		},
+	}
+
+	policies := map[string]*conf_v1.Policy{
+		"default/jwt-policy": {
+			Spec: conf_v1.PolicySpec{
+				JWTAuth: &conf_v1.JWTAuth{
+					Realm:  "My Test API",
+					Secret: "jwt-secret",
+					Token:  "$arg_token",
+					Claims: &conf_v1.JWTClaims{
+						Issuer:     "https://idp.example.com",
+						Roles:      []string{"admin"},
+						RejectBody: "Access denied",
 					},
 				},
 			},
-			context: "spec",
-			expected: policiesCfg{
-				IngressMTLS: &version2.IngressMTLS{
-					ClientCert:   mTLSCertPath,
-					ClientCrl:    mTLSCrlPath,
-					VerifyClient: "off",
-					VerifyDepth:  1,
-				},
-			},
-			msg: "ingressMTLS reference with ca.crl field in secret",
 		},
-		{
-			policyRefs: []conf_v1.PolicyReference{
-				{
-					Name:      "ingress-mtls-policy-crl",
-					Namespace: "default",
+		"default/jwt-policy-jwks": {
+			Spec: conf_v1.PolicySpec{
+				JWTAuth: &conf_v1.JWTAuth{
+					Realm:    "My Test API",
+					JwksURI:  "https://idp.example.com:8443/keys",
+					KeyCache: "1h",
 				},
 			},
-			policies: map[string]*conf_v1.Policy{
-				"default/ingress-mtls-policy-crl": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "ingress-mtls-policy-crl",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						IngressMTLS: &conf_v1.IngressMTLS{
-							ClientCertSecret: "ingress-mtls-secret",
-							CrlFileName:      "default-ingress-mtls-secret-ca.crl",
-							VerifyClient:     "off",
+		},
+		"default/jwt-policy-inline-flags": {
+			Spec: conf_v1.PolicySpec{
+				JWTAuth: &conf_v1.JWTAuth{
+					Realm:  "My Test API",
+					Secret: "jwt-secret",
+					Claims: &conf_v1.JWTClaims{
+						Match: []conf_v1.JWTClaimMatch{
+							{
+								Name:  "role",
+								Regex: "(?i)admin",
+							},
 						},
 					},
 				},
 			},
-			context: "spec",
-			expected: policiesCfg{
-				IngressMTLS: &version2.IngressMTLS{
-					ClientCert:   mTLSCertPath,
-					ClientCrl:    mTLSCrlPath,
-					VerifyClient: "off",
-					VerifyDepth:  1,
+		},
+		"default/external-auth-policy": {
+			Spec: conf_v1.PolicySpec{
+				ExternalAuth: &conf_v1.ExternalAuth{
+					Service: "auth-svc",
+					Port:    8080,
+					Path:    "/check",
 				},
 			},
-			msg: "ingressMTLS reference with crl field in policy",
 		},
+	}
+
+	tests := []struct {
+		policyRefs       []conf_v1.PolicyReference
+		expected         policiesCfg
+		expectedWarnings []string
+		msg              string
+	}{
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "egress-mtls-policy",
-					Namespace: "default",
-				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/egress-mtls-policy": {
-					Spec: conf_v1.PolicySpec{
-						EgressMTLS: &conf_v1.EgressMTLS{
-							TLSSecret:         "egress-mtls-secret",
-							ServerName:        true,
-							SessionReuse:      createPointerFromBool(false),
-							TrustedCertSecret: "egress-trusted-ca-secret",
-						},
-					},
+					Name: "jwt-policy",
 				},
 			},
-			context: "route",
 			expected: policiesCfg{
-				EgressMTLS: &version2.EgressMTLS{
-					Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
-					CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
-					Ciphers:        "DEFAULT",
-					Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
-					ServerName:     true,
-					SessionReuse:   false,
-					VerifyDepth:    1,
-					VerifyServer:   false,
-					TrustedCert:    "/etc/nginx/secrets/default-egress-trusted-ca-secret",
-					SSLName:        "$proxy_host",
+				JWTAuth: &version2.JWTAuth{
+					Secret: "/etc/nginx/secrets/default-jwt-secret",
+					Realm:  "My Test API",
+					Token:  "$arg_token",
+					Claims: &version2.JWTClaims{
+						RejectLocation: "@jwt_claims_reject_default_jwt_policy_default_test",
+						RejectBody:     "Access denied",
+					},
+					Verifier: &version2.JWTVerifier{
+						Path:        "/internal_location_jwtauth_default_jwt-policy",
+						TokenSource: "arg_token",
+						Claims: base64.StdEncoding.EncodeToString(
+							[]byte(`[{"claim":"iss","regexes":["^https://idp\\.example\\.com$"]},{"claim":"roles","regexes":["(^|[\\s,])admin([\\s,]|$)"]}]`),
+						),
+					},
 				},
 			},
-			msg: "egressMTLS reference",
+			msg: "jwt reference with claims",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "egress-mtls-policy",
-					Namespace: "default",
-				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/egress-mtls-policy": {
-					Spec: conf_v1.PolicySpec{
-						EgressMTLS: &conf_v1.EgressMTLS{
-							TLSSecret:         "egress-mtls-secret",
-							ServerName:        true,
-							SessionReuse:      createPointerFromBool(false),
-							TrustedCertSecret: "egress-trusted-ca-secret-crl",
-						},
-					},
+					Name: "jwt-policy-jwks",
 				},
 			},
-			context: "route",
 			expected: policiesCfg{
-				EgressMTLS: &version2.EgressMTLS{
-					Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
-					CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
-					Ciphers:        "DEFAULT",
-					Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
-					ServerName:     true,
-					SessionReuse:   false,
-					VerifyDepth:    1,
-					VerifyServer:   false,
-					TrustedCert:    mTLSCertPath,
-					SSLName:        "$proxy_host",
+				JWTAuth: &version2.JWTAuth{
+					Key:   "default/jwt-policy-jwks",
+					Realm: "My Test API",
+					JwksURI: version2.JwksURI{
+						JwksScheme: "https",
+						JwksHost:   "idp.example.com",
+						JwksPort:   "8443",
+						JwksPath:   "/keys",
+					},
+					KeyCache: "1h",
+					Verifier: &version2.JWTVerifier{
+						Path: "/internal_location_jwtauth_default_jwt-policy-jwks",
+					},
 				},
+				JWKSAuthEnabled: true,
 			},
-			msg: "egressMTLS with crt and crl",
+			msg: "jwks reference",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "oidc-policy",
-					Namespace: "default",
+					Name: "external-auth-policy",
 				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/oidc-policy": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "oidc-policy",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						OIDC: &conf_v1.OIDC{
-							AuthEndpoint:      "http://example.com/auth",
-							TokenEndpoint:     "http://example.com/token",
-							JWKSURI:           "http://example.com/jwks",
-							ClientID:          "client-id",
-							ClientSecret:      "oidc-secret",
-							Scope:             "scope",
-							RedirectURI:       "/redirect",
-							ZoneSyncLeeway:    createPointerFromInt(20),
-							AccessTokenEnable: true,
-						},
-					},
+				{
+					Name: "jwt-policy-jwks",
 				},
 			},
 			expected: policiesCfg{
-				OIDC: true,
+				ExternalAuth: &version2.ExternalAuth{
+					Path:        "/internal_location_extauth_default_external-auth-policy",
+					ProxyPass:   "http://vs_default_test_extauth_default_external-auth-policy/check",
+					ServiceName: "auth-svc",
+				},
 			},
-			msg: "oidc reference",
+			expectedWarnings: []string{
+				"JWT policy default/jwt-policy-jwks can't be combined with an external auth policy in the same context in NGINX and will be ignored",
+			},
+			msg: "jwt reference after external auth reference",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "waf-policy",
-					Namespace: "default",
-				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/waf-policy": {
-					Spec: conf_v1.PolicySpec{
-						WAF: &conf_v1.WAF{
-							Enable:   true,
-							ApPolicy: "default/dataguard-alarm",
-							SecurityLog: &conf_v1.SecurityLog{
-								Enable:    true,
-								ApLogConf: "default/logconf",
-								LogDest:   "syslog:server=127.0.0.1:514",
-							},
-						},
-					},
+					Name: "jwt-policy-inline-flags",
 				},
 			},
-			context: "route",
 			expected: policiesCfg{
-				WAF: &version2.WAF{
-					Enable:              "on",
-					ApPolicy:            "/etc/nginx/waf/nac-policies/default-dataguard-alarm",
-					ApSecurityLogEnable: true,
-					ApLogConf:           []string{"/etc/nginx/waf/nac-logconfs/default-logconf syslog:server=127.0.0.1:514"},
+				ErrorReturn: &version2.Return{
+					Code: 500,
 				},
 			},
-			msg: "WAF reference",
+			expectedWarnings: []string{
+				`JWT policy default/jwt-policy-inline-flags has a regex for the claim role that is not supported in NGINX: "(?i" is not supported`,
+			},
+			msg: "jwt reference with a regex not supported by njs",
 		},
 	}
 
-	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
-
 	for _, test := range tests {
-		result := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, policyOpts)
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+
+		result := vsc.generatePolicies(ownerDetails, test.policyRefs, policies, specContext, policyOpts)
 		if diff := cmp.Diff(test.expected, result); diff != "" {
 			t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
 		}
-		if len(vsc.warnings) > 0 {
-			t.Errorf("generatePolicies() returned unexpected warnings %v for the case of %s", vsc.warnings, test.msg)
+		if diff := cmp.Diff(test.expectedWarnings, vsc.warnings[nil]); diff != "" {
+			t.Errorf("generatePolicies() '%v' warnings mismatch (-want +got):\n%s", test.msg, diff)
 		}
 	}
 }
 
-func TestGeneratePolicies_GeneratesWAFPolicyOnValidApBundle(t *testing.T) {
+func TestValidateNJSRegex(t *testing.T) {
 	t.Parallel()
+	validRegexes := []string{
+		"",
+		"^admin$",
+		`(^|[\s,])admin([\s,]|$)`,
+		"^(?:admin|editor)$",
+		"^(?<role>admin)$",
+		"admin(?=s)",
+		`\(?admin`,
+	}
+	for _, regex := range validRegexes {
+		if err := validateNJSRegex(regex); err != nil {
+			t.Errorf("validateNJSRegex(%q) returned error %v", regex, err)
+		}
+	}
 
-	ownerDetails := policyOwnerDetails{
-		owner:          nil, // nil is OK for the unit test
-		ownerNamespace: "default",
-		vsNamespace:    "default",
-		vsName:         "test",
+	invalidRegexes := []string{
+		"(?i)admin",
+		"(?P<role>admin)",
+		"(?s:.*)",
+		`\Aadmin\z`,
+		`\pL+`,
+		"[[:alpha:]]+",
+	}
+	for _, regex := range invalidRegexes {
+		if err := validateNJSRegex(regex); err == nil {
+			t.Errorf("validateNJSRegex(%q) returned no error", regex)
+		}
 	}
+}
 
-	test := struct {
-		policyRefs []conf_v1.PolicyReference
-		policies   map[string]*conf_v1.Policy
-		policyOpts policyOptions
-		context    string
-		want       policiesCfg
-	}{
-		policyRefs: []conf_v1.PolicyReference{
+func TestGenerateJWTVerifierLocations(t *testing.T) {
+	t.Parallel()
+	serverJWTAuth := &version2.JWTAuth{
+		Realm:  "My Test API",
+		Secret: "/etc/nginx/secrets/default-jwt-secret",
+		Verifier: &version2.JWTVerifier{
+			Path: "/internal_location_jwtauth_default_jwt-policy",
+		},
+	}
+	locationJWTAuth := &version2.JWTAuth{
+		Realm:  "My Test API",
+		Secret: "/etc/nginx/secrets/default-jwt-secret",
+		Verifier: &version2.JWTVerifier{
+			Path: "/internal_location_jwtauth_default_jwt-policy-2",
+		},
+	}
+	locations := []version2.Location{
+		{
+			Path:    "/add-job",
+			JWTAuth: locationJWTAuth,
+		},
+		{
+			Path:    "/jobs",
+			JWTAuth: locationJWTAuth,
+		},
+		{
+			Path:    "/",
+			JWTAuth: serverJWTAuth,
+		},
+		{
+			Path: "/plus",
+			JWTAuth: &version2.JWTAuth{
+				Realm:  "My Test API",
+				Secret: "/etc/nginx/secrets/default-jwt-secret",
+			},
+		},
+	}
+	expected := []version2.JWTAuth{*serverJWTAuth, *locationJWTAuth}
+
+	result := generateJWTVerifierLocations(serverJWTAuth, locations)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateJWTVerifierLocations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateJWTClaimNames(t *testing.T) {
+	t.Parallel()
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			Spec: conf_v1.VirtualServerSpec{
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Proxy: &conf_v1.ActionProxy{
+								Upstream: "tea",
+								RequestHeaders: &conf_v1.ProxyRequestHeaders{
+									Set: []conf_v1.Header{
+										{
+											Name:  "X-Tenant",
+											Value: "${jwt_claim_tenantId}",
+										},
+									},
+								},
+								ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+									Add: []conf_v1.AddHeader{
+										{
+											Header: conf_v1.Header{
+												Name:  "X-User",
+												Value: "${jwt_claim_name} ${http_x_user}",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Path:     "/juice",
+						SplitKey: "${jwt_claim_group}",
+						Splits: []conf_v1.Split{
+							{
+								Weight: 100,
+								Action: &conf_v1.Action{
+									Pass: "juice",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
 			{
-				Name:      "waf-bundle",
-				Namespace: "default",
+				Spec: conf_v1.VirtualServerRouteSpec{
+					Subroutes: []conf_v1.Route{
+						{
+							Path:             "/coffee",
+							LocationSnippets: "add_header X-Email $jwt_claim_email;",
+							Matches: []conf_v1.Match{
+								{
+									SplitKey: "${jwt_claim_region}",
+									Splits: []conf_v1.Split{
+										{
+											Weight: 100,
+											Action: &conf_v1.Action{
+												Proxy: &conf_v1.ActionProxy{
+													Upstream: "coffee",
+													RequestHeaders: &conf_v1.ProxyRequestHeaders{
+														Set: []conf_v1.Header{
+															{
+																Name:  "X-Team",
+																Value: "${jwt_claim_team}",
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							Action: &conf_v1.Action{
+								Pass: "coffee",
+							},
+						},
+					},
+				},
 			},
 		},
-		policies: map[string]*conf_v1.Policy{
-			"default/waf-bundle": {
+		Policies: map[string]*conf_v1.Policy{
+			"default/rate-limit-policy": {
 				Spec: conf_v1.PolicySpec{
-					WAF: &conf_v1.WAF{
-						Enable:   true,
-						ApBundle: "testWAFPolicyBundle.tgz",
+					RateLimit: &conf_v1.RateLimit{
+						TierSelector: "${jwt_claim_plan}",
 					},
 				},
 			},
 		},
-		context: "route",
 	}
 
-	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
-	want := policiesCfg{
-		WAF: &version2.WAF{
-			Enable:   "on",
-			ApBundle: "/etc/nginx/waf/bundles/testWAFPolicyBundle.tgz",
+	tests := []struct {
+		vsEx         *VirtualServerEx
+		hasVerifiers bool
+		expected     []string
+		msg          string
+	}{
+		{
+			vsEx:         vsEx,
+			hasVerifiers: true,
+			expected:     []string{"group", "name", "region", "sub", "team", "tenantId"},
+			msg:          "claims referenced in headers and split keys with verifiers",
+		},
+		{
+			vsEx:         vsEx,
+			hasVerifiers: false,
+			expected:     []string{"group", "name", "region", "team", "tenantId"},
+			msg:          "claims referenced in headers and split keys without verifiers",
+		},
+		{
+			vsEx: &VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{},
+			},
+			hasVerifiers: true,
+			expected:     []string{"sub"},
+			msg:          "no referenced claims with verifiers",
+		},
+		{
+			vsEx: &VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{},
+			},
+			hasVerifiers: false,
+			expected:     nil,
+			msg:          "no referenced claims without verifiers",
 		},
 	}
-	got := vsc.generatePolicies(ownerDetails, test.policyRefs, test.policies, test.context, policyOptions{})
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+
+	for _, test := range tests {
+		result := generateJWTClaimNames(test.vsEx, test.hasVerifiers)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateJWTClaimNames() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
 	}
 }
 
@@ -4913,75 +6755,154 @@ func TestGeneratePoliciesFails(t *testing.T) {
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "rateLimit-policy",
+					Name:      "rateLimit-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "rateLimit-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "test",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+						},
+					},
+				},
+				"default/rateLimit-policy2": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:        "test2",
+							ZoneSize:   "20M",
+							Rate:       "20r/s",
+							DryRun:     &dryRunOverride,
+							LogLevel:   "info",
+							RejectCode: &rejectCodeOverride,
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{},
+			expected: policiesCfg{
+				LimitReqZones: []version2.LimitReqZone{
+					{
+						Key:      "test",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+					{
+						Key:      "test2",
+						ZoneSize: "20M",
+						Rate:     "20r/s",
+						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+					},
+				},
+				LimitReqOptions: version2.LimitReqOptions{
+					LogLevel:   "error",
+					RejectCode: 503,
+				},
+				LimitReqs: []version2.LimitReq{
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+					},
+					{
+						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+					},
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`RateLimit policy default/rateLimit-policy2 with limit request option dryRun='true' is overridden to dryRun='false' by the first policy reference in this context`,
+					`RateLimit policy default/rateLimit-policy2 with limit request option logLevel='info' is overridden to logLevel='error' by the first policy reference in this context`,
+					`RateLimit policy default/rateLimit-policy2 with limit request option rejectCode='505' is overridden to rejectCode='503' by the first policy reference in this context`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "rate limit policy limit request option override",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "bandwidthLimit-policy",
 					Namespace: "default",
 				},
 				{
-					Name:      "rateLimit-policy2",
+					Name:      "bandwidthLimit-policy2",
 					Namespace: "default",
 				},
 			},
 			policies: map[string]*conf_v1.Policy{
-				"default/rateLimit-policy": {
+				"default/bandwidthLimit-policy": {
 					Spec: conf_v1.PolicySpec{
-						RateLimit: &conf_v1.RateLimit{
-							Key:      "test",
-							ZoneSize: "10M",
-							Rate:     "10r/s",
+						BandwidthLimit: &conf_v1.BandwidthLimit{
+							Key:         "test",
+							Connections: 10,
+							ZoneSize:    "10M",
+							Rate:        "1m",
 						},
 					},
 				},
-				"default/rateLimit-policy2": {
+				"default/bandwidthLimit-policy2": {
 					Spec: conf_v1.PolicySpec{
-						RateLimit: &conf_v1.RateLimit{
-							Key:        "test2",
-							ZoneSize:   "20M",
-							Rate:       "20r/s",
-							DryRun:     &dryRunOverride,
-							LogLevel:   "info",
-							RejectCode: &rejectCodeOverride,
+						BandwidthLimit: &conf_v1.BandwidthLimit{
+							Key:         "test2",
+							Connections: 20,
+							ZoneSize:    "20M",
+							DryRun:      &dryRunOverride,
+							LogLevel:    "info",
+							RejectCode:  &rejectCodeOverride,
+							Rate:        "2m",
 						},
 					},
 				},
 			},
 			policyOpts: policyOptions{},
 			expected: policiesCfg{
-				LimitReqZones: []version2.LimitReqZone{
+				LimitConnZones: []version2.LimitConnZone{
 					{
 						Key:      "test",
+						ZoneName: "pol_bl_default_bandwidthLimit-policy_default_test",
 						ZoneSize: "10M",
-						Rate:     "10r/s",
-						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
 					},
 					{
 						Key:      "test2",
+						ZoneName: "pol_bl_default_bandwidthLimit-policy2_default_test",
 						ZoneSize: "20M",
-						Rate:     "20r/s",
-						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
 					},
 				},
-				LimitReqOptions: version2.LimitReqOptions{
+				LimitConnOptions: version2.LimitConnOptions{
 					LogLevel:   "error",
 					RejectCode: 503,
 				},
-				LimitReqs: []version2.LimitReq{
+				LimitConns: []version2.LimitConn{
 					{
-						ZoneName: "pol_rl_default_rateLimit-policy_default_test",
+						ZoneName:    "pol_bl_default_bandwidthLimit-policy_default_test",
+						Connections: 10,
 					},
 					{
-						ZoneName: "pol_rl_default_rateLimit-policy2_default_test",
+						ZoneName:    "pol_bl_default_bandwidthLimit-policy2_default_test",
+						Connections: 20,
 					},
 				},
+				LimitRate: &version2.LimitRate{
+					Rate: "1m",
+				},
 			},
 			expectedWarnings: Warnings{
 				nil: {
-					`RateLimit policy default/rateLimit-policy2 with limit request option dryRun='true' is overridden to dryRun='false' by the first policy reference in this context`,
-					`RateLimit policy default/rateLimit-policy2 with limit request option logLevel='info' is overridden to logLevel='error' by the first policy reference in this context`,
-					`RateLimit policy default/rateLimit-policy2 with limit request option rejectCode='505' is overridden to rejectCode='503' by the first policy reference in this context`,
+					`BandwidthLimit policy default/bandwidthLimit-policy2 with rate='2m' is overridden to rate='1m' by the first policy reference in this context`,
+					`BandwidthLimit policy default/bandwidthLimit-policy2 with limit connection option dryRun='true' is overridden to dryRun='false' by the first policy reference in this context`,
+					`BandwidthLimit policy default/bandwidthLimit-policy2 with limit connection option logLevel='info' is overridden to logLevel='error' by the first policy reference in this context`,
+					`BandwidthLimit policy default/bandwidthLimit-policy2 with limit connection option rejectCode='505' is overridden to rejectCode='503' by the first policy reference in this context`,
 				},
 			},
 			expectedOidc: &oidcPolicyCfg{},
-			msg:          "rate limit policy limit request option override",
+			msg:          "bandwidth limit policy option override",
 		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
@@ -5291,6 +7212,211 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			expectedOidc: &oidcPolicyCfg{},
 			msg:          "multi basic auth reference",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "cors-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "cors-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/cors-policy": {
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins: []string{"*"},
+						},
+					},
+				},
+				"default/cors-policy2": {
+					Spec: conf_v1.PolicySpec{
+						CORS: &conf_v1.CORS{
+							AllowOrigins: []string{"https://example.com"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				CORS: &version2.CORS{
+					PreflightPath: "/internal_location_cors_default_cors-policy",
+					AllowOrigin:   "*",
+					AllowMethods:  "GET, HEAD, POST",
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple CORS policies in the same context is not valid. CORS policy default/cors-policy2 will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "multi cors reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ext-auth-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "ext-auth-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ext-auth-policy": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							Service: "auth-svc",
+							Port:    8080,
+							Path:    "/auth",
+						},
+					},
+				},
+				"default/ext-auth-policy2": {
+					Spec: conf_v1.PolicySpec{
+						ExternalAuth: &conf_v1.ExternalAuth{
+							Service: "auth-svc2",
+							Port:    8080,
+							Path:    "/auth",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				ExternalAuth: &version2.ExternalAuth{
+					Path:        "/internal_location_extauth_default_ext-auth-policy",
+					ProxyPass:   "http://vs_default_test_extauth_default_ext-auth-policy/auth",
+					ServiceName: "auth-svc",
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple external auth policies in the same context is not valid. External auth policy default/ext-auth-policy2 will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "multi external auth reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "api-key-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/api-key-policy": {
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Header: []string{"X-API-Key"},
+							},
+							ClientSecret: "api-key-secret",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/api-key-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeHtpasswd,
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`API key policy default/api-key-policy references a secret default/api-key-secret of a wrong type 'nginx.org/htpasswd', must be 'nginx.org/apikey'`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "api key references wrong secret type",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "api-key-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "api-key-policy2",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/api-key-policy": {
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Header: []string{"X-API-Key"},
+							},
+							ClientSecret: "api-key-secret",
+						},
+					},
+				},
+				"default/api-key-policy2": {
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Query: []string{"apikey"},
+							},
+							ClientSecret: "api-key-secret",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/api-key-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeAPIKey,
+							Data: map[string][]byte{
+								"client1": []byte("password"),
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				APIKey: &version2.APIKey{
+					Sources:        []string{"$http_x_api_key"},
+					ClientVariable: "$apikey_auth_client_name_default_api_key_policy_default_test",
+					RejectCode:     401,
+				},
+				Maps: []version2.Map{
+					{
+						Source:   "$apikey_auth_hash",
+						Variable: "$apikey_auth_client_name_default_api_key_policy_default_test",
+						Parameters: []version2.Parameter{
+							{
+								Value:  `"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`,
+								Result: `"client1"`,
+							},
+							{
+								Value:  "default",
+								Result: `""`,
+							},
+						},
+					},
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Multiple API key policies in the same context is not valid. API key policy default/api-key-policy2 will be ignored`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "multi api key reference",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -6074,15 +8200,16 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			},
 			expectedOidc: &oidcPolicyCfg{
 				&version2.OIDC{
-					AuthEndpoint:      "https://foo.com/auth",
-					TokenEndpoint:     "https://foo.com/token",
-					JwksURI:           "https://foo.com/certs",
-					ClientID:          "foo",
-					ClientSecret:      "super_secret_123",
-					RedirectURI:       "/_codexch",
-					Scope:             "openid",
-					ZoneSyncLeeway:    200,
-					AccessTokenEnable: true,
+					AuthEndpoint:          "https://foo.com/auth",
+					TokenEndpoint:         "https://foo.com/token",
+					JwksURI:               "https://foo.com/certs",
+					ClientID:              "foo",
+					ClientSecret:          "super_secret_123",
+					RedirectURI:           "/_codexch",
+					Scope:                 "openid",
+					ZoneSyncLeeway:        200,
+					AccessTokenEnable:     true,
+					PostLogoutRedirectURI: "/_logout",
 				},
 				"default/oidc-policy",
 			},
@@ -6150,10 +8277,40 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			expectedOidc: &oidcPolicyCfg{},
 			msg:          "multi waf",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "geo-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/geo-policy": {
+					Spec: conf_v1.PolicySpec{
+						Geo: &conf_v1.Geo{
+							CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+							AllowCountries:  []string{"US"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					`Geo policy default/geo-policy requires the geoip2 module, which is loaded with the geoip2 ConfigMap key`,
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "geo without the geoip2 module",
+		},
 	}
 
 	for _, test := range tests {
-		vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false, &StaticConfigParams{}, false)
 
 		if test.oidcPolCfg != nil {
 			vsc.oidcPolCfg = test.oidcPolCfg
@@ -6193,32 +8350,163 @@ func TestRemoveDuplicates(t *testing.T) {
 				{ZoneName: "test2"},
 				{ZoneName: "test3"},
 			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+	}
+	for _, test := range tests {
+		result := removeDuplicateLimitReqZones(test.rlz)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
+		}
+	}
+}
+
+func TestRemoveDuplicateMaps(t *testing.T) {
+	t.Parallel()
+	maps := []version2.Map{
+		{Variable: "$test"},
+		{Variable: "$test"},
+		{Variable: "$test2"},
+	}
+	expected := []version2.Map{
+		{Variable: "$test"},
+		{Variable: "$test2"},
+	}
+
+	result := removeDuplicateMaps(maps)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("removeDuplicateMaps() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateCORSPreflightLocations(t *testing.T) {
+	t.Parallel()
+	cors := &version2.CORS{
+		PreflightPath: "/internal_location_cors_default_cors-policy",
+		AllowOrigin:   "*",
+		AllowMethods:  "GET, HEAD, POST",
+	}
+	cors2 := &version2.CORS{
+		PreflightPath: "/internal_location_cors_default_cors-policy2",
+		AllowOrigin:   "*",
+		AllowMethods:  "GET",
+	}
+	locations := []version2.Location{
+		{Path: "/", CORS: cors},
+		{Path: "/tea"},
+		{Path: "/coffee", CORS: cors},
+		{Path: "/juice", CORS: cors2},
+	}
+	expected := []version2.CORS{*cors, *cors2}
+
+	result := generateCORSPreflightLocations(locations)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateCORSPreflightLocations() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateExternalAuthLocations(t *testing.T) {
+	t.Parallel()
+	extAuth := &version2.ExternalAuth{
+		Path:        "/internal_location_extauth_default_ext-auth-policy",
+		ProxyPass:   "http://vs_default_cafe_extauth_default_ext-auth-policy/auth",
+		ServiceName: "auth-svc",
+	}
+	locations := []version2.Location{
+		{Path: "/", ExternalAuth: extAuth},
+		{Path: "/tea"},
+		{Path: "/coffee", ExternalAuth: extAuth},
+	}
+	expected := []version2.ExternalAuth{*extAuth}
+
+	result := generateExternalAuthLocations(locations)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateExternalAuthLocations() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateExternalAuthUpstreams(t *testing.T) {
+	t.Parallel()
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/ext-auth-policy": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ext-auth-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					ExternalAuth: &conf_v1.ExternalAuth{
+						Service: "auth-svc",
+						Port:    8080,
+						Path:    "/auth",
+					},
+				},
+			},
+			"default/allow-policy": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "allow-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					AccessControl: &conf_v1.AccessControl{
+						Allow: []string{"127.0.0.1"},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/auth-svc:8080": {
+				"10.0.0.40:8080",
 			},
 		},
+	}
+
+	expected := []version2.Upstream{
 		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-				{ZoneName: "test3"},
+			Name: "vs_default_cafe_extauth_default_ext-auth-policy",
+			UpstreamLabels: version2.UpstreamLabels{
+				Service:           "auth-svc",
+				ResourceType:      "virtualserver",
+				ResourceName:      "cafe",
+				ResourceNamespace: "default",
 			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
+			Servers: []version2.UpstreamServer{
+				{
+					Address: "10.0.0.40:8080",
+				},
 			},
 		},
 	}
-	for _, test := range tests {
-		result := removeDuplicateLimitReqZones(test.rlz)
-		if !reflect.DeepEqual(result, test.expected) {
-			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
-		}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{}, false, false, &StaticConfigParams{}, false)
+
+	result := vsc.generateExternalAuthUpstreams(vsEx)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateExternalAuthUpstreams() returned unexpected result (-want +got):\n%s", diff)
 	}
 }
 
@@ -6726,6 +9014,19 @@ func TestGenerateLocationForProxying(t *testing.T) {
 	}
 }
 
+func TestGenerateLocationForProxyingWithForwardedHeaders(t *testing.T) {
+	t.Parallel()
+	cfgParams := ConfigParams{}
+	upstream := conf_v1.Upstream{
+		ForwardedHeaders: true,
+	}
+
+	result := generateLocationForProxying("/", "test-upstream", upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "")
+	if !result.ForwardedHeaders {
+		t.Error("generateLocationForProxying() returned a location with ForwardedHeaders=false, but expected true")
+	}
+}
+
 func TestGenerateLocationForGrpcProxying(t *testing.T) {
 	t.Parallel()
 	cfgParams := ConfigParams{
@@ -7592,6 +9893,7 @@ func TestGenerateSplits(t *testing.T) {
 
 	resultSplitClient, resultLocations, resultReturnLocations := generateSplits(
 		splits,
+		"",
 		upstreamNamer,
 		crUpstreams,
 		variableNamer,
@@ -7619,6 +9921,228 @@ func TestGenerateSplits(t *testing.T) {
 	}
 }
 
+func TestGenerateMirror(t *testing.T) {
+	t.Parallel()
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := NewUpstreamNamerForVirtualServer(&virtualServer)
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v1": {Service: "coffee-v1"},
+		"vs_default_cafe_coffee-v2": {Service: "coffee-v2", TLS: conf_v1.UpstreamTLS{Enable: true}},
+	}
+
+	tests := []struct {
+		path     string
+		action   *conf_v1.Action
+		expected *version2.Mirror
+		msg      string
+	}{
+		{
+			path: "/coffee",
+			action: &conf_v1.Action{
+				Pass: "coffee-v1",
+			},
+			expected: nil,
+			msg:      "pass action",
+		},
+		{
+			path: "/coffee",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "coffee-v1",
+				},
+			},
+			expected: nil,
+			msg:      "proxy action without mirror",
+		},
+		{
+			path: "/coffee",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "coffee-v1",
+					Mirror: &conf_v1.ProxyMirror{
+						Upstream: "coffee-v2",
+					},
+				},
+			},
+			expected: &version2.Mirror{
+				ProxyPass:    "https://vs_default_cafe_coffee-v2$request_uri",
+				ProxySSLName: "coffee-v2.default.svc",
+				ServiceName:  "coffee-v2",
+				Percentage:   100,
+			},
+			msg: "proxy action with mirror to TLS upstream",
+		},
+		{
+			path: "/coffee",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "coffee-v2",
+					Mirror: &conf_v1.ProxyMirror{
+						Upstream:   "coffee-v1",
+						Percentage: createPointerFromInt(25),
+					},
+				},
+			},
+			expected: &version2.Mirror{
+				ProxyPass:   "http://vs_default_cafe_coffee-v1$request_uri",
+				ServiceName: "coffee-v1",
+				Percentage:  25,
+			},
+			msg: "proxy action with sampled mirror",
+		},
+		{
+			path: "/coffee",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream:    "coffee-v2",
+					RewritePath: "/beans",
+					Mirror: &conf_v1.ProxyMirror{
+						Upstream: "coffee-v1",
+					},
+				},
+			},
+			expected: &version2.Mirror{
+				ProxyPass:   "http://vs_default_cafe_coffee-v1",
+				Rewrites:    []string{"^ $request_uri_no_args", `"^/coffee(.*)$" "/beans$1" break`},
+				ServiceName: "coffee-v1",
+				Percentage:  100,
+			},
+			msg: "proxy action with rewrite path and mirror",
+		},
+		{
+			path: "~ ^/coffee/(.*)",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream:    "coffee-v2",
+					RewritePath: "/beans/$1",
+					Mirror: &conf_v1.ProxyMirror{
+						Upstream: "coffee-v1",
+					},
+				},
+			},
+			expected: &version2.Mirror{
+				ProxyPass:   "http://vs_default_cafe_coffee-v1",
+				Rewrites:    []string{"^ $request_uri_no_args", `"^^/coffee/(.*)" "/beans/$1" break`},
+				ServiceName: "coffee-v1",
+				Percentage:  100,
+			},
+			msg: "proxy action with regex path, rewrite path and mirror",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateMirror(test.path, test.action, upstreamNamer, crUpstreams)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateMirror() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateMirrorLocations(t *testing.T) {
+	t.Parallel()
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	variableNamer := newVariableNamer(&virtualServer)
+
+	locations := []version2.Location{
+		{
+			Path: "/tea",
+		},
+		{
+			Path:   "/coffee",
+			Mirror: &version2.Mirror{ProxyPass: "http://vs_default_cafe_coffee-v2", Percentage: 100},
+		},
+		{
+			Path:   "/juice",
+			Mirror: &version2.Mirror{ProxyPass: "http://vs_default_cafe_juice-v2", Percentage: 5},
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path: "/tea",
+		},
+		{
+			Path: "/coffee",
+			Mirror: &version2.Mirror{
+				Path:       "/internal_location_mirror_0",
+				ProxyPass:  "http://vs_default_cafe_coffee-v2",
+				Percentage: 100,
+			},
+		},
+		{
+			Path: "/juice",
+			Mirror: &version2.Mirror{
+				Path:           "/internal_location_mirror_1",
+				ProxyPass:      "http://vs_default_cafe_juice-v2",
+				Percentage:     5,
+				SampleVariable: "$vs_default_cafe_mirror_1",
+			},
+		},
+	}
+	expectedSplitClients := []version2.SplitClient{
+		{
+			Source:   "$request_id",
+			Variable: "$vs_default_cafe_mirror_1",
+			Distributions: []version2.Distribution{
+				{
+					Weight: "5%",
+					Value:  "1",
+				},
+				{
+					Weight: "*",
+					Value:  `""`,
+				},
+			},
+		},
+	}
+
+	splitClients := generateMirrorLocations(locations, variableNamer)
+	if diff := cmp.Diff(expectedSplitClients, splitClients); diff != "" {
+		t.Errorf("generateMirrorLocations() split clients mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("generateMirrorLocations() locations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateSplitClientSource(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		splitKey string
+		expected string
+	}{
+		{
+			splitKey: "",
+			expected: "$request_id",
+		},
+		{
+			splitKey: "${cookie_user}",
+			expected: `"${cookie_user}"`,
+		},
+		{
+			splitKey: "${remote_addr}${http_x_tenant}",
+			expected: `"${remote_addr}${http_x_tenant}"`,
+		},
+	}
+
+	for _, test := range tests {
+		result := generateSplitClientSource(test.splitKey)
+		if result != test.expected {
+			t.Errorf("generateSplitClientSource(%q) returned %q but expected %q", test.splitKey, result, test.expected)
+		}
+	}
+}
+
 func TestGenerateDefaultSplitsConfig(t *testing.T) {
 	t.Parallel()
 	route := conf_v1.Route{
@@ -8551,6 +11075,94 @@ func TestGenerateMatchesConfigWithMultipleSplits(t *testing.T) {
 	}
 }
 
+func TestGenerateMatchesConfigWithSplitKey(t *testing.T) {
+	t.Parallel()
+	splits := []conf_v1.Split{
+		{
+			Weight: 90,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v1",
+			},
+		},
+		{
+			Weight: 10,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v2",
+			},
+		},
+	}
+	route := conf_v1.Route{
+		Path: "/",
+		Matches: []conf_v1.Match{
+			{
+				Conditions: []conf_v1.Condition{
+					{
+						Header: "x-version",
+						Value:  "v1",
+					},
+				},
+				Splits: splits,
+			},
+			{
+				Conditions: []conf_v1.Condition{
+					{
+						Header: "x-version",
+						Value:  "v2",
+					},
+				},
+				Splits:   splits,
+				SplitKey: "${http_x_tenant}",
+			},
+		},
+		Splits:   splits,
+		SplitKey: "${cookie_user}",
+	}
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := NewUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := newVariableNamer(&virtualServer)
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v1": {Service: "coffee-v1"},
+		"vs_default_cafe_coffee-v2": {Service: "coffee-v2"},
+	}
+
+	expectedSources := []string{
+		`"${cookie_user}"`,
+		`"${http_x_tenant}"`,
+		`"${cookie_user}"`,
+	}
+
+	result := generateMatchesConfig(
+		route,
+		upstreamNamer,
+		crUpstreams,
+		variableNamer,
+		0,
+		0,
+		&ConfigParams{},
+		errorPageDetails{},
+		"",
+		false,
+		0,
+		false,
+		"",
+		"",
+		Warnings{},
+	)
+
+	var sources []string
+	for _, sc := range result.SplitClients {
+		sources = append(sources, sc.Source)
+	}
+	if diff := cmp.Diff(expectedSources, sources); diff != "" {
+		t.Errorf("generateMatchesConfig() split client sources mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestGenerateValueForMatchesRouteMap(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -11203,8 +13815,10 @@ var (
 	}
 
 	virtualServerExWithCustomHTTPAndHTTPSListeners = VirtualServerEx{
-		HTTPPort:  8083,
-		HTTPSPort: 8443,
+		Listeners: []conf_v1alpha1.Listener{
+			{Name: "http-8083", Port: 8083, Protocol: "HTTP"},
+			{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+		},
 		VirtualServer: &conf_v1.VirtualServer{
 			ObjectMeta: meta_v1.ObjectMeta{
 				Name:      "cafe",
@@ -11221,7 +13835,9 @@ var (
 	}
 
 	virtualServerExWithCustomHTTPListener = VirtualServerEx{
-		HTTPPort: 8083,
+		Listeners: []conf_v1alpha1.Listener{
+			{Name: "http-8083", Port: 8083, Protocol: "HTTP"},
+		},
 		VirtualServer: &conf_v1.VirtualServer{
 			ObjectMeta: meta_v1.ObjectMeta{
 				Name:      "cafe",
@@ -11237,7 +13853,9 @@ var (
 	}
 
 	virtualServerExWithCustomHTTPSListener = VirtualServerEx{
-		HTTPSPort: 8443,
+		Listeners: []conf_v1alpha1.Listener{
+			{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+		},
 		VirtualServer: &conf_v1.VirtualServer{
 			ObjectMeta: meta_v1.ObjectMeta{
 				Name:      "cafe",
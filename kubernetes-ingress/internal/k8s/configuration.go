@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 const (
@@ -22,6 +23,7 @@ const (
 	virtualServerKind      = "VirtualServer"
 	virtualServerRouteKind = "VirtualServerRoute"
 	transportServerKind    = "TransportServer"
+	httpRouteKind          = "HTTPRoute"
 )
 
 // Operation defines an operation to perform for a resource.
@@ -39,6 +41,7 @@ const (
 // - Regular or Master Ingress
 // - VirtualServer
 // - TransportServer
+// - HTTPRoute
 type Resource interface {
 	GetObjectMeta() *metav1.ObjectMeta
 	GetKeyWithKind() string
@@ -201,6 +204,7 @@ type VirtualServerConfiguration struct {
 	Warnings            []string
 	HTTPPort            int
 	HTTPSPort           int
+	Listeners           []conf_v1alpha1.Listener
 }
 
 // NewVirtualServerConfiguration creates a VirtualServerConfiguration.
@@ -261,6 +265,8 @@ func (vsc *VirtualServerConfiguration) IsEqual(resource Resource) bool {
 // TransportServerConfiguration holds a TransportServer resource.
 type TransportServerConfiguration struct {
 	ListenerPort    int
+	ListenerIPv4    string
+	ListenerIPv6    string
 	TransportServer *conf_v1alpha1.TransportServer
 	Warnings        []string
 }
@@ -301,7 +307,10 @@ func (tsc *TransportServerConfiguration) IsEqual(resource Resource) bool {
 		return false
 	}
 
-	return compareObjectMetas(tsc.GetObjectMeta(), resource.GetObjectMeta()) && tsc.ListenerPort == tsConfig.ListenerPort
+	return compareObjectMetas(tsc.GetObjectMeta(), resource.GetObjectMeta()) &&
+		tsc.ListenerPort == tsConfig.ListenerPort &&
+		tsc.ListenerIPv4 == tsConfig.ListenerIPv4 &&
+		tsc.ListenerIPv6 == tsConfig.ListenerIPv6
 }
 
 func compareObjectMetas(meta1 *metav1.ObjectMeta, meta2 *metav1.ObjectMeta) bool {
@@ -337,6 +346,11 @@ type Configuration struct {
 	virtualServerRoutes map[string]*conf_v1.VirtualServerRoute
 	transportServers    map[string]*conf_v1alpha1.TransportServer
 
+	// Gateway API resources; only the Gateways with the matching GatewayClass are stored
+	gateways        map[string]*gateway_v1beta1.Gateway
+	httpRoutes      map[string]*gateway_v1beta1.HTTPRoute
+	referenceGrants map[string]*gateway_v1beta1.ReferenceGrant
+
 	globalConfiguration *conf_v1alpha1.GlobalConfiguration
 
 	hostProblems     map[string]ConfigurationProblem
@@ -389,6 +403,9 @@ func NewConfiguration(
 		virtualServers:               make(map[string]*conf_v1.VirtualServer),
 		virtualServerRoutes:          make(map[string]*conf_v1.VirtualServerRoute),
 		transportServers:             make(map[string]*conf_v1alpha1.TransportServer),
+		gateways:                     make(map[string]*gateway_v1beta1.Gateway),
+		httpRoutes:                   make(map[string]*gateway_v1beta1.HTTPRoute),
+		referenceGrants:              make(map[string]*gateway_v1beta1.ReferenceGrant),
 		hostProblems:                 make(map[string]ConfigurationProblem),
 		hasCorrectIngressClass:       hasCorrectIngressClass,
 		virtualServerValidator:       virtualServerValidator,
@@ -544,6 +561,89 @@ func (c *Configuration) DeleteVirtualServer(key string) ([]ResourceChange, []Con
 	return c.rebuildHosts()
 }
 
+// AddOrUpdateGateway adds or updates the Gateway resource.
+func (c *Configuration) AddOrUpdateGateway(gw *gateway_v1beta1.Gateway) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := getResourceKey(&gw.ObjectMeta)
+
+	if !c.hasCorrectIngressClass(gw) {
+		delete(c.gateways, key)
+	} else {
+		c.gateways[key] = gw
+	}
+
+	return c.rebuildHosts()
+}
+
+// DeleteGateway deletes a Gateway resource by the key.
+func (c *Configuration) DeleteGateway(key string) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, exists := c.gateways[key]
+	if !exists {
+		return nil, nil
+	}
+
+	delete(c.gateways, key)
+
+	return c.rebuildHosts()
+}
+
+// AddOrUpdateHTTPRoute adds or updates the HTTPRoute resource.
+func (c *Configuration) AddOrUpdateHTTPRoute(route *gateway_v1beta1.HTTPRoute) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := getResourceKey(&route.ObjectMeta)
+	c.httpRoutes[key] = route
+
+	return c.rebuildHosts()
+}
+
+// DeleteHTTPRoute deletes an HTTPRoute resource by the key.
+func (c *Configuration) DeleteHTTPRoute(key string) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, exists := c.httpRoutes[key]
+	if !exists {
+		return nil, nil
+	}
+
+	delete(c.httpRoutes, key)
+
+	return c.rebuildHosts()
+}
+
+// AddOrUpdateReferenceGrant adds or updates the ReferenceGrant resource.
+func (c *Configuration) AddOrUpdateReferenceGrant(grant *gateway_v1beta1.ReferenceGrant) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := getResourceKey(&grant.ObjectMeta)
+	c.referenceGrants[key] = grant
+
+	return c.rebuildHosts()
+}
+
+// DeleteReferenceGrant deletes a ReferenceGrant resource by the key.
+func (c *Configuration) DeleteReferenceGrant(key string) ([]ResourceChange, []ConfigurationProblem) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, exists := c.referenceGrants[key]
+	if !exists {
+		return nil, nil
+	}
+
+	delete(c.referenceGrants, key)
+
+	return c.rebuildHosts()
+}
+
 // AddOrUpdateVirtualServerRoute adds or updates the VirtualServerRoute.
 func (c *Configuration) AddOrUpdateVirtualServerRoute(vsr *conf_v1.VirtualServerRoute) ([]ResourceChange, []ConfigurationProblem) {
 	c.lock.Lock()
@@ -650,6 +750,45 @@ func (c *Configuration) GetGlobalConfiguration() *conf_v1alpha1.GlobalConfigurat
 	return c.globalConfiguration
 }
 
+// FindHostHolder returns the key with the kind of the resource that holds the host.
+// It returns an empty string if the host is free or if the resource with the kind and the object meta holds it.
+func (c *Configuration) FindHostHolder(host string, kind string, objectMeta *metav1.ObjectMeta) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	holder, exists := c.hosts[host]
+	if !exists || holder.GetKeyWithKind() == getResourceKeyWithKind(kind, objectMeta) {
+		return ""
+	}
+
+	return holder.GetKeyWithKind()
+}
+
+// FindListenerHolder returns the key with the kind of the resource that holds the TransportServer listener.
+// TransportServers with different hosts can share a listener, so a non-empty host is only taken by a resource with
+// the same host or by a resource without a host.
+// It returns an empty string if the listener is free or if the resource with the kind and the object meta holds it.
+func (c *Configuration) FindListenerHolder(listener string, host string, kind string, objectMeta *metav1.ObjectMeta) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	key := getResourceKeyWithKind(kind, objectMeta)
+
+	for _, l := range getSortedTransportServerConfigurationKeys(c.listeners) {
+		holder := c.listeners[l]
+		if holder.TransportServer.Spec.Listener.Name != listener || holder.GetKeyWithKind() == key {
+			continue
+		}
+
+		holderHost := holder.TransportServer.Spec.Host
+		if host == "" || holderHost == "" || holderHost == host {
+			return holder.GetKeyWithKind()
+		}
+	}
+
+	return ""
+}
+
 // AddOrUpdateTransportServer adds or updates the TransportServer.
 func (c *Configuration) AddOrUpdateTransportServer(ts *conf_v1alpha1.TransportServer) ([]ResourceChange, []ConfigurationProblem) {
 	c.lock.Lock()
@@ -795,49 +934,135 @@ func (c *Configuration) buildListenersAndTSConfigurations() (newListeners map[st
 		}
 
 		tsc.ListenerPort = listener.Port
+		tsc.ListenerIPv4 = listener.IPv4
+		tsc.ListenerIPv6 = listener.IPv6
 
-		holder, exists := newListeners[listener.Name]
+		listenerKey := getListenerKey(listener.Name, ts.Spec.Host)
+
+		holder, exists := newListeners[listenerKey]
 		if !exists {
-			newListeners[listener.Name] = tsc
+			newListeners[listenerKey] = tsc
 			continue
 		}
 
 		warning := fmt.Sprintf("listener %s is taken by another resource", listener.Name)
+		if ts.Spec.Host != "" {
+			warning = fmt.Sprintf("host %s of listener %s is taken by another resource", ts.Spec.Host, listener.Name)
+		}
 
 		if !holder.Wins(tsc) {
 			holder.AddWarning(warning)
-			newListeners[listener.Name] = tsc
+			newListeners[listenerKey] = tsc
 		} else {
 			tsc.AddWarning(warning)
 		}
 	}
 
+	resolveListenerCollisions(newListeners)
+
 	return newListeners, newTSConfigs
 }
 
+// getListenerKey returns the key of a TransportServer in the listeners map.
+// TransportServers with hosts share the listener, so the key of such a TransportServer includes the host.
+func getListenerKey(listener string, host string) string {
+	if host == "" {
+		return listener
+	}
+	return fmt.Sprintf("%s/%s", listener, host)
+}
+
+// resolveListenerCollisions makes sure that a listener is held either by a single TransportServer without a host or
+// by TransportServers with hosts. If both kinds reference the same listener, the oldest resource wins.
+func resolveListenerCollisions(listeners map[string]*TransportServerConfiguration) {
+	keys := getSortedTransportServerConfigurationKeys(listeners)
+
+	for _, key := range keys {
+		holder, exists := listeners[key]
+		if !exists || holder.TransportServer.Spec.Host != "" {
+			continue
+		}
+
+		listener := holder.TransportServer.Spec.Listener.Name
+		holderWins := true
+		var hostKeys []string
+
+		for _, k := range keys {
+			h, exists := listeners[k]
+			if !exists || h.TransportServer.Spec.Host == "" || h.TransportServer.Spec.Listener.Name != listener {
+				continue
+			}
+
+			hostKeys = append(hostKeys, k)
+			if !holder.Wins(h) {
+				holderWins = false
+			}
+		}
+
+		if len(hostKeys) == 0 {
+			continue
+		}
+
+		warning := fmt.Sprintf("listener %s is taken by another resource", listener)
+
+		if !holderWins {
+			holder.AddWarning(warning)
+			delete(listeners, key)
+			continue
+		}
+
+		for _, k := range hostKeys {
+			listeners[k].AddWarning(warning)
+			delete(listeners, k)
+		}
+	}
+}
+
 func (c *Configuration) buildListenersForVSConfiguration(vsc *VirtualServerConfiguration) {
 	vs := vsc.VirtualServer
 	if vs.Spec.Listener != nil && c.globalConfiguration != nil {
 		if gcListener, ok := c.listenerMap[vs.Spec.Listener.HTTP]; ok {
 			if gcListener.Protocol == conf_v1.HTTPProtocol && !gcListener.Ssl {
 				vsc.HTTPPort = gcListener.Port
+				vsc.Listeners = append(vsc.Listeners, gcListener)
 			}
 		}
 
 		if gcListener, ok := c.listenerMap[vs.Spec.Listener.HTTPS]; ok {
 			if gcListener.Protocol == conf_v1.HTTPProtocol && gcListener.Ssl {
 				vsc.HTTPSPort = gcListener.Port
+				vsc.Listeners = append(vsc.Listeners, gcListener)
+			}
+		}
+
+		for _, name := range vs.Spec.Listener.Additional {
+			if gcListener, ok := c.listenerMap[name]; ok && gcListener.Protocol == conf_v1.HTTPProtocol {
+				vsc.Listeners = append(vsc.Listeners, gcListener)
 			}
 		}
 	}
 }
 
+// GetVirtualServerListeners returns the custom listeners the VirtualServer is bound to.
+func (c *Configuration) GetVirtualServerListeners(vs *conf_v1.VirtualServer) []conf_v1alpha1.Listener {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	vsc, ok := c.hosts[vs.Spec.Host].(*VirtualServerConfiguration)
+	if !ok || vsc.VirtualServer.Namespace != vs.Namespace || vsc.VirtualServer.Name != vs.Name {
+		return nil
+	}
+
+	return vsc.Listeners
+}
+
 // GetResources returns all configuration resources.
 func (c *Configuration) GetResources() []Resource {
 	return c.GetResourcesWithFilter(resourceFilter{
 		Ingresses:        true,
 		VirtualServers:   true,
 		TransportServers: true,
+		HTTPRoutes:       true,
 	})
 }
 
@@ -845,6 +1070,7 @@ type resourceFilter struct {
 	Ingresses        bool
 	VirtualServers   bool
 	TransportServers bool
+	HTTPRoutes       bool
 }
 
 // GetResourcesWithFilter returns resources using the filter.
@@ -868,6 +1094,10 @@ func (c *Configuration) GetResourcesWithFilter(filter resourceFilter) []Resource
 			if filter.TransportServers {
 				resources[r.GetKeyWithKind()] = r
 			}
+		case *HTTPRouteConfiguration:
+			if filter.HTTPRoutes {
+				resources[r.GetKeyWithKind()] = r
+			}
 		}
 	}
 
@@ -960,6 +1190,11 @@ func (c *Configuration) findResourcesForResourceReference(namespace string, name
 				result = append(result, r)
 				continue
 			}
+		case *HTTPRouteConfiguration:
+			if checker.IsReferencedByHTTPRoute(namespace, name, impl) {
+				result = append(result, r)
+				continue
+			}
 		}
 	}
 
@@ -984,6 +1219,7 @@ func (c *Configuration) rebuildHosts() ([]ResourceChange, []ConfigurationProblem
 	newHosts, newResources := c.buildHostsAndResources()
 
 	updateActiveHostsForIngresses(newHosts, newResources)
+	updateActiveHostsForHTTPRoutes(newHosts, newResources)
 
 	removedHosts, updatedHosts, addedHosts := detectChangesInHosts(c.hosts, newHosts)
 	changes := createResourceChangesForHosts(removedHosts, updatedHosts, addedHosts, c.hosts, newHosts)
@@ -1031,6 +1267,20 @@ func updateActiveHostsForIngresses(hosts map[string]Resource, resources map[stri
 	}
 }
 
+func updateActiveHostsForHTTPRoutes(hosts map[string]Resource, resources map[string]Resource) {
+	for _, r := range resources {
+		hrConfig, ok := r.(*HTTPRouteConfiguration)
+		if !ok {
+			continue
+		}
+
+		for host := range hrConfig.Servers {
+			res := hosts[host]
+			hrConfig.ValidHosts[host] = res.GetKeyWithKind() == r.GetKeyWithKind()
+		}
+	}
+}
+
 func detectChangesInProblems(newProblems map[string]ConfigurationProblem, oldProblems map[string]ConfigurationProblem) []ConfigurationProblem {
 	var result []ConfigurationProblem
 
@@ -1053,8 +1303,7 @@ func detectChangesInProblems(newProblems map[string]ConfigurationProblem, oldPro
 
 func (c *Configuration) addProblemsForTSConfigsWithoutActiveListener(tsConfigs map[string]*TransportServerConfiguration, problems map[string]ConfigurationProblem) {
 	for _, tsc := range tsConfigs {
-		holder, exists := c.listeners[tsc.TransportServer.Spec.Listener.Name]
-		if !exists {
+		if tsc.ListenerPort == 0 {
 			p := ConfigurationProblem{
 				Object:  tsc.TransportServer,
 				IsError: false,
@@ -1065,12 +1314,18 @@ func (c *Configuration) addProblemsForTSConfigsWithoutActiveListener(tsConfigs m
 			continue
 		}
 
-		if !tsc.IsEqual(holder) {
+		holder, exists := c.listeners[getListenerKey(tsc.TransportServer.Spec.Listener.Name, tsc.TransportServer.Spec.Host)]
+		if !exists || !tsc.IsEqual(holder) {
+			message := fmt.Sprintf("Listener %s is taken by another resource", tsc.TransportServer.Spec.Listener.Name)
+			if exists && tsc.TransportServer.Spec.Host != "" {
+				message = fmt.Sprintf("Host %s of listener %s is taken by another resource", tsc.TransportServer.Spec.Host, tsc.TransportServer.Spec.Listener.Name)
+			}
+
 			p := ConfigurationProblem{
 				Object:  tsc.TransportServer,
 				IsError: false,
 				Reason:  "Rejected",
-				Message: fmt.Sprintf("Listener %s is taken by another resource", tsc.TransportServer.Spec.Listener.Name),
+				Message: message,
 			}
 			problems[tsc.GetKeyWithKind()] = p
 		}
@@ -1121,6 +1376,34 @@ func (c *Configuration) addProblemsForResourcesWithoutActiveHost(resources map[s
 				}
 				problems[r.GetKeyWithKind()] = p
 			}
+		case *HTTPRouteConfiguration:
+			atLeastOneValidHost := false
+			for _, v := range impl.ValidHosts {
+				if v {
+					atLeastOneValidHost = true
+					break
+				}
+			}
+			if atLeastOneValidHost {
+				continue
+			}
+
+			message := "All hosts are taken by other resources"
+			if len(impl.Servers) == 0 {
+				message = "The route is not accepted by any Gateway listener"
+			}
+
+			// The problem refers to a copy of the HTTPRoute with the statuses of the parents to report.
+			route := impl.HTTPRoute.DeepCopy()
+			route.Status.Parents = newRejectedRouteParents(impl.Parents, message)
+
+			p := ConfigurationProblem{
+				Object:  route,
+				IsError: false,
+				Reason:  "Rejected",
+				Message: message,
+			}
+			problems[r.GetKeyWithKind()] = p
 		}
 	}
 }
@@ -1169,6 +1452,19 @@ func (c *Configuration) addWarningsForVirtualServersWithMissConfiguredListeners(
 					continue
 				}
 			}
+
+			for _, name := range vsc.VirtualServer.Spec.Listener.Additional {
+				listener, exists := c.listenerMap[name]
+				if !exists {
+					warningMsg := fmt.Sprintf("Listener %s is not defined in GlobalConfiguration", name)
+					c.hosts[vsc.VirtualServer.Spec.Host].AddWarning(warningMsg)
+					continue
+				}
+				if listener.Protocol != conf_v1.HTTPProtocol {
+					warningMsg := fmt.Sprintf("Listener %s can't be used by a VirtualServer as its protocol is not HTTP", name)
+					c.hosts[vsc.VirtualServer.Spec.Host].AddWarning(warningMsg)
+				}
+			}
 		}
 	}
 }
@@ -1492,6 +1788,38 @@ func (c *Configuration) buildHostsAndResources() (newHosts map[string]Resource,
 		}
 	}
 
+	// Step 4 - Build hosts from HTTPRoute resources
+
+	validateVirtualServer := func(vs *conf_v1.VirtualServer) error {
+		return c.virtualServerValidator.ValidateVirtualServer(vs)
+	}
+
+	for _, key := range getSortedHTTPRouteKeys(c.httpRoutes) {
+		resource := newHTTPRouteConfiguration(c.httpRoutes[key], c.gateways, c.referenceGrants, validateVirtualServer)
+		if resource == nil {
+			continue
+		}
+
+		newResources[resource.GetKeyWithKind()] = resource
+
+		for _, host := range getSortedHTTPRouteServerHosts(resource.Servers) {
+			holder, exists := newHosts[host]
+			if !exists {
+				newHosts[host] = resource
+				continue
+			}
+
+			warning := fmt.Sprintf("host %s is taken by another resource", host)
+
+			if !holder.Wins(resource) {
+				newHosts[host] = resource
+				holder.AddWarning(warning)
+			} else {
+				resource.AddWarning(warning)
+			}
+		}
+	}
+
 	return newHosts, newResources
 }
 
@@ -1700,6 +2028,30 @@ func getSortedVirtualServerRouteKeys(m map[string]*conf_v1.VirtualServerRoute) [
 	return keys
 }
 
+func getSortedHTTPRouteKeys(m map[string]*gateway_v1beta1.HTTPRoute) []string {
+	var keys []string
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func getSortedHTTPRouteServerHosts(m map[string]*HTTPRouteServer) []string {
+	var hosts []string
+
+	for h := range m {
+		hosts = append(hosts, h)
+	}
+
+	sort.Strings(hosts)
+
+	return hosts
+}
+
 func getSortedProblemKeys(m map[string]ConfigurationProblem) []string {
 	var keys []string
 
@@ -1779,7 +2131,7 @@ func detectChangesInHosts(oldHosts map[string]Resource, newHosts map[string]Reso
 			continue
 		}
 
-		if newVsc.HTTPPort != oldVsc.HTTPPort || newVsc.HTTPSPort != oldVsc.HTTPSPort {
+		if newVsc.HTTPPort != oldVsc.HTTPPort || newVsc.HTTPSPort != oldVsc.HTTPSPort || !reflect.DeepEqual(newVsc.Listeners, oldVsc.Listeners) {
 			updatedHosts = append(updatedHosts, h)
 		}
 	}
@@ -10,11 +10,19 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
 	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 func createTestConfiguration() *Configuration {
+	gatewayClassLister := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	_ = gatewayClassLister.Add(&gateway_v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       gateway_v1beta1.GatewayClassSpec{ControllerName: GatewayControllerName},
+	})
 	lbc := LoadBalancerController{
-		ingressClass: "nginx",
+		ingressClass:       "nginx",
+		gatewayClassLister: gatewayClassLister,
 	}
 	isPlus := false
 	appProtectEnabled := false
@@ -2154,6 +2162,215 @@ func TestListenerFlip(t *testing.T) {
 	}
 }
 
+func TestAddTransportServersWithHostsToSharedListener(t *testing.T) {
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1alpha1.Listener{
+		{
+			Name:     "tcp-8443",
+			Port:     8443,
+			Protocol: "TCP",
+		},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	now := metav1.Now()
+
+	fooTS := createTestTLSTerminationTransportServer("foo", "tcp-8443", "foo.example.com")
+	fooTS.CreationTimestamp = now
+
+	barTS := createTestTLSTerminationTransportServer("bar", "tcp-8443", "bar.example.com")
+	barTS.CreationTimestamp = metav1.NewTime(now.Add(1 * time.Second))
+
+	otherFooTS := createTestTLSTerminationTransportServer("other-foo", "tcp-8443", "foo.example.com")
+	otherFooTS.CreationTimestamp = metav1.NewTime(now.Add(2 * time.Second))
+
+	noHostTS := createTestTransportServer("no-host", "tcp-8443", "TCP")
+	noHostTS.CreationTimestamp = metav1.NewTime(now.Add(3 * time.Second))
+
+	var expectedProblems []ConfigurationProblem
+	var expectedChanges []ResourceChange
+
+	// Add TransportServer with the host foo.example.com
+
+	expectedChanges = []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &TransportServerConfiguration{
+				ListenerPort:    8443,
+				TransportServer: fooTS,
+			},
+		},
+	}
+
+	changes, problems := configuration.AddOrUpdateTransportServer(fooTS)
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	// Add TransportServer with the host bar.example.com to the same listener
+
+	expectedChanges = []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &TransportServerConfiguration{
+				ListenerPort:    8443,
+				TransportServer: barTS,
+			},
+		},
+	}
+
+	changes, problems = configuration.AddOrUpdateTransportServer(barTS)
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	// Add TransportServer with the taken host foo.example.com
+
+	expectedChanges = nil
+	expectedProblems = []ConfigurationProblem{
+		{
+			Object:  otherFooTS,
+			IsError: false,
+			Reason:  "Rejected",
+			Message: "Host foo.example.com of listener tcp-8443 is taken by another resource",
+		},
+	}
+
+	changes, problems = configuration.AddOrUpdateTransportServer(otherFooTS)
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	// Add TransportServer without a host to the same listener
+
+	expectedChanges = nil
+	expectedProblems = []ConfigurationProblem{
+		{
+			Object:  noHostTS,
+			IsError: false,
+			Reason:  "Rejected",
+			Message: "Listener tcp-8443 is taken by another resource",
+		},
+	}
+
+	changes, problems = configuration.AddOrUpdateTransportServer(noHostTS)
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	// Delete TransportServer with the host foo.example.com
+
+	expectedChanges = []ResourceChange{
+		{
+			Op: Delete,
+			Resource: &TransportServerConfiguration{
+				ListenerPort:    8443,
+				TransportServer: fooTS,
+			},
+		},
+		{
+			Op: AddOrUpdate,
+			Resource: &TransportServerConfiguration{
+				ListenerPort:    8443,
+				TransportServer: otherFooTS,
+			},
+		},
+	}
+	expectedProblems = nil
+
+	changes, problems = configuration.DeleteTransportServer("default/foo")
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("DeleteTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("DeleteTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddTransportServerWithHostToListenerTakenByTransportServerWithoutHost(t *testing.T) {
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1alpha1.Listener{
+		{
+			Name:     "tcp-8443",
+			Port:     8443,
+			Protocol: "TCP",
+		},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	now := metav1.Now()
+
+	noHostTS := createTestTransportServer("no-host", "tcp-8443", "TCP")
+	noHostTS.CreationTimestamp = now
+
+	fooTS := createTestTLSTerminationTransportServer("foo", "tcp-8443", "foo.example.com")
+	fooTS.CreationTimestamp = metav1.NewTime(now.Add(1 * time.Second))
+
+	configuration.AddOrUpdateTransportServer(noHostTS)
+
+	// Add TransportServer with a host to the listener taken by the TransportServer without a host
+
+	var expectedChanges []ResourceChange
+	expectedProblems := []ConfigurationProblem{
+		{
+			Object:  fooTS,
+			IsError: false,
+			Reason:  "Rejected",
+			Message: "Listener tcp-8443 is taken by another resource",
+		},
+	}
+
+	changes, problems := configuration.AddOrUpdateTransportServer(fooTS)
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("AddOrUpdateTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	// Delete TransportServer without a host
+
+	expectedChanges = []ResourceChange{
+		{
+			Op: Delete,
+			Resource: &TransportServerConfiguration{
+				ListenerPort:    8443,
+				TransportServer: noHostTS,
+			},
+		},
+		{
+			Op: AddOrUpdate,
+			Resource: &TransportServerConfiguration{
+				ListenerPort:    8443,
+				TransportServer: fooTS,
+			},
+		},
+	}
+	expectedProblems = nil
+
+	changes, problems = configuration.DeleteTransportServer("default/no-host")
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("DeleteTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedProblems, problems); diff != "" {
+		t.Errorf("DeleteTransportServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
 func TestAddInvalidTransportServer(t *testing.T) {
 	configuration := createTestConfiguration()
 
@@ -2283,6 +2500,146 @@ func TestDeleteNonExistingTransportServer(t *testing.T) {
 	}
 }
 
+func TestFindHostHolder(t *testing.T) {
+	configuration := createTestConfiguration()
+
+	vs := createTestVirtualServer("virtualserver", "foo.example.com")
+	configuration.AddOrUpdateVirtualServer(vs)
+
+	otherVS := createTestVirtualServer("other-virtualserver", "foo.example.com")
+
+	tests := []struct {
+		host     string
+		kind     string
+		meta     *metav1.ObjectMeta
+		expected string
+		msg      string
+	}{
+		{
+			host:     "foo.example.com",
+			kind:     virtualServerKind,
+			meta:     &otherVS.ObjectMeta,
+			expected: "VirtualServer/default/virtualserver",
+			msg:      "host held by another VirtualServer",
+		},
+		{
+			host:     "foo.example.com",
+			kind:     transportServerKind,
+			meta:     &vs.ObjectMeta,
+			expected: "VirtualServer/default/virtualserver",
+			msg:      "host held by a VirtualServer with the same name",
+		},
+		{
+			host:     "foo.example.com",
+			kind:     virtualServerKind,
+			meta:     &vs.ObjectMeta,
+			expected: "",
+			msg:      "host held by the same VirtualServer",
+		},
+		{
+			host:     "bar.example.com",
+			kind:     virtualServerKind,
+			meta:     &otherVS.ObjectMeta,
+			expected: "",
+			msg:      "free host",
+		},
+	}
+
+	for _, test := range tests {
+		result := configuration.FindHostHolder(test.host, test.kind, test.meta)
+		if result != test.expected {
+			t.Errorf("FindHostHolder() returned %q but expected %q for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestFindListenerHolder(t *testing.T) {
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1alpha1.Listener{
+		{
+			Name:     "tcp-7777",
+			Port:     7777,
+			Protocol: "TCP",
+		},
+		{
+			Name:     "tcp-8443",
+			Port:     8443,
+			Protocol: "TCP",
+		},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	ts := createTestTransportServer("transportserver", "tcp-7777", "TCP")
+	configuration.AddOrUpdateTransportServer(ts)
+
+	hostTS := createTestTLSTerminationTransportServer("host-transportserver", "tcp-8443", "foo.example.com")
+	configuration.AddOrUpdateTransportServer(hostTS)
+
+	otherTS := createTestTransportServer("other-transportserver", "tcp-7777", "TCP")
+
+	tests := []struct {
+		listener string
+		host     string
+		meta     *metav1.ObjectMeta
+		expected string
+		msg      string
+	}{
+		{
+			listener: "tcp-7777",
+			meta:     &otherTS.ObjectMeta,
+			expected: "TransportServer/default/transportserver",
+			msg:      "listener held by another TransportServer",
+		},
+		{
+			listener: "tcp-7777",
+			meta:     &ts.ObjectMeta,
+			expected: "",
+			msg:      "listener held by the same TransportServer",
+		},
+		{
+			listener: "tcp-8888",
+			meta:     &otherTS.ObjectMeta,
+			expected: "",
+			msg:      "free listener",
+		},
+		{
+			listener: "tcp-7777",
+			host:     "foo.example.com",
+			meta:     &otherTS.ObjectMeta,
+			expected: "TransportServer/default/transportserver",
+			msg:      "listener held by another TransportServer without a host",
+		},
+		{
+			listener: "tcp-8443",
+			host:     "foo.example.com",
+			meta:     &otherTS.ObjectMeta,
+			expected: "TransportServer/default/host-transportserver",
+			msg:      "host of listener held by another TransportServer",
+		},
+		{
+			listener: "tcp-8443",
+			host:     "bar.example.com",
+			meta:     &otherTS.ObjectMeta,
+			expected: "",
+			msg:      "free host of listener shared by TransportServers with hosts",
+		},
+		{
+			listener: "tcp-8443",
+			meta:     &otherTS.ObjectMeta,
+			expected: "TransportServer/default/host-transportserver",
+			msg:      "listener shared by TransportServers with hosts",
+		},
+	}
+
+	for _, test := range tests {
+		result := configuration.FindListenerHolder(test.listener, test.host, transportServerKind, test.meta)
+		if result != test.expected {
+			t.Errorf("FindListenerHolder() returned %q but expected %q for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
 func TestAddOrUpdateGlobalConfiguration(t *testing.T) {
 	configuration := createTestConfiguration()
 
@@ -2620,6 +2977,7 @@ func TestAddGlobalConfigurationThenAddVirtualServerWithValidCustomListeners(t *t
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2658,6 +3016,7 @@ func TestAddVirtualServerWithValidCustomListenersFirstThenAddGlobalConfiguration
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2709,6 +3068,7 @@ func TestAddVirtualServerWithCustomHttpListenerThatDoNotExistInGlobalConfigurati
 				VirtualServer: virtualServer,
 				HTTPPort:      0,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPSListener,
 				Warnings:      []string{"Listener http-bogus is not defined in GlobalConfiguration"},
 			},
 		},
@@ -2736,6 +3096,7 @@ func TestAddVirtualServerWithCustomHttpsListenerThatDoNotExistInGlobalConfigurat
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     0,
+				Listeners:     customHTTPListener,
 				Warnings:      []string{"Listener https-bogus is not defined in GlobalConfiguration"},
 			},
 		},
@@ -2763,6 +3124,7 @@ func TestDeleteHttpListenerFromExistingGlobalConfigurationWithVirtualServerDeplo
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2776,6 +3138,7 @@ func TestDeleteHttpListenerFromExistingGlobalConfigurationWithVirtualServerDeplo
 				VirtualServer: virtualServer,
 				HTTPPort:      0,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPSListener,
 				Warnings:      []string{"Listener http-8082 is not defined in GlobalConfiguration"},
 			},
 		},
@@ -2803,6 +3166,7 @@ func TestDeleteHttpsListenerFromExistingGlobalConfigurationWithVirtualServerDepl
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2815,6 +3179,7 @@ func TestDeleteHttpsListenerFromExistingGlobalConfigurationWithVirtualServerDepl
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     0,
+				Listeners:     customHTTPListener,
 				Warnings:      []string{"Listener https-8442 is not defined in GlobalConfiguration"},
 			},
 		},
@@ -2841,6 +3206,7 @@ func TestDeleteGlobalConfigurationWithVirtualServerDeployedWithValidCustomListen
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2875,6 +3241,7 @@ func TestRenameHttpListenerInExistingGlobalConfigurationWithVirtualServerDeploye
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2888,6 +3255,7 @@ func TestRenameHttpListenerInExistingGlobalConfigurationWithVirtualServerDeploye
 				VirtualServer: virtualServer,
 				HTTPPort:      0,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPSListener,
 				Warnings:      []string{"Listener http-8082 is not defined in GlobalConfiguration"},
 			},
 		},
@@ -2911,6 +3279,7 @@ func TestRenameHttpsListenerInExistingGlobalConfigurationWithVirtualServerDeploy
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -2923,6 +3292,7 @@ func TestRenameHttpsListenerInExistingGlobalConfigurationWithVirtualServerDeploy
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     0,
+				Listeners:     customHTTPListener,
 				Warnings:      []string{"Listener https-8442 is not defined in GlobalConfiguration"},
 			},
 		},
@@ -2951,6 +3321,7 @@ func TestAddVirtualServerWithCustomHttpListenerInHttpsBlock(t *testing.T) {
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     0,
+				Listeners:     customHTTPListener,
 				Warnings:      []string{expectedWarningMsg},
 			},
 		},
@@ -2980,6 +3351,7 @@ func TestAddVirtualServerWithCustomHttpsListenerInHttpBlock(t *testing.T) {
 				VirtualServer: virtualServer,
 				HTTPPort:      0,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPSListener,
 				Warnings:      []string{expectedWarningMsg},
 			},
 		},
@@ -2988,6 +3360,57 @@ func TestAddVirtualServerWithCustomHttpsListenerInHttpBlock(t *testing.T) {
 	addOrUpdateVirtualServer(t, configuration, virtualServer, expectedChanges, noProblems)
 }
 
+func TestAddVirtualServerWithAdditionalListeners(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	listeners := append([]conf_v1alpha1.Listener{
+		{
+			Name:     "https-9443",
+			Port:     9443,
+			Protocol: "HTTP",
+			Ssl:      true,
+		},
+		{
+			Name:     "tcp-9000",
+			Port:     9000,
+			Protocol: "TCP",
+		},
+	}, customHTTPAndHTTPSListeners...)
+
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	virtualServer := createTestVirtualServerWithListeners(
+		"cafe",
+		"cafe.example.com",
+		"http-8082",
+		"https-8442")
+	virtualServer.Spec.Listener.Additional = []string{"https-9443", "tcp-9000", "http-bogus"}
+
+	expectedChanges := []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer: virtualServer,
+				HTTPPort:      8082,
+				HTTPSPort:     8442,
+				Listeners:     append(customHTTPAndHTTPSListeners, listeners[0]),
+				Warnings: []string{
+					"Listener tcp-9000 can't be used by a VirtualServer as its protocol is not HTTP",
+					"Listener http-bogus is not defined in GlobalConfiguration",
+				},
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, virtualServer, expectedChanges, noProblems)
+
+	expectedListeners := append(customHTTPAndHTTPSListeners, listeners[0])
+	if diff := cmp.Diff(expectedListeners, configuration.GetVirtualServerListeners(virtualServer)); diff != "" {
+		t.Errorf("GetVirtualServerListeners() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
 func TestAddVirtualServerWithNoHttpsListener(t *testing.T) {
 	t.Parallel()
 	configuration := createTestConfiguration()
@@ -3007,6 +3430,7 @@ func TestAddVirtualServerWithNoHttpsListener(t *testing.T) {
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     0,
+				Listeners:     customHTTPListener,
 			},
 		},
 	}
@@ -3033,6 +3457,7 @@ func TestAddVirtualServerWithNoHttpListener(t *testing.T) {
 				VirtualServer: virtualServer,
 				HTTPPort:      0,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPSListener,
 			},
 		},
 	}
@@ -3085,6 +3510,7 @@ func TestAddVirtualServerWithValidCustomListenersAndChangeValueOfSslToFalseInGlo
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -3100,6 +3526,7 @@ func TestAddVirtualServerWithValidCustomListenersAndChangeValueOfSslToFalseInGlo
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     0,
+				Listeners:     customHTTPListener,
 				Warnings:      []string{expectedWarningMsg},
 			},
 		},
@@ -3127,6 +3554,7 @@ func TestAddVirtualServerWithValidCustomListenersAndChangeValueOfSslToTrueInGlob
 				VirtualServer: virtualServer,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -3142,6 +3570,7 @@ func TestAddVirtualServerWithValidCustomListenersAndChangeValueOfSslToTrueInGlob
 				VirtualServer: virtualServer,
 				HTTPPort:      0,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPSListener,
 				Warnings:      []string{expectedWarningMsg},
 			},
 		},
@@ -3175,6 +3604,7 @@ func TestAddMultipleVirtualServersWithTheSameCustomListeners(t *testing.T) {
 				VirtualServer: virtualServerCafe,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -3187,6 +3617,7 @@ func TestAddMultipleVirtualServersWithTheSameCustomListeners(t *testing.T) {
 				VirtualServer: virtualServerFoo,
 				HTTPPort:      8082,
 				HTTPSPort:     8442,
+				Listeners:     customHTTPAndHTTPSListeners,
 			},
 		},
 	}
@@ -3733,6 +4164,16 @@ func createTestTLSPassthroughTransportServer(name string, host string) *conf_v1a
 	return ts
 }
 
+func createTestTLSTerminationTransportServer(name string, listenerName string, host string) *conf_v1alpha1.TransportServer {
+	ts := createTestTransportServer(name, listenerName, "TCP")
+	ts.Spec.Host = host
+	ts.Spec.TLS = &conf_v1alpha1.TLS{
+		Secret: "tls-secret",
+	}
+
+	return ts
+}
+
 func createTestGlobalConfiguration(listeners []conf_v1alpha1.Listener) *conf_v1alpha1.GlobalConfiguration {
 	return &conf_v1alpha1.GlobalConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3964,6 +4405,7 @@ type testReferenceChecker struct {
 	onlyVirtualServers      bool
 	onlyVirtualServerRoutes bool
 	onlyTransportServers    bool
+	onlyHTTPRoutes          bool
 }
 
 func (rc *testReferenceChecker) IsReferencedByIngress(namespace string, name string, _ *networking.Ingress) bool {
@@ -3986,6 +4428,10 @@ func (rc *testReferenceChecker) IsReferencedByTransportServer(namespace string,
 	return rc.onlyTransportServers && namespace == rc.resourceNamespace && name == rc.resourceName
 }
 
+func (rc *testReferenceChecker) IsReferencedByHTTPRoute(namespace string, name string, _ *HTTPRouteConfiguration) bool {
+	return rc.onlyHTTPRoutes && namespace == rc.resourceNamespace && name == rc.resourceName
+}
+
 func TestFindResourcesForResourceReference(t *testing.T) {
 	t.Parallel()
 	regularIng := createTestIngress("regular-ingress", "foo.example.com")
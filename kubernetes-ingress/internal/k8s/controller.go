@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
@@ -69,12 +70,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gateway_versioned "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gateway_informers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 )
 
 const (
 	ingressClassKey = "kubernetes.io/ingress.class"
 	// IngressControllerName holds Ingress Controller name
 	IngressControllerName = "nginx.org/ingress-controller"
+	// GatewayControllerName holds the name of the controller of the GatewayClasses handled by the Ingress Controller
+	GatewayControllerName = "nginx.org/gateway-controller"
 
 	typeKeyword     = "type"
 	helmReleaseType = "helm.sh/release.v1"
@@ -106,14 +113,17 @@ type LoadBalancerController struct {
 	client                        kubernetes.Interface
 	confClient                    k8s_nginx.Interface
 	dynClient                     dynamic.Interface
+	gatewayClient                 gateway_versioned.Interface
 	restConfig                    *rest.Config
 	cacheSyncs                    []cache.InformerSynced
 	namespacedInformers           map[string]*namespacedInformer
 	configMapController           cache.Controller
 	globalConfigurationController cache.Controller
+	gatewayClassController        cache.Controller
 	ingressLinkInformer           cache.SharedIndexInformer
 	configMapLister               storeToConfigMapLister
 	globalConfigurationLister     cache.Store
+	gatewayClassLister            cache.Store
 	ingressLinkLister             cache.Store
 	namespaceLabeledLister        cache.Store
 	syncQueue                     *taskQueue
@@ -140,6 +150,7 @@ type LoadBalancerController struct {
 	controllerNamespace           string
 	wildcardTLSSecret             string
 	areCustomResourcesEnabled     bool
+	isGatewayAPIEnabled           bool
 	enableOIDC                    bool
 	metricsCollector              collectors.ControllerCollector
 	globalConfigurationValidator  *validation.GlobalConfigurationValidator
@@ -171,6 +182,7 @@ type NewLoadBalancerControllerInput struct {
 	KubeClient                   kubernetes.Interface
 	ConfClient                   k8s_nginx.Interface
 	DynClient                    dynamic.Interface
+	GatewayClient                gateway_versioned.Interface
 	RestConfig                   *rest.Config
 	ResyncPeriod                 time.Duration
 	Namespace                    []string
@@ -191,6 +203,7 @@ type NewLoadBalancerControllerInput struct {
 	ConfigMaps                   string
 	GlobalConfiguration          string
 	AreCustomResourcesEnabled    bool
+	IsGatewayAPIEnabled          bool
 	EnableOIDC                   bool
 	MetricsCollector             collectors.ControllerCollector
 	GlobalConfigurationValidator *validation.GlobalConfigurationValidator
@@ -215,6 +228,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		client:                       input.KubeClient,
 		confClient:                   input.ConfClient,
 		dynClient:                    input.DynClient,
+		gatewayClient:                input.GatewayClient,
 		restConfig:                   input.RestConfig,
 		configurator:                 input.NginxConfigurator,
 		defaultServerSecret:          input.DefaultServerSecret,
@@ -231,6 +245,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		controllerNamespace:          input.ControllerNamespace,
 		wildcardTLSSecret:            input.WildcardTLSSecret,
 		areCustomResourcesEnabled:    input.AreCustomResourcesEnabled,
+		isGatewayAPIEnabled:          input.IsGatewayAPIEnabled,
 		enableOIDC:                   input.EnableOIDC,
 		metricsCollector:             input.MetricsCollector,
 		globalConfigurationValidator: input.GlobalConfigurationValidator,
@@ -291,6 +306,10 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		}
 	}
 
+	if lbc.isGatewayAPIEnabled {
+		lbc.addGatewayClassHandler(createGatewayClassHandlers(lbc))
+	}
+
 	if input.ConfigMaps != "" {
 		nginxConfigMapsNS, nginxConfigMapsName, err := ParseNamespaceName(input.ConfigMaps)
 		if err != nil {
@@ -317,6 +336,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		namespacedInformers:    lbc.namespacedInformers,
 		keyFunc:                keyFunc,
 		confClient:             input.ConfClient,
+		gatewayClient:          input.GatewayClient,
 		hasCorrectIngressClass: lbc.HasCorrectIngressClass,
 	}
 
@@ -334,6 +354,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		input.CertManagerEnabled,
 		input.IsIPV6Disabled,
 	)
+	lbc.statusUpdater.getVirtualServerListeners = lbc.configuration.GetVirtualServerListeners
 
 	lbc.appProtectConfiguration = appprotect.NewConfiguration()
 	lbc.dosConfiguration = appprotectdos.NewConfiguration(input.AppProtectDosEnabled)
@@ -349,6 +370,7 @@ type namespacedInformer struct {
 	confSharedInformerFactory    k8s_nginx_informers.SharedInformerFactory
 	secretInformerFactory        informers.SharedInformerFactory
 	dynInformerFactory           dynamicinformer.DynamicSharedInformerFactory
+	gatewayInformerFactory       gateway_informers.SharedInformerFactory
 	ingressLister                storeToIngressLister
 	svcLister                    cache.Store
 	endpointSliceLister          storeToEndpointSliceLister
@@ -364,8 +386,12 @@ type namespacedInformer struct {
 	appProtectUserSigLister      cache.Store
 	transportServerLister        cache.Store
 	policyLister                 cache.Store
+	gatewayLister                cache.Store
+	httpRouteLister              cache.Store
+	referenceGrantLister         cache.Store
 	isSecretsEnabledNamespace    bool
 	areCustomResourcesEnabled    bool
+	isGatewayAPIEnabled          bool
 	appProtectEnabled            bool
 	appProtectDosEnabled         bool
 	stopCh                       chan struct{}
@@ -418,6 +444,15 @@ func (lbc *LoadBalancerController) newNamespacedInformer(ns string) *namespacedI
 
 	}
 
+	if lbc.isGatewayAPIEnabled {
+		nsi.isGatewayAPIEnabled = true
+		nsi.gatewayInformerFactory = gateway_informers.NewSharedInformerFactoryWithOptions(lbc.gatewayClient, lbc.resync, gateway_informers.WithNamespace(ns))
+
+		nsi.addGatewayHandler(createGatewayHandlers(lbc))
+		nsi.addHTTPRouteHandler(createHTTPRouteHandlers(lbc))
+		nsi.addReferenceGrantHandler(createReferenceGrantHandlers(lbc))
+	}
+
 	if lbc.appProtectEnabled || lbc.appProtectDosEnabled {
 		nsi.dynInformerFactory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(lbc.dynClient, 0, ns, nil)
 		if lbc.appProtectEnabled {
@@ -613,6 +648,44 @@ func (nsi *namespacedInformer) addTransportServerHandler(handlers cache.Resource
 	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
 }
 
+func (lbc *LoadBalancerController) addGatewayClassHandler(handlers cache.ResourceEventHandlerFuncs) {
+	lbc.gatewayClassLister, lbc.gatewayClassController = cache.NewInformer(
+		cache.NewListWatchFromClient(
+			lbc.gatewayClient.GatewayV1beta1().RESTClient(),
+			"gatewayclasses",
+			"",
+			fields.Everything()),
+		&gateway_v1beta1.GatewayClass{},
+		lbc.resync,
+		handlers,
+	)
+	lbc.cacheSyncs = append(lbc.cacheSyncs, lbc.gatewayClassController.HasSynced)
+}
+
+func (nsi *namespacedInformer) addGatewayHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := nsi.gatewayInformerFactory.Gateway().V1beta1().Gateways().Informer()
+	informer.AddEventHandler(handlers)
+	nsi.gatewayLister = informer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
+}
+
+func (nsi *namespacedInformer) addHTTPRouteHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := nsi.gatewayInformerFactory.Gateway().V1beta1().HTTPRoutes().Informer()
+	informer.AddEventHandler(handlers)
+	nsi.httpRouteLister = informer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
+}
+
+func (nsi *namespacedInformer) addReferenceGrantHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := nsi.gatewayInformerFactory.Gateway().V1beta1().ReferenceGrants().Informer()
+	informer.AddEventHandler(handlers)
+	nsi.referenceGrantLister = informer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
+}
+
 func (lbc *LoadBalancerController) addIngressLinkHandler(handlers cache.ResourceEventHandlerFuncs, name string) {
 	optionsModifier := func(options *meta_v1.ListOptions) {
 		options.FieldSelector = fields.Set{"metadata.name": name}.String()
@@ -699,6 +772,9 @@ func (lbc *LoadBalancerController) Run() {
 	if lbc.watchGlobalConfiguration {
 		go lbc.globalConfigurationController.Run(lbc.ctx.Done())
 	}
+	if lbc.isGatewayAPIEnabled {
+		go lbc.gatewayClassController.Run(lbc.ctx.Done())
+	}
 	if lbc.watchIngressLink {
 		go lbc.ingressLinkInformer.Run(lbc.ctx.Done())
 	}
@@ -746,6 +822,10 @@ func (nsi *namespacedInformer) start() {
 	if nsi.appProtectEnabled || nsi.appProtectDosEnabled {
 		go nsi.dynInformerFactory.Start(nsi.stopCh)
 	}
+
+	if nsi.isGatewayAPIEnabled {
+		go nsi.gatewayInformerFactory.Start(nsi.stopCh)
+	}
 }
 
 func (nsi *namespacedInformer) stop() {
@@ -790,7 +870,7 @@ func (lbc *LoadBalancerController) syncEndpointSlices(task task) bool {
 	}
 
 	endpointSlice := obj.(*discovery_v1.EndpointSlice)
-	svcResource := lbc.configuration.FindResourcesForService(endpointSlice.Namespace, endpointSlice.Labels["kubernetes.io/service-name"])
+	svcResource := lbc.findResourcesForService(endpointSlice.Namespace, endpointSlice.Labels["kubernetes.io/service-name"])
 
 	resourceExes := lbc.createExtendedResources(svcResource)
 
@@ -853,8 +933,11 @@ func (lbc *LoadBalancerController) createExtendedResources(resources []Resource)
 				result.IngressExes = append(result.IngressExes, ingEx)
 			}
 		case *TransportServerConfiguration:
-			tsEx := lbc.createTransportServerEx(impl.TransportServer, impl.ListenerPort)
+			tsEx := lbc.createTransportServerEx(impl)
 			result.TransportServerExes = append(result.TransportServerExes, tsEx)
+		case *HTTPRouteConfiguration:
+			vsExes := lbc.createHTTPRouteVirtualServerExes(impl)
+			result.VirtualServerExes = append(result.VirtualServerExes, vsExes...)
 		}
 	}
 
@@ -912,7 +995,12 @@ func (lbc *LoadBalancerController) updateAllConfigs() {
 	eventType := api_v1.EventTypeNormal
 	eventWarningMessage := ""
 
-	if updateErr != nil {
+	var invalidErr *configs.InvalidResourcesError
+	if errors.As(updateErr, &invalidErr) {
+		eventTitle = "UpdatedWithError"
+		eventType = api_v1.EventTypeWarning
+		eventWarningMessage = fmt.Sprintf("but the configuration of the invalid resources was not applied: %v", updateErr)
+	} else if updateErr != nil {
 		eventTitle = "UpdatedWithError"
 		eventType = api_v1.EventTypeWarning
 		eventWarningMessage = fmt.Sprintf("but was not applied: %v", updateErr)
@@ -1030,6 +1118,14 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.syncDosProtectedResource(task)
 	case ingressLink:
 		lbc.syncIngressLink(task)
+	case gatewayClass:
+		lbc.syncGatewayClass(task)
+	case gateway:
+		lbc.syncGateway(task)
+	case httpRoute:
+		lbc.syncHTTPRoute(task)
+	case referenceGrant:
+		lbc.syncReferenceGrant(task)
 	}
 
 	if !lbc.isNginxReady && lbc.syncQueue.Len() == 0 {
@@ -1370,12 +1466,12 @@ func (lbc *LoadBalancerController) syncPolicy(task task) {
 	resources := lbc.configuration.FindResourcesForPolicy(namespace, name)
 	resourceExes := lbc.createExtendedResources(resources)
 
-	// Only VirtualServers support policies
-	if len(resourceExes.VirtualServerExes) == 0 {
+	// Only VirtualServers and TransportServers support policies
+	if len(resourceExes.VirtualServerExes) == 0 && len(resourceExes.TransportServerExes) == 0 {
 		return
 	}
 
-	warnings, updateErr := lbc.configurator.AddOrUpdateVirtualServers(resourceExes.VirtualServerExes)
+	warnings, updateErr := lbc.configurator.AddOrUpdateResources(resourceExes)
 	lbc.updateResourcesStatusAndEvents(resources, warnings, updateErr)
 
 	// Note: updating the status of a policy based on a reload is not needed.
@@ -1411,6 +1507,100 @@ func (lbc *LoadBalancerController) syncTransportServer(task task) {
 	lbc.processProblems(problems)
 }
 
+// syncGatewayClass syncs the Gateways of the GatewayClass, because the GatewayClass determines whether
+// the Ingress Controller handles them.
+func (lbc *LoadBalancerController) syncGatewayClass(task task) {
+	glog.V(2).Infof("Syncing Gateways of GatewayClass: %v\n", task.Key)
+
+	for _, nsi := range lbc.namespacedInformers {
+		for _, obj := range nsi.gatewayLister.List() {
+			gw := obj.(*gateway_v1beta1.Gateway)
+			if string(gw.Spec.GatewayClassName) != task.Key {
+				continue
+			}
+
+			changes, problems := lbc.configuration.AddOrUpdateGateway(gw)
+			lbc.processChanges(changes)
+			lbc.processProblems(problems)
+		}
+	}
+}
+
+func (lbc *LoadBalancerController) syncGateway(task task) {
+	key := task.Key
+	ns, _, _ := cache.SplitMetaNamespaceKey(key)
+	obj, gwExists, err := lbc.getNamespacedInformer(ns).gatewayLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	var changes []ResourceChange
+	var problems []ConfigurationProblem
+
+	if !gwExists {
+		glog.V(2).Infof("Deleting Gateway: %v\n", key)
+		changes, problems = lbc.configuration.DeleteGateway(key)
+	} else {
+		glog.V(2).Infof("Adding or Updating Gateway: %v\n", key)
+		gw := obj.(*gateway_v1beta1.Gateway)
+		changes, problems = lbc.configuration.AddOrUpdateGateway(gw)
+	}
+
+	lbc.processChanges(changes)
+	lbc.processProblems(problems)
+}
+
+func (lbc *LoadBalancerController) syncHTTPRoute(task task) {
+	key := task.Key
+	ns, _, _ := cache.SplitMetaNamespaceKey(key)
+	obj, routeExists, err := lbc.getNamespacedInformer(ns).httpRouteLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	var changes []ResourceChange
+	var problems []ConfigurationProblem
+
+	if !routeExists {
+		glog.V(2).Infof("Deleting HTTPRoute: %v\n", key)
+		changes, problems = lbc.configuration.DeleteHTTPRoute(key)
+	} else {
+		glog.V(2).Infof("Adding or Updating HTTPRoute: %v\n", key)
+		route := obj.(*gateway_v1beta1.HTTPRoute)
+		changes, problems = lbc.configuration.AddOrUpdateHTTPRoute(route)
+	}
+
+	lbc.processChanges(changes)
+	lbc.processProblems(problems)
+}
+
+func (lbc *LoadBalancerController) syncReferenceGrant(task task) {
+	key := task.Key
+	ns, _, _ := cache.SplitMetaNamespaceKey(key)
+	obj, grantExists, err := lbc.getNamespacedInformer(ns).referenceGrantLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	var changes []ResourceChange
+	var problems []ConfigurationProblem
+
+	if !grantExists {
+		glog.V(2).Infof("Deleting ReferenceGrant: %v\n", key)
+		changes, problems = lbc.configuration.DeleteReferenceGrant(key)
+	} else {
+		glog.V(2).Infof("Adding or Updating ReferenceGrant: %v\n", key)
+		grant := obj.(*gateway_v1beta1.ReferenceGrant)
+		changes, problems = lbc.configuration.AddOrUpdateReferenceGrant(grant)
+	}
+
+	lbc.processChanges(changes)
+	lbc.processProblems(problems)
+}
+
 func (lbc *LoadBalancerController) syncGlobalConfiguration(task task) {
 	key := task.Key
 	obj, gcExists, err := lbc.globalConfigurationLister.GetByKey(key)
@@ -1527,6 +1717,12 @@ func (lbc *LoadBalancerController) processProblems(problems []ConfigurationProbl
 				if err != nil {
 					glog.Errorf("Error when updating the status for VirtualServerRoute %v/%v: %v", obj.Namespace, obj.Name, err)
 				}
+			case *gateway_v1beta1.HTTPRoute:
+				// the problem refers to a copy of the HTTPRoute with the statuses of the parents to report
+				err := lbc.statusUpdater.UpdateHTTPRouteStatus(obj, obj.Status.Parents)
+				if err != nil {
+					glog.Errorf("Error when updating the status for HTTPRoute %v/%v: %v", obj.Namespace, obj.Name, err)
+				}
 			}
 		}
 	}
@@ -1557,10 +1753,15 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 					lbc.updateRegularIngressStatusAndEvents(impl, warnings, addOrUpdateErr)
 				}
 			case *TransportServerConfiguration:
-				tsEx := lbc.createTransportServerEx(impl.TransportServer, impl.ListenerPort)
+				tsEx := lbc.createTransportServerEx(impl)
 
 				warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateTransportServer(tsEx)
 				lbc.updateTransportServerStatusAndEvents(impl, warnings, addOrUpdateErr)
+			case *HTTPRouteConfiguration:
+				vsExes := lbc.createHTTPRouteVirtualServerExes(impl)
+
+				warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateHTTPRoute(getResourceKey(&impl.HTTPRoute.ObjectMeta), vsExes)
+				lbc.updateHTTPRouteStatusAndEvents(impl, warnings, addOrUpdateErr)
 			}
 		} else if c.Op == Delete {
 			switch impl := c.Resource.(type) {
@@ -1629,6 +1830,14 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 				if tsExists {
 					lbc.updateTransportServerStatusAndEventsOnDelete(impl, c.Error, deleteErr)
 				}
+			case *HTTPRouteConfiguration:
+				key := getResourceKey(&impl.HTTPRoute.ObjectMeta)
+
+				// The status of the HTTPRoute that lost its hosts is updated when its problem is processed.
+				deleteErr := lbc.configurator.DeleteHTTPRoute(key)
+				if deleteErr != nil {
+					glog.Errorf("Error when deleting configuration for HTTPRoute %v: %v", key, deleteErr)
+				}
 			}
 		}
 	}
@@ -1659,7 +1868,7 @@ func (lbc *LoadBalancerController) processChangesFromGlobalConfiguration(changes
 			}
 		case *TransportServerConfiguration:
 			if c.Op == AddOrUpdate {
-				tsEx := lbc.createTransportServerEx(impl.TransportServer, impl.ListenerPort)
+				tsEx := lbc.createTransportServerEx(impl)
 
 				updatedTSExes = append(updatedTSExes, tsEx)
 				updatedResources = append(updatedResources, impl)
@@ -2003,11 +2212,62 @@ func (lbc *LoadBalancerController) updateResourcesStatusAndEvents(resources []Re
 			}
 		case *TransportServerConfiguration:
 			lbc.updateTransportServerStatusAndEvents(impl, warnings, operationErr)
+		case *HTTPRouteConfiguration:
+			lbc.updateHTTPRouteStatusAndEvents(impl, warnings, operationErr)
+		}
+	}
+}
+
+// getResourceError returns the error of the operation for the resource. When NGINX rejected the configuration of
+// only some of the resources, the other resources get no error.
+func getResourceError(resource Resource, operationErr error) error {
+	var invalidErr *configs.InvalidResourcesError
+	if errors.As(operationErr, &invalidErr) {
+		return invalidErr.Resources[resource.GetKeyWithKind()]
+	}
+	return operationErr
+}
+
+func (lbc *LoadBalancerController) updateHTTPRouteStatusAndEvents(hrConfig *HTTPRouteConfiguration, warnings configs.Warnings, operationErr error) {
+	operationErr = getResourceError(hrConfig, operationErr)
+
+	eventTitle := "AddedOrUpdated"
+	eventType := api_v1.EventTypeNormal
+	eventWarningMessage := ""
+
+	allWarnings := hrConfig.Warnings
+	for _, server := range hrConfig.GetValidServers() {
+		allWarnings = append(allWarnings, warnings[server.VirtualServer]...)
+	}
+
+	if len(allWarnings) > 0 {
+		eventType = api_v1.EventTypeWarning
+		eventTitle = "AddedOrUpdatedWithWarning"
+		eventWarningMessage = fmt.Sprintf("with warning(s): %s", formatWarningMessages(allWarnings))
+	}
+
+	parents := hrConfig.Parents
+	if operationErr != nil {
+		eventType = api_v1.EventTypeWarning
+		eventTitle = "AddedOrUpdatedWithError"
+		eventWarningMessage = fmt.Sprintf("%s; but was not applied: %v", eventWarningMessage, operationErr)
+		parents = newRejectedRouteParents(parents, eventWarningMessage)
+	}
+
+	msg := fmt.Sprintf("Configuration for %v was added or updated %s", getResourceKey(&hrConfig.HTTPRoute.ObjectMeta), eventWarningMessage)
+	lbc.recorder.Eventf(hrConfig.HTTPRoute, eventType, eventTitle, msg)
+
+	if lbc.reportCustomResourceStatusEnabled() {
+		err := lbc.statusUpdater.UpdateHTTPRouteStatus(hrConfig.HTTPRoute, parents)
+		if err != nil {
+			glog.Errorf("Error when updating the status for HTTPRoute %v/%v: %v", hrConfig.HTTPRoute.Namespace, hrConfig.HTTPRoute.Name, err)
 		}
 	}
 }
 
 func (lbc *LoadBalancerController) updateMergeableIngressStatusAndEvents(ingConfig *IngressConfiguration, warnings configs.Warnings, operationErr error) {
+	operationErr = getResourceError(ingConfig, operationErr)
+
 	eventType := api_v1.EventTypeNormal
 	eventTitle := "AddedOrUpdated"
 	eventWarningMessage := ""
@@ -2093,6 +2353,8 @@ func (lbc *LoadBalancerController) updateMergeableIngressStatusAndEvents(ingConf
 }
 
 func (lbc *LoadBalancerController) updateRegularIngressStatusAndEvents(ingConfig *IngressConfiguration, warnings configs.Warnings, operationErr error) {
+	operationErr = getResourceError(ingConfig, operationErr)
+
 	eventType := api_v1.EventTypeNormal
 	eventTitle := "AddedOrUpdated"
 	eventWarningMessage := ""
@@ -2127,6 +2389,8 @@ func (lbc *LoadBalancerController) updateRegularIngressStatusAndEvents(ingConfig
 }
 
 func (lbc *LoadBalancerController) updateTransportServerStatusAndEvents(tsConfig *TransportServerConfiguration, warnings configs.Warnings, operationErr error) {
+	operationErr = getResourceError(tsConfig, operationErr)
+
 	eventTitle := "AddedOrUpdated"
 	eventType := api_v1.EventTypeNormal
 	eventWarningMessage := ""
@@ -2165,6 +2429,8 @@ func (lbc *LoadBalancerController) updateTransportServerStatusAndEvents(tsConfig
 }
 
 func (lbc *LoadBalancerController) updateVirtualServerStatusAndEvents(vsConfig *VirtualServerConfiguration, warnings configs.Warnings, operationErr error) {
+	operationErr = getResourceError(vsConfig, operationErr)
+
 	eventType := api_v1.EventTypeNormal
 	eventTitle := "AddedOrUpdated"
 	eventWarningMessage := ""
@@ -2378,7 +2644,7 @@ func (lbc *LoadBalancerController) syncService(task task) {
 	// it is safe to ignore the error
 	namespace, name, _ := ParseNamespaceName(key)
 
-	resources := lbc.configuration.FindResourcesForService(namespace, name)
+	resources := lbc.findResourcesForService(namespace, name)
 
 	if len(resources) == 0 {
 		return
@@ -2393,6 +2659,22 @@ func (lbc *LoadBalancerController) syncService(task task) {
 	lbc.updateResourcesStatusAndEvents(resources, warnings, updateErr)
 }
 
+// findResourcesForService finds resources that reference the specified service,
+// including the resources that reference it through the external auth policies.
+func (lbc *LoadBalancerController) findResourcesForService(svcNamespace string, svcName string) []Resource {
+	resources := lbc.configuration.FindResourcesForService(svcNamespace, svcName)
+
+	if lbc.areCustomResourcesEnabled {
+		for _, pol := range lbc.getPoliciesForService(svcNamespace, svcName) {
+			resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
+		}
+
+		resources = removeDuplicateResources(resources)
+	}
+
+	return resources
+}
+
 // IsExternalServiceForStatus matches the service specified by the external-service cli arg
 func (lbc *LoadBalancerController) IsExternalServiceForStatus(svc *api_v1.Service) bool {
 	return lbc.statusUpdater.namespace == svc.Namespace && lbc.statusUpdater.externalServiceName == svc.Name
@@ -2968,6 +3250,68 @@ func (lbc *LoadBalancerController) getAppProtectPolicy(ing *networking.Ingress)
 	return apPolicy, nil
 }
 
+// createHTTPRouteVirtualServerExes creates the VirtualServerExes for the servers of the hosts that the HTTPRoute holds.
+func (lbc *LoadBalancerController) createHTTPRouteVirtualServerExes(hrConfig *HTTPRouteConfiguration) []*configs.VirtualServerEx {
+	var result []*configs.VirtualServerEx
+
+	for _, server := range hrConfig.GetValidServers() {
+		vs := server.VirtualServer
+		virtualServerEx := &configs.VirtualServerEx{
+			VirtualServer:  vs,
+			SecretRefs:     make(map[string]*secrets.SecretReference),
+			ApPolRefs:      make(map[string]*unstructured.Unstructured),
+			LogConfRefs:    make(map[string]*unstructured.Unstructured),
+			DosProtectedEx: make(map[string]*configs.DosEx),
+		}
+
+		if server.TLSSecret != "" {
+			secretRef := lbc.secretStore.GetSecret(server.TLSSecret)
+			if secretRef.Error != nil {
+				glog.Warningf("Error trying to get the secret %v for HTTPRoute %v/%v: %v", server.TLSSecret, hrConfig.HTTPRoute.Namespace, hrConfig.HTTPRoute.Name, secretRef.Error)
+			}
+
+			// The generated VirtualServer references the secret in its own namespace
+			virtualServerEx.SecretRefs[vs.Namespace+"/"+vs.Spec.TLS.Secret] = secretRef
+		}
+
+		endpoints := make(map[string][]string)
+		externalNameSvcs := make(map[string]bool)
+		podsByIP := make(map[string]configs.PodInfo)
+
+		for _, u := range vs.Spec.Upstreams {
+			// The endpoints are keyed by the namespace of the VirtualServer, while the Service can be in another namespace
+			endpointsKey := configs.GenerateEndpointsKey(vs.Namespace, u.Service, u.Subselector, u.Port)
+
+			podEndps, external, err := lbc.getEndpointsForUpstream(server.ServiceNamespaces[u.Name], u.Service, u.Port)
+			if err != nil {
+				glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+			}
+			if err == nil && external && lbc.isNginxPlus {
+				externalNameSvcs[configs.GenerateExternalNameSvcKey(vs.Namespace, u.Service)] = true
+			}
+
+			endpoints[endpointsKey] = getIPAddressesFromEndpoints(podEndps)
+
+			if (lbc.isNginxPlus && lbc.isPrometheusEnabled) || lbc.isLatencyMetricsEnabled {
+				for _, endpoint := range podEndps {
+					podsByIP[endpoint.Address] = configs.PodInfo{
+						Name:         endpoint.PodName,
+						MeshPodOwner: endpoint.MeshPodOwner,
+					}
+				}
+			}
+		}
+
+		virtualServerEx.Endpoints = endpoints
+		virtualServerEx.ExternalNameSvcs = externalNameSvcs
+		virtualServerEx.PodsByIP = podsByIP
+
+		result = append(result, virtualServerEx)
+	}
+
+	return result
+}
+
 func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.VirtualServer, virtualServerRoutes []*conf_v1.VirtualServerRoute) *configs.VirtualServerEx {
 	virtualServerEx := configs.VirtualServerEx{
 		VirtualServer:  virtualServer,
@@ -2979,8 +3323,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 
 	resource := lbc.configuration.hosts[virtualServer.Spec.Host]
 	if vsc, ok := resource.(*VirtualServerConfiguration); ok {
-		virtualServerEx.HTTPPort = vsc.HTTPPort
-		virtualServerEx.HTTPSPort = vsc.HTTPSPort
+		virtualServerEx.Listeners = vsc.Listeners
 	}
 
 	if virtualServer.Spec.TLS != nil && virtualServer.Spec.TLS.Secret != "" {
@@ -3019,6 +3362,10 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	if err != nil {
 		glog.Warningf("Error getting OIDC secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 	}
+	err = lbc.addAPIKeySecretRefs(virtualServerEx.SecretRefs, policies)
+	if err != nil {
+		glog.Warningf("Error getting APIKey secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
 
 	err = lbc.addWAFPolicyRefs(virtualServerEx.ApPolRefs, virtualServerEx.LogConfRefs, policies)
 	if err != nil {
@@ -3123,6 +3470,10 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		if err != nil {
 			glog.Warningf("Error getting OIDC secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 		}
+		err = lbc.addAPIKeySecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
+		if err != nil {
+			glog.Warningf("Error getting APIKey secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+		}
 	}
 
 	for _, vsr := range virtualServerRoutes {
@@ -3153,6 +3504,11 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 				glog.Warningf("Error getting OIDC secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
 			}
 
+			err = lbc.addAPIKeySecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
+			if err != nil {
+				glog.Warningf("Error getting APIKey secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+			}
+
 			err = lbc.addWAFPolicyRefs(virtualServerEx.ApPolRefs, virtualServerEx.LogConfRefs, vsrSubroutePolicies)
 			if err != nil {
 				glog.Warningf("Error getting WAF policies for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
@@ -3211,6 +3567,25 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		}
 	}
 
+	for _, pol := range policies {
+		if pol.Spec.ExternalAuth == nil {
+			continue
+		}
+
+		extAuth := pol.Spec.ExternalAuth
+		endpointsKey := configs.GenerateEndpointsKey(pol.Namespace, extAuth.Service, nil, extAuth.Port)
+
+		podEndps, external, err := lbc.getEndpointsForUpstream(pol.Namespace, extAuth.Service, extAuth.Port)
+		if err != nil {
+			glog.Warningf("Error getting Endpoints for the auth service %v of Policy %v/%v: %v", extAuth.Service, pol.Namespace, pol.Name, err)
+		}
+		if err == nil && external && lbc.isNginxPlus {
+			externalNameSvcs[configs.GenerateExternalNameSvcKey(pol.Namespace, extAuth.Service)] = true
+		}
+
+		endpoints[endpointsKey] = getIPAddressesFromEndpoints(podEndps)
+	}
+
 	virtualServerEx.Endpoints = endpoints
 	virtualServerEx.VirtualServerRoutes = virtualServerRoutes
 	virtualServerEx.ExternalNameSvcs = externalNameSvcs
@@ -3405,6 +3780,24 @@ func (lbc *LoadBalancerController) addOIDCSecretRefs(secretRefs map[string]*secr
 	return nil
 }
 
+func (lbc *LoadBalancerController) addAPIKeySecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
+	for _, pol := range policies {
+		if pol.Spec.APIKey == nil {
+			continue
+		}
+
+		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.APIKey.ClientSecret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			return secretRef.Error
+		}
+	}
+	return nil
+}
+
 // addWAFPolicyRefs ensures the app protect resources that are referenced in policies exist.
 func (lbc *LoadBalancerController) addWAFPolicyRefs(
 	apPolRef, logConfRef map[string]*unstructured.Unstructured,
@@ -3483,6 +3876,24 @@ func findPoliciesForSecret(policies []*conf_v1.Policy, secretNamespace string, s
 			res = append(res, pol)
 		} else if pol.Spec.OIDC != nil && pol.Spec.OIDC.ClientSecret == secretName && pol.Namespace == secretNamespace {
 			res = append(res, pol)
+		} else if pol.Spec.APIKey != nil && pol.Spec.APIKey.ClientSecret == secretName && pol.Namespace == secretNamespace {
+			res = append(res, pol)
+		}
+	}
+
+	return res
+}
+
+func (lbc *LoadBalancerController) getPoliciesForService(svcNamespace string, svcName string) []*conf_v1.Policy {
+	return findPoliciesForService(lbc.getAllPolicies(), svcNamespace, svcName)
+}
+
+func findPoliciesForService(policies []*conf_v1.Policy, svcNamespace string, svcName string) []*conf_v1.Policy {
+	var res []*conf_v1.Policy
+
+	for _, pol := range policies {
+		if pol.Spec.ExternalAuth != nil && pol.Spec.ExternalAuth.Service == svcName && pol.Namespace == svcNamespace {
+			res = append(res, pol)
 		}
 	}
 
@@ -3528,7 +3939,8 @@ func isMatchingResourceRef(ownerNs, resRef, key string) bool {
 	return resRef == key
 }
 
-func (lbc *LoadBalancerController) createTransportServerEx(transportServer *conf_v1alpha1.TransportServer, listenerPort int) *configs.TransportServerEx {
+func (lbc *LoadBalancerController) createTransportServerEx(tsConfig *TransportServerConfiguration) *configs.TransportServerEx {
+	transportServer := tsConfig.TransportServer
 	endpoints := make(map[string][]string)
 	externalNameSvcs := make(map[string]bool)
 	podsByIP := make(map[string]string)
@@ -3569,15 +3981,44 @@ func (lbc *LoadBalancerController) createTransportServerEx(transportServer *conf
 		secretRefs[secretKey] = secretRef
 	}
 
+	policies, policyErrors := lbc.getPolicies(getTransportServerPolicyReferences(transportServer), transportServer.Namespace)
+	for _, err := range policyErrors {
+		glog.Warningf("Error getting policy for TransportServer %s/%s: %v", transportServer.Namespace, transportServer.Name, err)
+	}
+
+	err := lbc.addIngressMTLSSecretRefs(secretRefs, policies)
+	if err != nil {
+		glog.Warningf("Error getting IngressMTLS secret for TransportServer %s/%s: %v", transportServer.Namespace, transportServer.Name, err)
+	}
+	err = lbc.addEgressMTLSSecretRefs(secretRefs, policies)
+	if err != nil {
+		glog.Warningf("Error getting EgressMTLS secrets for TransportServer %s/%s: %v", transportServer.Namespace, transportServer.Name, err)
+	}
+
 	return &configs.TransportServerEx{
-		ListenerPort:     listenerPort,
+		ListenerPort:     tsConfig.ListenerPort,
+		ListenerIPv4:     tsConfig.ListenerIPv4,
+		ListenerIPv6:     tsConfig.ListenerIPv6,
 		TransportServer:  transportServer,
 		Endpoints:        endpoints,
 		PodsByIP:         podsByIP,
 		ExternalNameSvcs: externalNameSvcs,
 		DisableIPV6:      disableIPV6,
 		SecretRefs:       secretRefs,
+		Policies:         createPolicyMap(policies),
+	}
+}
+
+// getTransportServerPolicyReferences returns the policy references of the TransportServer as the references used by VirtualServers.
+func getTransportServerPolicyReferences(ts *conf_v1alpha1.TransportServer) []conf_v1.PolicyReference {
+	var refs []conf_v1.PolicyReference
+	for _, p := range ts.Spec.Policies {
+		refs = append(refs, conf_v1.PolicyReference{
+			Name:      p.Name,
+			Namespace: p.Namespace,
+		})
 	}
+	return refs
 }
 
 func (lbc *LoadBalancerController) getEndpointsForUpstream(namespace string, upstreamService string, upstreamPort uint16) (endps []podEndpoint, isExternal bool, err error) {
@@ -3964,6 +4405,24 @@ func (lbc *LoadBalancerController) getServiceForIngressBackend(backend *networki
 	return nil, fmt.Errorf("service %s doesn't exist", svcKey)
 }
 
+// isGatewayClassOfController checks if the controller of the GatewayClass with the name is the Ingress Controller.
+func (lbc *LoadBalancerController) isGatewayClassOfController(name string) bool {
+	if lbc.gatewayClassLister == nil {
+		return false
+	}
+
+	obj, exists, err := lbc.gatewayClassLister.GetByKey(name)
+	if err != nil {
+		glog.Errorf("Error when getting GatewayClass %v: %v", name, err)
+		return false
+	}
+	if !exists {
+		return false
+	}
+
+	return obj.(*gateway_v1beta1.GatewayClass).Spec.ControllerName == GatewayControllerName
+}
+
 // HasCorrectIngressClass checks if resource ingress class annotation (if exists) or ingressClass string for VS/VSR is matching with Ingress Controller class
 func (lbc *LoadBalancerController) HasCorrectIngressClass(obj interface{}) bool {
 	var class string
@@ -3976,6 +4435,8 @@ func (lbc *LoadBalancerController) HasCorrectIngressClass(obj interface{}) bool
 		class = obj.Spec.IngressClass
 	case *conf_v1.Policy:
 		class = obj.Spec.IngressClass
+	case *gateway_v1beta1.Gateway:
+		return lbc.isGatewayClassOfController(string(obj.Spec.GatewayClassName))
 	case *networking.Ingress:
 		class = obj.Annotations[ingressClassKey]
 		if class == "" && obj.Spec.IngressClassName != nil {
@@ -4205,6 +4666,18 @@ func (lbc *LoadBalancerController) syncDosProtectedResource(task task) {
 	lbc.processAppProtectDosProblems(problems)
 }
 
+// FindHostHolder returns the key with the kind of the resource that holds the host in the configuration.
+// It returns an empty string if the host is free or if the resource with the kind and the object meta holds it.
+func (lbc *LoadBalancerController) FindHostHolder(host string, kind string, objectMeta *meta_v1.ObjectMeta) string {
+	return lbc.configuration.FindHostHolder(host, kind, objectMeta)
+}
+
+// FindListenerHolder returns the key with the kind of the resource that holds the TransportServer listener in the configuration.
+// It returns an empty string if the listener is free or if the resource with the kind and the object meta holds it.
+func (lbc *LoadBalancerController) FindListenerHolder(listener string, host string, kind string, objectMeta *meta_v1.ObjectMeta) string {
+	return lbc.configuration.FindListenerHolder(listener, host, kind, objectMeta)
+}
+
 // IsNginxReady returns ready status of NGINX
 func (lbc *LoadBalancerController) IsNginxReady() bool {
 	return lbc.isNginxReady
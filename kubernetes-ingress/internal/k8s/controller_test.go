@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 func TestHasCorrectIngressClass(t *testing.T) {
@@ -222,6 +223,71 @@ func TestIngressClassForCustomResources(t *testing.T) {
 	}
 }
 
+func TestIngressClassForGateways(t *testing.T) {
+	t.Parallel()
+	gatewayClassLister := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, gc := range []*gateway_v1beta1.GatewayClass{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "nginx"},
+			Spec:       gateway_v1beta1.GatewayClassSpec{ControllerName: GatewayControllerName},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "other"},
+			Spec:       gateway_v1beta1.GatewayClassSpec{ControllerName: "example.com/gateway-controller"},
+		},
+	} {
+		err := gatewayClassLister.Add(gc)
+		if err != nil {
+			t.Fatalf("Add() returned an unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		lbc              *LoadBalancerController
+		gatewayClassName string
+		expected         bool
+		msg              string
+	}{
+		{
+			lbc:              &LoadBalancerController{ingressClass: "nginx", gatewayClassLister: gatewayClassLister},
+			gatewayClassName: "nginx",
+			expected:         true,
+			msg:              "Ingress Controller handles a Gateway of a GatewayClass with its controller name",
+		},
+		{
+			lbc:              &LoadBalancerController{ingressClass: "other", gatewayClassLister: gatewayClassLister},
+			gatewayClassName: "other",
+			expected:         false,
+			msg:              "Ingress Controller doesn't handle a Gateway of a GatewayClass with another controller name",
+		},
+		{
+			lbc:              &LoadBalancerController{ingressClass: "missing", gatewayClassLister: gatewayClassLister},
+			gatewayClassName: "missing",
+			expected:         false,
+			msg:              "Ingress Controller doesn't handle a Gateway of a nonexistent GatewayClass",
+		},
+		{
+			lbc:              &LoadBalancerController{ingressClass: "nginx"},
+			gatewayClassName: "nginx",
+			expected:         false,
+			msg:              "Ingress Controller doesn't handle Gateways when the GatewayClasses are not watched",
+		},
+	}
+
+	for _, test := range tests {
+		gw := &gateway_v1beta1.Gateway{
+			Spec: gateway_v1beta1.GatewaySpec{
+				GatewayClassName: gateway_v1beta1.ObjectName(test.gatewayClassName),
+			},
+		}
+
+		result := test.lbc.HasCorrectIngressClass(gw)
+		if result != test.expected {
+			t.Errorf("HasCorrectIngressClass() returned %v but expected %v for the case of %q", result, test.expected, test.msg)
+		}
+	}
+}
+
 func TestComparePorts(t *testing.T) {
 	t.Parallel()
 	scenarios := []struct {
@@ -2058,7 +2124,7 @@ func TestGetPolicies(t *testing.T) {
 
 	expectedPolicies := []*conf_v1.Policy{validPolicy}
 	expectedErrors := []error{
-		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `jwt`, `oidc`, `waf`"),
+		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `cors`, `externalAuth`, `apiKey`, `connectionLimit`, `geo`, `bandwidthLimit`, `jwt`, `oidc`, `waf`"),
 		errors.New("policy nginx-ingress/valid-policy doesn't exist"),
 		errors.New("failed to get policy nginx-ingress/some-policy: GetByKey error"),
 		errors.New("referenced policy default/valid-policy-ingress-class has incorrect ingress class: test-class (controller ingress class: )"),
@@ -2406,6 +2472,17 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			},
 		},
 	}
+	apiKeyPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "api-key-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			APIKey: &conf_v1.APIKey{
+				ClientSecret: "api-key-secret",
+			},
+		},
+	}
 
 	tests := []struct {
 		policies        []*conf_v1.Policy
@@ -2512,6 +2589,20 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			expected:        []*conf_v1.Policy{oidcPol},
 			msg:             "Find policy in default ns, ignore other types",
 		},
+		{
+			policies:        []*conf_v1.Policy{apiKeyPol},
+			secretNamespace: "default",
+			secretName:      "api-key-secret",
+			expected:        []*conf_v1.Policy{apiKeyPol},
+			msg:             "Find policy in default ns",
+		},
+		{
+			policies:        []*conf_v1.Policy{oidcPol, apiKeyPol},
+			secretNamespace: "default",
+			secretName:      "api-key-secret",
+			expected:        []*conf_v1.Policy{apiKeyPol},
+			msg:             "Find policy in default ns, ignore other types",
+		},
 	}
 	for _, test := range tests {
 		result := findPoliciesForSecret(test.policies, test.secretNamespace, test.secretName)
@@ -2521,6 +2612,92 @@ func TestFindPoliciesForSecret(t *testing.T) {
 	}
 }
 
+func TestFindPoliciesForService(t *testing.T) {
+	t.Parallel()
+	extAuthPol1 := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				Service: "auth-svc",
+				Port:    8080,
+				Path:    "/auth",
+			},
+		},
+	}
+
+	extAuthPol2 := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ext-auth-policy",
+			Namespace: "ns-1",
+		},
+		Spec: conf_v1.PolicySpec{
+			ExternalAuth: &conf_v1.ExternalAuth{
+				Service: "auth-svc",
+				Port:    8080,
+				Path:    "/auth",
+			},
+		},
+	}
+
+	basicPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "basic-auth-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			BasicAuth: &conf_v1.BasicAuth{
+				Secret: "auth-svc",
+			},
+		},
+	}
+
+	tests := []struct {
+		policies     []*conf_v1.Policy
+		svcNamespace string
+		svcName      string
+		expected     []*conf_v1.Policy
+		msg          string
+	}{
+		{
+			policies:     []*conf_v1.Policy{extAuthPol1},
+			svcNamespace: "default",
+			svcName:      "auth-svc",
+			expected:     []*conf_v1.Policy{extAuthPol1},
+			msg:          "Find policy in default ns",
+		},
+		{
+			policies:     []*conf_v1.Policy{extAuthPol2},
+			svcNamespace: "default",
+			svcName:      "auth-svc",
+			expected:     nil,
+			msg:          "Ignore policies in other namespaces",
+		},
+		{
+			policies:     []*conf_v1.Policy{extAuthPol1, extAuthPol2},
+			svcNamespace: "default",
+			svcName:      "other-svc",
+			expected:     nil,
+			msg:          "Ignore policies for other services",
+		},
+		{
+			policies:     []*conf_v1.Policy{basicPol, extAuthPol1, extAuthPol2},
+			svcNamespace: "ns-1",
+			svcName:      "auth-svc",
+			expected:     []*conf_v1.Policy{extAuthPol2},
+			msg:          "Find policy in non default ns, ignore other types",
+		},
+	}
+	for _, test := range tests {
+		result := findPoliciesForService(test.policies, test.svcNamespace, test.svcName)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("findPoliciesForService() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func errorComparer(e1, e2 error) bool {
 	if e1 == nil || e2 == nil {
 		return errors.Is(e1, e2)
@@ -3725,3 +3902,59 @@ func TestPreSyncSecrets(t *testing.T) {
 		t.Errorf("GetSecret(%q) returned a reference without an expected error", unsupportedKey)
 	}
 }
+
+func TestGetResourceError(t *testing.T) {
+	t.Parallel()
+
+	nginxErr := errors.New("nginx: [emerg] unknown directive \"broken\"")
+	reloadErr := errors.New("nginx reload failed")
+	invalidErr := fmt.Errorf("error reloading NGINX for VirtualServer default/tea: %w", &configs.InvalidResourcesError{
+		Resources: map[string]error{"VirtualServer/default/cafe": nginxErr},
+		Err:       reloadErr,
+	})
+
+	cafe := &VirtualServerConfiguration{
+		VirtualServer: &conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "cafe"}},
+	}
+	tea := &VirtualServerConfiguration{
+		VirtualServer: &conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "tea"}},
+	}
+
+	tests := []struct {
+		resource     Resource
+		operationErr error
+		expected     error
+		msg          string
+	}{
+		{
+			resource:     cafe,
+			operationErr: invalidErr,
+			expected:     nginxErr,
+			msg:          "invalid resource",
+		},
+		{
+			resource:     tea,
+			operationErr: invalidErr,
+			expected:     nil,
+			msg:          "valid resource reloaded with an invalid resource",
+		},
+		{
+			resource:     tea,
+			operationErr: reloadErr,
+			expected:     reloadErr,
+			msg:          "failed reload",
+		},
+		{
+			resource:     tea,
+			operationErr: nil,
+			expected:     nil,
+			msg:          "successful reload",
+		},
+	}
+
+	for _, test := range tests {
+		if err := getResourceError(test.resource, test.operationErr); !errors.Is(err, test.expected) {
+			t.Errorf("getResourceError() returned %v, want %v for the case of %s", err, test.expected, test.msg)
+		}
+	}
+}
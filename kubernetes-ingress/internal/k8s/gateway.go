@@ -0,0 +1,954 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	gatewayKind         = "Gateway"
+	secretKind          = "Secret"
+	serviceKind         = "Service"
+	gatewayAPIGroup     = gateway_v1beta1.GroupName
+	httpListenerPort    = 80
+	httpsListenerPort   = 443
+	httpRouteNamePrefix = "httproute"
+)
+
+// HTTPRouteConfiguration holds an HTTPRoute along with the VirtualServers generated for its hosts.
+// It implements the Resource interface.
+type HTTPRouteConfiguration struct {
+	HTTPRoute *gateway_v1beta1.HTTPRoute
+	// Servers maps the hosts of the Gateway listeners that accept the HTTPRoute to the generated servers.
+	Servers map[string]*HTTPRouteServer
+	// ValidHosts marks the hosts that the HTTPRoute holds.
+	ValidHosts map[string]bool
+	// Parents holds the statuses of the parents of the HTTPRoute that are Gateways of the Ingress Controller.
+	Parents  []gateway_v1beta1.RouteParentStatus
+	Warnings []string
+}
+
+// HTTPRouteServer holds the VirtualServer generated for a host of an HTTPRoute.
+type HTTPRouteServer struct {
+	VirtualServer *conf_v1.VirtualServer
+	// TLSSecret is the key of the TLS Secret of the Gateway listener. For example, my-namespace/my-secret.
+	TLSSecret string
+	// ServiceNamespaces maps the upstreams of the VirtualServer to the namespaces of their Services.
+	ServiceNamespaces map[string]string
+}
+
+// GetObjectMeta returns the resource ObjectMeta.
+func (hrc *HTTPRouteConfiguration) GetObjectMeta() *metav1.ObjectMeta {
+	return &hrc.HTTPRoute.ObjectMeta
+}
+
+// GetKeyWithKind returns the key of the resource with its kind. For example, HTTPRoute/my-namespace/my-name.
+func (hrc *HTTPRouteConfiguration) GetKeyWithKind() string {
+	key := getResourceKey(&hrc.HTTPRoute.ObjectMeta)
+	return fmt.Sprintf("%s/%s", httpRouteKind, key)
+}
+
+// Wins tells if this resource wins over the specified resource.
+// It is used to determine which resource should win over a host.
+func (hrc *HTTPRouteConfiguration) Wins(resource Resource) bool {
+	return chooseObjectMetaWinner(hrc.GetObjectMeta(), resource.GetObjectMeta())
+}
+
+// AddWarning adds a warning.
+func (hrc *HTTPRouteConfiguration) AddWarning(warning string) {
+	hrc.Warnings = append(hrc.Warnings, warning)
+}
+
+// IsEqual tests if the HTTPRouteConfiguration is equal to the resource.
+// Because the servers depend on the Gateways and ReferenceGrants, not only on the HTTPRoute,
+// the servers and the statuses of the parents are compared as well.
+func (hrc *HTTPRouteConfiguration) IsEqual(resource Resource) bool {
+	hrConfig, ok := resource.(*HTTPRouteConfiguration)
+	if !ok {
+		return false
+	}
+
+	return compareObjectMetas(hrc.GetObjectMeta(), hrConfig.GetObjectMeta()) &&
+		reflect.DeepEqual(hrc.ValidHosts, hrConfig.ValidHosts) &&
+		reflect.DeepEqual(hrc.Servers, hrConfig.Servers) &&
+		reflect.DeepEqual(hrc.Parents, hrConfig.Parents)
+}
+
+// GetValidServers returns the servers of the hosts that the HTTPRoute holds, sorted by host.
+func (hrc *HTTPRouteConfiguration) GetValidServers() []*HTTPRouteServer {
+	var hosts []string
+	for h, valid := range hrc.ValidHosts {
+		if valid {
+			hosts = append(hosts, h)
+		}
+	}
+	sort.Strings(hosts)
+
+	var servers []*HTTPRouteServer
+	for _, h := range hosts {
+		servers = append(servers, hrc.Servers[h])
+	}
+	return servers
+}
+
+// routeRejection explains why a parent doesn't accept an HTTPRoute.
+type routeRejection struct {
+	reason  gateway_v1beta1.RouteConditionReason
+	message string
+}
+
+// newHTTPRouteConfiguration creates an HTTPRouteConfiguration for the HTTPRoute that references the Gateways.
+// Only the Gateways of the Ingress Controller are expected in the gateways map.
+// It returns nil if the HTTPRoute doesn't reference any of those Gateways.
+func newHTTPRouteConfiguration(
+	route *gateway_v1beta1.HTTPRoute,
+	gateways map[string]*gateway_v1beta1.Gateway,
+	referenceGrants map[string]*gateway_v1beta1.ReferenceGrant,
+	validateVirtualServer func(vs *conf_v1.VirtualServer) error,
+) *HTTPRouteConfiguration {
+	hrc := &HTTPRouteConfiguration{
+		HTTPRoute:  route,
+		Servers:    make(map[string]*HTTPRouteServer),
+		ValidHosts: make(map[string]bool),
+	}
+
+	// host -> TLS secret key; an empty key means a host of an HTTP listener
+	hostSecrets := make(map[string]string)
+	var acceptedParents []int
+
+	for _, ref := range route.Spec.ParentRefs {
+		if !isGatewayParentRef(ref) {
+			continue
+		}
+
+		ns := route.Namespace
+		if ref.Namespace != nil {
+			ns = string(*ref.Namespace)
+		}
+
+		gw, exists := gateways[ns+"/"+string(ref.Name)]
+		if !exists {
+			continue
+		}
+
+		hosts, rejection := attachHTTPRouteToGateway(route, ref, gw, referenceGrants)
+		for h, secret := range hosts {
+			if hostSecrets[h] == "" {
+				hostSecrets[h] = secret
+			}
+		}
+
+		parent := gateway_v1beta1.RouteParentStatus{
+			ParentRef:      ref,
+			ControllerName: gateway_v1beta1.GatewayController(GatewayControllerName),
+		}
+		if rejection != nil {
+			parent.Conditions = append(parent.Conditions,
+				newRouteCondition(route, gateway_v1beta1.RouteConditionAccepted, metav1.ConditionFalse, rejection.reason, rejection.message))
+		} else {
+			acceptedParents = append(acceptedParents, len(hrc.Parents))
+			parent.Conditions = append(parent.Conditions,
+				newRouteCondition(route, gateway_v1beta1.RouteConditionAccepted, metav1.ConditionTrue, gateway_v1beta1.RouteReasonAccepted, "The route is accepted"))
+		}
+		hrc.Parents = append(hrc.Parents, parent)
+	}
+
+	if len(hrc.Parents) == 0 {
+		return nil
+	}
+
+	if len(acceptedParents) == 0 {
+		return hrc
+	}
+
+	converted := convertHTTPRouteRules(route, referenceGrants)
+	hrc.Warnings = append(hrc.Warnings, converted.warnings...)
+
+	resolvedRefs := newRouteCondition(route, gateway_v1beta1.RouteConditionResolvedRefs, metav1.ConditionTrue, gateway_v1beta1.RouteReasonResolvedRefs, "All references are resolved")
+	if converted.unresolvedRef != nil {
+		resolvedRefs = newRouteCondition(route, gateway_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, converted.unresolvedRef.reason, converted.unresolvedRef.message)
+	}
+
+	hosts := make([]string, 0, len(hostSecrets))
+	for h := range hostSecrets {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+
+	validationErr := converted.unsupportedValue
+	for i, h := range hosts {
+		if validationErr != nil {
+			break
+		}
+
+		vs := &conf_v1.VirtualServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         route.Namespace,
+				Name:              fmt.Sprintf("%s_%s_%d", httpRouteNamePrefix, route.Name, i),
+				UID:               route.UID,
+				CreationTimestamp: route.CreationTimestamp,
+				Generation:        route.Generation,
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host:      h,
+				Upstreams: converted.upstreams,
+				Routes:    converted.routes,
+			},
+		}
+
+		server := &HTTPRouteServer{
+			VirtualServer:     vs,
+			ServiceNamespaces: converted.serviceNamespaces,
+		}
+
+		if secret := hostSecrets[h]; secret != "" {
+			_, name, _ := strings.Cut(secret, "/")
+			vs.Spec.TLS = &conf_v1.TLS{
+				Secret: name,
+			}
+			server.TLSSecret = secret
+		}
+
+		if err := validateVirtualServer(vs); err != nil {
+			validationErr = err
+			break
+		}
+
+		hrc.Servers[h] = server
+	}
+
+	if validationErr != nil {
+		hrc.Servers = make(map[string]*HTTPRouteServer)
+		msg := fmt.Sprintf("The route cannot be converted into NGINX configuration: %v", validationErr)
+		hrc.AddWarning(msg)
+		for _, i := range acceptedParents {
+			hrc.Parents[i].Conditions[0] = newRouteCondition(route, gateway_v1beta1.RouteConditionAccepted, metav1.ConditionFalse, gateway_v1beta1.RouteReasonUnsupportedValue, msg)
+		}
+		return hrc
+	}
+
+	for _, i := range acceptedParents {
+		hrc.Parents[i].Conditions = append(hrc.Parents[i].Conditions, resolvedRefs)
+	}
+
+	return hrc
+}
+
+// newRejectedRouteParents returns copies of the statuses of the parents where the parents that accepted the HTTPRoute reject it.
+func newRejectedRouteParents(parents []gateway_v1beta1.RouteParentStatus, message string) []gateway_v1beta1.RouteParentStatus {
+	var result []gateway_v1beta1.RouteParentStatus
+
+	for _, p := range parents {
+		parent := *p.DeepCopy()
+		for i := range parent.Conditions {
+			c := &parent.Conditions[i]
+			if c.Type == string(gateway_v1beta1.RouteConditionAccepted) && c.Status == metav1.ConditionTrue {
+				c.Status = metav1.ConditionFalse
+				c.Reason = "Rejected"
+				c.Message = message
+			}
+		}
+		result = append(result, parent)
+	}
+
+	return result
+}
+
+func isGatewayParentRef(ref gateway_v1beta1.ParentReference) bool {
+	if ref.Group != nil && string(*ref.Group) != gatewayAPIGroup {
+		return false
+	}
+	return ref.Kind == nil || string(*ref.Kind) == gatewayKind
+}
+
+func newRouteCondition(route *gateway_v1beta1.HTTPRoute, condType gateway_v1beta1.RouteConditionType, status metav1.ConditionStatus,
+	reason gateway_v1beta1.RouteConditionReason, message string,
+) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(condType),
+		Status:             status,
+		ObservedGeneration: route.Generation,
+		Reason:             string(reason),
+		Message:            message,
+	}
+}
+
+// attachHTTPRouteToGateway finds the listeners of the Gateway that accept the HTTPRoute and returns the hosts of the route
+// along with the TLS secrets of the listeners. If no listener accepts the route, it returns the reason.
+func attachHTTPRouteToGateway(route *gateway_v1beta1.HTTPRoute, ref gateway_v1beta1.ParentReference, gw *gateway_v1beta1.Gateway,
+	referenceGrants map[string]*gateway_v1beta1.ReferenceGrant,
+) (map[string]string, *routeRejection) {
+	hosts := make(map[string]string)
+	rejection := &routeRejection{
+		reason:  gateway_v1beta1.RouteReasonNoMatchingParent,
+		message: fmt.Sprintf("Gateway %s/%s has no matching listener", gw.Namespace, gw.Name),
+	}
+
+	for _, l := range gw.Spec.Listeners {
+		if ref.SectionName != nil && *ref.SectionName != l.Name {
+			continue
+		}
+		if ref.Port != nil && *ref.Port != l.Port {
+			continue
+		}
+
+		secret, err := validateGatewayListener(gw, l, referenceGrants)
+		if err != nil {
+			rejection = &routeRejection{
+				reason:  gateway_v1beta1.RouteReasonNotAllowedByListeners,
+				message: fmt.Sprintf("Listener %s of Gateway %s/%s is not supported: %v", l.Name, gw.Namespace, gw.Name, err),
+			}
+			continue
+		}
+
+		if !isHTTPRouteAllowedByListener(route, gw, l) {
+			rejection = &routeRejection{
+				reason:  gateway_v1beta1.RouteReasonNotAllowedByListeners,
+				message: fmt.Sprintf("Listener %s of Gateway %s/%s doesn't allow the route", l.Name, gw.Namespace, gw.Name),
+			}
+			continue
+		}
+
+		listenerHosts, err := getHTTPRouteHostsForListener(route, l)
+		if err != nil {
+			rejection = &routeRejection{
+				reason:  gateway_v1beta1.RouteReasonNoMatchingListenerHostname,
+				message: fmt.Sprintf("Listener %s of Gateway %s/%s: %v", l.Name, gw.Namespace, gw.Name, err),
+			}
+			continue
+		}
+
+		for _, h := range listenerHosts {
+			if hosts[h] == "" {
+				hosts[h] = secret
+			}
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil, rejection
+	}
+	return hosts, nil
+}
+
+// validateGatewayListener validates that the listener is supported and returns the key of its TLS secret.
+// Only HTTP listeners on port 80 and HTTPS listeners on port 443 that terminate TLS are supported.
+func validateGatewayListener(gw *gateway_v1beta1.Gateway, l gateway_v1beta1.Listener, referenceGrants map[string]*gateway_v1beta1.ReferenceGrant) (string, error) {
+	switch l.Protocol {
+	case gateway_v1beta1.HTTPProtocolType:
+		if l.Port != httpListenerPort {
+			return "", fmt.Errorf("HTTP listeners must use port %d", httpListenerPort)
+		}
+		return "", nil
+	case gateway_v1beta1.HTTPSProtocolType:
+		if l.Port != httpsListenerPort {
+			return "", fmt.Errorf("HTTPS listeners must use port %d", httpsListenerPort)
+		}
+	default:
+		return "", fmt.Errorf("protocol %s is not supported", l.Protocol)
+	}
+
+	if l.TLS == nil || len(l.TLS.CertificateRefs) == 0 {
+		return "", fmt.Errorf("HTTPS listeners must reference a certificate")
+	}
+	if l.TLS.Mode != nil && *l.TLS.Mode != gateway_v1beta1.TLSModeTerminate {
+		return "", fmt.Errorf("TLS mode %s is not supported", *l.TLS.Mode)
+	}
+
+	ref := l.TLS.CertificateRefs[0]
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != secretKind) {
+		return "", fmt.Errorf("the certificate must be a Secret")
+	}
+
+	ns := gw.Namespace
+	if ref.Namespace != nil {
+		ns = string(*ref.Namespace)
+	}
+	if ns != gw.Namespace && !isReferenceGranted(referenceGrants, gatewayAPIGroup, gatewayKind, gw.Namespace, "", secretKind, ns, string(ref.Name)) {
+		return "", fmt.Errorf("no ReferenceGrant allows the reference to Secret %s/%s", ns, ref.Name)
+	}
+
+	return ns + "/" + string(ref.Name), nil
+}
+
+func isHTTPRouteAllowedByListener(route *gateway_v1beta1.HTTPRoute, gw *gateway_v1beta1.Gateway, l gateway_v1beta1.Listener) bool {
+	if l.AllowedRoutes != nil && len(l.AllowedRoutes.Kinds) > 0 {
+		allowed := false
+		for _, k := range l.AllowedRoutes.Kinds {
+			if (k.Group == nil || string(*k.Group) == gatewayAPIGroup) && k.Kind == httpRouteKind {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	from := gateway_v1beta1.NamespacesFromSame
+	if l.AllowedRoutes != nil && l.AllowedRoutes.Namespaces != nil && l.AllowedRoutes.Namespaces.From != nil {
+		from = *l.AllowedRoutes.Namespaces.From
+	}
+
+	switch from {
+	case gateway_v1beta1.NamespacesFromAll:
+		return true
+	case gateway_v1beta1.NamespacesFromSame:
+		return route.Namespace == gw.Namespace
+	default:
+		// Selecting the namespaces by labels is not supported
+		return false
+	}
+}
+
+// getHTTPRouteHostsForListener returns the hostnames of the HTTPRoute that match the hostname of the listener.
+func getHTTPRouteHostsForListener(route *gateway_v1beta1.HTTPRoute, l gateway_v1beta1.Listener) ([]string, error) {
+	if l.Hostname == nil || *l.Hostname == "" {
+		if len(route.Spec.Hostnames) == 0 {
+			return nil, fmt.Errorf("either the listener or the route must specify a hostname")
+		}
+		var hosts []string
+		for _, h := range route.Spec.Hostnames {
+			hosts = append(hosts, string(h))
+		}
+		return hosts, nil
+	}
+
+	listenerHost := string(*l.Hostname)
+	if len(route.Spec.Hostnames) == 0 {
+		return []string{listenerHost}, nil
+	}
+
+	var hosts []string
+	for _, h := range route.Spec.Hostnames {
+		routeHost := string(h)
+		switch {
+		case routeHost == listenerHost:
+			hosts = append(hosts, routeHost)
+		case matchesWildcardHost(routeHost, listenerHost):
+			hosts = append(hosts, routeHost)
+		case matchesWildcardHost(listenerHost, routeHost):
+			hosts = append(hosts, listenerHost)
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no hostname of the route matches the hostname %s", listenerHost)
+	}
+	return hosts, nil
+}
+
+// matchesWildcardHost tells if the host matches the wildcard host like *.example.com.
+func matchesWildcardHost(host string, wildcardHost string) bool {
+	if !strings.HasPrefix(wildcardHost, "*.") || strings.HasPrefix(host, "*.") {
+		return false
+	}
+	return strings.HasSuffix(host, wildcardHost[1:]) && len(host) > len(wildcardHost)-1
+}
+
+// isReferenceGranted tells if a ReferenceGrant in the namespace of the target allows the reference.
+func isReferenceGranted(referenceGrants map[string]*gateway_v1beta1.ReferenceGrant, fromGroup string, fromKind string, fromNamespace string,
+	toGroup string, toKind string, toNamespace string, toName string,
+) bool {
+	for _, grant := range referenceGrants {
+		if grant.Namespace != toNamespace {
+			continue
+		}
+
+		fromAllowed := false
+		for _, from := range grant.Spec.From {
+			if string(from.Group) == fromGroup && string(from.Kind) == fromKind && string(from.Namespace) == fromNamespace {
+				fromAllowed = true
+				break
+			}
+		}
+		if !fromAllowed {
+			continue
+		}
+
+		for _, to := range grant.Spec.To {
+			if string(to.Group) == toGroup && string(to.Kind) == toKind && (to.Name == nil || string(*to.Name) == toName) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// convertedHTTPRouteRules holds the upstreams and routes of a VirtualServer generated from the rules of an HTTPRoute.
+type convertedHTTPRouteRules struct {
+	upstreams         []conf_v1.Upstream
+	routes            []conf_v1.Route
+	serviceNamespaces map[string]string
+	warnings          []string
+	unresolvedRef     *routeRejection
+	// unsupportedValue is the first value of the rules that cannot be converted, which rejects the HTTPRoute.
+	unsupportedValue error
+}
+
+// httpRouteBackend is a backend of an HTTPRoute rule. The upstream of a backend that cannot be resolved is empty.
+type httpRouteBackend struct {
+	upstream string
+	weight   int
+}
+
+// convertHTTPRouteRules converts the rules of the HTTPRoute into the upstreams and routes of a VirtualServer.
+// The matches of the rules are grouped by path. The matches with headers, query parameters or a method become
+// the matches of the VirtualServer route, while the match without them becomes the action of the route.
+func convertHTTPRouteRules(route *gateway_v1beta1.HTTPRoute, referenceGrants map[string]*gateway_v1beta1.ReferenceGrant) *convertedHTTPRouteRules {
+	result := &convertedHTTPRouteRules{
+		serviceNamespaces: make(map[string]string),
+	}
+
+	// service:port -> namespace, to detect the Services with the same name and port in different namespaces
+	servicePorts := make(map[string]string)
+	routesByPath := make(map[string]*conf_v1.Route)
+	var paths []string
+
+	for i, rule := range route.Spec.Rules {
+		rulePath := fmt.Sprintf("spec.rules[%d]", i)
+
+		var backends []httpRouteBackend
+		for j, ref := range rule.BackendRefs {
+			refPath := fmt.Sprintf("%s.backendRefs[%d]", rulePath, j)
+
+			if len(ref.Filters) > 0 {
+				result.warnings = append(result.warnings, fmt.Sprintf("%s.filters are not supported and ignored", refPath))
+			}
+
+			// the requests for a backend that cannot be resolved get a 500 response
+			upstream, rejection := result.addUpstream(route, ref.BackendRef, servicePorts, referenceGrants)
+			if rejection != nil && result.unresolvedRef == nil {
+				result.unresolvedRef = &routeRejection{
+					reason:  rejection.reason,
+					message: fmt.Sprintf("%s: %s", refPath, rejection.message),
+				}
+			}
+
+			weight := 1
+			if ref.Weight != nil {
+				weight = int(*ref.Weight)
+			}
+			if weight > 0 {
+				backends = append(backends, httpRouteBackend{upstream: upstream, weight: weight})
+			}
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			pathType := gateway_v1beta1.PathMatchPathPrefix
+			value := "/"
+			matches = []gateway_v1beta1.HTTPRouteMatch{{Path: &gateway_v1beta1.HTTPPathMatch{Type: &pathType, Value: &value}}}
+		}
+
+		for j, m := range matches {
+			matchPath := fmt.Sprintf("%s.matches[%d]", rulePath, j)
+
+			path, pathType, err := convertHTTPPathMatch(m.Path)
+			if err != nil {
+				result.warnings = append(result.warnings, fmt.Sprintf("%s.path is ignored: %v", matchPath, err))
+				continue
+			}
+
+			conditions, err := convertHTTPRouteMatchConditions(m)
+			if errors.Is(err, errNegatedMatchValue) {
+				if result.unsupportedValue == nil {
+					result.unsupportedValue = fmt.Errorf("%s: %w", matchPath, err)
+				}
+				continue
+			}
+			if err != nil {
+				result.warnings = append(result.warnings, fmt.Sprintf("%s is ignored: %v", matchPath, err))
+				continue
+			}
+
+			action, splits, warnings := convertHTTPRouteRuleAction(rule, pathType, backends)
+			for _, w := range warnings {
+				result.warnings = append(result.warnings, fmt.Sprintf("%s: %s", rulePath, w))
+			}
+
+			r, exists := routesByPath[path]
+			if !exists {
+				r = &conf_v1.Route{Path: path}
+				routesByPath[path] = r
+				paths = append(paths, path)
+			}
+
+			if len(conditions) == 0 {
+				// the older rules take precedence
+				if r.Action == nil && len(r.Splits) == 0 {
+					r.Action = action
+					r.Splits = splits
+				}
+				continue
+			}
+
+			r.Matches = append(r.Matches, conf_v1.Match{
+				Conditions: conditions,
+				Action:     action,
+				Splits:     splits,
+			})
+		}
+	}
+
+	for _, p := range paths {
+		r := routesByPath[p]
+
+		// the matches with more conditions are more specific, so they are evaluated first
+		sort.SliceStable(r.Matches, func(i, j int) bool {
+			return len(r.Matches[i].Conditions) > len(r.Matches[j].Conditions)
+		})
+
+		if r.Action == nil && len(r.Splits) == 0 {
+			r.Action = &conf_v1.Action{
+				Return: &conf_v1.ActionReturn{
+					Code: 404,
+					Body: "Not Found",
+				},
+			}
+		}
+
+		result.routes = append(result.routes, *r)
+	}
+
+	return result
+}
+
+// addUpstream adds an upstream for the backend and returns its name.
+// If the backend cannot be resolved, it returns the reason.
+func (result *convertedHTTPRouteRules) addUpstream(route *gateway_v1beta1.HTTPRoute, ref gateway_v1beta1.BackendRef, servicePorts map[string]string,
+	referenceGrants map[string]*gateway_v1beta1.ReferenceGrant,
+) (string, *routeRejection) {
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != serviceKind) {
+		return "", &routeRejection{
+			reason:  gateway_v1beta1.RouteReasonInvalidKind,
+			message: "only Services are supported as backends",
+		}
+	}
+
+	if ref.Port == nil {
+		return "", &routeRejection{
+			reason:  gateway_v1beta1.RouteReasonUnsupportedValue,
+			message: "port is required",
+		}
+	}
+
+	ns := route.Namespace
+	if ref.Namespace != nil {
+		ns = string(*ref.Namespace)
+	}
+
+	if ns != route.Namespace && !isReferenceGranted(referenceGrants, gatewayAPIGroup, httpRouteKind, route.Namespace, "", serviceKind, ns, string(ref.Name)) {
+		return "", &routeRejection{
+			reason:  gateway_v1beta1.RouteReasonRefNotPermitted,
+			message: fmt.Sprintf("no ReferenceGrant allows the reference to Service %s/%s", ns, ref.Name),
+		}
+	}
+
+	// The endpoints of the upstreams are keyed by the namespace of the VirtualServer, the name of the Service and the port.
+	servicePort := fmt.Sprintf("%s:%d", ref.Name, *ref.Port)
+	if otherNs, exists := servicePorts[servicePort]; exists && otherNs != ns {
+		return "", &routeRejection{
+			reason:  gateway_v1beta1.RouteReasonUnsupportedValue,
+			message: fmt.Sprintf("Service %s/%s conflicts with Service %s/%s with the same name and port", ns, ref.Name, otherNs, ref.Name),
+		}
+	}
+	servicePorts[servicePort] = ns
+
+	name := fmt.Sprintf("%s-%d", ref.Name, *ref.Port)
+	if ns != route.Namespace {
+		name = fmt.Sprintf("%s-%s-%d", ns, ref.Name, *ref.Port)
+	}
+
+	if _, exists := result.serviceNamespaces[name]; !exists {
+		result.serviceNamespaces[name] = ns
+		result.upstreams = append(result.upstreams, conf_v1.Upstream{
+			Name:    name,
+			Service: string(ref.Name),
+			Port:    uint16(*ref.Port),
+		})
+	}
+
+	return name, nil
+}
+
+// convertHTTPPathMatch converts the path match into the path of a VirtualServer route.
+func convertHTTPPathMatch(match *gateway_v1beta1.HTTPPathMatch) (string, gateway_v1beta1.PathMatchType, error) {
+	pathType := gateway_v1beta1.PathMatchPathPrefix
+	value := "/"
+
+	if match != nil {
+		if match.Type != nil {
+			pathType = *match.Type
+		}
+		if match.Value != nil {
+			value = *match.Value
+		}
+	}
+
+	switch pathType {
+	case gateway_v1beta1.PathMatchPathPrefix:
+		return value, pathType, nil
+	case gateway_v1beta1.PathMatchExact:
+		return "=" + value, pathType, nil
+	case gateway_v1beta1.PathMatchRegularExpression:
+		return "~ " + value, pathType, nil
+	default:
+		return "", pathType, fmt.Errorf("type %s is not supported", pathType)
+	}
+}
+
+// errNegatedMatchValue is returned for the header and query parameter values that start with '!',
+// because such a value negates the condition of a VirtualServer match.
+var errNegatedMatchValue = errors.New("values starting with '!' are not supported")
+
+// convertHTTPRouteMatchConditions converts the header, query parameter and method matches into the conditions of a VirtualServer match.
+func convertHTTPRouteMatchConditions(match gateway_v1beta1.HTTPRouteMatch) ([]conf_v1.Condition, error) {
+	var conditions []conf_v1.Condition
+
+	for _, h := range match.Headers {
+		if h.Type != nil && *h.Type != gateway_v1beta1.HeaderMatchExact {
+			return nil, fmt.Errorf("header match type %s is not supported", *h.Type)
+		}
+		if strings.HasPrefix(h.Value, "!") {
+			return nil, fmt.Errorf("header %s: %w", h.Name, errNegatedMatchValue)
+		}
+		conditions = append(conditions, conf_v1.Condition{
+			Header: string(h.Name),
+			Value:  h.Value,
+		})
+	}
+
+	for _, q := range match.QueryParams {
+		if q.Type != nil && *q.Type != gateway_v1beta1.QueryParamMatchExact {
+			return nil, fmt.Errorf("query parameter match type %s is not supported", *q.Type)
+		}
+		if strings.HasPrefix(q.Value, "!") {
+			return nil, fmt.Errorf("query parameter %s: %w", q.Name, errNegatedMatchValue)
+		}
+		conditions = append(conditions, conf_v1.Condition{
+			Argument: string(q.Name),
+			Value:    q.Value,
+		})
+	}
+
+	if match.Method != nil {
+		conditions = append(conditions, conf_v1.Condition{
+			Variable: "$request_method",
+			Value:    string(*match.Method),
+		})
+	}
+
+	return conditions, nil
+}
+
+// convertHTTPRouteRuleAction converts the filters and the backends of the rule into the action or the splits of a VirtualServer route.
+func convertHTTPRouteRuleAction(rule gateway_v1beta1.HTTPRouteRule, pathType gateway_v1beta1.PathMatchType, backends []httpRouteBackend) (*conf_v1.Action, []conf_v1.Split, []string) {
+	var warnings []string
+	var requestHeaders *conf_v1.ProxyRequestHeaders
+	rewritePath := ""
+
+	for i, f := range rule.Filters {
+		switch f.Type {
+		case gateway_v1beta1.HTTPRouteFilterRequestRedirect:
+			if f.RequestRedirect == nil {
+				continue
+			}
+			redirect, err := convertHTTPRequestRedirect(f.RequestRedirect)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("filters[%d] is ignored: %v", i, err))
+				continue
+			}
+			return &conf_v1.Action{Redirect: redirect}, nil, warnings
+		case gateway_v1beta1.HTTPRouteFilterRequestHeaderModifier:
+			if f.RequestHeaderModifier == nil {
+				continue
+			}
+			if requestHeaders == nil {
+				requestHeaders = &conf_v1.ProxyRequestHeaders{}
+			}
+			requestHeaders.Set = append(requestHeaders.Set, convertHTTPHeaderFilter(f.RequestHeaderModifier)...)
+		case gateway_v1beta1.HTTPRouteFilterURLRewrite:
+			if f.URLRewrite == nil {
+				continue
+			}
+			if f.URLRewrite.Hostname != nil {
+				if requestHeaders == nil {
+					requestHeaders = &conf_v1.ProxyRequestHeaders{}
+				}
+				requestHeaders.Set = append(requestHeaders.Set, conf_v1.Header{Name: "Host", Value: string(*f.URLRewrite.Hostname)})
+			}
+			if f.URLRewrite.Path != nil {
+				path, err := convertHTTPPathModifier(f.URLRewrite.Path, pathType)
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("filters[%d].urlRewrite.path is ignored: %v", i, err))
+					continue
+				}
+				rewritePath = path
+			}
+		default:
+			warnings = append(warnings, fmt.Sprintf("filters[%d] of type %s is not supported and ignored", i, f.Type))
+		}
+	}
+
+	newInternalServerError := func() *conf_v1.Action {
+		return &conf_v1.Action{
+			Return: &conf_v1.ActionReturn{
+				Code: 500,
+				Body: "Internal Server Error",
+			},
+		}
+	}
+
+	hasValidBackends := slices.ContainsFunc(backends, func(b httpRouteBackend) bool {
+		return b.upstream != ""
+	})
+	if !hasValidBackends {
+		return newInternalServerError(), nil, warnings
+	}
+
+	newAction := func(upstream string) *conf_v1.Action {
+		if upstream == "" {
+			return newInternalServerError()
+		}
+		if requestHeaders == nil && rewritePath == "" {
+			return &conf_v1.Action{Pass: upstream}
+		}
+		return &conf_v1.Action{
+			Proxy: &conf_v1.ActionProxy{
+				Upstream:       upstream,
+				RewritePath:    rewritePath,
+				RequestHeaders: requestHeaders,
+			},
+		}
+	}
+
+	if len(backends) == 1 {
+		return newAction(backends[0].upstream), nil, warnings
+	}
+
+	weights := make([]int, len(backends))
+	for i, b := range backends {
+		weights[i] = b.weight
+	}
+	weights = normalizeSplitWeights(weights)
+
+	var splits []conf_v1.Split
+	for i, b := range backends {
+		splits = append(splits, conf_v1.Split{
+			Weight: weights[i],
+			Action: newAction(b.upstream),
+		})
+	}
+
+	return nil, splits, warnings
+}
+
+// convertHTTPHeaderFilter converts the header modifier into the headers to set.
+// NGINX doesn't append the values of the headers, so the added headers are set.
+// The removed headers are set to an empty value, which makes NGINX not pass them.
+func convertHTTPHeaderFilter(filter *gateway_v1beta1.HTTPHeaderFilter) []conf_v1.Header {
+	var headers []conf_v1.Header
+
+	for _, h := range filter.Set {
+		headers = append(headers, conf_v1.Header{Name: string(h.Name), Value: h.Value})
+	}
+	for _, h := range filter.Add {
+		headers = append(headers, conf_v1.Header{Name: string(h.Name), Value: h.Value})
+	}
+	for _, name := range filter.Remove {
+		headers = append(headers, conf_v1.Header{Name: name, Value: ""})
+	}
+
+	return headers
+}
+
+// convertHTTPPathModifier converts the path modifier of a rewrite into the rewrite path of a VirtualServer action.
+// The full path can only be replaced for exact paths, and the prefix only for prefix and exact paths.
+func convertHTTPPathModifier(modifier *gateway_v1beta1.HTTPPathModifier, pathType gateway_v1beta1.PathMatchType) (string, error) {
+	switch modifier.Type {
+	case gateway_v1beta1.FullPathHTTPPathModifier:
+		if pathType != gateway_v1beta1.PathMatchExact || modifier.ReplaceFullPath == nil {
+			return "", fmt.Errorf("replacing the full path is only supported for Exact paths")
+		}
+		return *modifier.ReplaceFullPath, nil
+	case gateway_v1beta1.PrefixMatchHTTPPathModifier:
+		if pathType == gateway_v1beta1.PathMatchRegularExpression || modifier.ReplacePrefixMatch == nil {
+			return "", fmt.Errorf("replacing the prefix is only supported for PathPrefix and Exact paths")
+		}
+		return *modifier.ReplacePrefixMatch, nil
+	default:
+		return "", fmt.Errorf("type %s is not supported", modifier.Type)
+	}
+}
+
+// convertHTTPRequestRedirect converts the redirect filter into a VirtualServer redirect action.
+// The parts of the URL that the filter doesn't specify are taken from the request.
+func convertHTTPRequestRedirect(filter *gateway_v1beta1.HTTPRequestRedirectFilter) (*conf_v1.ActionRedirect, error) {
+	scheme := "${scheme}"
+	if filter.Scheme != nil {
+		scheme = *filter.Scheme
+	}
+
+	host := "${host}"
+	if filter.Hostname != nil {
+		host = string(*filter.Hostname)
+	}
+	if filter.Port != nil {
+		host = fmt.Sprintf("%s:%d", host, *filter.Port)
+	}
+
+	path := "${request_uri}"
+	if filter.Path != nil {
+		if filter.Path.Type != gateway_v1beta1.FullPathHTTPPathModifier || filter.Path.ReplaceFullPath == nil {
+			return nil, fmt.Errorf("only replacing the full path is supported in redirects")
+		}
+		path = *filter.Path.ReplaceFullPath
+	}
+
+	code := 302
+	if filter.StatusCode != nil {
+		code = *filter.StatusCode
+	}
+
+	return &conf_v1.ActionRedirect{
+		URL:  fmt.Sprintf("%s://%s%s", scheme, host, path),
+		Code: code,
+	}, nil
+}
+
+// normalizeSplitWeights scales the positive weights so that they add up to 100, keeping every weight within 1-99.
+func normalizeSplitWeights(weights []int) []int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	result := make([]int, len(weights))
+	sum := 0
+	largest := 0
+	for i, w := range weights {
+		result[i] = w * 100 / total
+		if result[i] < 1 {
+			result[i] = 1
+		}
+		sum += result[i]
+		if result[i] > result[largest] {
+			largest = i
+		}
+	}
+
+	// the rounding difference goes to the largest weight
+	result[largest] += 100 - sum
+
+	return result
+}
@@ -0,0 +1,880 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func createTestGateway(name string, listeners ...gateway_v1beta1.Listener) *gateway_v1beta1.Gateway {
+	return &gateway_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Spec: gateway_v1beta1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        listeners,
+		},
+	}
+}
+
+func createTestHTTPListener(name string, hostname string) gateway_v1beta1.Listener {
+	l := gateway_v1beta1.Listener{
+		Name:     gateway_v1beta1.SectionName(name),
+		Port:     80,
+		Protocol: gateway_v1beta1.HTTPProtocolType,
+	}
+	if hostname != "" {
+		h := gateway_v1beta1.Hostname(hostname)
+		l.Hostname = &h
+	}
+	return l
+}
+
+func createTestHTTPSListener(name string, hostname string, secretNamespace string, secretName string) gateway_v1beta1.Listener {
+	l := createTestHTTPListener(name, hostname)
+	l.Port = 443
+	l.Protocol = gateway_v1beta1.HTTPSProtocolType
+	ref := gateway_v1beta1.SecretObjectReference{
+		Name: gateway_v1beta1.ObjectName(secretName),
+	}
+	if secretNamespace != "" {
+		ns := gateway_v1beta1.Namespace(secretNamespace)
+		ref.Namespace = &ns
+	}
+	l.TLS = &gateway_v1beta1.GatewayTLSConfig{
+		CertificateRefs: []gateway_v1beta1.SecretObjectReference{ref},
+	}
+	return l
+}
+
+func createTestHTTPRoute(name string, gateway string, hostnames []string, rules ...gateway_v1beta1.HTTPRouteRule) *gateway_v1beta1.HTTPRoute {
+	route := &gateway_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: gateway_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gateway_v1beta1.CommonRouteSpec{
+				ParentRefs: []gateway_v1beta1.ParentReference{
+					{
+						Name: gateway_v1beta1.ObjectName(gateway),
+					},
+				},
+			},
+			Rules: rules,
+		},
+	}
+	for _, h := range hostnames {
+		route.Spec.Hostnames = append(route.Spec.Hostnames, gateway_v1beta1.Hostname(h))
+	}
+	return route
+}
+
+func createTestBackendRef(namespace string, name string, port int32, weight int32) gateway_v1beta1.HTTPBackendRef {
+	p := gateway_v1beta1.PortNumber(port)
+	ref := gateway_v1beta1.HTTPBackendRef{
+		BackendRef: gateway_v1beta1.BackendRef{
+			BackendObjectReference: gateway_v1beta1.BackendObjectReference{
+				Name: gateway_v1beta1.ObjectName(name),
+				Port: &p,
+			},
+			Weight: &weight,
+		},
+	}
+	if namespace != "" {
+		ns := gateway_v1beta1.Namespace(namespace)
+		ref.Namespace = &ns
+	}
+	return ref
+}
+
+func createTestPathMatch(pathType gateway_v1beta1.PathMatchType, value string) *gateway_v1beta1.HTTPPathMatch {
+	return &gateway_v1beta1.HTTPPathMatch{
+		Type:  &pathType,
+		Value: &value,
+	}
+}
+
+func createTestServiceReferenceGrant(namespace string, fromNamespace string) *gateway_v1beta1.ReferenceGrant {
+	return &gateway_v1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "grant",
+		},
+		Spec: gateway_v1beta1.ReferenceGrantSpec{
+			From: []gateway_v1beta1.ReferenceGrantFrom{
+				{
+					Group:     gatewayAPIGroup,
+					Kind:      httpRouteKind,
+					Namespace: gateway_v1beta1.Namespace(fromNamespace),
+				},
+			},
+			To: []gateway_v1beta1.ReferenceGrantTo{
+				{
+					Kind: serviceKind,
+				},
+			},
+		},
+	}
+}
+
+func TestConvertHTTPRouteRules(t *testing.T) {
+	t.Parallel()
+	method := gateway_v1beta1.HTTPMethodPost
+	route := createTestHTTPRoute("route", "gateway", []string{"cafe.example.com"},
+		gateway_v1beta1.HTTPRouteRule{
+			Matches: []gateway_v1beta1.HTTPRouteMatch{
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchPathPrefix, "/coffee"),
+				},
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchExact, "/tea"),
+				},
+			},
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "coffee-svc", 80, 1),
+			},
+		},
+		gateway_v1beta1.HTTPRouteRule{
+			Matches: []gateway_v1beta1.HTTPRouteMatch{
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchPathPrefix, "/coffee"),
+					Headers: []gateway_v1beta1.HTTPHeaderMatch{
+						{
+							Name:  "version",
+							Value: "v2",
+						},
+					},
+					Method: &method,
+				},
+			},
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "coffee-v2-svc", 80, 80),
+				createTestBackendRef("", "coffee-v3-svc", 80, 20),
+			},
+		},
+		gateway_v1beta1.HTTPRouteRule{
+			Matches: []gateway_v1beta1.HTTPRouteMatch{
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchPathPrefix, "/juice"),
+					QueryParams: []gateway_v1beta1.HTTPQueryParamMatch{
+						{
+							Name:  "size",
+							Value: "large",
+						},
+					},
+				},
+			},
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "juice-svc", 8080, 1),
+			},
+		},
+	)
+
+	expectedUpstreams := []conf_v1.Upstream{
+		{
+			Name:    "coffee-svc-80",
+			Service: "coffee-svc",
+			Port:    80,
+		},
+		{
+			Name:    "coffee-v2-svc-80",
+			Service: "coffee-v2-svc",
+			Port:    80,
+		},
+		{
+			Name:    "coffee-v3-svc-80",
+			Service: "coffee-v3-svc",
+			Port:    80,
+		},
+		{
+			Name:    "juice-svc-8080",
+			Service: "juice-svc",
+			Port:    8080,
+		},
+	}
+	expectedRoutes := []conf_v1.Route{
+		{
+			Path: "/coffee",
+			Matches: []conf_v1.Match{
+				{
+					Conditions: []conf_v1.Condition{
+						{
+							Header: "version",
+							Value:  "v2",
+						},
+						{
+							Variable: "$request_method",
+							Value:    "POST",
+						},
+					},
+					Splits: []conf_v1.Split{
+						{
+							Weight: 80,
+							Action: &conf_v1.Action{Pass: "coffee-v2-svc-80"},
+						},
+						{
+							Weight: 20,
+							Action: &conf_v1.Action{Pass: "coffee-v3-svc-80"},
+						},
+					},
+				},
+			},
+			Action: &conf_v1.Action{Pass: "coffee-svc-80"},
+		},
+		{
+			Path:   "=/tea",
+			Action: &conf_v1.Action{Pass: "coffee-svc-80"},
+		},
+		{
+			Path: "/juice",
+			Matches: []conf_v1.Match{
+				{
+					Conditions: []conf_v1.Condition{
+						{
+							Argument: "size",
+							Value:    "large",
+						},
+					},
+					Action: &conf_v1.Action{Pass: "juice-svc-8080"},
+				},
+			},
+			Action: &conf_v1.Action{
+				Return: &conf_v1.ActionReturn{
+					Code: 404,
+					Body: "Not Found",
+				},
+			},
+		},
+	}
+
+	result := convertHTTPRouteRules(route, nil)
+
+	if diff := cmp.Diff(expectedUpstreams, result.upstreams); diff != "" {
+		t.Errorf("convertHTTPRouteRules() returned unexpected upstreams (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedRoutes, result.routes); diff != "" {
+		t.Errorf("convertHTTPRouteRules() returned unexpected routes (-want +got):\n%s", diff)
+	}
+	if len(result.warnings) > 0 {
+		t.Errorf("convertHTTPRouteRules() returned unexpected warnings: %v", result.warnings)
+	}
+	if result.unresolvedRef != nil {
+		t.Errorf("convertHTTPRouteRules() returned unexpected unresolved reference: %v", result.unresolvedRef)
+	}
+}
+
+func TestConvertHTTPRouteRulesWithFilters(t *testing.T) {
+	t.Parallel()
+	hostname := gateway_v1beta1.PreciseHostname("tea.example.com")
+	prefix := "/"
+	code := 301
+	scheme := "https"
+	route := createTestHTTPRoute("route", "gateway", nil,
+		gateway_v1beta1.HTTPRouteRule{
+			Matches: []gateway_v1beta1.HTTPRouteMatch{
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchPathPrefix, "/tea"),
+				},
+			},
+			Filters: []gateway_v1beta1.HTTPRouteFilter{
+				{
+					Type: gateway_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gateway_v1beta1.HTTPHeaderFilter{
+						Add: []gateway_v1beta1.HTTPHeader{
+							{
+								Name:  "X-Tea",
+								Value: "green",
+							},
+						},
+						Remove: []string{"X-Coffee"},
+					},
+				},
+				{
+					Type: gateway_v1beta1.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gateway_v1beta1.HTTPURLRewriteFilter{
+						Hostname: &hostname,
+						Path: &gateway_v1beta1.HTTPPathModifier{
+							Type:               gateway_v1beta1.PrefixMatchHTTPPathModifier,
+							ReplacePrefixMatch: &prefix,
+						},
+					},
+				},
+				{
+					Type: gateway_v1beta1.HTTPRouteFilterRequestMirror,
+				},
+			},
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "tea-svc", 80, 1),
+			},
+		},
+		gateway_v1beta1.HTTPRouteRule{
+			Matches: []gateway_v1beta1.HTTPRouteMatch{
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchPathPrefix, "/coffee"),
+				},
+			},
+			Filters: []gateway_v1beta1.HTTPRouteFilter{
+				{
+					Type: gateway_v1beta1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gateway_v1beta1.HTTPRequestRedirectFilter{
+						Scheme:     &scheme,
+						StatusCode: &code,
+					},
+				},
+			},
+		},
+		gateway_v1beta1.HTTPRouteRule{
+			Matches: []gateway_v1beta1.HTTPRouteMatch{
+				{
+					Path: createTestPathMatch(gateway_v1beta1.PathMatchPathPrefix, "/juice"),
+				},
+			},
+		},
+	)
+
+	expectedRoutes := []conf_v1.Route{
+		{
+			Path: "/tea",
+			Action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream:    "tea-svc-80",
+					RewritePath: "/",
+					RequestHeaders: &conf_v1.ProxyRequestHeaders{
+						Set: []conf_v1.Header{
+							{
+								Name:  "X-Tea",
+								Value: "green",
+							},
+							{
+								Name:  "X-Coffee",
+								Value: "",
+							},
+							{
+								Name:  "Host",
+								Value: "tea.example.com",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Path: "/coffee",
+			Action: &conf_v1.Action{
+				Redirect: &conf_v1.ActionRedirect{
+					URL:  "https://${host}${request_uri}",
+					Code: 301,
+				},
+			},
+		},
+		{
+			Path: "/juice",
+			Action: &conf_v1.Action{
+				Return: &conf_v1.ActionReturn{
+					Code: 500,
+					Body: "Internal Server Error",
+				},
+			},
+		},
+	}
+	expectedWarnings := []string{
+		"spec.rules[0]: filters[2] of type RequestMirror is not supported and ignored",
+	}
+
+	result := convertHTTPRouteRules(route, nil)
+
+	if diff := cmp.Diff(expectedRoutes, result.routes); diff != "" {
+		t.Errorf("convertHTTPRouteRules() returned unexpected routes (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedWarnings, result.warnings); diff != "" {
+		t.Errorf("convertHTTPRouteRules() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestConvertHTTPRouteRulesCrossNamespaceBackends(t *testing.T) {
+	t.Parallel()
+	route := createTestHTTPRoute("route", "gateway", nil,
+		gateway_v1beta1.HTTPRouteRule{
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("backend", "coffee-svc", 80, 1),
+			},
+		},
+	)
+
+	tests := []struct {
+		referenceGrants       map[string]*gateway_v1beta1.ReferenceGrant
+		expectedUpstreams     []conf_v1.Upstream
+		expectedNamespaces    map[string]string
+		expectedUnresolvedRef bool
+		msg                   string
+	}{
+		{
+			referenceGrants: map[string]*gateway_v1beta1.ReferenceGrant{
+				"backend/grant": createTestServiceReferenceGrant("backend", "default"),
+			},
+			expectedUpstreams: []conf_v1.Upstream{
+				{
+					Name:    "backend-coffee-svc-80",
+					Service: "coffee-svc",
+					Port:    80,
+				},
+			},
+			expectedNamespaces: map[string]string{
+				"backend-coffee-svc-80": "backend",
+			},
+			expectedUnresolvedRef: false,
+			msg:                   "granted reference",
+		},
+		{
+			referenceGrants: map[string]*gateway_v1beta1.ReferenceGrant{
+				"other/grant": createTestServiceReferenceGrant("other", "default"),
+			},
+			expectedUpstreams:     nil,
+			expectedNamespaces:    map[string]string{},
+			expectedUnresolvedRef: true,
+			msg:                   "reference without grant",
+		},
+	}
+
+	for _, test := range tests {
+		result := convertHTTPRouteRules(route, test.referenceGrants)
+
+		if diff := cmp.Diff(test.expectedUpstreams, result.upstreams); diff != "" {
+			t.Errorf("convertHTTPRouteRules() returned unexpected upstreams for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff(test.expectedNamespaces, result.serviceNamespaces); diff != "" {
+			t.Errorf("convertHTTPRouteRules() returned unexpected service namespaces for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if unresolved := result.unresolvedRef != nil; unresolved != test.expectedUnresolvedRef {
+			t.Errorf("convertHTTPRouteRules() returned unresolved reference %v but expected %v for the case of %s", unresolved, test.expectedUnresolvedRef, test.msg)
+		}
+	}
+}
+
+func TestConvertHTTPRouteRulesWithInvalidBackend(t *testing.T) {
+	t.Parallel()
+	route := createTestHTTPRoute("route", "gateway", nil,
+		gateway_v1beta1.HTTPRouteRule{
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "coffee-svc", 80, 80),
+				createTestBackendRef("backend", "tea-svc", 80, 20),
+			},
+		},
+	)
+
+	expectedRoutes := []conf_v1.Route{
+		{
+			Path: "/",
+			Splits: []conf_v1.Split{
+				{
+					Weight: 80,
+					Action: &conf_v1.Action{
+						Pass: "coffee-svc-80",
+					},
+				},
+				{
+					Weight: 20,
+					Action: &conf_v1.Action{
+						Return: &conf_v1.ActionReturn{
+							Code: 500,
+							Body: "Internal Server Error",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result := convertHTTPRouteRules(route, nil)
+
+	if diff := cmp.Diff(expectedRoutes, result.routes); diff != "" {
+		t.Errorf("convertHTTPRouteRules() returned unexpected routes (-want +got):\n%s", diff)
+	}
+	if result.unresolvedRef == nil {
+		t.Errorf("convertHTTPRouteRules() returned no unresolved reference for the backend without a ReferenceGrant")
+	}
+}
+
+func TestGetHTTPRouteHostsForListener(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		routeHosts    []string
+		listenerHost  string
+		expected      []string
+		expectedError bool
+		msg           string
+	}{
+		{
+			routeHosts:   []string{"cafe.example.com"},
+			listenerHost: "",
+			expected:     []string{"cafe.example.com"},
+			msg:          "listener without hostname",
+		},
+		{
+			routeHosts:   nil,
+			listenerHost: "cafe.example.com",
+			expected:     []string{"cafe.example.com"},
+			msg:          "route without hostnames",
+		},
+		{
+			routeHosts:   []string{"cafe.example.com", "tea.example.com", "coffee.example.org"},
+			listenerHost: "*.example.com",
+			expected:     []string{"cafe.example.com", "tea.example.com"},
+			msg:          "wildcard listener hostname",
+		},
+		{
+			routeHosts:   []string{"*.example.com"},
+			listenerHost: "cafe.example.com",
+			expected:     []string{"cafe.example.com"},
+			msg:          "wildcard route hostname",
+		},
+		{
+			routeHosts:    []string{"cafe.example.org"},
+			listenerHost:  "cafe.example.com",
+			expected:      nil,
+			expectedError: true,
+			msg:           "no matching hostnames",
+		},
+		{
+			routeHosts:    nil,
+			listenerHost:  "",
+			expected:      nil,
+			expectedError: true,
+			msg:           "no hostnames",
+		},
+	}
+
+	for _, test := range tests {
+		route := createTestHTTPRoute("route", "gateway", test.routeHosts)
+		listener := createTestHTTPListener("http", test.listenerHost)
+
+		result, err := getHTTPRouteHostsForListener(route, listener)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("getHTTPRouteHostsForListener() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if (err != nil) != test.expectedError {
+			t.Errorf("getHTTPRouteHostsForListener() returned error %v for the case of %s", err, test.msg)
+		}
+	}
+}
+
+func TestNormalizeSplitWeights(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		weights  []int
+		expected []int
+	}{
+		{
+			weights:  []int{1, 1},
+			expected: []int{50, 50},
+		},
+		{
+			weights:  []int{1, 1, 1},
+			expected: []int{34, 33, 33},
+		},
+		{
+			weights:  []int{1, 1000},
+			expected: []int{1, 99},
+		},
+		{
+			weights:  []int{30, 70},
+			expected: []int{30, 70},
+		},
+	}
+
+	for _, test := range tests {
+		result := normalizeSplitWeights(test.weights)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("normalizeSplitWeights(%v) returned unexpected result (-want +got):\n%s", test.weights, diff)
+		}
+	}
+}
+
+func TestNewHTTPRouteConfiguration(t *testing.T) {
+	t.Parallel()
+	gateway := createTestGateway("gateway",
+		createTestHTTPListener("http", "*.example.com"),
+		createTestHTTPSListener("https", "cafe.example.com", "", "cafe-secret"),
+	)
+	gateways := map[string]*gateway_v1beta1.Gateway{
+		"default/gateway": gateway,
+	}
+	route := createTestHTTPRoute("route", "gateway", []string{"cafe.example.com", "tea.example.com"},
+		gateway_v1beta1.HTTPRouteRule{
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "cafe-svc", 80, 1),
+			},
+		},
+	)
+	validVirtualServer := func(*conf_v1.VirtualServer) error { return nil }
+
+	hrc := newHTTPRouteConfiguration(route, gateways, nil, validVirtualServer)
+
+	expectedServers := map[string]*HTTPRouteServer{
+		"cafe.example.com": {
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "httproute_route_0",
+					CreationTimestamp: route.CreationTimestamp,
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host: "cafe.example.com",
+					TLS: &conf_v1.TLS{
+						Secret: "cafe-secret",
+					},
+					Upstreams: []conf_v1.Upstream{
+						{
+							Name:    "cafe-svc-80",
+							Service: "cafe-svc",
+							Port:    80,
+						},
+					},
+					Routes: []conf_v1.Route{
+						{
+							Path:   "/",
+							Action: &conf_v1.Action{Pass: "cafe-svc-80"},
+						},
+					},
+				},
+			},
+			TLSSecret: "default/cafe-secret",
+			ServiceNamespaces: map[string]string{
+				"cafe-svc-80": "default",
+			},
+		},
+		"tea.example.com": {
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "httproute_route_1",
+					CreationTimestamp: route.CreationTimestamp,
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host: "tea.example.com",
+					Upstreams: []conf_v1.Upstream{
+						{
+							Name:    "cafe-svc-80",
+							Service: "cafe-svc",
+							Port:    80,
+						},
+					},
+					Routes: []conf_v1.Route{
+						{
+							Path:   "/",
+							Action: &conf_v1.Action{Pass: "cafe-svc-80"},
+						},
+					},
+				},
+			},
+			ServiceNamespaces: map[string]string{
+				"cafe-svc-80": "default",
+			},
+		},
+	}
+	expectedParents := []gateway_v1beta1.RouteParentStatus{
+		{
+			ParentRef:      route.Spec.ParentRefs[0],
+			ControllerName: gateway_v1beta1.GatewayController(GatewayControllerName),
+			Conditions: []metav1.Condition{
+				{
+					Type:    string(gateway_v1beta1.RouteConditionAccepted),
+					Status:  metav1.ConditionTrue,
+					Reason:  string(gateway_v1beta1.RouteReasonAccepted),
+					Message: "The route is accepted",
+				},
+				{
+					Type:    string(gateway_v1beta1.RouteConditionResolvedRefs),
+					Status:  metav1.ConditionTrue,
+					Reason:  string(gateway_v1beta1.RouteReasonResolvedRefs),
+					Message: "All references are resolved",
+				},
+			},
+		},
+	}
+
+	if hrc == nil {
+		t.Fatal("newHTTPRouteConfiguration() returned nil")
+	}
+	if diff := cmp.Diff(expectedServers, hrc.Servers); diff != "" {
+		t.Errorf("newHTTPRouteConfiguration() returned unexpected servers (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedParents, hrc.Parents); diff != "" {
+		t.Errorf("newHTTPRouteConfiguration() returned unexpected parents (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewHTTPRouteConfigurationRejected(t *testing.T) {
+	t.Parallel()
+	otherNamespace := gateway_v1beta1.Namespace("other")
+	gateways := map[string]*gateway_v1beta1.Gateway{
+		"default/gateway": createTestGateway("gateway", createTestHTTPListener("http", "cafe.example.com")),
+		"default/gateway-with-cross-namespace-secret": createTestGateway("gateway-with-cross-namespace-secret",
+			createTestHTTPSListener("https", "", "other", "cafe-secret")),
+	}
+	validVirtualServer := func(*conf_v1.VirtualServer) error { return nil }
+
+	tests := []struct {
+		route                 *gateway_v1beta1.HTTPRoute
+		validateVirtualServer func(*conf_v1.VirtualServer) error
+		expectedReason        gateway_v1beta1.RouteConditionReason
+		msg                   string
+	}{
+		{
+			route:                 createTestHTTPRoute("route", "gateway", []string{"tea.example.com"}),
+			validateVirtualServer: validVirtualServer,
+			expectedReason:        gateway_v1beta1.RouteReasonNoMatchingListenerHostname,
+			msg:                   "no matching hostnames",
+		},
+		{
+			route:                 createTestHTTPRoute("route", "gateway-with-cross-namespace-secret", []string{"cafe.example.com"}),
+			validateVirtualServer: validVirtualServer,
+			expectedReason:        gateway_v1beta1.RouteReasonNotAllowedByListeners,
+			msg:                   "listener with a secret without a grant",
+		},
+		{
+			route: func() *gateway_v1beta1.HTTPRoute {
+				r := createTestHTTPRoute("route", "gateway", []string{"cafe.example.com"})
+				r.Namespace = string(otherNamespace)
+				r.Spec.ParentRefs[0].Namespace = &otherNamespace
+				return r
+			}(),
+			validateVirtualServer: validVirtualServer,
+			expectedReason:        "",
+			msg:                   "route referencing an unknown gateway",
+		},
+		{
+			route: createTestHTTPRoute("route", "gateway", []string{"cafe.example.com"}),
+			validateVirtualServer: func(*conf_v1.VirtualServer) error {
+				return errors.New("invalid")
+			},
+			expectedReason: gateway_v1beta1.RouteReasonUnsupportedValue,
+			msg:            "invalid generated VirtualServer",
+		},
+		{
+			route: createTestHTTPRoute("route", "gateway", []string{"cafe.example.com"},
+				gateway_v1beta1.HTTPRouteRule{
+					Matches: []gateway_v1beta1.HTTPRouteMatch{
+						{
+							Headers: []gateway_v1beta1.HTTPHeaderMatch{
+								{
+									Name:  "x-env",
+									Value: "!prod",
+								},
+							},
+						},
+					},
+				},
+			),
+			validateVirtualServer: validVirtualServer,
+			expectedReason:        gateway_v1beta1.RouteReasonUnsupportedValue,
+			msg:                   "header match value starting with '!'",
+		},
+		{
+			route: createTestHTTPRoute("route", "gateway", []string{"cafe.example.com"},
+				gateway_v1beta1.HTTPRouteRule{
+					Matches: []gateway_v1beta1.HTTPRouteMatch{
+						{
+							QueryParams: []gateway_v1beta1.HTTPQueryParamMatch{
+								{
+									Name:  "env",
+									Value: "!prod",
+								},
+							},
+						},
+					},
+				},
+			),
+			validateVirtualServer: validVirtualServer,
+			expectedReason:        gateway_v1beta1.RouteReasonUnsupportedValue,
+			msg:                   "query parameter match value starting with '!'",
+		},
+	}
+
+	for _, test := range tests {
+		hrc := newHTTPRouteConfiguration(test.route, gateways, nil, test.validateVirtualServer)
+
+		if test.expectedReason == "" {
+			if hrc != nil {
+				t.Errorf("newHTTPRouteConfiguration() returned %v but expected nil for the case of %s", hrc, test.msg)
+			}
+			continue
+		}
+
+		if hrc == nil {
+			t.Errorf("newHTTPRouteConfiguration() returned nil for the case of %s", test.msg)
+			continue
+		}
+		if len(hrc.Servers) > 0 {
+			t.Errorf("newHTTPRouteConfiguration() returned servers %v but expected none for the case of %s", hrc.Servers, test.msg)
+		}
+
+		accepted := hrc.Parents[0].Conditions[0]
+		if accepted.Status != metav1.ConditionFalse || accepted.Reason != string(test.expectedReason) {
+			t.Errorf("newHTTPRouteConfiguration() returned condition %v but expected reason %s for the case of %s", accepted, test.expectedReason, test.msg)
+		}
+	}
+}
+
+func TestAddOrUpdateHTTPRouteWithHostCollisions(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	vs := createTestVirtualServer("virtualserver", "cafe.example.com")
+	configuration.AddOrUpdateVirtualServer(vs)
+
+	gateway := createTestGateway("gateway", createTestHTTPListener("http", ""))
+	configuration.AddOrUpdateGateway(gateway)
+
+	route := createTestHTTPRoute("route", "gateway", []string{"cafe.example.com", "tea.example.com"},
+		gateway_v1beta1.HTTPRouteRule{
+			BackendRefs: []gateway_v1beta1.HTTPBackendRef{
+				createTestBackendRef("", "cafe-svc", 80, 1),
+			},
+		},
+	)
+
+	changes, problems := configuration.AddOrUpdateHTTPRoute(route)
+
+	if len(changes) != 1 || changes[0].Op != AddOrUpdate {
+		t.Fatalf("AddOrUpdateHTTPRoute() returned unexpected changes %v", changes)
+	}
+	hrc, ok := changes[0].Resource.(*HTTPRouteConfiguration)
+	if !ok {
+		t.Fatalf("AddOrUpdateHTTPRoute() returned a change for unexpected resource %v", changes[0].Resource)
+	}
+
+	expectedValidHosts := map[string]bool{
+		"cafe.example.com": false,
+		"tea.example.com":  true,
+	}
+	if diff := cmp.Diff(expectedValidHosts, hrc.ValidHosts); diff != "" {
+		t.Errorf("AddOrUpdateHTTPRoute() returned unexpected valid hosts (-want +got):\n%s", diff)
+	}
+	if len(problems) != 0 {
+		t.Errorf("AddOrUpdateHTTPRoute() returned unexpected problems %v", problems)
+	}
+	if configuration.hosts["cafe.example.com"] == Resource(hrc) {
+		t.Errorf("AddOrUpdateHTTPRoute() took the host of the older VirtualServer")
+	}
+
+	changes, problems = configuration.DeleteGateway("default/gateway")
+
+	if len(changes) != 1 || changes[0].Op != Delete {
+		t.Errorf("DeleteGateway() returned unexpected changes %v", changes)
+	}
+	if len(problems) != 0 {
+		t.Errorf("DeleteGateway() returned unexpected problems %v", problems)
+	}
+	if _, exists := configuration.hosts["tea.example.com"]; exists {
+		t.Errorf("DeleteGateway() didn't release the host of the HTTPRoute")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // createConfigMapHandlers builds the handler funcs for config maps
@@ -713,3 +714,143 @@ func createNamespaceHandlers(lbc *LoadBalancerController) cache.ResourceEventHan
 		},
 	}
 }
+
+func createGatewayClassHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			gc := obj.(*gateway_v1beta1.GatewayClass)
+			glog.V(3).Infof("Adding GatewayClass: %v", gc.Name)
+			lbc.AddSyncQueue(gc)
+		},
+		DeleteFunc: func(obj interface{}) {
+			gc, isGatewayClass := obj.(*gateway_v1beta1.GatewayClass)
+			if !isGatewayClass {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					return
+				}
+				gc, ok = deletedState.Obj.(*gateway_v1beta1.GatewayClass)
+				if !ok {
+					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-GatewayClass object: %v", deletedState.Obj)
+					return
+				}
+			}
+			glog.V(3).Infof("Removing GatewayClass: %v", gc.Name)
+			lbc.AddSyncQueue(gc)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			curGc := cur.(*gateway_v1beta1.GatewayClass)
+			oldGc := old.(*gateway_v1beta1.GatewayClass)
+			if !reflect.DeepEqual(oldGc.Spec, curGc.Spec) {
+				glog.V(3).Infof("GatewayClass %v changed, syncing", curGc.Name)
+				lbc.AddSyncQueue(curGc)
+			}
+		},
+	}
+}
+
+func createGatewayHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			gw := obj.(*gateway_v1beta1.Gateway)
+			glog.V(3).Infof("Adding Gateway: %v", gw.Name)
+			lbc.AddSyncQueue(gw)
+		},
+		DeleteFunc: func(obj interface{}) {
+			gw, isGateway := obj.(*gateway_v1beta1.Gateway)
+			if !isGateway {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					return
+				}
+				gw, ok = deletedState.Obj.(*gateway_v1beta1.Gateway)
+				if !ok {
+					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-Gateway object: %v", deletedState.Obj)
+					return
+				}
+			}
+			glog.V(3).Infof("Removing Gateway: %v", gw.Name)
+			lbc.AddSyncQueue(gw)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			curGw := cur.(*gateway_v1beta1.Gateway)
+			oldGw := old.(*gateway_v1beta1.Gateway)
+			if !reflect.DeepEqual(oldGw.Spec, curGw.Spec) {
+				glog.V(3).Infof("Gateway %v changed, syncing", curGw.Name)
+				lbc.AddSyncQueue(curGw)
+			}
+		},
+	}
+}
+
+func createHTTPRouteHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			route := obj.(*gateway_v1beta1.HTTPRoute)
+			glog.V(3).Infof("Adding HTTPRoute: %v", route.Name)
+			lbc.AddSyncQueue(route)
+		},
+		DeleteFunc: func(obj interface{}) {
+			route, isHTTPRoute := obj.(*gateway_v1beta1.HTTPRoute)
+			if !isHTTPRoute {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					return
+				}
+				route, ok = deletedState.Obj.(*gateway_v1beta1.HTTPRoute)
+				if !ok {
+					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-HTTPRoute object: %v", deletedState.Obj)
+					return
+				}
+			}
+			glog.V(3).Infof("Removing HTTPRoute: %v", route.Name)
+			lbc.AddSyncQueue(route)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			curRoute := cur.(*gateway_v1beta1.HTTPRoute)
+			oldRoute := old.(*gateway_v1beta1.HTTPRoute)
+			if !reflect.DeepEqual(oldRoute.Spec, curRoute.Spec) {
+				glog.V(3).Infof("HTTPRoute %v changed, syncing", curRoute.Name)
+				lbc.AddSyncQueue(curRoute)
+			}
+		},
+	}
+}
+
+func createReferenceGrantHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			grant := obj.(*gateway_v1beta1.ReferenceGrant)
+			glog.V(3).Infof("Adding ReferenceGrant: %v", grant.Name)
+			lbc.AddSyncQueue(grant)
+		},
+		DeleteFunc: func(obj interface{}) {
+			grant, isReferenceGrant := obj.(*gateway_v1beta1.ReferenceGrant)
+			if !isReferenceGrant {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					return
+				}
+				grant, ok = deletedState.Obj.(*gateway_v1beta1.ReferenceGrant)
+				if !ok {
+					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-ReferenceGrant object: %v", deletedState.Obj)
+					return
+				}
+			}
+			glog.V(3).Infof("Removing ReferenceGrant: %v", grant.Name)
+			lbc.AddSyncQueue(grant)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			curGrant := cur.(*gateway_v1beta1.ReferenceGrant)
+			oldGrant := old.(*gateway_v1beta1.ReferenceGrant)
+			if !reflect.DeepEqual(oldGrant.Spec, curGrant.Spec) {
+				glog.V(3).Infof("ReferenceGrant %v changed, syncing", curGrant.Name)
+				lbc.AddSyncQueue(curGrant)
+			}
+		},
+	}
+}
@@ -15,6 +15,7 @@ type resourceReferenceChecker interface {
 	IsReferencedByVirtualServer(namespace string, name string, vs *v1.VirtualServer) bool
 	IsReferencedByVirtualServerRoute(namespace string, name string, vsr *v1.VirtualServerRoute) bool
 	IsReferencedByTransportServer(namespace string, name string, ts *conf_v1alpha1.TransportServer) bool
+	IsReferencedByHTTPRoute(namespace string, name string, hrc *HTTPRouteConfiguration) bool
 }
 
 type secretReferenceChecker struct {
@@ -91,7 +92,25 @@ func (rc *secretReferenceChecker) IsReferencedByVirtualServerRoute(_ string, _ s
 	return false
 }
 
-func (rc *secretReferenceChecker) IsReferencedByTransportServer(_ string, _ string, _ *conf_v1alpha1.TransportServer) bool {
+func (rc *secretReferenceChecker) IsReferencedByTransportServer(secretNamespace string, secretName string, ts *conf_v1alpha1.TransportServer) bool {
+	if ts.Namespace != secretNamespace {
+		return false
+	}
+
+	if ts.Spec.TLS != nil && ts.Spec.TLS.Secret == secretName {
+		return true
+	}
+
+	return false
+}
+
+func (rc *secretReferenceChecker) IsReferencedByHTTPRoute(secretNamespace string, secretName string, hrc *HTTPRouteConfiguration) bool {
+	for _, server := range hrc.Servers {
+		if server.TLSSecret == secretNamespace+"/"+secretName {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -179,6 +198,18 @@ func (rc *serviceReferenceChecker) IsReferencedByTransportServer(svcNamespace st
 	return false
 }
 
+func (rc *serviceReferenceChecker) IsReferencedByHTTPRoute(svcNamespace string, svcName string, hrc *HTTPRouteConfiguration) bool {
+	for _, server := range hrc.Servers {
+		for _, u := range server.VirtualServer.Spec.Upstreams {
+			if u.Service == svcName && server.ServiceNamespaces[u.Name] == svcNamespace {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 type policyReferenceChecker struct{}
 
 func newPolicyReferenceChecker() *policyReferenceChecker {
@@ -217,7 +248,11 @@ func (rc *policyReferenceChecker) IsReferencedByVirtualServerRoute(policyNamespa
 	return false
 }
 
-func (rc *policyReferenceChecker) IsReferencedByTransportServer(_ string, _ string, _ *conf_v1alpha1.TransportServer) bool {
+func (rc *policyReferenceChecker) IsReferencedByTransportServer(policyNamespace string, policyName string, ts *conf_v1alpha1.TransportServer) bool {
+	return isPolicyReferenced(getTransportServerPolicyReferences(ts), ts.Namespace, policyNamespace, policyName)
+}
+
+func (rc *policyReferenceChecker) IsReferencedByHTTPRoute(_ string, _ string, _ *HTTPRouteConfiguration) bool {
 	return false
 }
 
@@ -259,6 +294,10 @@ func (rc *appProtectResourceReferenceChecker) IsReferencedByTransportServer(_ st
 	return false
 }
 
+func (rc *appProtectResourceReferenceChecker) IsReferencedByHTTPRoute(_ string, _ string, _ *HTTPRouteConfiguration) bool {
+	return false
+}
+
 func isPolicyReferenced(policies []v1.PolicyReference, resourceNamespace string, policyNamespace string, policyName string) bool {
 	for _, p := range policies {
 		namespace := p.Namespace
@@ -318,3 +357,7 @@ func (rc *dosResourceReferenceChecker) IsReferencedByVirtualServerRoute(namespac
 func (rc *dosResourceReferenceChecker) IsReferencedByTransportServer(_ string, _ string, _ *conf_v1alpha1.TransportServer) bool {
 	return false
 }
+
+func (rc *dosResourceReferenceChecker) IsReferencedByHTTPRoute(_ string, _ string, _ *HTTPRouteConfiguration) bool {
+	return false
+}
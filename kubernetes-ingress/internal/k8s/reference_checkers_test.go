@@ -314,13 +314,82 @@ func TestSecretIsReferencedByVirtualServerRoute(t *testing.T) {
 
 func TestSecretIsReferencedByTransportServer(t *testing.T) {
 	t.Parallel()
-	isPlus := false // doesn't matter for TransportServer
-	rc := newSecretReferenceChecker(isPlus)
+	tests := []struct {
+		ts              *conf_v1alpha1.TransportServer
+		secretNamespace string
+		secretName      string
+		expected        bool
+		msg             string
+	}{
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					TLS: &conf_v1alpha1.TLS{
+						Secret: "test-secret",
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        true,
+			msg:             "tls secret is referenced",
+		},
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					TLS: &conf_v1alpha1.TLS{
+						Secret: "test-secret",
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "some-secret",
+			expected:        false,
+			msg:             "wrong name for tls secret",
+		},
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					TLS: &conf_v1alpha1.TLS{
+						Secret: "test-secret",
+					},
+				},
+			},
+			secretNamespace: "some-namespace",
+			secretName:      "test-secret",
+			expected:        false,
+			msg:             "wrong namespace for tls secret",
+		},
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        false,
+			msg:             "no tls",
+		},
+	}
 
-	// always returns false
-	result := rc.IsReferencedByTransportServer("", "", nil)
-	if result != false {
-		t.Error("IsReferencedByTransportServer() returned true but expected false")
+	for _, test := range tests {
+		isPlus := false // doesn't matter for TransportServer
+		rc := newSecretReferenceChecker(isPlus)
+
+		result := rc.IsReferencedByTransportServer(test.secretNamespace, test.secretName, test.ts)
+		if result != test.expected {
+			t.Errorf("IsReferencedByTransportServer() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
 }
 
@@ -651,7 +720,7 @@ func TestIsServiceReferencedByTransportServer(t *testing.T) {
 	}
 }
 
-func TestPolicyIsReferencedByIngressesAndTransportServers(t *testing.T) {
+func TestPolicyIsReferencedByIngresses(t *testing.T) {
 	t.Parallel()
 	rc := newPolicyReferenceChecker()
 
@@ -664,10 +733,92 @@ func TestPolicyIsReferencedByIngressesAndTransportServers(t *testing.T) {
 	if result {
 		t.Error("IsReferencedByMinion() returned true but expected false")
 	}
+}
+
+func TestPolicyIsReferencedByTransportServer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ts              *conf_v1alpha1.TransportServer
+		policyNamespace string
+		policyName      string
+		expected        bool
+		msg             string
+	}{
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					Policies: []conf_v1alpha1.PolicyReference{
+						{
+							Name: "test-policy",
+						},
+					},
+				},
+			},
+			policyNamespace: "default",
+			policyName:      "test-policy",
+			expected:        true,
+			msg:             "policy is referenced",
+		},
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					Policies: []conf_v1alpha1.PolicyReference{
+						{
+							Name:      "test-policy",
+							Namespace: "nginx-ingress",
+						},
+					},
+				},
+			},
+			policyNamespace: "nginx-ingress",
+			policyName:      "test-policy",
+			expected:        true,
+			msg:             "policy is referenced in another namespace",
+		},
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1alpha1.TransportServerSpec{
+					Policies: []conf_v1alpha1.PolicyReference{
+						{
+							Name: "test-policy",
+						},
+					},
+				},
+			},
+			policyNamespace: "nginx-ingress",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "wrong namespace",
+		},
+		{
+			ts: &conf_v1alpha1.TransportServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+			},
+			policyNamespace: "default",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "no policies",
+		},
+	}
 
-	result = rc.IsReferencedByTransportServer("", "", nil)
-	if result {
-		t.Error("IsReferencedByTransportServer() returned true but expected false")
+	for _, test := range tests {
+		rc := newPolicyReferenceChecker()
+
+		result := rc.IsReferencedByTransportServer(test.policyNamespace, test.policyName, test.ts)
+		if result != test.expected {
+			t.Errorf("IsReferencedByTransportServer() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
 }
 
@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/k8s/appprotectdos"
+	"github.com/nginxinc/kubernetes-ingress/internal/k8s/secrets"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RenderInput holds the resources and the parameters for rendering the NGINX configuration offline.
+type RenderInput struct {
+	NginxConfigurator            *configs.Configurator
+	ConfigMap                    *api_v1.ConfigMap
+	Objects                      []runtime.Object
+	IngressClass                 string
+	IsNginxPlus                  bool
+	EnableOIDC                   bool
+	IsTLSPassthroughEnabled      bool
+	SnippetsEnabled              bool
+	VirtualServerValidator       *validation.VirtualServerValidator
+	GlobalConfigurationValidator *validation.GlobalConfigurationValidator
+	TransportServerValidator     *validation.TransportServerValidator
+}
+
+// Render generates the NGINX configuration for the objects of the input without a cluster.
+// The Ingress, VirtualServer, VirtualServerRoute, TransportServer and GlobalConfiguration resources
+// are processed the same way as by the LoadBalancerController, while the Services, EndpointSlices,
+// Pods, Secrets and Policies are looked up in local stores instead of informers.
+// It returns the problems of the resources and the warnings of the generated configuration.
+func Render(input RenderInput) ([]ConfigurationProblem, configs.Warnings, error) {
+	lbc := &LoadBalancerController{
+		configurator:              input.NginxConfigurator,
+		isNginxPlus:               input.IsNginxPlus,
+		ingressClass:              input.IngressClass,
+		enableOIDC:                input.EnableOIDC,
+		areCustomResourcesEnabled: true,
+		dosConfiguration:          appprotectdos.NewConfiguration(false),
+		secretStore:               secrets.NewLocalSecretStore(input.NginxConfigurator),
+	}
+
+	nsi := &namespacedInformer{
+		svcLister:                 cache.NewStore(cache.MetaNamespaceKeyFunc),
+		endpointSliceLister:       storeToEndpointSliceLister{Store: cache.NewStore(cache.MetaNamespaceKeyFunc)},
+		podLister:                 indexerToPodLister{Indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})},
+		secretLister:              cache.NewStore(cache.MetaNamespaceKeyFunc),
+		policyLister:              cache.NewStore(cache.MetaNamespaceKeyFunc),
+		isSecretsEnabledNamespace: true,
+		areCustomResourcesEnabled: true,
+	}
+	lbc.namespacedInformers = map[string]*namespacedInformer{"": nsi}
+
+	lbc.configuration = NewConfiguration(
+		lbc.HasCorrectIngressClass,
+		input.IsNginxPlus,
+		false,
+		false,
+		false,
+		input.VirtualServerValidator,
+		input.GlobalConfigurationValidator,
+		input.TransportServerValidator,
+		input.IsTLSPassthroughEnabled,
+		input.SnippetsEnabled,
+		false,
+		false,
+	)
+
+	// The objects that the resources reference are added to the stores first,
+	// so that they are found when the resources are processed.
+	var resources []runtime.Object
+	for _, obj := range input.Objects {
+		var err error
+		switch o := obj.(type) {
+		case *api_v1.Service:
+			err = nsi.svcLister.Add(o)
+		case *discovery_v1.EndpointSlice:
+			err = nsi.endpointSliceLister.Add(o)
+		case *api_v1.Pod:
+			err = nsi.podLister.Add(o)
+		case *api_v1.Secret:
+			err = nsi.secretLister.Add(o)
+			if err == nil && secrets.IsSupportedSecretType(o.Type) {
+				lbc.secretStore.AddOrUpdateSecret(o)
+			}
+		case *conf_v1.Policy:
+			err = nsi.policyLister.Add(o)
+		case *networking.Ingress, *conf_v1.VirtualServer, *conf_v1.VirtualServerRoute,
+			*conf_v1alpha1.TransportServer, *conf_v1alpha1.GlobalConfiguration:
+			resources = append(resources, o)
+		default:
+			glog.V(3).Infof("Skipping unsupported object %T", obj)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// The resources are processed in the order that doesn't report problems that are resolved by the resources
+	// processed later, like a minion Ingress without its master or a TransportServer without its listener.
+	sort.SliceStable(resources, func(i, j int) bool {
+		return getRenderRank(resources[i]) < getRenderRank(resources[j])
+	})
+
+	var allProblems []ConfigurationProblem
+	for _, obj := range resources {
+		var problems []ConfigurationProblem
+		switch o := obj.(type) {
+		case *networking.Ingress:
+			_, problems = lbc.configuration.AddOrUpdateIngress(o)
+		case *conf_v1.VirtualServer:
+			_, problems = lbc.configuration.AddOrUpdateVirtualServer(o)
+		case *conf_v1.VirtualServerRoute:
+			_, problems = lbc.configuration.AddOrUpdateVirtualServerRoute(o)
+		case *conf_v1alpha1.TransportServer:
+			_, problems = lbc.configuration.AddOrUpdateTransportServer(o)
+		case *conf_v1alpha1.GlobalConfiguration:
+			var err error
+			_, problems, err = lbc.configuration.AddOrUpdateGlobalConfiguration(o)
+			if err != nil {
+				return nil, nil, fmt.Errorf("GlobalConfiguration %s is invalid: %w", getResourceKey(&o.ObjectMeta), err)
+			}
+		}
+		allProblems = append(allProblems, problems...)
+	}
+
+	cfgParams := configs.NewDefaultConfigParams(input.IsNginxPlus)
+	if input.ConfigMap != nil {
+		cfgParams = configs.ParseConfigMap(input.ConfigMap, input.IsNginxPlus, false, false, input.IsTLSPassthroughEnabled)
+	}
+
+	configResources := lbc.configuration.GetResources()
+
+	glog.V(3).Infof("Rendering %v resources", len(configResources))
+
+	warnings, err := input.NginxConfigurator.UpdateConfig(cfgParams, lbc.createExtendedResources(configResources))
+	if err != nil {
+		return allProblems, warnings, fmt.Errorf("error rendering configuration: %w", err)
+	}
+
+	for _, r := range configResources {
+		switch impl := r.(type) {
+		case *IngressConfiguration:
+			for _, w := range impl.Warnings {
+				warnings.AddWarning(impl.Ingress, w)
+			}
+		case *VirtualServerConfiguration:
+			for _, w := range impl.Warnings {
+				warnings.AddWarning(impl.VirtualServer, w)
+			}
+		case *TransportServerConfiguration:
+			for _, w := range impl.Warnings {
+				warnings.AddWarning(impl.TransportServer, w)
+			}
+		}
+	}
+
+	return allProblems, warnings, nil
+}
+
+// getRenderRank returns the position of the kind of the resource in the order of processing by Render.
+func getRenderRank(obj runtime.Object) int {
+	switch o := obj.(type) {
+	case *conf_v1alpha1.GlobalConfiguration:
+		return 0
+	case *networking.Ingress:
+		if isMinion(o) {
+			return 2
+		}
+		return 1
+	case *conf_v1.VirtualServer:
+		return 3
+	case *conf_v1.VirtualServerRoute:
+		return 4
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func createTestRenderInput(t *testing.T, confPath string, objects []runtime.Object) RenderInput {
+	t.Helper()
+
+	templateExecutor, err := version1.NewTemplateExecutor("../configs/version1/nginx.tmpl", "../configs/version1/nginx.ingress.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	templateExecutorV2, err := version2.NewTemplateExecutor("../configs/version2/nginx.virtualserver.tmpl", "../configs/version2/nginx.transportserver.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager, err := nginx.NewFileManager(confPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	staticCfgParams := &configs.StaticConfigParams{
+		NginxStatus:           true,
+		NginxStatusAllowCIDRs: []string{"127.0.0.1"},
+		NginxStatusPort:       8080,
+	}
+	cnf := configs.NewConfigurator(manager, staticCfgParams, configs.NewDefaultConfigParams(false), templateExecutor, templateExecutorV2, false, false, nil, false, nil, false)
+
+	return RenderInput{
+		NginxConfigurator:            cnf,
+		Objects:                      objects,
+		IngressClass:                 "nginx",
+		VirtualServerValidator:       validation.NewVirtualServerValidator(),
+		GlobalConfigurationValidator: validation.NewGlobalConfigurationValidator(map[int]bool{80: true, 443: true}),
+		TransportServerValidator:     validation.NewTransportServerValidator(false, false, false),
+	}
+}
+
+func createTestRenderObjects() []runtime.Object {
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: "cafe.example.com",
+			Upstreams: []conf_v1.Upstream{
+				{
+					Name:    "coffee",
+					Service: "coffee-svc",
+					Port:    80,
+				},
+			},
+			Routes: []conf_v1.Route{
+				{
+					Path: "/coffee",
+					Action: &conf_v1.Action{
+						Pass: "coffee",
+					},
+				},
+			},
+		},
+	}
+
+	svc := &api_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "coffee-svc",
+			Namespace: "default",
+		},
+		Spec: api_v1.ServiceSpec{
+			Ports: []api_v1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+
+	ready := true
+	port := int32(8080)
+	endpointSlice := &discovery_v1.EndpointSlice{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "coffee-svc-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				"kubernetes.io/service-name": "coffee-svc",
+			},
+		},
+		Ports: []discovery_v1.EndpointPort{
+			{
+				Port: &port,
+			},
+		},
+		Endpoints: []discovery_v1.Endpoint{
+			{
+				Addresses: []string{"10.0.0.1"},
+				Conditions: discovery_v1.EndpointConditions{
+					Ready: &ready,
+				},
+			},
+		},
+	}
+
+	// The resources come before the objects they reference to make sure the order doesn't matter.
+	return []runtime.Object{vs, svc, endpointSlice}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	confPath := t.TempDir()
+	input := createTestRenderInput(t, confPath, createTestRenderObjects())
+	input.ConfigMap = &api_v1.ConfigMap{
+		Data: map[string]string{
+			"worker-processes": "3",
+		},
+	}
+
+	problems, _, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() returned unexpected error: %v", err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("Render() returned unexpected problems: %v", problems)
+	}
+
+	expectedFiles := map[string][]string{
+		"nginx.conf": {
+			"worker_processes  3;",
+		},
+		"conf.d/vs_default_cafe.conf": {
+			"upstream vs_default_cafe_coffee {",
+			"server 10.0.0.1:8080",
+			"server_name cafe.example.com;",
+		},
+	}
+	for name, wantStrings := range expectedFiles {
+		content, err := os.ReadFile(path.Join(confPath, name))
+		if err != nil {
+			t.Fatalf("Render() didn't write %v: %v", name, err)
+		}
+		for _, want := range wantStrings {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("Render() generated %v without %q", name, want)
+			}
+		}
+	}
+}
+
+func TestRenderReportsProblems(t *testing.T) {
+	t.Parallel()
+
+	objects := createTestRenderObjects()
+	vs := objects[0].(*conf_v1.VirtualServer)
+	vs.Spec.Routes[0].Action.Pass = "tea"
+
+	confPath := t.TempDir()
+	problems, _, err := Render(createTestRenderInput(t, confPath, objects))
+	if err != nil {
+		t.Fatalf("Render() returned unexpected error: %v", err)
+	}
+
+	if len(problems) != 1 || problems[0].Object != vs || !problems[0].IsError {
+		t.Errorf("Render() returned problems %v, want one error for the VirtualServer", problems)
+	}
+
+	if _, err := os.Stat(path.Join(confPath, "conf.d/vs_default_cafe.conf")); !os.IsNotExist(err) {
+		t.Errorf("Render() generated the config of the invalid VirtualServer")
+	}
+}
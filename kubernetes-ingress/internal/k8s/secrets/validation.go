@@ -6,6 +6,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"regexp"
+	"sort"
 
 	api_v1 "k8s.io/api/core/v1"
 )
@@ -34,6 +35,9 @@ const SecretTypeOIDC api_v1.SecretType = "nginx.org/oidc"
 // SecretTypeHtpasswd contains an htpasswd file for use in HTTP Basic authorization.. #nosec G101
 const SecretTypeHtpasswd api_v1.SecretType = "nginx.org/htpasswd" // #nosec G101
 
+// SecretTypeAPIKey contains a list of client IDs and their API keys for use in API key authentication. #nosec G101
+const SecretTypeAPIKey api_v1.SecretType = "nginx.org/apikey" // #nosec G101
+
 // ValidateTLSSecret validates the secret. If it is valid, the function returns nil.
 func ValidateTLSSecret(secret *api_v1.Secret) error {
 	if secret.Type != api_v1.SecretTypeTLS {
@@ -125,13 +129,42 @@ func ValidateHtpasswdSecret(secret *api_v1.Secret) error {
 	return nil
 }
 
+// ValidateAPIKeySecret validates the secret. If it is valid, the function returns nil.
+func ValidateAPIKeySecret(secret *api_v1.Secret) error {
+	if secret.Type != SecretTypeAPIKey {
+		return fmt.Errorf("APIKey secret must be of the type %v", SecretTypeAPIKey)
+	}
+
+	// the keys of the data field are the client IDs and the values are their API keys.
+	clientIDs := make([]string, 0, len(secret.Data))
+	for clientID := range secret.Data {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	clients := make(map[string]string)
+	for _, clientID := range clientIDs {
+		key := secret.Data[clientID]
+		if len(key) == 0 {
+			return fmt.Errorf("APIKey secret has an empty API key for the client %v", clientID)
+		}
+		if existing, exists := clients[string(key)]; exists {
+			return fmt.Errorf("APIKey secret has the same API key for the clients %v and %v", existing, clientID)
+		}
+		clients[string(key)] = clientID
+	}
+
+	return nil
+}
+
 // IsSupportedSecretType checks if the secret type is supported.
 func IsSupportedSecretType(secretType api_v1.SecretType) bool {
 	return secretType == api_v1.SecretTypeTLS ||
 		secretType == SecretTypeCA ||
 		secretType == SecretTypeJWK ||
 		secretType == SecretTypeOIDC ||
-		secretType == SecretTypeHtpasswd
+		secretType == SecretTypeHtpasswd ||
+		secretType == SecretTypeAPIKey
 }
 
 // ValidateSecret validates the secret. If it is valid, the function returns nil.
@@ -147,6 +180,8 @@ func ValidateSecret(secret *api_v1.Secret) error {
 		return ValidateOIDCSecret(secret)
 	case SecretTypeHtpasswd:
 		return ValidateHtpasswdSecret(secret)
+	case SecretTypeAPIKey:
+		return ValidateAPIKeySecret(secret)
 	}
 
 	return fmt.Errorf("Secret is of the unsupported type %v", secret.Type)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -20,6 +22,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gateway_versioned "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
 
 // statusUpdater reports Ingress, VirtualServer and VirtualServerRoute status information via the kubernetes
@@ -32,6 +36,7 @@ type statusUpdater struct {
 	externalStatusAddress    string
 	externalServiceAddresses []string
 	externalServicePorts     string
+	externalService          *api_v1.Service
 	bigIPAddress             string
 	bigIPPorts               string
 	externalEndpoints        []conf_v1.ExternalEndpoint
@@ -40,7 +45,12 @@ type statusUpdater struct {
 	keyFunc                  func(obj interface{}) (string, error)
 	namespacedInformers      map[string]*namespacedInformer
 	confClient               k8s_nginx.Interface
+	gatewayClient            gateway_versioned.Interface
 	hasCorrectIngressClass   func(interface{}) bool
+	// getVirtualServerListeners returns the custom listeners a VirtualServer is bound to.
+	getVirtualServerListeners func(*conf_v1.VirtualServer) []conf_v1alpha1.Listener
+	// containerPorts maps the names of the container ports of the Ingress Controller pod to the port numbers.
+	containerPorts map[string]int
 }
 
 func (su *statusUpdater) UpdateExternalEndpointsForResources(resource []Resource) error {
@@ -262,6 +272,45 @@ func getExternalServicePorts(svc *api_v1.Service) string {
 	return fmt.Sprintf("[%v]", strings.Join(ports, ","))
 }
 
+// getExternalServicePortsForListeners returns the ports of the external Service that target the ports of the listeners.
+// A named target port is resolved with the container ports of the Ingress Controller pod. If the container port
+// doesn't exist, the Service port matches the listener with the same name.
+// If there is no external Service, the ports of the listeners are returned.
+func getExternalServicePortsForListeners(svc *api_v1.Service, containerPorts map[string]int, listeners []conf_v1alpha1.Listener) string {
+	var ports []string
+
+	for _, l := range listeners {
+		if svc == nil {
+			ports = append(ports, strconv.Itoa(l.Port))
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			var matches bool
+			if port.TargetPort.Type == intstr.String {
+				if targetPort, exists := containerPorts[port.TargetPort.StrVal]; exists {
+					matches = targetPort == l.Port
+				} else {
+					matches = port.Name == l.Name
+				}
+			} else {
+				targetPort := port.TargetPort.IntValue()
+				if targetPort == 0 {
+					targetPort = int(port.Port)
+				}
+				matches = targetPort == l.Port
+			}
+
+			if matches {
+				ports = append(ports, strconv.Itoa(int(port.Port)))
+				break
+			}
+		}
+	}
+
+	return fmt.Sprintf("[%v]", strings.Join(ports, ","))
+}
+
 func getExternalServiceAddress(svc *api_v1.Service) []string {
 	addresses := []string{}
 	if svc == nil {
@@ -324,6 +373,7 @@ func (su *statusUpdater) SaveStatusFromExternalService(svc *api_v1.Service) {
 	su.externalServiceAddresses = ips
 	ports := getExternalServicePorts(svc)
 	su.externalServicePorts = ports
+	su.externalService = svc
 	if su.externalStatusAddress != "" {
 		glog.V(3).Info("skipping external service address/ports - external-status-address is set and takes precedence")
 		return
@@ -455,6 +505,95 @@ func (su *statusUpdater) UpdateTransportServerStatus(ts *conf_v1alpha1.Transport
 	return err
 }
 
+// UpdateHTTPRouteStatus updates the statuses of the parents of the HTTPRoute that belong to the Ingress Controller.
+// The statuses of the parents of other controllers are kept.
+func (su *statusUpdater) UpdateHTTPRouteStatus(route *gateway_v1beta1.HTTPRoute, parents []gateway_v1beta1.RouteParentStatus) error {
+	routeLatest, exists, err := su.getNamespacedInformer(route.Namespace).httpRouteLister.Get(route)
+	if err != nil {
+		glog.V(3).Infof("error getting HTTPRoute from Store: %v", err)
+		return err
+	}
+	if !exists {
+		glog.V(3).Infof("HTTPRoute doesn't exist in Store")
+		return nil
+	}
+
+	routeCopy := routeLatest.(*gateway_v1beta1.HTTPRoute).DeepCopy()
+	routeCopy.Status.Parents = mergeRouteParentStatuses(routeCopy.Status.Parents, parents, metav1.Now())
+
+	if reflect.DeepEqual(routeLatest.(*gateway_v1beta1.HTTPRoute).Status, routeCopy.Status) {
+		return nil
+	}
+
+	_, err = su.gatewayClient.GatewayV1beta1().HTTPRoutes(routeCopy.Namespace).UpdateStatus(context.TODO(), routeCopy, metav1.UpdateOptions{})
+	if err != nil {
+		glog.V(3).Infof("error setting HTTPRoute %v/%v status, retrying: %v", routeCopy.Namespace, routeCopy.Name, err)
+		return su.retryUpdateHTTPRouteStatus(routeCopy)
+	}
+	return err
+}
+
+func (su *statusUpdater) retryUpdateHTTPRouteStatus(routeCopy *gateway_v1beta1.HTTPRoute) error {
+	route, err := su.gatewayClient.GatewayV1beta1().HTTPRoutes(routeCopy.Namespace).Get(context.TODO(), routeCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	route.Status.Parents = mergeRouteParentStatuses(route.Status.Parents, routeCopy.Status.Parents, metav1.Now())
+	_, err = su.gatewayClient.GatewayV1beta1().HTTPRoutes(route.Namespace).UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mergeRouteParentStatuses replaces the statuses of the parents of the Ingress Controller with the new ones.
+// The transition time of a condition is kept if its status doesn't change.
+func mergeRouteParentStatuses(current []gateway_v1beta1.RouteParentStatus, parents []gateway_v1beta1.RouteParentStatus, now metav1.Time) []gateway_v1beta1.RouteParentStatus {
+	var result []gateway_v1beta1.RouteParentStatus
+	oldConditions := make(map[string]metav1.Condition)
+
+	for _, p := range current {
+		if p.ControllerName != gateway_v1beta1.GatewayController(GatewayControllerName) {
+			result = append(result, p)
+			continue
+		}
+		for _, c := range p.Conditions {
+			oldConditions[getParentRefKey(p.ParentRef)+"/"+c.Type] = c
+		}
+	}
+
+	for _, p := range parents {
+		parent := *p.DeepCopy()
+		for i := range parent.Conditions {
+			c := &parent.Conditions[i]
+			c.LastTransitionTime = now
+			if old, exists := oldConditions[getParentRefKey(p.ParentRef)+"/"+c.Type]; exists && old.Status == c.Status {
+				c.LastTransitionTime = old.LastTransitionTime
+			}
+		}
+		result = append(result, parent)
+	}
+
+	return result
+}
+
+func getParentRefKey(ref gateway_v1beta1.ParentReference) string {
+	var namespace, sectionName string
+	var port int32
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	if ref.SectionName != nil {
+		sectionName = string(*ref.SectionName)
+	}
+	if ref.Port != nil {
+		port = int32(*ref.Port)
+	}
+	return fmt.Sprintf("%s/%s/%s/%d", namespace, ref.Name, sectionName, port)
+}
+
 func hasTsStatusChanged(ts *conf_v1alpha1.TransportServer, state string, reason string, message string) bool {
 	if ts.Status.State != state {
 		return true
@@ -488,14 +627,17 @@ func (su *statusUpdater) UpdateVirtualServerStatus(vs *conf_v1.VirtualServer, st
 
 	vsCopy := vsLatest.(*conf_v1.VirtualServer).DeepCopy()
 
-	if !hasVsStatusChanged(vsCopy, state, reason, message) {
+	listeners, externalEndpoints := su.generateVirtualServerListenersStatus(vsCopy)
+
+	if !hasVsStatusChanged(vsCopy, state, reason, message) && slices.Equal(vsCopy.Status.Listeners, listeners) {
 		return nil
 	}
 
 	vsCopy.Status.State = state
 	vsCopy.Status.Reason = reason
 	vsCopy.Status.Message = message
-	vsCopy.Status.ExternalEndpoints = su.externalEndpoints
+	vsCopy.Status.ExternalEndpoints = externalEndpoints
+	vsCopy.Status.Listeners = listeners
 
 	_, err = su.confClient.K8sV1().VirtualServers(vsCopy.Namespace).UpdateStatus(context.TODO(), vsCopy, metav1.UpdateOptions{})
 	if err != nil {
@@ -626,7 +768,7 @@ func (su *statusUpdater) updateVirtualServerExternalEndpoints(vs *conf_v1.Virtua
 	}
 
 	vsCopy := vsLatest.(*conf_v1.VirtualServer).DeepCopy()
-	vsCopy.Status.ExternalEndpoints = su.externalEndpoints
+	vsCopy.Status.Listeners, vsCopy.Status.ExternalEndpoints = su.generateVirtualServerListenersStatus(vsCopy)
 
 	_, err = su.confClient.K8sV1().VirtualServers(vsCopy.Namespace).UpdateStatus(context.TODO(), vsCopy, metav1.UpdateOptions{})
 	if err != nil {
@@ -679,6 +821,68 @@ func (su *statusUpdater) generateExternalEndpointsFromStatus(status []networking
 	return externalEndpoints
 }
 
+// generateVirtualServerListenersStatus returns the names of the custom listeners the VirtualServer is bound to
+// along with the external endpoints that report the ports of those listeners.
+func (su *statusUpdater) generateVirtualServerListenersStatus(vs *conf_v1.VirtualServer) ([]string, []conf_v1.ExternalEndpoint) {
+	if su.getVirtualServerListeners == nil {
+		return nil, su.externalEndpoints
+	}
+
+	listeners := su.getVirtualServerListeners(vs)
+	if len(listeners) == 0 {
+		return nil, su.externalEndpoints
+	}
+
+	var names []string
+	for _, l := range listeners {
+		names = append(names, l.Name)
+	}
+
+	svc := su.externalService
+	if su.externalStatusAddress != "" || su.bigIPAddress != "" {
+		svc = nil
+	}
+	var containerPorts map[string]int
+	if svc != nil {
+		containerPorts = su.getContainerPorts()
+	}
+	ports := getExternalServicePortsForListeners(svc, containerPorts, listeners)
+
+	var externalEndpoints []conf_v1.ExternalEndpoint
+	for _, endpoint := range su.externalEndpoints {
+		endpoint.Ports = ports
+		externalEndpoints = append(externalEndpoints, endpoint)
+	}
+
+	return names, externalEndpoints
+}
+
+// getContainerPorts returns the named container ports of the Ingress Controller pod. The ports are retrieved from the
+// API once.
+func (su *statusUpdater) getContainerPorts() map[string]int {
+	if su.containerPorts != nil {
+		return su.containerPorts
+	}
+
+	pod, err := su.client.CoreV1().Pods(os.Getenv("POD_NAMESPACE")).Get(context.TODO(), os.Getenv("POD_NAME"), metav1.GetOptions{})
+	if err != nil {
+		glog.V(3).Infof("error getting the Ingress Controller pod: %v", err)
+		return nil
+	}
+
+	containerPorts := make(map[string]int)
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name != "" {
+				containerPorts[p.Name] = int(p.ContainerPort)
+			}
+		}
+	}
+	su.containerPorts = containerPorts
+
+	return su.containerPorts
+}
+
 func hasPolicyStatusChanged(pol *conf_v1.Policy, state string, reason string, message string) bool {
 	return pol.Status.State != state || pol.Status.Reason != reason || pol.Status.Message != message
 }
@@ -617,6 +617,185 @@ func TestGetExternalServicePorts(t *testing.T) {
 	}
 }
 
+func TestGetExternalServicePortsForListeners(t *testing.T) {
+	t.Parallel()
+	listeners := []conf_v1alpha1.Listener{
+		{Name: "http-8083", Port: 8083, Protocol: "HTTP"},
+		{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+		{Name: "https-9443", Port: 9443, Protocol: "HTTP", Ssl: true},
+	}
+
+	tests := []struct {
+		svc      *v1.Service
+		expected string
+		msg      string
+	}{
+		{
+			svc:      nil,
+			expected: "[8083,8443,9443]",
+			msg:      "no external service",
+		},
+		{
+			svc: &v1.Service{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{
+						{
+							Port: int32(80),
+							TargetPort: intstr.IntOrString{
+								Type:   intstr.Int,
+								IntVal: 8083,
+							},
+						},
+						{
+							Port: int32(8443),
+						},
+						{
+							Port: int32(443),
+							TargetPort: intstr.IntOrString{
+								Type:   intstr.Int,
+								IntVal: 443,
+							},
+						},
+					},
+				},
+			},
+			expected: "[80,8443]",
+			msg:      "external service exposes some of the listeners",
+		},
+		{
+			svc: &v1.Service{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{
+						{
+							Name: "http-custom",
+							Port: int32(80),
+							TargetPort: intstr.IntOrString{
+								Type:   intstr.String,
+								StrVal: "http-custom",
+							},
+						},
+						{
+							Name: "https-8443",
+							Port: int32(443),
+							TargetPort: intstr.IntOrString{
+								Type:   intstr.String,
+								StrVal: "https-custom",
+							},
+						},
+						{
+							Name: "https-other",
+							Port: int32(9000),
+							TargetPort: intstr.IntOrString{
+								Type:   intstr.String,
+								StrVal: "https-other",
+							},
+						},
+					},
+				},
+			},
+			expected: "[80,443]",
+			msg:      "external service with named target ports",
+		},
+	}
+
+	containerPorts := map[string]int{
+		"http-custom": 8083,
+	}
+
+	for _, test := range tests {
+		ports := getExternalServicePortsForListeners(test.svc, containerPorts, listeners)
+		if ports != test.expected {
+			t.Errorf("getExternalServicePortsForListeners() returned %v but expected %v for the case of %s", ports, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGetContainerPorts(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "nginx-ingress")
+	t.Setenv("POD_NAME", "nginx-ingress-1")
+
+	fakeClient := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "nginx-ingress-1",
+				Namespace: "nginx-ingress",
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Ports: []v1.ContainerPort{
+							{Name: "http", ContainerPort: 80},
+							{ContainerPort: 443},
+							{Name: "https-custom", ContainerPort: 8443},
+						},
+					},
+				},
+			},
+		},
+	)
+	su := statusUpdater{
+		client: fakeClient,
+	}
+
+	expected := map[string]int{
+		"http":         80,
+		"https-custom": 8443,
+	}
+
+	containerPorts := su.getContainerPorts()
+	if diff := cmp.Diff(expected, containerPorts); diff != "" {
+		t.Errorf("getContainerPorts() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerListenersStatus(t *testing.T) {
+	t.Parallel()
+	externalEndpoints := []conf_v1.ExternalEndpoint{
+		{IP: "8.8.8.8", Ports: "[80,443]"},
+	}
+	listeners := []conf_v1alpha1.Listener{
+		{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+	}
+
+	su := statusUpdater{
+		externalEndpoints:     externalEndpoints,
+		externalStatusAddress: "8.8.8.8",
+		getVirtualServerListeners: func(vs *conf_v1.VirtualServer) []conf_v1alpha1.Listener {
+			if vs.Spec.Listener == nil {
+				return nil
+			}
+			return listeners
+		},
+	}
+
+	names, endpoints := su.generateVirtualServerListenersStatus(&conf_v1.VirtualServer{})
+	if names != nil {
+		t.Errorf("generateVirtualServerListenersStatus() returned listeners %v for a VirtualServer without listeners", names)
+	}
+	if diff := cmp.Diff(externalEndpoints, endpoints); diff != "" {
+		t.Errorf("generateVirtualServerListenersStatus() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	vs := &conf_v1.VirtualServer{
+		Spec: conf_v1.VirtualServerSpec{
+			Listener: &conf_v1.Listener{
+				HTTPS: "https-8443",
+			},
+		},
+	}
+	expectedEndpoints := []conf_v1.ExternalEndpoint{
+		{IP: "8.8.8.8", Ports: "[8443]"},
+	}
+
+	names, endpoints = su.generateVirtualServerListenersStatus(vs)
+	if diff := cmp.Diff([]string{"https-8443"}, names); diff != "" {
+		t.Errorf("generateVirtualServerListenersStatus() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedEndpoints, endpoints); diff != "" {
+		t.Errorf("generateVirtualServerListenersStatus() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
 func TestIsRequiredPort(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
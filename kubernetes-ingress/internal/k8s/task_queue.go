@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
+	gateway_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // taskQueue manages a work queue through an independent worker that
@@ -127,6 +128,10 @@ const (
 	appProtectDosLogConf
 	appProtectDosProtectedResource
 	ingressLink
+	gatewayClass
+	gateway
+	httpRoute
+	referenceGrant
 )
 
 // task is an element of a taskQueue
@@ -163,6 +168,14 @@ func newTask(key string, obj interface{}) (task, error) {
 		k = transportserver
 	case *v1beta1.DosProtectedResource:
 		k = appProtectDosProtectedResource
+	case *gateway_v1beta1.GatewayClass:
+		k = gatewayClass
+	case *gateway_v1beta1.Gateway:
+		k = gateway
+	case *gateway_v1beta1.HTTPRoute:
+		k = httpRoute
+	case *gateway_v1beta1.ReferenceGrant:
+		k = referenceGrant
 	case *unstructured.Unstructured:
 		if objectKind := obj.(*unstructured.Unstructured).GetKind(); objectKind == appprotect.PolicyGVK.Kind {
 			k = appProtectPolicy
@@ -10,6 +10,7 @@ import (
 type ManagerCollector interface {
 	IncNginxReloadCount(isEndPointUpdate bool)
 	IncNginxReloadErrors()
+	IncNginxReloadRollbacks()
 	UpdateLastReloadTime(ms time.Duration)
 	Register(registry *prometheus.Registry) error
 }
@@ -19,6 +20,7 @@ type LocalManagerMetricsCollector struct {
 	// Metrics
 	reloadsTotal     *prometheus.CounterVec
 	reloadsError     prometheus.Counter
+	reloadsRollback  prometheus.Counter
 	lastReloadStatus prometheus.Gauge
 	lastReloadTime   prometheus.Gauge
 }
@@ -43,6 +45,14 @@ func NewLocalManagerMetricsCollector(constLabels map[string]string) *LocalManage
 				ConstLabels: constLabels,
 			},
 		),
+		reloadsRollback: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "nginx_reload_rollbacks_total",
+				Namespace:   metricsNamespace,
+				Help:        "Number of rollbacks of the configuration changes rejected by NGINX after unsuccessful NGINX reloads",
+				ConstLabels: constLabels,
+			},
+		),
 		lastReloadStatus: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name:        "nginx_last_reload_status",
@@ -83,6 +93,11 @@ func (nc *LocalManagerMetricsCollector) IncNginxReloadErrors() {
 	nc.updateLastReloadStatus(false)
 }
 
+// IncNginxReloadRollbacks increments the counter of rollbacks of the configuration changes rejected by NGINX
+func (nc *LocalManagerMetricsCollector) IncNginxReloadRollbacks() {
+	nc.reloadsRollback.Inc()
+}
+
 // updateLastReloadStatus updates the last NGINX reload status metric
 func (nc *LocalManagerMetricsCollector) updateLastReloadStatus(up bool) {
 	var status float64
@@ -101,6 +116,7 @@ func (nc *LocalManagerMetricsCollector) UpdateLastReloadTime(duration time.Durat
 func (nc *LocalManagerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	nc.reloadsTotal.Describe(ch)
 	nc.reloadsError.Describe(ch)
+	nc.reloadsRollback.Describe(ch)
 	nc.lastReloadStatus.Describe(ch)
 	nc.lastReloadTime.Describe(ch)
 }
@@ -109,6 +125,7 @@ func (nc *LocalManagerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 func (nc *LocalManagerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	nc.reloadsTotal.Collect(ch)
 	nc.reloadsError.Collect(ch)
+	nc.reloadsRollback.Collect(ch)
 	nc.lastReloadStatus.Collect(ch)
 	nc.lastReloadTime.Collect(ch)
 }
@@ -135,5 +152,8 @@ func (nc *ManagerFakeCollector) IncNginxReloadCount(_ bool) {}
 // IncNginxReloadErrors implements a fake IncNginxReloadErrors
 func (nc *ManagerFakeCollector) IncNginxReloadErrors() {}
 
+// IncNginxReloadRollbacks implements a fake IncNginxReloadRollbacks
+func (nc *ManagerFakeCollector) IncNginxReloadRollbacks() {}
+
 // UpdateLastReloadTime implements a fake UpdateLastReloadTime
 func (nc *ManagerFakeCollector) UpdateLastReloadTime(_ time.Duration) {}
@@ -0,0 +1,174 @@
+package nginx
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/golang/glog"
+	"github.com/nginxinc/nginx-plus-go-client/client"
+)
+
+// FileManager writes NGINX configuration files to a folder without running NGINX.
+// It allows generating the configuration offline, for example, to review it.
+type FileManager struct {
+	confdPath                   string
+	streamConfdPath             string
+	secretsPath                 string
+	mainConfFilename            string
+	dhparamFilename             string
+	tlsPassthroughHostsFilename string
+	version                     string
+}
+
+// NewFileManager creates a FileManager that writes the configuration files to confPath.
+// The version is reported as the NGINX version.
+func NewFileManager(confPath string, version string) (*FileManager, error) {
+	fm := &FileManager{
+		confdPath:                   path.Join(confPath, "conf.d"),
+		streamConfdPath:             path.Join(confPath, "stream-conf.d"),
+		secretsPath:                 path.Join(confPath, "secrets"),
+		mainConfFilename:            path.Join(confPath, "nginx.conf"),
+		dhparamFilename:             path.Join(confPath, "secrets", "dhparam.pem"),
+		tlsPassthroughHostsFilename: path.Join(confPath, "tls-passthrough-hosts.conf"),
+		version:                     version,
+	}
+
+	for _, dir := range []string{fm.confdPath, fm.streamConfdPath, fm.secretsPath} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create folder %v: %w", dir, err)
+		}
+	}
+
+	return fm, nil
+}
+
+// CreateMainConfig writes the main NGINX configuration file.
+func (fm *FileManager) CreateMainConfig(content []byte) {
+	createConfig(fm.mainConfFilename, content)
+}
+
+// CreateConfig writes a configuration file to the conf.d folder.
+func (fm *FileManager) CreateConfig(name string, content []byte) {
+	createConfig(path.Join(fm.confdPath, name+".conf"), content)
+}
+
+// DeleteConfig deletes a configuration file from the conf.d folder.
+func (fm *FileManager) DeleteConfig(name string) {
+	deleteConfig(path.Join(fm.confdPath, name+".conf"))
+}
+
+// CreateStreamConfig writes a configuration file to the stream-conf.d folder.
+func (fm *FileManager) CreateStreamConfig(name string, content []byte) {
+	createConfig(path.Join(fm.streamConfdPath, name+".conf"), content)
+}
+
+// DeleteStreamConfig deletes a configuration file from the stream-conf.d folder.
+func (fm *FileManager) DeleteStreamConfig(name string) {
+	deleteConfig(path.Join(fm.streamConfdPath, name+".conf"))
+}
+
+// CreateTLSPassthroughHostsConfig writes the TLS Passthrough hosts configuration file.
+func (fm *FileManager) CreateTLSPassthroughHostsConfig(content []byte) {
+	createConfig(fm.tlsPassthroughHostsFilename, content)
+}
+
+// CreateSecret writes a secret file to the secrets folder.
+func (fm *FileManager) CreateSecret(name string, content []byte, mode os.FileMode) string {
+	filename := fm.GetFilenameForSecret(name)
+
+	glog.V(3).Infof("Writing secret to %v", filename)
+
+	createFileAndWriteAtomically(filename, fm.secretsPath, mode, content)
+
+	return filename
+}
+
+// DeleteSecret deletes a secret file from the secrets folder.
+func (fm *FileManager) DeleteSecret(name string) {
+	deleteConfig(fm.GetFilenameForSecret(name))
+}
+
+// GetFilenameForSecret constructs the filename for the secret.
+func (fm *FileManager) GetFilenameForSecret(name string) string {
+	return path.Join(fm.secretsPath, name)
+}
+
+// CreateDHParam writes the dhparam.pem file to the secrets folder.
+func (fm *FileManager) CreateDHParam(content string) (string, error) {
+	err := createFileAndWrite(fm.dhparamFilename, []byte(content))
+	if err != nil {
+		return fm.dhparamFilename, fmt.Errorf("failed to write dhparam file from %v: %w", fm.dhparamFilename, err)
+	}
+
+	return fm.dhparamFilename, nil
+}
+
+// CreateAppProtectResourceFile is not supported, as App Protect resources are not rendered.
+func (*FileManager) CreateAppProtectResourceFile(name string, _ []byte) {
+	glog.V(3).Infof("Skipping App Protect Resource %v", name)
+}
+
+// DeleteAppProtectResourceFile is not supported, as App Protect resources are not rendered.
+func (*FileManager) DeleteAppProtectResourceFile(name string) {
+	glog.V(3).Infof("Skipping App Protect Resource %v", name)
+}
+
+// ClearAppProtectFolder is not supported, as App Protect resources are not rendered.
+func (*FileManager) ClearAppProtectFolder(name string) {
+	glog.V(3).Infof("Skipping App Protect folder %v", name)
+}
+
+// CreateOpenTracingTracerConfig is not supported, as the tracer config is not rendered.
+func (*FileManager) CreateOpenTracingTracerConfig(_ string) error {
+	glog.V(3).Info("Skipping OpenTracing tracer config file")
+	return nil
+}
+
+// Start does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) Start(_ chan error) {}
+
+// Version returns the NGINX version of the FileManager.
+func (fm *FileManager) Version() string {
+	return fm.version
+}
+
+// Reload does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) Reload(_ bool) error {
+	return nil
+}
+
+// Quit does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) Quit() {}
+
+// UpdateConfigVersionFile does nothing, as the config version is only used by a running NGINX.
+func (*FileManager) UpdateConfigVersionFile(_ bool) {}
+
+// SetPlusClients does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) SetPlusClients(_ *client.NginxClient, _ *http.Client) {}
+
+// UpdateServersInPlus does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) UpdateServersInPlus(_ string, _ []string, _ ServerConfig) error {
+	return nil
+}
+
+// UpdateStreamServersInPlus does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) UpdateStreamServersInPlus(_ string, _ []string) error {
+	return nil
+}
+
+// SetOpenTracing does nothing, as the tracer config is not rendered.
+func (*FileManager) SetOpenTracing(_ bool) {}
+
+// AppProtectPluginStart does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) AppProtectPluginStart(_ chan error, _ string) {}
+
+// AppProtectPluginQuit does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) AppProtectPluginQuit() {}
+
+// AppProtectDosAgentStart does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) AppProtectDosAgentStart(_ chan error, _ bool, _ int, _ int, _ int) {}
+
+// AppProtectDosAgentQuit does nothing, as FileManager doesn't run NGINX.
+func (*FileManager) AppProtectDosAgentQuit() {}
@@ -0,0 +1,48 @@
+package nginx
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestFileManager_WritesAndDeletesConfigs(t *testing.T) {
+	t.Parallel()
+
+	confPath := t.TempDir()
+	fm, err := NewFileManager(confPath, "nginx/1.25.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fm.CreateMainConfig([]byte("main"))
+	fm.CreateConfig("vs_default_cafe", []byte("http"))
+	fm.CreateStreamConfig("ts_default_dns", []byte("stream"))
+
+	files := map[string]string{
+		"nginx.conf":                        "main",
+		"conf.d/vs_default_cafe.conf":       "http",
+		"stream-conf.d/ts_default_dns.conf": "stream",
+	}
+	for name, expected := range files {
+		content, err := os.ReadFile(path.Join(confPath, name))
+		if err != nil {
+			t.Fatalf("file %v was not written: %v", name, err)
+		}
+		if string(content) != expected {
+			t.Errorf("file %v has content %q, want %q", name, content, expected)
+		}
+	}
+
+	fm.DeleteConfig("vs_default_cafe")
+	if _, err := os.Stat(path.Join(confPath, "conf.d/vs_default_cafe.conf")); !os.IsNotExist(err) {
+		t.Errorf("DeleteConfig() didn't delete the config file")
+	}
+
+	if fm.Version() != "nginx/1.25.3" {
+		t.Errorf("Version() returned %q, want %q", fm.Version(), "nginx/1.25.3")
+	}
+	if err := fm.Reload(false); err != nil {
+		t.Errorf("Reload() returned unexpected error: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -105,6 +106,40 @@ type LocalManager struct {
 	OpenTracing                  bool
 	appProtectPluginPid          int
 	appProtectDosAgentPid        int
+	configSnapshots              map[string]configSnapshot
+	// reloadFunc reloads NGINX and waits for the new config version. It is replaced in the tests.
+	reloadFunc func(isEndpointsUpdate bool) error
+	// testConfigFunc tests the NGINX configuration. It is replaced in the tests.
+	testConfigFunc func() error
+}
+
+// configSnapshot holds the content of a file before its first change after the last successful reload.
+type configSnapshot struct {
+	// name is the name of the conf.d or stream-conf.d config. It is empty for the other files.
+	name     string
+	content  []byte
+	exists   bool
+	isSecret bool
+	// mode is the mode of the file, which is kept for the secrets.
+	mode os.FileMode
+}
+
+// ConfigRollbackError is returned by Reload when NGINX rejects some of the files changed since the last successful
+// reload. The previous content of the rejected files is restored, while the other changes are applied.
+type ConfigRollbackError struct {
+	// InvalidConfigs maps the names of the rejected conf.d and stream-conf.d configs, or the paths of the other
+	// rejected files, to the errors of NGINX.
+	InvalidConfigs map[string]error
+	// Err is the error of the failed reload.
+	Err error
+}
+
+func (e *ConfigRollbackError) Error() string {
+	return fmt.Sprintf("%v; the changes rejected by NGINX were rolled back", e.Err)
+}
+
+func (e *ConfigRollbackError) Unwrap() error {
+	return e.Err
 }
 
 // NewLocalManager creates a LocalManager.
@@ -114,7 +149,7 @@ func NewLocalManager(confPath string, debug bool, mc collectors.ManagerCollector
 		glog.Fatalf("error instantiating a verifyConfigGenerator: %v", err)
 	}
 
-	manager := LocalManager{
+	manager := &LocalManager{
 		confdPath:                   path.Join(confPath, "conf.d"),
 		streamConfdPath:             path.Join(confPath, "stream-conf.d"),
 		secretsPath:                 path.Join(confPath, "secrets"),
@@ -127,9 +162,12 @@ func NewLocalManager(confPath string, debug bool, mc collectors.ManagerCollector
 		configVersion:               0,
 		verifyClient:                newVerifyClient(timeout),
 		metricsCollector:            mc,
+		configSnapshots:             make(map[string]configSnapshot),
 	}
+	manager.reloadFunc = manager.reload
+	manager.testConfigFunc = manager.testConfig
 
-	return &manager
+	return manager
 }
 
 // CreateMainConfig creates the main NGINX configuration file. If the file already exists, it will be overridden.
@@ -137,6 +175,7 @@ func (lm *LocalManager) CreateMainConfig(content []byte) {
 	glog.V(3).Infof("Writing main config to %v", lm.mainConfFilename)
 	glog.V(3).Infof(string(content))
 
+	lm.snapshotFile(lm.mainConfFilename, "", false)
 	err := createFileAndWrite(lm.mainConfFilename, content)
 	if err != nil {
 		glog.Fatalf("Failed to write main config: %v", err)
@@ -145,7 +184,9 @@ func (lm *LocalManager) CreateMainConfig(content []byte) {
 
 // CreateConfig creates a configuration file. If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateConfig(name string, content []byte) {
-	createConfig(lm.getFilenameForConfig(name), content)
+	filename := lm.getFilenameForConfig(name)
+	lm.snapshotFile(filename, name, false)
+	createConfig(filename, content)
 }
 
 func createConfig(filename string, content []byte) {
@@ -160,7 +201,9 @@ func createConfig(filename string, content []byte) {
 
 // DeleteConfig deletes the configuration file from the conf.d folder.
 func (lm *LocalManager) DeleteConfig(name string) {
-	deleteConfig(lm.getFilenameForConfig(name))
+	filename := lm.getFilenameForConfig(name)
+	lm.snapshotFile(filename, name, false)
+	deleteConfig(filename)
 }
 
 func deleteConfig(filename string) {
@@ -178,12 +221,16 @@ func (lm *LocalManager) getFilenameForConfig(name string) string {
 // CreateStreamConfig creates a configuration file for stream module.
 // If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateStreamConfig(name string, content []byte) {
-	createConfig(lm.getFilenameForStreamConfig(name), content)
+	filename := lm.getFilenameForStreamConfig(name)
+	lm.snapshotFile(filename, name, false)
+	createConfig(filename, content)
 }
 
 // DeleteStreamConfig deletes the configuration file from the stream-conf.d folder.
 func (lm *LocalManager) DeleteStreamConfig(name string) {
-	deleteConfig(lm.getFilenameForStreamConfig(name))
+	filename := lm.getFilenameForStreamConfig(name)
+	lm.snapshotFile(filename, name, false)
+	deleteConfig(filename)
 }
 
 func (lm *LocalManager) getFilenameForStreamConfig(name string) string {
@@ -195,6 +242,7 @@ func (lm *LocalManager) getFilenameForStreamConfig(name string) string {
 // If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateTLSPassthroughHostsConfig(content []byte) {
 	glog.V(3).Infof("Writing TLS Passthrough Hosts config file to %v", lm.tlsPassthroughHostsFilename)
+	lm.snapshotFile(lm.tlsPassthroughHostsFilename, "", false)
 	createConfig(lm.tlsPassthroughHostsFilename, content)
 }
 
@@ -205,6 +253,7 @@ func (lm *LocalManager) CreateSecret(name string, content []byte, mode os.FileMo
 
 	glog.V(3).Infof("Writing secret to %v", filename)
 
+	lm.snapshotFile(filename, "", true)
 	createFileAndWriteAtomically(filename, lm.secretsPath, mode, content)
 
 	return filename
@@ -216,6 +265,7 @@ func (lm *LocalManager) DeleteSecret(name string) {
 
 	glog.V(3).Infof("Deleting secret from %v", filename)
 
+	lm.snapshotFile(filename, "", true)
 	if err := os.Remove(filename); err != nil {
 		glog.Warningf("Failed to delete secret from %v: %v", filename, err)
 	}
@@ -287,10 +337,47 @@ func (lm *LocalManager) Start(done chan error) {
 	if err != nil {
 		glog.Fatalf("Could not get newest config version: %v", err)
 	}
+
+	lm.clearConfigSnapshots()
 }
 
-// Reload reloads NGINX.
+// Reload reloads NGINX. If the reload fails, the files changed since the last successful reload are restored
+// and the changes are applied again one by one, so that only the changes that NGINX rejects are rolled back.
+// Then NGINX is reloaded again. In that case, the returned ConfigRollbackError reports the rejected changes.
 func (lm *LocalManager) Reload(isEndpointsUpdate bool) error {
+	err := lm.reloadFunc(isEndpointsUpdate)
+	if err == nil {
+		lm.clearConfigSnapshots()
+		return nil
+	}
+
+	if len(lm.configSnapshots) == 0 {
+		return err
+	}
+
+	glog.Warningf("Rolling back the changes rejected by NGINX: %v", err)
+
+	invalidConfigs := lm.rollbackInvalidChanges()
+	if len(invalidConfigs) > 0 {
+		lm.metricsCollector.IncNginxReloadRollbacks()
+	}
+
+	if rollbackErr := lm.reloadFunc(isEndpointsUpdate); rollbackErr != nil {
+		return fmt.Errorf("%w; rolling back the changes rejected by NGINX failed: %v", err, rollbackErr)
+	}
+	lm.clearConfigSnapshots()
+
+	if len(invalidConfigs) == 0 {
+		return err
+	}
+
+	return &ConfigRollbackError{
+		InvalidConfigs: invalidConfigs,
+		Err:            err,
+	}
+}
+
+func (lm *LocalManager) reload(isEndpointsUpdate bool) error {
 	// write a new config version
 	lm.configVersion++
 	lm.UpdateConfigVersionFile(lm.OpenTracing)
@@ -307,6 +394,11 @@ func (lm *LocalManager) Reload(isEndpointsUpdate bool) error {
 	err := lm.verifyClient.WaitForCorrectVersion(lm.configVersion)
 	if err != nil {
 		lm.metricsCollector.IncNginxReloadErrors()
+		// NGINX reports the errors of the new configuration only in its log, so we test the configuration
+		// to return the error text.
+		if testErr := lm.testConfigFunc(); testErr != nil {
+			return fmt.Errorf("could not get newest config version: %w; %v", err, testErr)
+		}
 		return fmt.Errorf("could not get newest config version: %w", err)
 	}
 
@@ -317,6 +409,120 @@ func (lm *LocalManager) Reload(isEndpointsUpdate bool) error {
 	return nil
 }
 
+// testConfig tests the NGINX configuration.
+func (lm *LocalManager) testConfig() error {
+	binaryFilename := getBinaryFileName(lm.debug)
+	return shellOut(fmt.Sprintf("%v -t -q -e stderr", binaryFilename))
+}
+
+// snapshotFile saves the content of the file, unless it has already been saved since the last successful reload.
+func (lm *LocalManager) snapshotFile(filename string, name string, isSecret bool) {
+	if _, exists := lm.configSnapshots[filename]; exists {
+		return
+	}
+
+	snapshot, err := readConfigSnapshot(filename, name, isSecret)
+	if err != nil {
+		glog.Warningf("Failed to read %v for the rollback: %v", filename, err)
+		return
+	}
+
+	lm.configSnapshots[filename] = snapshot
+}
+
+func readConfigSnapshot(filename string, name string, isSecret bool) (configSnapshot, error) {
+	snapshot := configSnapshot{name: name, isSecret: isSecret}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshot, nil
+		}
+		return snapshot, err
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return snapshot, err
+	}
+
+	snapshot.content = content
+	snapshot.exists = true
+	snapshot.mode = info.Mode().Perm()
+
+	return snapshot, nil
+}
+
+// rollbackInvalidChanges restores the files changed since the last successful reload and applies the changes again
+// one by one, testing the configuration after every change. The changes that NGINX rejects are rolled back.
+// Because a change can depend on another change, like a config on a new secret, the rejected changes are applied
+// again while other changes are accepted.
+// It returns the errors of NGINX for the rolled back changes by the names of the configs or the paths of the files.
+func (lm *LocalManager) rollbackInvalidChanges() map[string]error {
+	changes := make(map[string]configSnapshot)
+	var pending []string
+
+	for filename, snapshot := range lm.configSnapshots {
+		change, err := readConfigSnapshot(filename, snapshot.name, snapshot.isSecret)
+		if err != nil {
+			glog.Warningf("Failed to read %v for the rollback: %v", filename, err)
+			change = snapshot
+		}
+
+		changes[filename] = change
+		pending = append(pending, filename)
+		lm.restoreFile(filename, snapshot)
+	}
+	sort.Strings(pending)
+
+	invalidChanges := make(map[string]error)
+	for accepted := true; accepted && len(pending) > 0; {
+		accepted = false
+		var rejected []string
+
+		for _, filename := range pending {
+			change := changes[filename]
+			key := change.name
+			if key == "" {
+				key = filename
+			}
+
+			lm.restoreFile(filename, change)
+			if err := lm.testConfigFunc(); err != nil {
+				lm.restoreFile(filename, lm.configSnapshots[filename])
+				invalidChanges[key] = err
+				rejected = append(rejected, filename)
+				continue
+			}
+
+			delete(invalidChanges, key)
+			accepted = true
+		}
+
+		pending = rejected
+	}
+
+	return invalidChanges
+}
+
+// restoreFile writes the content of the snapshot to the file or deletes the file if it didn't exist.
+func (lm *LocalManager) restoreFile(filename string, snapshot configSnapshot) {
+	switch {
+	case !snapshot.exists:
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			glog.Warningf("Failed to delete %v for the rollback: %v", filename, err)
+		}
+	case snapshot.isSecret:
+		createFileAndWriteAtomically(filename, lm.secretsPath, snapshot.mode, snapshot.content)
+	default:
+		createConfig(filename, snapshot.content)
+	}
+}
+
+func (lm *LocalManager) clearConfigSnapshots() {
+	lm.configSnapshots = make(map[string]configSnapshot)
+}
+
 // Quit shutdowns NGINX gracefully.
 func (lm *LocalManager) Quit() {
 	glog.V(3).Info("Quitting nginx")
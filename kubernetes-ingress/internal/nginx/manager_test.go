@@ -0,0 +1,255 @@
+package nginx
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+)
+
+var errBrokenConfig = errors.New("nginx: [emerg] unknown directive \"broken\"")
+
+func newTestLocalManager(t *testing.T) *LocalManager {
+	t.Helper()
+
+	confPath := t.TempDir()
+	for _, dir := range []string{"conf.d", "stream-conf.d", "secrets"} {
+		if err := os.Mkdir(path.Join(confPath, dir), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lm := &LocalManager{
+		confdPath:                   path.Join(confPath, "conf.d"),
+		streamConfdPath:             path.Join(confPath, "stream-conf.d"),
+		secretsPath:                 path.Join(confPath, "secrets"),
+		mainConfFilename:            path.Join(confPath, "nginx.conf"),
+		tlsPassthroughHostsFilename: path.Join(confPath, "tls-passthrough-hosts.conf"),
+		configSnapshots:             make(map[string]configSnapshot),
+		metricsCollector:            collectors.NewManagerFakeCollector(),
+	}
+	lm.testConfigFunc = func() error {
+		return testConfigFiles(t, lm)
+	}
+
+	return lm
+}
+
+// testConfigFiles rejects the configs with the broken directive and the configs that include a missing secret.
+func testConfigFiles(t *testing.T, lm *LocalManager) error {
+	t.Helper()
+
+	for _, dir := range []string{lm.confdPath, lm.streamConfdPath} {
+		files, err := filepath.Glob(path.Join(dir, "*"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			content, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(content), "broken") {
+				return errBrokenConfig
+			}
+			if secret, found := strings.CutPrefix(string(content), "secret "); found {
+				if _, err := os.Stat(lm.GetFilenameForSecret(secret)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func readTestFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ""
+		}
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func TestRollbackInvalidChanges(t *testing.T) {
+	t.Parallel()
+
+	lm := newTestLocalManager(t)
+
+	// the state at the last successful reload
+	lm.CreateMainConfig([]byte("main"))
+	lm.CreateConfig("vs_default_cafe", []byte("cafe"))
+	lm.CreateConfig("vs_default_tea", []byte("tea"))
+	lm.CreateConfig("vs_default_juice", []byte("secret default-juice-secret"))
+	lm.CreateSecret("default-juice-secret", []byte("juice secret"), TLSSecretFileMode)
+	lm.CreateStreamConfig("ts_default_dns", []byte("dns"))
+	lm.clearConfigSnapshots()
+
+	// the changes of the failed reload
+	lm.CreateMainConfig([]byte("new main"))
+	lm.CreateConfig("vs_default_cafe", []byte("broken cafe"))
+	lm.CreateConfig("vs_default_cafe", []byte("another broken cafe"))
+	lm.DeleteConfig("vs_default_tea")
+	lm.CreateConfig("vs_default_coffee", []byte("secret default-coffee-secret"))
+	lm.CreateSecret("default-coffee-secret", []byte("coffee secret"), TLSSecretFileMode)
+	lm.DeleteSecret("default-juice-secret")
+	lm.DeleteStreamConfig("ts_default_dns")
+
+	invalidChanges := lm.rollbackInvalidChanges()
+
+	expectedFiles := map[string]string{
+		lm.mainConfFilename:                                 "new main",
+		lm.getFilenameForConfig("vs_default_cafe"):          "cafe",
+		lm.getFilenameForConfig("vs_default_tea"):           "",
+		lm.getFilenameForConfig("vs_default_coffee"):        "secret default-coffee-secret",
+		lm.GetFilenameForSecret("default-coffee-secret"):    "coffee secret",
+		lm.getFilenameForConfig("vs_default_juice"):         "secret default-juice-secret",
+		lm.GetFilenameForSecret("default-juice-secret"):     "juice secret",
+		lm.getFilenameForStreamConfig("ts_default_dns"):     "",
+		lm.getFilenameForStreamConfig("ts_default_missing"): "",
+	}
+	for filename, expected := range expectedFiles {
+		if content := readTestFile(t, filename); content != expected {
+			t.Errorf("rollbackInvalidChanges() left %v with %q, want %q", filename, content, expected)
+		}
+	}
+
+	info, err := os.Stat(lm.GetFilenameForSecret("default-coffee-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != TLSSecretFileMode {
+		t.Errorf("rollbackInvalidChanges() wrote the secret with mode %v, want %v", info.Mode().Perm(), os.FileMode(TLSSecretFileMode))
+	}
+
+	if len(invalidChanges) != 2 {
+		t.Errorf("rollbackInvalidChanges() returned %v, want the errors for the cafe config and the juice secret", invalidChanges)
+	}
+	if !errors.Is(invalidChanges["vs_default_cafe"], errBrokenConfig) {
+		t.Errorf("rollbackInvalidChanges() returned %v for the cafe config, want %v", invalidChanges["vs_default_cafe"], errBrokenConfig)
+	}
+	if _, exists := invalidChanges[lm.GetFilenameForSecret("default-juice-secret")]; !exists {
+		t.Errorf("rollbackInvalidChanges() didn't return the error for the deletion of the secret used by the juice config")
+	}
+}
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	reloadErr := errors.New("nginx reload failed")
+
+	tests := []struct {
+		reloadErrs          []error
+		cafeChange          string
+		expectedErr         string
+		expectedInvalid     []string
+		expectedCafeContent string
+		expectedTeaContent  string
+		msg                 string
+	}{
+		{
+			reloadErrs:          []error{nil},
+			cafeChange:          "new cafe",
+			expectedCafeContent: "new cafe",
+			expectedTeaContent:  "new tea",
+			msg:                 "successful reload",
+		},
+		{
+			reloadErrs:          []error{reloadErr, nil},
+			cafeChange:          "broken cafe",
+			expectedErr:         "nginx reload failed; the changes rejected by NGINX were rolled back",
+			expectedInvalid:     []string{"vs_default_cafe"},
+			expectedCafeContent: "cafe",
+			expectedTeaContent:  "new tea",
+			msg:                 "only the rejected change is rolled back",
+		},
+		{
+			reloadErrs:          []error{reloadErr, errors.New("nginx is down")},
+			cafeChange:          "broken cafe",
+			expectedErr:         "nginx reload failed; rolling back the changes rejected by NGINX failed: nginx is down",
+			expectedCafeContent: "cafe",
+			expectedTeaContent:  "new tea",
+			msg:                 "failed reload and failed rollback",
+		},
+		{
+			reloadErrs:          []error{reloadErr, nil},
+			cafeChange:          "new cafe",
+			expectedErr:         "nginx reload failed",
+			expectedCafeContent: "new cafe",
+			expectedTeaContent:  "new tea",
+			msg:                 "failed reload without rejected changes",
+		},
+	}
+
+	for _, test := range tests {
+		lm := newTestLocalManager(t)
+
+		// the state at the last successful reload
+		lm.CreateConfig("vs_default_cafe", []byte("cafe"))
+		lm.CreateConfig("vs_default_tea", []byte("tea"))
+		lm.clearConfigSnapshots()
+
+		lm.CreateConfig("vs_default_cafe", []byte(test.cafeChange))
+		lm.CreateConfig("vs_default_tea", []byte("new tea"))
+
+		var reloads int
+		lm.reloadFunc = func(_ bool) error {
+			reloads++
+			return test.reloadErrs[reloads-1]
+		}
+
+		err := lm.Reload(false)
+		if test.expectedErr == "" && err != nil {
+			t.Errorf("Reload() returned error %v for the case of %s", err, test.msg)
+		}
+		if test.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), test.expectedErr)) {
+			t.Errorf("Reload() returned error %v, want %q for the case of %s", err, test.expectedErr, test.msg)
+		}
+		if test.expectedErr != "" && !errors.Is(err, reloadErr) {
+			t.Errorf("Reload() returned error %v that doesn't wrap the error of the failed reload for the case of %s", err, test.msg)
+		}
+
+		var rollbackErr *ConfigRollbackError
+		if len(test.expectedInvalid) > 0 {
+			if !errors.As(err, &rollbackErr) {
+				t.Fatalf("Reload() returned error %v, want a ConfigRollbackError for the case of %s", err, test.msg)
+			}
+			for _, name := range test.expectedInvalid {
+				if _, exists := rollbackErr.InvalidConfigs[name]; !exists {
+					t.Errorf("Reload() didn't report the rejected config %s for the case of %s", name, test.msg)
+				}
+			}
+			if len(rollbackErr.InvalidConfigs) != len(test.expectedInvalid) {
+				t.Errorf("Reload() reported the rejected configs %v, want %v for the case of %s", rollbackErr.InvalidConfigs, test.expectedInvalid, test.msg)
+			}
+		} else if errors.As(err, &rollbackErr) {
+			t.Errorf("Reload() returned a ConfigRollbackError %v for the case of %s", err, test.msg)
+		}
+
+		if reloads != len(test.reloadErrs) {
+			t.Errorf("Reload() reloaded NGINX %d times, want %d for the case of %s", reloads, len(test.reloadErrs), test.msg)
+		}
+
+		if content := readTestFile(t, lm.getFilenameForConfig("vs_default_cafe")); content != test.expectedCafeContent {
+			t.Errorf("Reload() left the cafe config with %q, want %q for the case of %s", content, test.expectedCafeContent, test.msg)
+		}
+		if content := readTestFile(t, lm.getFilenameForConfig("vs_default_tea")); content != test.expectedTeaContent {
+			t.Errorf("Reload() left the tea config with %q, want %q for the case of %s", content, test.expectedTeaContent, test.msg)
+		}
+
+		// the snapshots of the last successful reload are kept when the rollback fails
+		if rollbackFailed := len(test.reloadErrs) > 1 && test.reloadErrs[1] != nil; rollbackFailed != (len(lm.configSnapshots) > 0) {
+			t.Errorf("Reload() left the snapshots %v for the case of %s", lm.configSnapshots, test.msg)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	methodNotAllowedCode = 405
+	methodNotAllowedType = "text/plain"
+	methodNotAllowedBody = "Method Not Allowed"
+)
+
+// Options defines the metadata and the upstream of the generated resources.
+type Options struct {
+	Name      string
+	Namespace string
+	Host      string
+	Service   string
+	Port      uint16
+}
+
+// GenerateVirtualServer generates a VirtualServer with a route for every path of the OpenAPI document.
+// The requests with the methods of the operations of a path are passed to the service.
+// All other requests are answered with a 405 response.
+func GenerateVirtualServer(doc *Document, opts Options) (*conf_v1.VirtualServer, error) {
+	routes, err := generateRoutes(doc, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := &conf_v1.VirtualServer{
+		TypeMeta: meta_v1.TypeMeta{
+			APIVersion: "k8s.nginx.org/v1",
+			Kind:       "VirtualServer",
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host:      opts.Host,
+			Upstreams: generateUpstreams(opts),
+			Routes:    routes,
+		},
+	}
+
+	if err := validation.NewVirtualServerValidator().ValidateVirtualServer(vs); err != nil {
+		return nil, fmt.Errorf("generated VirtualServer is invalid: %w", err)
+	}
+
+	return vs, nil
+}
+
+// GenerateVirtualServerRoute generates a VirtualServerRoute with a subroute for every path of the OpenAPI document.
+// The subroutes are generated in the same way as the routes of GenerateVirtualServer.
+func GenerateVirtualServerRoute(doc *Document, opts Options) (*conf_v1.VirtualServerRoute, error) {
+	routes, err := generateRoutes(doc, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	vsr := &conf_v1.VirtualServerRoute{
+		TypeMeta: meta_v1.TypeMeta{
+			APIVersion: "k8s.nginx.org/v1",
+			Kind:       "VirtualServerRoute",
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Host:      opts.Host,
+			Upstreams: generateUpstreams(opts),
+			Subroutes: routes,
+		},
+	}
+
+	if err := validation.NewVirtualServerValidator().ValidateVirtualServerRoute(vsr); err != nil {
+		return nil, fmt.Errorf("generated VirtualServerRoute is invalid: %w", err)
+	}
+
+	return vsr, nil
+}
+
+func generateUpstreams(opts Options) []conf_v1.Upstream {
+	return []conf_v1.Upstream{
+		{
+			Name:    opts.Service,
+			Service: opts.Service,
+			Port:    opts.Port,
+		},
+	}
+}
+
+type pathRoute struct {
+	path  string
+	regex *regexp.Regexp
+	item  PathItem
+}
+
+func generateRoutes(doc *Document, upstream string) ([]conf_v1.Route, error) {
+	var pathRoutes []pathRoute
+	templates := make(map[string]string)
+
+	for path, item := range doc.Paths {
+		if len(item.Methods()) == 0 {
+			continue
+		}
+
+		regex, err := convertPathTemplate(path)
+		if err != nil {
+			return nil, err
+		}
+		if regex != nil {
+			if other, exists := templates[regex.String()]; exists {
+				return nil, fmt.Errorf("paths %q and %q match the same requests", other, path)
+			}
+			templates[regex.String()] = path
+		}
+
+		pathRoutes = append(pathRoutes, pathRoute{
+			path:  path,
+			regex: regex,
+			item:  item,
+		})
+	}
+
+	if len(pathRoutes) == 0 {
+		return nil, fmt.Errorf("OpenAPI document has no operations")
+	}
+
+	sortPathRoutes(pathRoutes)
+
+	var routes []conf_v1.Route
+	for _, pr := range groupPrefixPathRoutes(pathRoutes) {
+		routes = append(routes, generateRoute(generateRoutePath(pr, pathRoutes), pr.item.Methods(), upstream))
+	}
+
+	return routes, nil
+}
+
+// groupPrefixPathRoutes groups the paths that become prefix paths by their first segment, so that a single route
+// is generated for every prefix. The path of a group is the longest common prefix of its paths that ends at
+// a segment boundary, and its methods are the methods of all its paths.
+// A group keeps the position of its first path.
+func groupPrefixPathRoutes(pathRoutes []pathRoute) []pathRoute {
+	var result []pathRoute
+	groups := make(map[string]int)
+
+	for _, pr := range pathRoutes {
+		if pr.regex != nil || isMatchedByRegex(pr.path, pathRoutes) {
+			result = append(result, pr)
+			continue
+		}
+
+		firstSegment := strings.SplitN(strings.TrimPrefix(pr.path, "/"), "/", 2)[0]
+		i, exists := groups[firstSegment]
+		if !exists {
+			groups[firstSegment] = len(result)
+			result = append(result, pr)
+			continue
+		}
+
+		prefix := commonPathPrefix(result[i].path, pr.path)
+		if isMatchedByRegex(prefix, pathRoutes) {
+			// the regex location would take the requests of the prefix, so the path gets its own route
+			result = append(result, pr)
+			continue
+		}
+
+		result[i].path = prefix
+		result[i].item = result[i].item.merge(pr.item)
+	}
+
+	return result
+}
+
+// commonPathPrefix returns the longest common prefix of the paths that ends at a segment boundary.
+// For example, /jobs for /jobs and /jobs/latest, and /jobs/ for /jobs/latest and /jobs/oldest.
+func commonPathPrefix(a, b string) string {
+	aSegments := strings.Split(a, "/")
+	bSegments := strings.Split(b, "/")
+
+	n := 0
+	for n < len(aSegments) && n < len(bSegments) && aSegments[n] == bSegments[n] {
+		n++
+	}
+
+	prefix := strings.Join(aSegments[:n], "/")
+	if prefix == a || prefix == b {
+		return prefix
+	}
+	return prefix + "/"
+}
+
+func isMatchedByRegex(path string, pathRoutes []pathRoute) bool {
+	for _, pr := range pathRoutes {
+		if pr.regex != nil && pr.regex.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func generateRoute(path string, methods []string, upstream string) conf_v1.Route {
+	var matches []conf_v1.Match
+	for _, m := range methods {
+		matches = append(matches, conf_v1.Match{
+			Conditions: []conf_v1.Condition{
+				{
+					Variable: "$request_method",
+					Value:    m,
+				},
+			},
+			Action: &conf_v1.Action{
+				Pass: upstream,
+			},
+		})
+	}
+
+	return conf_v1.Route{
+		Path:    path,
+		Matches: matches,
+		Action: &conf_v1.Action{
+			Return: &conf_v1.ActionReturn{
+				Code: methodNotAllowedCode,
+				Type: methodNotAllowedType,
+				Body: methodNotAllowedBody,
+			},
+		},
+	}
+}
+
+// generateRoutePath generates the path of the route of a path or a group of paths of the OpenAPI document.
+// A path template becomes a regex path. Any other path becomes a prefix path, unless one of
+// the regex paths matches it. In that case, it becomes an exact path, because NGINX prefers
+// regex locations to prefix locations.
+func generateRoutePath(pr pathRoute, pathRoutes []pathRoute) string {
+	if pr.regex != nil {
+		return "~ " + pr.regex.String()
+	}
+
+	if isMatchedByRegex(pr.path, pathRoutes) {
+		return "=" + pr.path
+	}
+
+	return pr.path
+}
+
+var pathParameterRegexp = regexp.MustCompile(`\{[^{}/]+\}`)
+
+// convertPathTemplate converts a path template like /jobs/{id} to the regular expression ^/jobs/[^/]+$.
+// It returns nil for paths without parameters.
+func convertPathTemplate(path string) (*regexp.Regexp, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path %q must start with /", path)
+	}
+
+	if !strings.ContainsAny(path, "{}") {
+		return nil, nil
+	}
+
+	segments := pathParameterRegexp.Split(path, -1)
+	for i, s := range segments {
+		if strings.ContainsAny(s, "{}") {
+			return nil, fmt.Errorf("path %q has an invalid path template", path)
+		}
+		segments[i] = regexp.QuoteMeta(s)
+	}
+
+	return regexp.Compile("^" + strings.Join(segments, "[^/]+") + "$")
+}
+
+// sortPathRoutes sorts the paths, so that the generated routes don't depend on the order of the document.
+// Regex paths are sorted by the length of their literal parts in the descending order, so that
+// the more specific regex locations are checked by NGINX first.
+func sortPathRoutes(pathRoutes []pathRoute) {
+	sort.Slice(pathRoutes, func(i, j int) bool {
+		a, b := pathRoutes[i], pathRoutes[j]
+		if (a.regex == nil) != (b.regex == nil) {
+			return a.regex == nil
+		}
+		if a.regex != nil {
+			aLen := len(pathParameterRegexp.ReplaceAllString(a.path, ""))
+			bLen := len(pathParameterRegexp.ReplaceAllString(b.path, ""))
+			if aLen != bLen {
+				return aLen > bLen
+			}
+		}
+		return a.path < b.path
+	})
+}
@@ -0,0 +1,316 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	conf_v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var jobsAPI = &Document{
+	OpenAPI: "3.0.3",
+	Paths: map[string]PathItem{
+		"/jobs": {
+			Get:  &Operation{},
+			Post: &Operation{},
+		},
+		"/jobs/{id}": {
+			Get:    &Operation{},
+			Delete: &Operation{},
+		},
+	},
+}
+
+var jobsOptions = Options{
+	Name:      "jobs",
+	Namespace: "default",
+	Host:      "jobs.example.com",
+	Service:   "jobs-svc",
+	Port:      80,
+}
+
+func createMethodMatch(method string, upstream string) conf_v1.Match {
+	return conf_v1.Match{
+		Conditions: []conf_v1.Condition{
+			{
+				Variable: "$request_method",
+				Value:    method,
+			},
+		},
+		Action: &conf_v1.Action{
+			Pass: upstream,
+		},
+	}
+}
+
+var methodNotAllowedAction = &conf_v1.Action{
+	Return: &conf_v1.ActionReturn{
+		Code: 405,
+		Type: "text/plain",
+		Body: "Method Not Allowed",
+	},
+}
+
+func TestGenerateVirtualServer(t *testing.T) {
+	t.Parallel()
+	expected := &conf_v1.VirtualServer{
+		TypeMeta: meta_v1.TypeMeta{
+			APIVersion: "k8s.nginx.org/v1",
+			Kind:       "VirtualServer",
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "jobs",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Host: "jobs.example.com",
+			Upstreams: []conf_v1.Upstream{
+				{
+					Name:    "jobs-svc",
+					Service: "jobs-svc",
+					Port:    80,
+				},
+			},
+			Routes: []conf_v1.Route{
+				{
+					Path: "/jobs",
+					Matches: []conf_v1.Match{
+						createMethodMatch("GET", "jobs-svc"),
+						createMethodMatch("POST", "jobs-svc"),
+					},
+					Action: methodNotAllowedAction,
+				},
+				{
+					Path: "~ ^/jobs/[^/]+$",
+					Matches: []conf_v1.Match{
+						createMethodMatch("GET", "jobs-svc"),
+						createMethodMatch("DELETE", "jobs-svc"),
+					},
+					Action: methodNotAllowedAction,
+				},
+			},
+		},
+	}
+
+	result, err := GenerateVirtualServer(jobsAPI, jobsOptions)
+	if err != nil {
+		t.Fatalf("GenerateVirtualServer() returned unexpected error %v", err)
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("GenerateVirtualServer() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerRoute(t *testing.T) {
+	t.Parallel()
+	expected := &conf_v1.VirtualServerRoute{
+		TypeMeta: meta_v1.TypeMeta{
+			APIVersion: "k8s.nginx.org/v1",
+			Kind:       "VirtualServerRoute",
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "jobs",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Host: "jobs.example.com",
+			Upstreams: []conf_v1.Upstream{
+				{
+					Name:    "jobs-svc",
+					Service: "jobs-svc",
+					Port:    80,
+				},
+			},
+			Subroutes: []conf_v1.Route{
+				{
+					Path: "/jobs",
+					Matches: []conf_v1.Match{
+						createMethodMatch("GET", "jobs-svc"),
+						createMethodMatch("POST", "jobs-svc"),
+					},
+					Action: methodNotAllowedAction,
+				},
+				{
+					Path: "~ ^/jobs/[^/]+$",
+					Matches: []conf_v1.Match{
+						createMethodMatch("GET", "jobs-svc"),
+						createMethodMatch("DELETE", "jobs-svc"),
+					},
+					Action: methodNotAllowedAction,
+				},
+			},
+		},
+	}
+
+	result, err := GenerateVirtualServerRoute(jobsAPI, jobsOptions)
+	if err != nil {
+		t.Fatalf("GenerateVirtualServerRoute() returned unexpected error %v", err)
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("GenerateVirtualServerRoute() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		doc  *Document
+		opts Options
+		msg  string
+	}{
+		{
+			doc: &Document{
+				Paths: map[string]PathItem{
+					"/jobs": {},
+				},
+			},
+			opts: jobsOptions,
+			msg:  "no operations",
+		},
+		{
+			doc: &Document{
+				Paths: map[string]PathItem{
+					"/jobs/{id}":    {Get: &Operation{}},
+					"/jobs/{jobId}": {Delete: &Operation{}},
+				},
+			},
+			opts: jobsOptions,
+			msg:  "conflicting path templates",
+		},
+		{
+			doc: &Document{
+				Paths: map[string]PathItem{
+					"/jobs/{id": {Get: &Operation{}},
+				},
+			},
+			opts: jobsOptions,
+			msg:  "invalid path template",
+		},
+		{
+			doc: jobsAPI,
+			opts: Options{
+				Name:      "jobs",
+				Namespace: "default",
+				Host:      "jobs.example.com",
+				Service:   "jobs_svc",
+				Port:      80,
+			},
+			msg: "invalid service name",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := GenerateVirtualServer(test.doc, test.opts)
+		if err == nil {
+			t.Errorf("GenerateVirtualServer() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestGenerateRoutePath(t *testing.T) {
+	t.Parallel()
+	doc := &Document{
+		Paths: map[string]PathItem{
+			"/jobs":              {Get: &Operation{}},
+			"/jobs/latest":       {Get: &Operation{}},
+			"/jobs/{id}":         {Get: &Operation{}},
+			"/jobs/{id}/logs":    {Get: &Operation{}},
+			"/jobs/{id}.json":    {Get: &Operation{}},
+			"/jobs/{id}/done":    {},
+			"/workers/{name}/v1": {Get: &Operation{}},
+		},
+	}
+
+	expected := []string{
+		"/jobs",
+		"=/jobs/latest",
+		`~ ^/workers/[^/]+/v1$`,
+		`~ ^/jobs/[^/]+\.json$`,
+		`~ ^/jobs/[^/]+/logs$`,
+		"~ ^/jobs/[^/]+$",
+	}
+
+	routes, err := generateRoutes(doc, "jobs-svc")
+	if err != nil {
+		t.Fatalf("generateRoutes() returned unexpected error %v", err)
+	}
+
+	var result []string
+	for _, r := range routes {
+		result = append(result, r.Path)
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateRoutes() paths mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateRoutesGroupsPrefixPaths(t *testing.T) {
+	t.Parallel()
+	doc := &Document{
+		Paths: map[string]PathItem{
+			"/jobs":            {Get: &Operation{}, Post: &Operation{}},
+			"/jobs/search":     {Get: &Operation{}},
+			"/jobs/stats/last": {Head: &Operation{}},
+			"/workers/active":  {Get: &Operation{}},
+			"/workers/idle":    {Put: &Operation{}},
+			"/health":          {Get: &Operation{}},
+		},
+	}
+
+	expected := []conf_v1.Route{
+		{
+			Path: "/health",
+			Matches: []conf_v1.Match{
+				createMethodMatch("GET", "jobs-svc"),
+			},
+			Action: methodNotAllowedAction,
+		},
+		{
+			Path: "/jobs",
+			Matches: []conf_v1.Match{
+				createMethodMatch("GET", "jobs-svc"),
+				createMethodMatch("POST", "jobs-svc"),
+				createMethodMatch("HEAD", "jobs-svc"),
+			},
+			Action: methodNotAllowedAction,
+		},
+		{
+			Path: "/workers/",
+			Matches: []conf_v1.Match{
+				createMethodMatch("GET", "jobs-svc"),
+				createMethodMatch("PUT", "jobs-svc"),
+			},
+			Action: methodNotAllowedAction,
+		},
+	}
+
+	routes, err := generateRoutes(doc, "jobs-svc")
+	if err != nil {
+		t.Fatalf("generateRoutes() returned unexpected error %v", err)
+	}
+	if diff := cmp.Diff(expected, routes); diff != "" {
+		t.Errorf("generateRoutes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommonPathPrefix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a        string
+		b        string
+		expected string
+	}{
+		{a: "/jobs", b: "/jobs/latest", expected: "/jobs"},
+		{a: "/jobs/latest", b: "/jobs", expected: "/jobs"},
+		{a: "/jobs/latest", b: "/jobs/oldest", expected: "/jobs/"},
+		{a: "/jobs/stats/daily", b: "/jobs/stats/weekly", expected: "/jobs/stats/"},
+		{a: "/jobs/", b: "/jobs/latest", expected: "/jobs/"},
+	}
+
+	for _, test := range tests {
+		if result := commonPathPrefix(test.a, test.b); result != test.expected {
+			t.Errorf("commonPathPrefix(%q, %q) returned %q but expected %q", test.a, test.b, result, test.expected)
+		}
+	}
+}
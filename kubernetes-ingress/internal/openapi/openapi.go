@@ -0,0 +1,120 @@
+// Package openapi generates VirtualServer and VirtualServerRoute resources from OpenAPI 3 documents.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Document is an OpenAPI 3 document.
+// Only the fields that are required to generate routes are parsed.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info is the metadata of an OpenAPI document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Get     *Operation `json:"get"`
+	Put     *Operation `json:"put"`
+	Post    *Operation `json:"post"`
+	Delete  *Operation `json:"delete"`
+	Options *Operation `json:"options"`
+	Head    *Operation `json:"head"`
+	Patch   *Operation `json:"patch"`
+	Trace   *Operation `json:"trace"`
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string `json:"operationId"`
+	Summary     string `json:"summary"`
+}
+
+// Methods returns the HTTP methods of the operations of the path item in a fixed order.
+func (p PathItem) Methods() []string {
+	operations := []struct {
+		method    string
+		operation *Operation
+	}{
+		{"GET", p.Get},
+		{"PUT", p.Put},
+		{"POST", p.Post},
+		{"DELETE", p.Delete},
+		{"OPTIONS", p.Options},
+		{"HEAD", p.Head},
+		{"PATCH", p.Patch},
+		{"TRACE", p.Trace},
+	}
+
+	var methods []string
+	for _, o := range operations {
+		if o.operation != nil {
+			methods = append(methods, o.method)
+		}
+	}
+	return methods
+}
+
+// merge returns a path item with the operations of both path items.
+func (p PathItem) merge(other PathItem) PathItem {
+	if p.Get == nil {
+		p.Get = other.Get
+	}
+	if p.Put == nil {
+		p.Put = other.Put
+	}
+	if p.Post == nil {
+		p.Post = other.Post
+	}
+	if p.Delete == nil {
+		p.Delete = other.Delete
+	}
+	if p.Options == nil {
+		p.Options = other.Options
+	}
+	if p.Head == nil {
+		p.Head = other.Head
+	}
+	if p.Patch == nil {
+		p.Patch = other.Patch
+	}
+	if p.Trace == nil {
+		p.Trace = other.Trace
+	}
+	return p
+}
+
+// Parse parses an OpenAPI 3 document in the YAML or JSON format.
+func Parse(data []byte) (*Document, error) {
+	// JSON is a subset of YAML, so both formats are converted to JSON first.
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI document: %w", err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI document: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, must be 3.x", doc.OpenAPI)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("OpenAPI document has no paths")
+	}
+
+	return &doc, nil
+}
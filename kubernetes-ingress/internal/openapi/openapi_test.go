@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		data     string
+		expected *Document
+		msg      string
+	}{
+		{
+			data: `
+openapi: 3.0.3
+info:
+  title: Jobs API
+  version: 1.0.0
+paths:
+  /jobs:
+    get:
+      operationId: listJobs
+    post:
+      operationId: createJob
+`,
+			expected: &Document{
+				OpenAPI: "3.0.3",
+				Info: Info{
+					Title:   "Jobs API",
+					Version: "1.0.0",
+				},
+				Paths: map[string]PathItem{
+					"/jobs": {
+						Get: &Operation{
+							OperationID: "listJobs",
+						},
+						Post: &Operation{
+							OperationID: "createJob",
+						},
+					},
+				},
+			},
+			msg: "yaml document",
+		},
+		{
+			data: `{"openapi": "3.1.0", "info": {"title": "Jobs API"}, "paths": {"/jobs/{id}": {"delete": {"summary": "Delete a job"}}}}`,
+			expected: &Document{
+				OpenAPI: "3.1.0",
+				Info: Info{
+					Title: "Jobs API",
+				},
+				Paths: map[string]PathItem{
+					"/jobs/{id}": {
+						Delete: &Operation{
+							Summary: "Delete a job",
+						},
+					},
+				},
+			},
+			msg: "json document",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := Parse([]byte(test.data))
+		if err != nil {
+			t.Errorf("Parse() returned unexpected error %v for the case of %s", err, test.msg)
+		}
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("Parse() '%s' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestParseFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		data string
+		msg  string
+	}{
+		{
+			data: `openapi: [3.0.3`,
+			msg:  "invalid yaml",
+		},
+		{
+			data: `
+swagger: "2.0"
+paths:
+  /jobs:
+    get: {}
+`,
+			msg: "unsupported version",
+		},
+		{
+			data: `openapi: 3.0.3`,
+			msg:  "no paths",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := Parse([]byte(test.data))
+		if err == nil {
+			t.Errorf("Parse() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestPathItemMethods(t *testing.T) {
+	t.Parallel()
+	item := PathItem{
+		Patch:  &Operation{},
+		Get:    &Operation{},
+		Delete: &Operation{},
+	}
+
+	expected := []string{"GET", "DELETE", "PATCH"}
+
+	result := item.Methods()
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Methods() mismatch (-want +got):\n%s", diff)
+	}
+}
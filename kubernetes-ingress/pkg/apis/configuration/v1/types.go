@@ -58,6 +58,8 @@ type VirtualServerSpec struct {
 type Listener struct {
 	HTTP  string `json:"http"`
 	HTTPS string `json:"https"`
+	// Additional references further http and https listeners defined in GlobalConfiguration.
+	Additional []string `json:"additional"`
 }
 
 // ExternalDNS defines externaldns sub-resource of a virtual server.
@@ -121,6 +123,7 @@ type Upstream struct {
 	UseClusterIP             bool              `json:"use-cluster-ip"`
 	NTLM                     bool              `json:"ntlm"`
 	Type                     string            `json:"type"`
+	ForwardedHeaders         bool              `json:"forwarded-headers"`
 }
 
 // UpstreamBuffers defines Buffer Configuration for an Upstream.
@@ -181,6 +184,7 @@ type Route struct {
 	Route            string            `json:"route"`
 	Action           *Action           `json:"action"`
 	Splits           []Split           `json:"splits"`
+	SplitKey         string            `json:"splitKey"`
 	Matches          []Match           `json:"matches"`
 	ErrorPages       []ErrorPage       `json:"errorPages"`
 	LocationSnippets string            `json:"location-snippets"`
@@ -214,6 +218,14 @@ type ActionProxy struct {
 	RewritePath     string                `json:"rewritePath"`
 	RequestHeaders  *ProxyRequestHeaders  `json:"requestHeaders"`
 	ResponseHeaders *ProxyResponseHeaders `json:"responseHeaders"`
+	Mirror          *ProxyMirror          `json:"mirror"`
+}
+
+// ProxyMirror defines the mirroring of requests to a second upstream in an ActionProxy.
+// The responses of the mirror upstream are ignored.
+type ProxyMirror struct {
+	Upstream   string `json:"upstream"`
+	Percentage *int   `json:"percentage"`
 }
 
 // ProxyRequestHeaders defines the request headers manipulation in an ActionProxy.
@@ -256,6 +268,7 @@ type Match struct {
 	Conditions []Condition `json:"conditions"`
 	Action     *Action     `json:"action"`
 	Splits     []Split     `json:"splits"`
+	SplitKey   string      `json:"splitKey"`
 }
 
 // ErrorPage defines an ErrorPage in a Route.
@@ -281,6 +294,8 @@ type TLS struct {
 	Secret      string       `json:"secret"`
 	Redirect    *TLSRedirect `json:"redirect"`
 	CertManager *CertManager `json:"cert-manager"`
+	// HTTP3 overrides the http3 ConfigMap key for the VirtualServer.
+	HTTP3 *bool `json:"http3"`
 }
 
 // TLSRedirect defines a redirect for a TLS.
@@ -308,6 +323,8 @@ type VirtualServerStatus struct {
 	Reason            string             `json:"reason"`
 	Message           string             `json:"message"`
 	ExternalEndpoints []ExternalEndpoint `json:"externalEndpoints,omitempty"`
+	// Listeners lists the names of the custom listeners the VirtualServer is bound to.
+	Listeners []string `json:"listeners,omitempty"`
 }
 
 // ExternalEndpoint defines the IP/ Hostname and ports used to connect to this resource.
@@ -410,15 +427,21 @@ type PolicyStatus struct {
 // The spec includes multiple fields, where each field represents a different policy.
 // Only one policy (field) is allowed.
 type PolicySpec struct {
-	IngressClass  string         `json:"ingressClassName"`
-	AccessControl *AccessControl `json:"accessControl"`
-	RateLimit     *RateLimit     `json:"rateLimit"`
-	JWTAuth       *JWTAuth       `json:"jwt"`
-	BasicAuth     *BasicAuth     `json:"basicAuth"`
-	IngressMTLS   *IngressMTLS   `json:"ingressMTLS"`
-	EgressMTLS    *EgressMTLS    `json:"egressMTLS"`
-	OIDC          *OIDC          `json:"oidc"`
-	WAF           *WAF           `json:"waf"`
+	IngressClass    string           `json:"ingressClassName"`
+	AccessControl   *AccessControl   `json:"accessControl"`
+	RateLimit       *RateLimit       `json:"rateLimit"`
+	JWTAuth         *JWTAuth         `json:"jwt"`
+	BasicAuth       *BasicAuth       `json:"basicAuth"`
+	IngressMTLS     *IngressMTLS     `json:"ingressMTLS"`
+	EgressMTLS      *EgressMTLS      `json:"egressMTLS"`
+	OIDC            *OIDC            `json:"oidc"`
+	WAF             *WAF             `json:"waf"`
+	CORS            *CORS            `json:"cors"`
+	ExternalAuth    *ExternalAuth    `json:"externalAuth"`
+	APIKey          *APIKey          `json:"apiKey"`
+	ConnectionLimit *ConnectionLimit `json:"connectionLimit"`
+	Geo             *Geo             `json:"geo"`
+	BandwidthLimit  *BandwidthLimit  `json:"bandwidthLimit"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -437,26 +460,92 @@ type AccessControl struct {
 	Deny  []string `json:"deny"`
 }
 
+// ConnectionLimit defines a connection limit policy for TransportServers.
+type ConnectionLimit struct {
+	Key         string `json:"key"`
+	Connections int    `json:"connections"`
+	ZoneSize    string `json:"zoneSize"`
+	DryRun      *bool  `json:"dryRun"`
+	LogLevel    string `json:"logLevel"`
+}
+
+// BandwidthLimit defines a policy that limits the number of concurrent requests per key
+// and the bandwidth of the responses.
+type BandwidthLimit struct {
+	Key         string `json:"key"`
+	Connections int    `json:"connections"`
+	ZoneSize    string `json:"zoneSize"`
+	DryRun      *bool  `json:"dryRun"`
+	LogLevel    string `json:"logLevel"`
+	RejectCode  *int   `json:"rejectCode"`
+	Rate        string `json:"rate"`
+	RateAfter   string `json:"rateAfter"`
+}
+
+// Geo defines a geolocation policy. The country and the autonomous system of a client are looked up
+// in GeoIP2 databases mounted into the NGINX Ingress Controller pod.
+type Geo struct {
+	CountryDatabase string   `json:"countryDatabase"`
+	ASNDatabase     string   `json:"asnDatabase"`
+	AllowCountries  []string `json:"allowCountries"`
+	DenyCountries   []string `json:"denyCountries"`
+	AllowASNs       []int    `json:"allowASNs"`
+	DenyASNs        []int    `json:"denyASNs"`
+	UnknownAction   string   `json:"unknownAction"`
+	RejectCode      *int     `json:"rejectCode"`
+	CountryHeader   string   `json:"countryHeader"`
+	ASNHeader       string   `json:"asnHeader"`
+}
+
 // RateLimit defines a rate limit policy.
 type RateLimit struct {
-	Rate       string `json:"rate"`
-	Key        string `json:"key"`
-	Delay      *int   `json:"delay"`
-	NoDelay    *bool  `json:"noDelay"`
-	Burst      *int   `json:"burst"`
-	ZoneSize   string `json:"zoneSize"`
-	DryRun     *bool  `json:"dryRun"`
-	LogLevel   string `json:"logLevel"`
-	RejectCode *int   `json:"rejectCode"`
+	Rate         string          `json:"rate"`
+	Key          string          `json:"key"`
+	Delay        *int            `json:"delay"`
+	NoDelay      *bool           `json:"noDelay"`
+	Burst        *int            `json:"burst"`
+	ZoneSize     string          `json:"zoneSize"`
+	DryRun       *bool           `json:"dryRun"`
+	LogLevel     string          `json:"logLevel"`
+	RejectCode   *int            `json:"rejectCode"`
+	Tiers        []RateLimitTier `json:"tiers"`
+	TierSelector string          `json:"tierSelector"`
+	DefaultTier  string          `json:"defaultTier"`
+}
+
+// RateLimitTier defines a tier of a rate limit policy.
+type RateLimitTier struct {
+	Name  string `json:"name"`
+	Rate  string `json:"rate"`
+	Burst *int   `json:"burst"`
 }
 
 // JWTAuth holds JWT authentication configuration.
 type JWTAuth struct {
-	Realm    string `json:"realm"`
-	Secret   string `json:"secret"`
-	Token    string `json:"token"`
-	JwksURI  string `json:"jwksURI"`
-	KeyCache string `json:"keyCache"`
+	Realm    string     `json:"realm"`
+	Secret   string     `json:"secret"`
+	Token    string     `json:"token"`
+	JwksURI  string     `json:"jwksURI"`
+	KeyCache string     `json:"keyCache"`
+	Claims   *JWTClaims `json:"claims"`
+}
+
+// JWTClaims defines the claims that a JWT must include for the request to be authorized.
+type JWTClaims struct {
+	Issuer     string          `json:"issuer"`
+	Audience   string          `json:"audience"`
+	Scopes     []string        `json:"scopes"`
+	Roles      []string        `json:"roles"`
+	RolesClaim string          `json:"rolesClaim"`
+	Match      []JWTClaimMatch `json:"match"`
+	RejectBody string          `json:"rejectBody"`
+}
+
+// JWTClaimMatch defines a claim that must be equal to the value or match the regular expression.
+type JWTClaimMatch struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Regex string `json:"regex"`
 }
 
 // BasicAuth holds HTTP Basic authentication configuration
@@ -489,16 +578,19 @@ type EgressMTLS struct {
 
 // OIDC defines an Open ID Connect policy.
 type OIDC struct {
-	AuthEndpoint      string   `json:"authEndpoint"`
-	TokenEndpoint     string   `json:"tokenEndpoint"`
-	JWKSURI           string   `json:"jwksURI"`
-	ClientID          string   `json:"clientID"`
-	ClientSecret      string   `json:"clientSecret"`
-	Scope             string   `json:"scope"`
-	RedirectURI       string   `json:"redirectURI"`
-	ZoneSyncLeeway    *int     `json:"zoneSyncLeeway"`
-	AuthExtraArgs     []string `json:"authExtraArgs"`
-	AccessTokenEnable bool     `json:"accessTokenEnable"`
+	AuthEndpoint          string   `json:"authEndpoint"`
+	TokenEndpoint         string   `json:"tokenEndpoint"`
+	JWKSURI               string   `json:"jwksURI"`
+	ClientID              string   `json:"clientID"`
+	ClientSecret          string   `json:"clientSecret"`
+	Scope                 string   `json:"scope"`
+	RedirectURI           string   `json:"redirectURI"`
+	ZoneSyncLeeway        *int     `json:"zoneSyncLeeway"`
+	AuthExtraArgs         []string `json:"authExtraArgs"`
+	AccessTokenEnable     bool     `json:"accessTokenEnable"`
+	EndSessionEndpoint    string   `json:"endSessionEndpoint"`
+	PostLogoutRedirectURI string   `json:"postLogoutRedirectURI"`
+	PKCEEnable            bool     `json:"pkceEnable"`
 }
 
 // WAF defines an WAF policy.
@@ -516,3 +608,37 @@ type SecurityLog struct {
 	ApLogConf string `json:"apLogConf"`
 	LogDest   string `json:"logDest"`
 }
+
+// CORS defines a Cross-Origin Resource Sharing policy.
+type CORS struct {
+	AllowOrigins     []string `json:"allowOrigins"`
+	AllowMethods     []string `json:"allowMethods"`
+	AllowHeaders     []string `json:"allowHeaders"`
+	ExposeHeaders    []string `json:"exposeHeaders"`
+	AllowCredentials *bool    `json:"allowCredentials"`
+	MaxAge           *int     `json:"maxAge"`
+}
+
+// ExternalAuth defines an external authorization policy. The requests are authorized by a subrequest to
+// the path of an auth service in the namespace of the policy.
+type ExternalAuth struct {
+	Service         string   `json:"service"`
+	Port            uint16   `json:"port"`
+	Path            string   `json:"path"`
+	RequestHeaders  []string `json:"requestHeaders"`
+	ResponseHeaders []string `json:"responseHeaders"`
+}
+
+// APIKey defines an API key authentication policy. The keys of the clients are stored
+// in a Secret of the type nginx.org/apikey in the namespace of the policy.
+type APIKey struct {
+	SuppliedIn   *SuppliedIn `json:"suppliedIn"`
+	ClientSecret string      `json:"clientSecret"`
+	RejectCode   *int        `json:"rejectCode"`
+}
+
+// SuppliedIn defines the request headers and query arguments where an API key is looked up.
+type SuppliedIn struct {
+	Header []string `json:"header"`
+	Query  []string `json:"query"`
+}
@@ -9,6 +9,32 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIKey) DeepCopyInto(out *APIKey) {
+	*out = *in
+	if in.SuppliedIn != nil {
+		in, out := &in.SuppliedIn, &out.SuppliedIn
+		*out = new(SuppliedIn)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RejectCode != nil {
+		in, out := &in.RejectCode, &out.RejectCode
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIKey.
+func (in *APIKey) DeepCopy() *APIKey {
+	if in == nil {
+		return nil
+	}
+	out := new(APIKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessControl) DeepCopyInto(out *AccessControl) {
 	*out = *in
@@ -79,6 +105,11 @@ func (in *ActionProxy) DeepCopyInto(out *ActionProxy) {
 		*out = new(ProxyResponseHeaders)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(ProxyMirror)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -141,6 +172,32 @@ func (in *AddHeader) DeepCopy() *AddHeader {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthLimit) DeepCopyInto(out *BandwidthLimit) {
+	*out = *in
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RejectCode != nil {
+		in, out := &in.RejectCode, &out.RejectCode
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthLimit.
+func (in *BandwidthLimit) DeepCopy() *BandwidthLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -157,6 +214,52 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORS) DeepCopyInto(out *CORS) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowMethods != nil {
+		in, out := &in.AllowMethods, &out.AllowMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExposeHeaders != nil {
+		in, out := &in.ExposeHeaders, &out.ExposeHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowCredentials != nil {
+		in, out := &in.AllowCredentials, &out.AllowCredentials
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORS.
+func (in *CORS) DeepCopy() *CORS {
+	if in == nil {
+		return nil
+	}
+	out := new(CORS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertManager) DeepCopyInto(out *CertManager) {
 	*out = *in
@@ -189,6 +292,27 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionLimit) DeepCopyInto(out *ConnectionLimit) {
+	*out = *in
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionLimit.
+func (in *ConnectionLimit) DeepCopy() *ConnectionLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressMTLS) DeepCopyInto(out *EgressMTLS) {
 	*out = *in
@@ -285,6 +409,32 @@ func (in *ErrorPageReturn) DeepCopy() *ErrorPageReturn {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAuth) DeepCopyInto(out *ExternalAuth) {
+	*out = *in
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAuth.
+func (in *ExternalAuth) DeepCopy() *ExternalAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNS) DeepCopyInto(out *ExternalDNS) {
 	*out = *in
@@ -329,6 +479,47 @@ func (in *ExternalEndpoint) DeepCopy() *ExternalEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Geo) DeepCopyInto(out *Geo) {
+	*out = *in
+	if in.AllowCountries != nil {
+		in, out := &in.AllowCountries, &out.AllowCountries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DenyCountries != nil {
+		in, out := &in.DenyCountries, &out.DenyCountries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowASNs != nil {
+		in, out := &in.AllowASNs, &out.AllowASNs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.DenyASNs != nil {
+		in, out := &in.DenyASNs, &out.DenyASNs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.RejectCode != nil {
+		in, out := &in.RejectCode, &out.RejectCode
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Geo.
+func (in *Geo) DeepCopy() *Geo {
+	if in == nil {
+		return nil
+	}
+	out := new(Geo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Header) DeepCopyInto(out *Header) {
 	*out = *in
@@ -400,6 +591,11 @@ func (in *IngressMTLS) DeepCopy() *IngressMTLS {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JWTAuth) DeepCopyInto(out *JWTAuth) {
 	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = new(JWTClaims)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -413,6 +609,74 @@ func (in *JWTAuth) DeepCopy() *JWTAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTClaimMatch) DeepCopyInto(out *JWTClaimMatch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTClaimMatch.
+func (in *JWTClaimMatch) DeepCopy() *JWTClaimMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTClaimMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTClaims) DeepCopyInto(out *JWTClaims) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make([]JWTClaimMatch, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTClaims.
+func (in *JWTClaims) DeepCopy() *JWTClaims {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTClaims)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Listener) DeepCopyInto(out *Listener) {
+	*out = *in
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Listener.
+func (in *Listener) DeepCopy() *Listener {
+	if in == nil {
+		return nil
+	}
+	out := new(Listener)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Match) DeepCopyInto(out *Match) {
 	*out = *in
@@ -565,7 +829,7 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 	if in.JWTAuth != nil {
 		in, out := &in.JWTAuth, &out.JWTAuth
 		*out = new(JWTAuth)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.BasicAuth != nil {
 		in, out := &in.BasicAuth, &out.BasicAuth
@@ -592,6 +856,36 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 		*out = new(WAF)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CORS != nil {
+		in, out := &in.CORS, &out.CORS
+		*out = new(CORS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalAuth != nil {
+		in, out := &in.ExternalAuth, &out.ExternalAuth
+		*out = new(ExternalAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(APIKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(ConnectionLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Geo != nil {
+		in, out := &in.Geo, &out.Geo
+		*out = new(Geo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BandwidthLimit != nil {
+		in, out := &in.BandwidthLimit, &out.BandwidthLimit
+		*out = new(BandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -657,6 +951,27 @@ func (in *ProviderSpecificProperty) DeepCopy() *ProviderSpecificProperty {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyMirror) DeepCopyInto(out *ProxyMirror) {
+	*out = *in
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyMirror.
+func (in *ProxyMirror) DeepCopy() *ProxyMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyRequestHeaders) DeepCopyInto(out *ProxyRequestHeaders) {
 	*out = *in
@@ -747,6 +1062,13 @@ func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]RateLimitTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -760,6 +1082,27 @@ func (in *RateLimit) DeepCopy() *RateLimit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitTier) DeepCopyInto(out *RateLimitTier) {
+	*out = *in
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitTier.
+func (in *RateLimitTier) DeepCopy() *RateLimitTier {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
@@ -860,6 +1203,32 @@ func (in *Split) DeepCopy() *Split {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SuppliedIn) DeepCopyInto(out *SuppliedIn) {
+	*out = *in
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuppliedIn.
+func (in *SuppliedIn) DeepCopy() *SuppliedIn {
+	if in == nil {
+		return nil
+	}
+	out := new(SuppliedIn)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
@@ -873,6 +1242,11 @@ func (in *TLS) DeepCopyInto(out *TLS) {
 		*out = new(CertManager)
 		**out = **in
 	}
+	if in.HTTP3 != nil {
+		in, out := &in.HTTP3, &out.HTTP3
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -1195,6 +1569,11 @@ func (in *VirtualServerRouteStatus) DeepCopy() *VirtualServerRouteStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 	*out = *in
+	if in.Listener != nil {
+		in, out := &in.Listener, &out.Listener
+		*out = new(Listener)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(TLS)
@@ -1241,6 +1620,11 @@ func (in *VirtualServerStatus) DeepCopyInto(out *VirtualServerStatus) {
 		*out = make([]ExternalEndpoint, len(*in))
 		copy(*out, *in)
 	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -33,8 +33,16 @@ type GlobalConfigurationSpec struct {
 type Listener struct {
 	Name     string `json:"name"`
 	Port     int    `json:"port"`
+	IPv4     string `json:"ipv4"`
+	IPv6     string `json:"ipv6"`
 	Protocol string `json:"protocol"`
 	Ssl      bool   `json:"ssl"`
+	// ProxyProtocol overrides the proxy-protocol ConfigMap key for an HTTP listener.
+	ProxyProtocol *bool `json:"proxyProtocol"`
+	// HTTP2 overrides the http2 ConfigMap key for an HTTP listener with SSL.
+	HTTP2 *bool `json:"http2"`
+	// HTTP3 overrides the http3 ConfigMap key for an HTTP listener with SSL.
+	HTTP3 *bool `json:"http3"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -77,6 +85,24 @@ type TransportServerSpec struct {
 	UpstreamParameters *UpstreamParameters     `json:"upstreamParameters"`
 	SessionParameters  *SessionParameters      `json:"sessionParameters"`
 	Action             *Action                 `json:"action"`
+	Splits             []Split                 `json:"splits"`
+	SplitKey           string                  `json:"splitKey"`
+	Matches            []TransportServerMatch  `json:"matches"`
+	Policies           []PolicyReference       `json:"policies"`
+	Log                *TransportServerLog     `json:"log"`
+}
+
+// PolicyReference references a policy by name and an optional namespace.
+type PolicyReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// TransportServerLog defines the access log of a TransportServer.
+type TransportServerLog struct {
+	Format      string `json:"format"`
+	Destination string `json:"destination"`
+	Condition   string `json:"condition"`
 }
 
 // TLS defines TLS configuration for a TransportServer.
@@ -100,6 +126,7 @@ type Upstream struct {
 	MaxConns            *int         `json:"maxConns"`
 	HealthCheck         *HealthCheck `json:"healthCheck"`
 	LoadBalancingMethod string       `json:"loadBalancingMethod"`
+	ProxyProtocol       bool         `json:"proxyProtocol"`
 }
 
 // HealthCheck defines the parameters for active Upstream HealthChecks.
@@ -141,6 +168,26 @@ type Action struct {
 	Pass string `json:"pass"`
 }
 
+// Split defines a split.
+type Split struct {
+	Weight int     `json:"weight"`
+	Action *Action `json:"action"`
+}
+
+// TransportServerMatch defines a match.
+type TransportServerMatch struct {
+	Conditions []Condition `json:"conditions"`
+	Action     *Action     `json:"action"`
+	Splits     []Split     `json:"splits"`
+	SplitKey   string      `json:"splitKey"`
+}
+
+// Condition defines a condition in a TransportServerMatch.
+type Condition struct {
+	Variable string `json:"variable"`
+	Value    string `json:"value"`
+}
+
 // TransportServerStatus defines the status for the TransportServer resource.
 type TransportServerStatus struct {
 	State   string `json:"state"`
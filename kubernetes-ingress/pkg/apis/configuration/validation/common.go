@@ -87,7 +87,10 @@ func validateSpecialVariable(nVar string, fieldPath *field.Path, isPlus bool) fi
 		addErrors(isValidSpecialHeaderLikeVariable(value))
 	case "cookie":
 		addErrors(isCookieName(value))
-	case "jwt_header", "jwt_claim":
+	case "jwt_claim":
+		// in NGINX, the verifier of the JWT policies sets the jwt_claim variables
+		addErrors(isValidSpecialHeaderLikeVariable(value))
+	case "jwt_header":
 		if !isPlus {
 			allErrs = append(allErrs, field.Forbidden(fieldPath, "is only supported in NGINX Plus"))
 		} else {
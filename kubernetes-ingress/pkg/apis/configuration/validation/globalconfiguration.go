@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 
@@ -49,15 +50,15 @@ func (gcv *GlobalConfigurationValidator) validateListeners(listeners []v1alpha1.
 
 	for i, l := range listeners {
 		idxPath := fieldPath.Index(i)
-		portProtocolKey := generatePortProtocolKey(l.Port, l.Protocol)
+		portProtocolKeys := generateListenerPortProtocolKeys(l)
 
 		listenerErrs := gcv.validateListener(l, idxPath)
 		if len(listenerErrs) > 0 {
 			allErrs = append(allErrs, listenerErrs...)
 		} else if listenerNames.Has(l.Name) {
 			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), l.Name))
-		} else if portProtocolCombinations.Has(portProtocolKey) {
-			msg := fmt.Sprintf("Duplicated port/protocol combination %s", portProtocolKey)
+		} else if duplicatedKeys := portProtocolCombinations.Intersection(sets.New(portProtocolKeys...)); duplicatedKeys.Len() > 0 {
+			msg := fmt.Sprintf("Duplicated port/protocol combination %s", strings.Join(sets.List(duplicatedKeys), ", "))
 			allErrs = append(allErrs, field.Duplicate(fieldPath, msg))
 		} else if protocol, ok := portProtocolMap[l.Port]; ok {
 			var msg string
@@ -77,9 +78,14 @@ func (gcv *GlobalConfigurationValidator) validateListeners(listeners []v1alpha1.
 					allErrs = append(allErrs, field.Forbidden(fieldPath, msg))
 				}
 			}
+			// Listeners with compatible protocols can share the port, for example on different addresses.
+			if msg == "" {
+				listenerNames.Insert(l.Name)
+				portProtocolCombinations.Insert(portProtocolKeys...)
+			}
 		} else {
 			listenerNames.Insert(l.Name)
-			portProtocolCombinations.Insert(portProtocolKey)
+			portProtocolCombinations.Insert(portProtocolKeys...)
 			portProtocolMap[l.Port] = l.Protocol
 		}
 	}
@@ -91,11 +97,65 @@ func generatePortProtocolKey(port int, protocol string) string {
 	return fmt.Sprintf("%d/%s", port, protocol)
 }
 
+// generateListenerPortProtocolKeys generates the port/protocol keys for every address the listener binds to.
+// A listener without addresses binds to all addresses, so its key doesn't include an address.
+func generateListenerPortProtocolKeys(listener v1alpha1.Listener) []string {
+	if listener.IPv4 == "" && listener.IPv6 == "" {
+		return []string{generatePortProtocolKey(listener.Port, listener.Protocol)}
+	}
+
+	var keys []string
+	if listener.IPv4 != "" {
+		keys = append(keys, fmt.Sprintf("%s:%s", net.ParseIP(listener.IPv4).String(), generatePortProtocolKey(listener.Port, listener.Protocol)))
+	}
+	if listener.IPv6 != "" {
+		keys = append(keys, fmt.Sprintf("[%s]:%s", net.ParseIP(listener.IPv6).String(), generatePortProtocolKey(listener.Port, listener.Protocol)))
+	}
+	return keys
+}
+
 func (gcv *GlobalConfigurationValidator) validateListener(listener v1alpha1.Listener, fieldPath *field.Path) field.ErrorList {
 	allErrs := validateGlobalConfigurationListenerName(listener.Name, fieldPath.Child("name"))
 	allErrs = append(allErrs, gcv.validateListenerPort(listener.Port, fieldPath.Child("port"))...)
 	allErrs = append(allErrs, validateListenerProtocol(listener.Protocol, fieldPath.Child("protocol"))...)
+	allErrs = append(allErrs, validateListenerAddresses(listener, fieldPath)...)
+	allErrs = append(allErrs, validateListenerHTTPOptions(listener, fieldPath)...)
+
+	return allErrs
+}
 
+func validateListenerHTTPOptions(listener v1alpha1.Listener, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if listener.ProxyProtocol != nil && listener.Protocol != "HTTP" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("proxyProtocol"), "is supported only for HTTP listeners"))
+	}
+	if listener.HTTP2 != nil && (listener.Protocol != "HTTP" || !listener.Ssl) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("http2"), "is supported only for HTTP listeners with ssl enabled"))
+	}
+	if listener.HTTP3 != nil && (listener.Protocol != "HTTP" || !listener.Ssl) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("http3"), "is supported only for HTTP listeners with ssl enabled"))
+	}
+
+	return allErrs
+}
+
+func validateListenerAddresses(listener v1alpha1.Listener, fieldPath *field.Path) field.ErrorList {
+	if listener.IPv4 == "" && listener.IPv6 == "" {
+		return nil
+	}
+
+	if listener.Protocol == "HTTP" {
+		return field.ErrorList{field.Forbidden(fieldPath, "ipv4 and ipv6 are supported only for TCP and UDP listeners")}
+	}
+
+	allErrs := field.ErrorList{}
+	if listener.IPv4 != "" {
+		allErrs = append(allErrs, validation.IsValidIPv4Address(fieldPath.Child("ipv4"), listener.IPv4)...)
+	}
+	if listener.IPv6 != "" {
+		allErrs = append(allErrs, validation.IsValidIPv6Address(fieldPath.Child("ipv6"), listener.IPv6)...)
+	}
 	return allErrs
 }
 
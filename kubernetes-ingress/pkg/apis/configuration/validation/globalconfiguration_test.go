@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
@@ -72,6 +73,19 @@ func TestValidateListeners(t *testing.T) {
 			Port:     53,
 			Protocol: "UDP",
 		},
+		{
+			Name:     "tcp-listener-private",
+			Port:     53,
+			IPv4:     "10.0.0.1",
+			Protocol: "TCP",
+		},
+		{
+			Name:     "tcp-listener-public",
+			Port:     53,
+			IPv4:     "192.168.1.1",
+			IPv6:     "2001:db8::1",
+			Protocol: "TCP",
+		},
 	}
 
 	gcv := createGlobalConfigurationValidator()
@@ -118,6 +132,86 @@ func TestValidateListenersFails(t *testing.T) {
 			},
 			msg: "duplicated port/protocol combination",
 		},
+		{
+			listeners: []v1alpha1.Listener{
+				{
+					Name:     "tcp-listener-1",
+					Port:     2201,
+					IPv4:     "10.0.0.1",
+					Protocol: "TCP",
+				},
+				{
+					Name:     "tcp-listener-2",
+					Port:     2201,
+					IPv4:     "10.0.0.1",
+					IPv6:     "::1",
+					Protocol: "TCP",
+				},
+			},
+			msg: "duplicated ip/port/protocol combination",
+		},
+		{
+			listeners: []v1alpha1.Listener{
+				{
+					Name:     "tcp-listener-1",
+					Port:     2201,
+					IPv6:     "::1",
+					Protocol: "TCP",
+				},
+				{
+					Name:     "tcp-listener-2",
+					Port:     2201,
+					IPv6:     "0:0::1",
+					Protocol: "TCP",
+				},
+			},
+			msg: "duplicated ipv6/port/protocol combination with different notations",
+		},
+		{
+			listeners: []v1alpha1.Listener{
+				{
+					Name:     "tcp-listener-1",
+					Port:     5353,
+					IPv4:     "10.0.0.1",
+					Protocol: "TCP",
+				},
+				{
+					Name:     "tcp-listener-2",
+					Port:     5353,
+					IPv4:     "10.0.0.2",
+					Protocol: "TCP",
+				},
+				{
+					Name:     "tcp-listener-2",
+					Port:     5354,
+					Protocol: "TCP",
+				},
+			},
+			msg: "duplicated name of a listener that shares a port",
+		},
+		{
+			listeners: []v1alpha1.Listener{
+				{
+					Name:     "tcp-listener-1",
+					Port:     5353,
+					IPv4:     "10.0.0.1",
+					Protocol: "TCP",
+				},
+				{
+					Name:     "tcp-listener-2",
+					Port:     5353,
+					IPv4:     "10.0.0.2",
+					Protocol: "TCP",
+				},
+				{
+					Name:     "tcp-listener-3",
+					Port:     5353,
+					IPv4:     "10.0.0.2",
+					Protocol: "TCP",
+				},
+			},
+			msg: "duplicated ip/port/protocol combination on a shared port",
+		},
 	}
 
 	gcv := createGlobalConfigurationValidator()
@@ -146,6 +240,26 @@ func TestValidateListener(t *testing.T) {
 	}
 }
 
+func TestValidateListener_PassesOnValidHTTPOptions(t *testing.T) {
+	t.Parallel()
+	listener := v1alpha1.Listener{
+		Name:          "https-listener",
+		Port:          8443,
+		Protocol:      "HTTP",
+		Ssl:           true,
+		ProxyProtocol: createPointerFromBool(false),
+		HTTP2:         createPointerFromBool(true),
+		HTTP3:         createPointerFromBool(true),
+	}
+
+	gcv := createGlobalConfigurationValidator()
+
+	allErrs := gcv.validateListener(listener, field.NewPath("listener"))
+	if len(allErrs) > 0 {
+		t.Errorf("validateListener() returned errors %v for valid input", allErrs)
+	}
+}
+
 func TestValidateListenerFails(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -184,6 +298,60 @@ func TestValidateListenerFails(t *testing.T) {
 			},
 			msg: "name of a built-in listener",
 		},
+		{
+			Listener: v1alpha1.Listener{
+				Name:     "tcp-listener",
+				Port:     2201,
+				IPv4:     "2001:db8::1",
+				Protocol: "TCP",
+			},
+			msg: "invalid ipv4",
+		},
+		{
+			Listener: v1alpha1.Listener{
+				Name:     "tcp-listener",
+				Port:     2201,
+				IPv6:     "10.0.0.1",
+				Protocol: "TCP",
+			},
+			msg: "invalid ipv6",
+		},
+		{
+			Listener: v1alpha1.Listener{
+				Name:     "http-listener",
+				Port:     8080,
+				IPv4:     "10.0.0.1",
+				Protocol: "HTTP",
+			},
+			msg: "ipv4 for an HTTP listener",
+		},
+		{
+			Listener: v1alpha1.Listener{
+				Name:          "tcp-listener",
+				Port:          2201,
+				Protocol:      "TCP",
+				ProxyProtocol: createPointerFromBool(true),
+			},
+			msg: "proxyProtocol for a TCP listener",
+		},
+		{
+			Listener: v1alpha1.Listener{
+				Name:     "http-listener",
+				Port:     8080,
+				Protocol: "HTTP",
+				HTTP2:    createPointerFromBool(true),
+			},
+			msg: "http2 for an HTTP listener without ssl",
+		},
+		{
+			Listener: v1alpha1.Listener{
+				Name:     "http-listener",
+				Port:     8080,
+				Protocol: "HTTP",
+				HTTP3:    createPointerFromBool(true),
+			},
+			msg: "http3 for an HTTP listener without ssl",
+		},
 	}
 
 	gcv := createGlobalConfigurationValidator()
@@ -210,6 +378,38 @@ func TestGeneratePortProtocolKey(t *testing.T) {
 	}
 }
 
+func TestGenerateListenerPortProtocolKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		listener v1alpha1.Listener
+		expected []string
+	}{
+		{
+			listener: v1alpha1.Listener{
+				Port:     53,
+				Protocol: "UDP",
+			},
+			expected: []string{"53/UDP"},
+		},
+		{
+			listener: v1alpha1.Listener{
+				Port:     53,
+				IPv4:     "10.0.0.1",
+				IPv6:     "2001:DB8::1",
+				Protocol: "TCP",
+			},
+			expected: []string{"10.0.0.1:53/TCP", "[2001:db8::1]:53/TCP"},
+		},
+	}
+
+	for _, test := range tests {
+		result := generateListenerPortProtocolKeys(test.listener)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateListenerPortProtocolKeys(%v) returned %v but expected %v", test.listener, result, test.expected)
+		}
+	}
+}
+
 func TestValidateListenerProtocol_FailsOnInvalidInput(t *testing.T) {
 	t.Parallel()
 	invalidProtocols := []string{
@@ -10,6 +10,7 @@ import (
 	"unicode"
 
 	v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -36,10 +37,6 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 	}
 
 	if spec.JWTAuth != nil {
-		if !isPlus {
-			return append(allErrs, field.Forbidden(fieldPath.Child("jwt"), "jwt secrets are only supported in NGINX Plus"))
-		}
-
 		allErrs = append(allErrs, validateJWT(spec.JWTAuth, fieldPath.Child("jwt"))...)
 		fieldCount++
 	}
@@ -85,10 +82,40 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 		fieldCount++
 	}
 
+	if spec.CORS != nil {
+		allErrs = append(allErrs, validateCORS(spec.CORS, fieldPath.Child("cors"))...)
+		fieldCount++
+	}
+
+	if spec.ExternalAuth != nil {
+		allErrs = append(allErrs, validateExternalAuth(spec.ExternalAuth, fieldPath.Child("externalAuth"))...)
+		fieldCount++
+	}
+
+	if spec.APIKey != nil {
+		allErrs = append(allErrs, validateAPIKey(spec.APIKey, fieldPath.Child("apiKey"))...)
+		fieldCount++
+	}
+
+	if spec.ConnectionLimit != nil {
+		allErrs = append(allErrs, validateConnectionLimit(spec.ConnectionLimit, fieldPath.Child("connectionLimit"), isPlus)...)
+		fieldCount++
+	}
+
+	if spec.Geo != nil {
+		allErrs = append(allErrs, validateGeo(spec.Geo, fieldPath.Child("geo"))...)
+		fieldCount++
+	}
+
+	if spec.BandwidthLimit != nil {
+		allErrs = append(allErrs, validateBandwidthLimit(spec.BandwidthLimit, fieldPath.Child("bandwidthLimit"), isPlus)...)
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`"
+		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `cors`, `externalAuth`, `apiKey`, `connectionLimit`, `geo`, `bandwidthLimit`, `jwt`"
 		if isPlus {
-			msg = fmt.Sprint(msg, ", `jwt`, `oidc`, `waf`")
+			msg = fmt.Sprint(msg, ", `oidc`, `waf`")
 		}
 		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
 	}
@@ -124,9 +151,20 @@ func validateAccessControl(accessControl *v1.AccessControl, fieldPath *field.Pat
 
 func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
 	allErrs := validateRateLimitZoneSize(rateLimit.ZoneSize, fieldPath.Child("zoneSize"))
-	allErrs = append(allErrs, validateRate(rateLimit.Rate, fieldPath.Child("rate"))...)
 	allErrs = append(allErrs, validateRateLimitKey(rateLimit.Key, fieldPath.Child("key"), isPlus)...)
 
+	if len(rateLimit.Tiers) > 0 {
+		allErrs = append(allErrs, validateRateLimitTiers(rateLimit, fieldPath, isPlus)...)
+	} else {
+		allErrs = append(allErrs, validateRate(rateLimit.Rate, fieldPath.Child("rate"))...)
+		if rateLimit.TierSelector != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("tierSelector"), "requires tiers"))
+		}
+		if rateLimit.DefaultTier != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("defaultTier"), "requires tiers"))
+		}
+	}
+
 	if rateLimit.Delay != nil {
 		allErrs = append(allErrs, validatePositiveInt(*rateLimit.Delay, fieldPath.Child("delay"))...)
 	}
@@ -149,6 +187,216 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 	return allErrs
 }
 
+func validateRateLimitTiers(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rateLimit.Rate != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rate"), "must not be used with tiers"))
+	}
+	allErrs = append(allErrs, validateRateLimitTierSelector(rateLimit.TierSelector, fieldPath.Child("tierSelector"), isPlus)...)
+
+	tierNames := sets.Set[string]{}
+	for i, tier := range rateLimit.Tiers {
+		idxPath := fieldPath.Child("tiers").Index(i)
+
+		if !rateLimitTierNameRegexp.MatchString(tier.Name) {
+			msg := validation.RegexError(rateLimitTierNameErrMsg, rateLimitTierNameFmt, "free", "gold")
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), tier.Name, msg))
+		} else if tierNames.Has(tier.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), tier.Name))
+		}
+		tierNames.Insert(tier.Name)
+
+		allErrs = append(allErrs, validateRate(tier.Rate, idxPath.Child("rate"))...)
+		if tier.Burst != nil {
+			allErrs = append(allErrs, validatePositiveInt(*tier.Burst, idxPath.Child("burst"))...)
+		}
+	}
+
+	if rateLimit.DefaultTier == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("defaultTier"), ""))
+	} else if !tierNames.Has(rateLimit.DefaultTier) {
+		allErrs = append(allErrs, field.NotFound(fieldPath.Child("defaultTier"), rateLimit.DefaultTier))
+	}
+
+	return allErrs
+}
+
+const (
+	rateLimitTierNameFmt    = `[a-zA-Z0-9_]+`
+	rateLimitTierNameErrMsg = "must consist of alphanumeric characters or '_'"
+
+	rateLimitTierSelectorFmt    = `\$\{[^{}]+\}`
+	rateLimitTierSelectorErrMsg = "must be a single variable"
+)
+
+var (
+	rateLimitTierNameRegexp     = regexp.MustCompile("^" + rateLimitTierNameFmt + "$")
+	rateLimitTierSelectorRegexp = regexp.MustCompile("^" + rateLimitTierSelectorFmt + "$")
+)
+
+var rateLimitTierSelectorSpecialVariables = []string{"arg_", "http_", "cookie_", "jwt_claim_"}
+
+func validateRateLimitTierSelector(selector string, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	if selector == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	if !rateLimitTierSelectorRegexp.MatchString(selector) {
+		msg := validation.RegexError(rateLimitTierSelectorErrMsg, rateLimitTierSelectorFmt, "${jwt_claim_plan}", "${http_x_plan}")
+		return field.ErrorList{field.Invalid(fieldPath, selector, msg)}
+	}
+	if !isPlus && strings.HasPrefix(selector, "${jwt_claim_") {
+		// in NGINX, the tier selector is evaluated before the verifier of a JWT policy sets the jwt_claim variables
+		return field.ErrorList{field.Forbidden(fieldPath, "the jwt_claim variables are only supported in NGINX Plus")}
+	}
+	return validateStringWithVariables(selector, fieldPath, rateLimitTierSelectorSpecialVariables, map[string]bool{}, isPlus)
+}
+
+func validateConnectionLimit(connectionLimit *v1.ConnectionLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := validateRateLimitZoneSize(connectionLimit.ZoneSize, fieldPath.Child("zoneSize"))
+	allErrs = append(allErrs, validateConnectionLimitKey(connectionLimit.Key, fieldPath.Child("key"), isPlus)...)
+	allErrs = append(allErrs, validatePositiveInt(connectionLimit.Connections, fieldPath.Child("connections"))...)
+
+	if connectionLimit.LogLevel != "" {
+		allErrs = append(allErrs, validateRateLimitLogLevel(connectionLimit.LogLevel, fieldPath.Child("logLevel"))...)
+	}
+
+	return allErrs
+}
+
+func validateBandwidthLimit(bandwidthLimit *v1.BandwidthLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	if bandwidthLimit.Connections == 0 && bandwidthLimit.Rate == "" {
+		return field.ErrorList{field.Required(fieldPath, "must specify at least one of: `connections` or `rate`")}
+	}
+
+	allErrs := field.ErrorList{}
+	if bandwidthLimit.Connections != 0 {
+		allErrs = append(allErrs, validatePositiveInt(bandwidthLimit.Connections, fieldPath.Child("connections"))...)
+		allErrs = append(allErrs, validateRateLimitKey(bandwidthLimit.Key, fieldPath.Child("key"), isPlus)...)
+		allErrs = append(allErrs, validateRateLimitZoneSize(bandwidthLimit.ZoneSize, fieldPath.Child("zoneSize"))...)
+
+		if bandwidthLimit.LogLevel != "" {
+			allErrs = append(allErrs, validateRateLimitLogLevel(bandwidthLimit.LogLevel, fieldPath.Child("logLevel"))...)
+		}
+
+		if bandwidthLimit.RejectCode != nil {
+			if *bandwidthLimit.RejectCode < 400 || *bandwidthLimit.RejectCode > 599 {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("rejectCode"), bandwidthLimit.RejectCode,
+					"must be within the range [400-599]"))
+			}
+		}
+	} else {
+		if bandwidthLimit.Key != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("key"), "requires connections"))
+		}
+		if bandwidthLimit.ZoneSize != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("zoneSize"), "requires connections"))
+		}
+	}
+
+	if bandwidthLimit.Rate != "" {
+		allErrs = append(allErrs, validateSize(bandwidthLimit.Rate, fieldPath.Child("rate"))...)
+	} else if bandwidthLimit.RateAfter != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rateAfter"), "requires rate"))
+	}
+	allErrs = append(allErrs, validateSize(bandwidthLimit.RateAfter, fieldPath.Child("rateAfter"))...)
+
+	return allErrs
+}
+
+func validateGeo(geo *v1.Geo, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if geo.AllowCountries != nil && geo.DenyCountries != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify at most one of: `allowCountries` or `denyCountries`"))
+	}
+	if geo.AllowASNs != nil && geo.DenyASNs != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify at most one of: `allowASNs` or `denyASNs`"))
+	}
+	if geo.AllowCountries == nil && geo.DenyCountries == nil && geo.AllowASNs == nil && geo.DenyASNs == nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify at least one of: `allowCountries`, `denyCountries`, `allowASNs` or `denyASNs`"))
+	}
+
+	for i, country := range geo.AllowCountries {
+		allErrs = append(allErrs, validateCountryCode(country, fieldPath.Child("allowCountries").Index(i))...)
+	}
+	for i, country := range geo.DenyCountries {
+		allErrs = append(allErrs, validateCountryCode(country, fieldPath.Child("denyCountries").Index(i))...)
+	}
+	for i, asn := range geo.AllowASNs {
+		allErrs = append(allErrs, validatePositiveInt(asn, fieldPath.Child("allowASNs").Index(i))...)
+	}
+	for i, asn := range geo.DenyASNs {
+		allErrs = append(allErrs, validatePositiveInt(asn, fieldPath.Child("denyASNs").Index(i))...)
+	}
+
+	usesCountry := geo.AllowCountries != nil || geo.DenyCountries != nil || geo.CountryHeader != ""
+	allErrs = append(allErrs, validateGeoDatabase(geo.CountryDatabase, usesCountry, fieldPath.Child("countryDatabase"))...)
+
+	usesASN := geo.AllowASNs != nil || geo.DenyASNs != nil || geo.ASNHeader != ""
+	allErrs = append(allErrs, validateGeoDatabase(geo.ASNDatabase, usesASN, fieldPath.Child("asnDatabase"))...)
+
+	if geo.UnknownAction != "" && geo.UnknownAction != "allow" && geo.UnknownAction != "deny" {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("unknownAction"), geo.UnknownAction, []string{"allow", "deny"}))
+	}
+
+	if geo.RejectCode != nil {
+		if *geo.RejectCode < 400 || *geo.RejectCode > 599 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("rejectCode"), geo.RejectCode,
+				"must be within the range [400-599]"))
+		}
+	}
+
+	if geo.CountryHeader != "" {
+		for _, msg := range validation.IsHTTPHeaderName(geo.CountryHeader) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("countryHeader"), geo.CountryHeader, msg))
+		}
+	}
+	if geo.ASNHeader != "" {
+		for _, msg := range validation.IsHTTPHeaderName(geo.ASNHeader) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("asnHeader"), geo.ASNHeader, msg))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	countryCodeFmt    = `[A-Z]{2}`
+	countryCodeErrMsg = "must be an ISO 3166-1 alpha-2 country code"
+)
+
+var countryCodeRegexp = regexp.MustCompile("^" + countryCodeFmt + "$")
+
+func validateCountryCode(country string, fieldPath *field.Path) field.ErrorList {
+	if !countryCodeRegexp.MatchString(country) {
+		msg := validation.RegexError(countryCodeErrMsg, countryCodeFmt, "US", "DE")
+		return field.ErrorList{field.Invalid(fieldPath, country, msg)}
+	}
+	return nil
+}
+
+const (
+	geoDatabaseFmt    = `/[^\s"'\\;{}$]+`
+	geoDatabaseErrMsg = "must be an absolute path"
+)
+
+var geoDatabaseRegexp = regexp.MustCompile("^" + geoDatabaseFmt + "$")
+
+func validateGeoDatabase(database string, required bool, fieldPath *field.Path) field.ErrorList {
+	if database == "" {
+		if required {
+			return field.ErrorList{field.Required(fieldPath, "")}
+		}
+		return nil
+	}
+	if !geoDatabaseRegexp.MatchString(database) {
+		msg := validation.RegexError(geoDatabaseErrMsg, geoDatabaseFmt, "/etc/nginx/geoip/GeoLite2-Country.mmdb")
+		return field.ErrorList{field.Invalid(fieldPath, database, msg)}
+	}
+	return nil
+}
+
 // validateJWT validates JWT Policy according the rules specified in documentation
 // for using [jwt] local k8s secrets and using [jwks] from remote location.
 //
@@ -161,6 +409,10 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	}
 	allErrs := validateRealm(jwt.Realm, fieldPath.Child("realm"))
 
+	if jwt.Claims != nil {
+		allErrs = append(allErrs, validateJWTClaims(jwt.Claims, fieldPath.Child("claims"))...)
+	}
+
 	// Use either JWT Secret or JWKS URI, they are mutually exclusive.
 	if jwt.Secret == "" && jwt.JwksURI == "" {
 		return append(allErrs, field.Required(fieldPath.Child("secret"), "either Secret or JwksURI must be present"))
@@ -201,6 +453,102 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateJWTClaims(claims *v1.JWTClaims, fieldPath *field.Path) field.ErrorList {
+	if claims.Issuer == "" && claims.Audience == "" && len(claims.Scopes) == 0 && len(claims.Roles) == 0 && len(claims.Match) == 0 {
+		return field.ErrorList{field.Required(fieldPath, "must specify at least one of: `issuer`, `audience`, `scopes`, `roles`, `match`")}
+	}
+
+	allErrs := field.ErrorList{}
+	if claims.Issuer != "" {
+		allErrs = append(allErrs, validateJWTClaimValue(claims.Issuer, fieldPath.Child("issuer"))...)
+	}
+	if claims.Audience != "" {
+		allErrs = append(allErrs, validateJWTClaimValue(claims.Audience, fieldPath.Child("audience"))...)
+	}
+	for i, scope := range claims.Scopes {
+		allErrs = append(allErrs, validateJWTClaimValue(scope, fieldPath.Child("scopes").Index(i))...)
+	}
+	for i, role := range claims.Roles {
+		allErrs = append(allErrs, validateJWTClaimValue(role, fieldPath.Child("roles").Index(i))...)
+	}
+	if claims.RolesClaim != "" {
+		if len(claims.Roles) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rolesClaim"), "requires roles"))
+		} else {
+			allErrs = append(allErrs, validateJWTClaimName(claims.RolesClaim, fieldPath.Child("rolesClaim"))...)
+		}
+	}
+
+	for i, m := range claims.Match {
+		idxPath := fieldPath.Child("match").Index(i)
+		if m.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			allErrs = append(allErrs, validateJWTClaimName(m.Name, idxPath.Child("name"))...)
+		}
+
+		switch {
+		case m.Value != "" && m.Regex != "":
+			allErrs = append(allErrs, field.Forbidden(idxPath, "only one of value or regex can be used"))
+		case m.Value != "":
+			allErrs = append(allErrs, validateJWTClaimValue(m.Value, idxPath.Child("value"))...)
+		case m.Regex != "":
+			allErrs = append(allErrs, validateJWTClaimRegex(m.Regex, idxPath.Child("regex"))...)
+		default:
+			allErrs = append(allErrs, field.Required(idxPath, "must specify value or regex"))
+		}
+	}
+
+	if claims.RejectBody != "" {
+		if err := ValidateEscapedString(claims.RejectBody, `Access denied`); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("rejectBody"), claims.RejectBody, err.Error()))
+		} else if strings.Contains(claims.RejectBody, "$") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("rejectBody"), claims.RejectBody, "must not contain variables"))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	jwtClaimNameFmt    = `[a-zA-Z_][a-zA-Z0-9_]*`
+	jwtClaimNameErrMsg = "must start with a letter or '_' and consist of alphanumeric characters or '_'"
+
+	jwtClaimValueFmt    = `[^\s"\\]+`
+	jwtClaimValueErrMsg = "must not contain whitespace, '\"' or '\\'"
+)
+
+var (
+	jwtClaimNameRegexp  = regexp.MustCompile("^" + jwtClaimNameFmt + "$")
+	jwtClaimValueRegexp = regexp.MustCompile("^" + jwtClaimValueFmt + "$")
+)
+
+func validateJWTClaimName(name string, fieldPath *field.Path) field.ErrorList {
+	if !jwtClaimNameRegexp.MatchString(name) {
+		msg := validation.RegexError(jwtClaimNameErrMsg, jwtClaimNameFmt, "roles", "tenant_id")
+		return field.ErrorList{field.Invalid(fieldPath, name, msg)}
+	}
+	return nil
+}
+
+func validateJWTClaimValue(value string, fieldPath *field.Path) field.ErrorList {
+	if !jwtClaimValueRegexp.MatchString(value) {
+		msg := validation.RegexError(jwtClaimValueErrMsg, jwtClaimValueFmt, "jobs:write", "https://idp.example.com")
+		return field.ErrorList{field.Invalid(fieldPath, value, msg)}
+	}
+	return nil
+}
+
+func validateJWTClaimRegex(regex string, fieldPath *field.Path) field.ErrorList {
+	if strings.Contains(regex, `"`) || strings.HasSuffix(regex, `\`) {
+		return field.ErrorList{field.Invalid(fieldPath, regex, "must not contain '\"' or end with '\\'")}
+	}
+	if _, err := regexp.Compile(regex); err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, regex, fmt.Sprintf("must be a valid regular expression: %v", err))}
+	}
+	return nil
+}
+
 func validateBasic(basic *v1.BasicAuth, fieldPath *field.Path) field.ErrorList {
 	if basic.Secret == "" {
 		return field.ErrorList{field.Required(fieldPath.Child("secret"), "")}
@@ -269,6 +617,12 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 	if oidc.AuthExtraArgs != nil {
 		allErrs = append(allErrs, validateQueryString(strings.Join(oidc.AuthExtraArgs, "&"), fieldPath.Child("authExtraArgs"))...)
 	}
+	if oidc.EndSessionEndpoint != "" {
+		allErrs = append(allErrs, validateEndSessionEndpoint(oidc.EndSessionEndpoint, fieldPath.Child("endSessionEndpoint"))...)
+	}
+	if oidc.PostLogoutRedirectURI != "" {
+		allErrs = append(allErrs, validatePostLogoutRedirectURI(oidc.PostLogoutRedirectURI, fieldPath.Child("postLogoutRedirectURI"))...)
+	}
 
 	allErrs = append(allErrs, validateURL(oidc.AuthEndpoint, fieldPath.Child("authEndpoint"))...)
 	allErrs = append(allErrs, validateURL(oidc.TokenEndpoint, fieldPath.Child("tokenEndpoint"))...)
@@ -307,6 +661,168 @@ func validateWAF(waf *v1.WAF, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateCORS(cors *v1.CORS, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	originsPath := fieldPath.Child("allowOrigins")
+	if len(cors.AllowOrigins) == 0 {
+		allErrs = append(allErrs, field.Required(originsPath, ""))
+	}
+
+	for i, origin := range cors.AllowOrigins {
+		if origin != "*" {
+			allErrs = append(allErrs, validateCORSOrigin(origin, originsPath.Index(i))...)
+			continue
+		}
+		if len(cors.AllowOrigins) > 1 {
+			allErrs = append(allErrs, field.Invalid(originsPath.Index(i), origin, "must be the only allowed origin"))
+		}
+		if cors.AllowCredentials != nil && *cors.AllowCredentials {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("allowCredentials"), "credentials cannot be allowed for any origin '*'"))
+		}
+	}
+
+	for i, method := range cors.AllowMethods {
+		if !corsMethodRegexp.MatchString(method) {
+			msg := validation.RegexError(corsMethodErrMsg, corsMethodFmt, "GET", "PUT", "DELETE")
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("allowMethods").Index(i), method, msg))
+		}
+	}
+
+	for i, header := range cors.AllowHeaders {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("allowHeaders").Index(i), header, msg))
+		}
+	}
+
+	for i, header := range cors.ExposeHeaders {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("exposeHeaders").Index(i), header, msg))
+		}
+	}
+
+	if cors.MaxAge != nil {
+		allErrs = append(allErrs, validatePositiveInt(*cors.MaxAge, fieldPath.Child("maxAge"))...)
+	}
+
+	return allErrs
+}
+
+func validateExternalAuth(externalAuth *v1.ExternalAuth, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateServiceName(externalAuth.Service, fieldPath.Child("service"))
+
+	for _, msg := range validation.IsValidPortNum(int(externalAuth.Port)) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("port"), externalAuth.Port, msg))
+	}
+
+	allErrs = append(allErrs, validatePath(externalAuth.Path, fieldPath.Child("path"))...)
+
+	for i, header := range externalAuth.RequestHeaders {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("requestHeaders").Index(i), header, msg))
+		}
+	}
+
+	for i, header := range externalAuth.ResponseHeaders {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("responseHeaders").Index(i), header, msg))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	queryArgNameFmt    = `[a-zA-Z0-9_]+`
+	queryArgNameErrMsg = "must consist of alphanumeric characters or '_'"
+)
+
+var queryArgNameRegexp = regexp.MustCompile("^" + queryArgNameFmt + "$")
+
+func validateAPIKey(apiKey *v1.APIKey, fieldPath *field.Path) field.ErrorList {
+	if apiKey.ClientSecret == "" {
+		return field.ErrorList{field.Required(fieldPath.Child("clientSecret"), "")}
+	}
+	allErrs := validateSecretName(apiKey.ClientSecret, fieldPath.Child("clientSecret"))
+
+	suppliedInPath := fieldPath.Child("suppliedIn")
+	if apiKey.SuppliedIn == nil || (len(apiKey.SuppliedIn.Header) == 0 && len(apiKey.SuppliedIn.Query) == 0) {
+		return append(allErrs, field.Required(suppliedInPath, "must specify at least one header or query argument"))
+	}
+
+	for i, header := range apiKey.SuppliedIn.Header {
+		for _, msg := range validation.IsHTTPHeaderName(header) {
+			allErrs = append(allErrs, field.Invalid(suppliedInPath.Child("header").Index(i), header, msg))
+		}
+	}
+
+	for i, arg := range apiKey.SuppliedIn.Query {
+		if !queryArgNameRegexp.MatchString(arg) {
+			msg := validation.RegexError(queryArgNameErrMsg, queryArgNameFmt, "apikey", "api_key")
+			allErrs = append(allErrs, field.Invalid(suppliedInPath.Child("query").Index(i), arg, msg))
+		}
+	}
+
+	if apiKey.RejectCode != nil {
+		if *apiKey.RejectCode < 400 || *apiKey.RejectCode > 599 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("rejectCode"), apiKey.RejectCode,
+				"must be within the range [400-599]"))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	corsMethodFmt    = `[A-Z]+`
+	corsMethodErrMsg = "must consist of uppercase letters"
+)
+
+var corsMethodRegexp = regexp.MustCompile("^" + corsMethodFmt + "$")
+
+// validateCORSOrigin validates an allowed origin of a CORS policy. The origin is either
+// an exact origin like https://example.com or a regular expression that starts with ~ (or ~* for case-insensitive matching).
+func validateCORSOrigin(origin string, fieldPath *field.Path) field.ErrorList {
+	if strings.HasPrefix(origin, "~") {
+		regex := strings.TrimPrefix(strings.TrimPrefix(origin, "~"), "*")
+		if regex == "" {
+			return field.ErrorList{field.Invalid(fieldPath, origin, "must contain a regular expression after ~")}
+		}
+		if _, err := regexp.Compile(regex); err != nil {
+			return field.ErrorList{field.Invalid(fieldPath, origin, fmt.Sprintf("must be a valid regular expression: %v", err))}
+		}
+		if err := ValidateEscapedString(origin, `~^https://.*\.example\.com$`); err != nil {
+			return field.ErrorList{field.Invalid(fieldPath, origin, err.Error())}
+		}
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, origin, err.Error())}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return field.ErrorList{field.Invalid(fieldPath, origin, "scheme must be http or https")}
+	}
+	if u.Host == "" {
+		return field.ErrorList{field.Invalid(fieldPath, origin, "hostname required")}
+	}
+	if u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return field.ErrorList{field.Invalid(fieldPath, origin, "must consist of a scheme, a hostname and an optional port only")}
+	}
+
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host = u.Host
+	}
+
+	allErrs := validateSSLName(host, fieldPath)
+	if port != "" {
+		allErrs = append(allErrs, validatePortNumber(port, fieldPath)...)
+	}
+	return allErrs
+}
+
 func validateLogConf(logConf, logDest string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -369,6 +885,37 @@ func validateOIDCScope(scope string, fieldPath *field.Path) field.ErrorList {
 	return nil
 }
 
+// oidcLogoutURIForbiddenChars are the characters that can't be used in the logout URIs of an OIDC policy,
+// because the URIs are rendered in quoted NGINX strings, where a '$' starts a variable.
+const (
+	oidcLogoutURIForbiddenChars = "\"\\$"
+	oidcLogoutURIErrMsg         = `must not contain '"', '\\' or '$'`
+)
+
+// validateEndSessionEndpoint accepts an absolute URL. The URL may include a query string, but not a fragment,
+// because the logout parameters are appended to it.
+func validateEndSessionEndpoint(uri string, fieldPath *field.Path) field.ErrorList {
+	if strings.ContainsAny(uri, oidcLogoutURIForbiddenChars) {
+		return field.ErrorList{field.Invalid(fieldPath, uri, oidcLogoutURIErrMsg)}
+	}
+	if strings.Contains(uri, "#") {
+		return field.ErrorList{field.Invalid(fieldPath, uri, "must not contain a fragment")}
+	}
+	return validateURL(uri, fieldPath)
+}
+
+// validatePostLogoutRedirectURI accepts either a path, which is resolved against the host of the VirtualServer,
+// or an absolute URL.
+func validatePostLogoutRedirectURI(uri string, fieldPath *field.Path) field.ErrorList {
+	if strings.ContainsAny(uri, oidcLogoutURIForbiddenChars) {
+		return field.ErrorList{field.Invalid(fieldPath, uri, oidcLogoutURIErrMsg)}
+	}
+	if strings.HasPrefix(uri, "/") {
+		return validatePath(uri, fieldPath)
+	}
+	return validateURL(uri, fieldPath)
+}
+
 func validateURL(name string, fieldPath *field.Path) field.ErrorList {
 	u, err := url.Parse(name)
 	if err != nil {
@@ -497,6 +1044,26 @@ func validateRateLimitKey(key string, fieldPath *field.Path, isPlus bool) field.
 	return append(allErrs, validateStringWithVariables(key, fieldPath, rateLimitKeySpecialVariables, rateLimitKeyVariables, isPlus)...)
 }
 
+// connectionLimitKeyVariables includes NGINX stream variables allowed to be used in a connectionLimit policy key.
+var connectionLimitKeyVariables = map[string]bool{
+	"binary_remote_addr":      true,
+	"remote_addr":             true,
+	"server_addr":             true,
+	"server_port":             true,
+	"ssl_preread_server_name": true,
+}
+
+func validateConnectionLimitKey(key string, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	if key == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	allErrs := field.ErrorList{}
+	if err := ValidateEscapedString(key, `Hello World! \n`, `\"${remote_addr}\" is unavailable. \n`); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, key, err.Error()))
+	}
+	return append(allErrs, validateStringWithVariables(key, fieldPath, nil, connectionLimitKeyVariables, isPlus)...)
+}
+
 var jwtTokenSpecialVariables = []string{"arg_", "http_", "cookie_"}
 
 func validateJWTToken(token string, fieldPath *field.Path) field.ErrorList {
@@ -522,7 +1089,7 @@ func validateJWTToken(token string, fieldPath *field.Path) field.ErrorList {
 	if !special {
 		return field.ErrorList{field.Invalid(fieldPath, token, "must only have special vars")}
 	}
-	// validateJWTToken is called only when NGINX Plus is running
+	// The token can't reference the jwt_ variables, which are the only ones that depend on NGINX Plus
 	return validateSpecialVariable(nVar, fieldPath, true)
 }
 
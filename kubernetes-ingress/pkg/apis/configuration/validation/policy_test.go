@@ -281,7 +281,23 @@ func TestValidatePolicy_PassesOnValidInput(t *testing.T) {
 			isPlus:           true,
 			enableOIDC:       false,
 			enableAppProtect: false,
-			msg:              "use jwt(plus only) policy",
+			msg:              "use jwt policy",
+		},
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:    "My Product API",
+						JwksURI:  "https://login.mydomain.com/keys",
+						KeyCache: "1h",
+						Token:    "$arg_token",
+					},
+				},
+			},
+			isPlus:           false,
+			enableOIDC:       false,
+			enableAppProtect: false,
+			msg:              "use jwt policy on OSS",
 		},
 		{
 			policy: &v1.Policy{
@@ -361,20 +377,6 @@ func TestValidatePolicy_FailsOnInvalidInput(t *testing.T) {
 			enableAppProtect: false,
 			msg:              "multiple policies in spec",
 		},
-		{
-			policy: &v1.Policy{
-				Spec: v1.PolicySpec{
-					JWTAuth: &v1.JWTAuth{
-						Realm:  "My Product API",
-						Secret: "my-jwk",
-					},
-				},
-			},
-			isPlus:           false,
-			enableOIDC:       false,
-			enableAppProtect: false,
-			msg:              "jwt(plus only) policy on OSS",
-		},
 		{
 			policy: &v1.Policy{
 				Spec: v1.PolicySpec{
@@ -581,6 +583,26 @@ func TestValidateRateLimit_PassesOnValidInput(t *testing.T) {
 			},
 			msg: "ratelimit all fields set",
 		},
+		{
+			rateLimit: &v1.RateLimit{
+				Key:      "${binary_remote_addr}",
+				ZoneSize: "10M",
+				Tiers: []v1.RateLimitTier{
+					{
+						Name: "free",
+						Rate: "10r/s",
+					},
+					{
+						Name:  "gold",
+						Rate:  "200r/s",
+						Burst: createPointerFromInt(50),
+					},
+				},
+				TierSelector: "${http_x_plan}",
+				DefaultTier:  "free",
+			},
+			msg: "ratelimit with tiers",
+		},
 	}
 
 	isPlus := false
@@ -651,6 +673,73 @@ func TestValidateRateLimit_FailsOnInvalidInput(t *testing.T) {
 			}),
 			msg: "invalid rateLimit logLevel",
 		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.TierSelector = "${http_x_plan}"
+			}),
+			msg: "rateLimit tierSelector without tiers",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Tiers = []v1.RateLimitTier{{Name: "free", Rate: "10r/s"}}
+				r.TierSelector = "${http_x_plan}"
+				r.DefaultTier = "free"
+			}),
+			msg: "rateLimit rate with tiers",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Rate = ""
+				r.Tiers = []v1.RateLimitTier{{Name: "free", Rate: "10r/s"}}
+				r.DefaultTier = "free"
+			}),
+			msg: "rateLimit tiers without tierSelector",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Rate = ""
+				r.Tiers = []v1.RateLimitTier{{Name: "free", Rate: "10r/s"}}
+				r.TierSelector = "${jwt_claim_plan}"
+				r.DefaultTier = "free"
+			}),
+			msg: "rateLimit tierSelector with jwt claim for NGINX",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Rate = ""
+				r.Tiers = []v1.RateLimitTier{{Name: "free", Rate: "10r/s"}}
+				r.TierSelector = "plan-${http_x_plan}"
+				r.DefaultTier = "free"
+			}),
+			msg: "rateLimit tierSelector with text",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Rate = ""
+				r.Tiers = []v1.RateLimitTier{{Name: "free", Rate: "10r/s"}, {Name: "free", Rate: "20r/s"}}
+				r.TierSelector = "${http_x_plan}"
+				r.DefaultTier = "free"
+			}),
+			msg: "rateLimit duplicate tiers",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Rate = ""
+				r.Tiers = []v1.RateLimitTier{{Name: "gold-plan", Rate: "0r/s"}}
+				r.TierSelector = "${http_x_plan}"
+				r.DefaultTier = "gold-plan"
+			}),
+			msg: "rateLimit invalid tier",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Rate = ""
+				r.Tiers = []v1.RateLimitTier{{Name: "free", Rate: "10r/s"}}
+				r.TierSelector = "${http_x_plan}"
+				r.DefaultTier = "gold"
+			}),
+			msg: "rateLimit unknown defaultTier",
+		},
 	}
 
 	isPlus := false
@@ -663,6 +752,102 @@ func TestValidateRateLimit_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateConnectionLimit_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	dryRun := true
+
+	tests := []struct {
+		connectionLimit *v1.ConnectionLimit
+		msg             string
+	}{
+		{
+			connectionLimit: &v1.ConnectionLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: 10,
+				ZoneSize:    "10M",
+			},
+			msg: "only required fields are set",
+		},
+		{
+			connectionLimit: &v1.ConnectionLimit{
+				Key:         "${ssl_preread_server_name}",
+				Connections: 100,
+				ZoneSize:    "1m",
+				DryRun:      &dryRun,
+				LogLevel:    "warn",
+			},
+			msg: "connectionLimit all fields set",
+		},
+	}
+
+	isPlus := false
+
+	for _, test := range tests {
+		allErrs := validateConnectionLimit(test.connectionLimit, field.NewPath("connectionLimit"), isPlus)
+		if len(allErrs) > 0 {
+			t.Errorf("validateConnectionLimit() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func createInvalidConnectionLimit(f func(c *v1.ConnectionLimit)) *v1.ConnectionLimit {
+	validConnectionLimit := &v1.ConnectionLimit{
+		Key:         "${binary_remote_addr}",
+		Connections: 10,
+		ZoneSize:    "10M",
+	}
+	f(validConnectionLimit)
+	return validConnectionLimit
+}
+
+func TestValidateConnectionLimit_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		connectionLimit *v1.ConnectionLimit
+		msg             string
+	}{
+		{
+			connectionLimit: createInvalidConnectionLimit(func(c *v1.ConnectionLimit) {
+				c.Key = ""
+			}),
+			msg: "missing connectionLimit key",
+		},
+		{
+			connectionLimit: createInvalidConnectionLimit(func(c *v1.ConnectionLimit) {
+				c.Key = "${request_uri}"
+			}),
+			msg: "invalid connectionLimit key variable use",
+		},
+		{
+			connectionLimit: createInvalidConnectionLimit(func(c *v1.ConnectionLimit) {
+				c.Connections = 0
+			}),
+			msg: "invalid connectionLimit connections",
+		},
+		{
+			connectionLimit: createInvalidConnectionLimit(func(c *v1.ConnectionLimit) {
+				c.ZoneSize = "31k"
+			}),
+			msg: "invalid connectionLimit zoneSize",
+		},
+		{
+			connectionLimit: createInvalidConnectionLimit(func(c *v1.ConnectionLimit) {
+				c.LogLevel = "invalid"
+			}),
+			msg: "invalid connectionLimit logLevel",
+		},
+	}
+
+	isPlus := false
+
+	for _, test := range tests {
+		allErrs := validateConnectionLimit(test.connectionLimit, field.NewPath("connectionLimit"), isPlus)
+		if len(allErrs) == 0 {
+			t.Errorf("validateConnectionLimit() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
 func TestValidateJWT_PassesOnValidInput(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1293,6 +1478,46 @@ func TestValidateOIDC_PassesOnValidOIDC(t *testing.T) {
 			},
 			msg: "offline access scope",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				EndSessionEndpoint:    "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/logout",
+				PostLogoutRedirectURI: "/signed-out",
+				PKCEEnable:            true,
+			},
+			msg: "logout with path redirect and pkce",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				EndSessionEndpoint:    "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/logout",
+				PostLogoutRedirectURI: "https://cafe.example.com/goodbye",
+			},
+			msg: "logout with url redirect",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				EndSessionEndpoint:    "https://idp.example.com/v2/logout?federated",
+				PostLogoutRedirectURI: "/signed-out",
+			},
+			msg: "logout with a query string in endSessionEndpoint",
+		},
 	}
 
 	for _, test := range tests {
@@ -1483,6 +1708,102 @@ func TestValidateOIDC_FailsOnInvalidOIDC(t *testing.T) {
 			},
 			msg: "invalid zoneSyncLeeway value",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:      "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:            "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+				Scope:              "openid",
+				EndSessionEndpoint: "/logout",
+			},
+			msg: "endSessionEndpoint without scheme and host",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				PostLogoutRedirectURI: "/signed out",
+			},
+			msg: "postLogoutRedirectURI path with whitespace",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				PostLogoutRedirectURI: "cafe.example.com/goodbye",
+			},
+			msg: "postLogoutRedirectURI without scheme",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				PostLogoutRedirectURI: `https://cafe.example.com/good"bye`,
+			},
+			msg: "postLogoutRedirectURI with double quote",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:          "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:         "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:               "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				Scope:                 "openid",
+				PostLogoutRedirectURI: "/signed-out$request_uri",
+			},
+			msg: "postLogoutRedirectURI path with a variable",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:      "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:            "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+				Scope:              "openid",
+				EndSessionEndpoint: `http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/log"out`,
+			},
+			msg: "endSessionEndpoint with double quote",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:      "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:            "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+				Scope:              "openid",
+				EndSessionEndpoint: "http://127.0.0.1:8080/auth/realms/$realm/protocol/openid-connect/logout",
+			},
+			msg: "endSessionEndpoint with a variable",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:       "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint:      "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/token",
+				JWKSURI:            "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/certs",
+				ClientID:           "client",
+				ClientSecret:       "secret",
+				Scope:              "openid",
+				EndSessionEndpoint: "http://127.0.0.1:8080/auth/realms/master/protocol/openid-connect/logout#end",
+			},
+			msg: "endSessionEndpoint with a fragment",
+		},
 	}
 
 	for _, test := range tests {
@@ -1754,3 +2075,758 @@ func TestValidateBasic_FailsOnMissingSecret(t *testing.T) {
 		t.Error("want error on invalid input")
 	}
 }
+
+func TestValidateCORS_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cors *v1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*"},
+			},
+			msg: "any origin",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:     []string{"https://example.com", "http://localhost:8080", `~^https://.*\.example\.com$`, `~*^https://APP\.example\.org$`},
+				AllowMethods:     []string{"GET", "PUT", "DELETE"},
+				AllowHeaders:     []string{"Content-Type", "Authorization"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				AllowCredentials: createPointerFromBool(true),
+				MaxAge:           createPointerFromInt(3600),
+			},
+			msg: "exact and regex origins with all fields",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateCORS() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCORS_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cors *v1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1.CORS{},
+			msg:  "missing origins",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"*", "https://example.com"},
+			},
+			msg: "any origin combined with other origins",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:     []string{"*"},
+				AllowCredentials: createPointerFromBool(true),
+			},
+			msg: "credentials allowed for any origin",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"example.com"},
+			},
+			msg: "origin without scheme",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"ftp://example.com"},
+			},
+			msg: "origin with invalid scheme",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com/path"},
+			},
+			msg: "origin with path",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com:99999"},
+			},
+			msg: "origin with invalid port",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"~^https://(.*\\.example\\.com$"},
+			},
+			msg: "invalid regex origin",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{`~^https://"example\.com$`},
+			},
+			msg: "regex origin with unescaped double quote",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				AllowMethods: []string{"get"},
+			},
+			msg: "invalid method",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				AllowHeaders: []string{"Content Type"},
+			},
+			msg: "invalid allowed header",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins:  []string{"https://example.com"},
+				ExposeHeaders: []string{"X-Request-Id;"},
+			},
+			msg: "invalid exposed header",
+		},
+		{
+			cors: &v1.CORS{
+				AllowOrigins: []string{"https://example.com"},
+				MaxAge:       createPointerFromInt(-1),
+			},
+			msg: "invalid max age",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCORS() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuth_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		externalAuth *v1.ExternalAuth
+		msg          string
+	}{
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: "auth-svc",
+				Port:    8080,
+				Path:    "/auth",
+			},
+			msg: "service, port and path",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service:         "auth-svc",
+				Port:            80,
+				Path:            "/api/v1/authorize",
+				RequestHeaders:  []string{"Authorization", "Cookie"},
+				ResponseHeaders: []string{"X-User-Id", "X-User-Roles"},
+			},
+			msg: "request and response headers",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.externalAuth, field.NewPath("externalAuth"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateExternalAuth() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuth_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		externalAuth *v1.ExternalAuth
+		msg          string
+	}{
+		{
+			externalAuth: &v1.ExternalAuth{
+				Port: 8080,
+				Path: "/auth",
+			},
+			msg: "missing service",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: "auth_svc",
+				Port:    8080,
+				Path:    "/auth",
+			},
+			msg: "invalid service",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: "auth-svc",
+				Path:    "/auth",
+			},
+			msg: "missing port",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: "auth-svc",
+				Port:    8080,
+			},
+			msg: "missing path",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service: "auth-svc",
+				Port:    8080,
+				Path:    "auth",
+			},
+			msg: "invalid path",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service:        "auth-svc",
+				Port:           8080,
+				Path:           "/auth",
+				RequestHeaders: []string{"Authorization:"},
+			},
+			msg: "invalid request header",
+		},
+		{
+			externalAuth: &v1.ExternalAuth{
+				Service:         "auth-svc",
+				Port:            8080,
+				Path:            "/auth",
+				ResponseHeaders: []string{"X User Id"},
+			},
+			msg: "invalid response header",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.externalAuth, field.NewPath("externalAuth"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateExternalAuth() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateAPIKey_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		apiKey *v1.APIKey
+		msg    string
+	}{
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{"X-API-Key"},
+				},
+				ClientSecret: "api-key-secret",
+			},
+			msg: "key supplied in a header",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Query: []string{"apikey", "api_key"},
+				},
+				ClientSecret: "api-key-secret",
+			},
+			msg: "key supplied in query arguments",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{"X-API-Key"},
+					Query:  []string{"apikey"},
+				},
+				ClientSecret: "api-key-secret",
+				RejectCode:   createPointerFromInt(403),
+			},
+			msg: "key supplied in a header or a query argument with reject code",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateAPIKey(test.apiKey, field.NewPath("apiKey"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateAPIKey() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateAPIKey_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		apiKey *v1.APIKey
+		msg    string
+	}{
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{"X-API-Key"},
+				},
+			},
+			msg: "missing client secret",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{"X-API-Key"},
+				},
+				ClientSecret: "api_key_secret",
+			},
+			msg: "invalid client secret",
+		},
+		{
+			apiKey: &v1.APIKey{
+				ClientSecret: "api-key-secret",
+			},
+			msg: "missing suppliedIn",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn:   &v1.SuppliedIn{},
+				ClientSecret: "api-key-secret",
+			},
+			msg: "empty suppliedIn",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{"X API Key"},
+				},
+				ClientSecret: "api-key-secret",
+			},
+			msg: "invalid header",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Query: []string{"api-key"},
+				},
+				ClientSecret: "api-key-secret",
+			},
+			msg: "invalid query argument",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{"X-API-Key"},
+				},
+				ClientSecret: "api-key-secret",
+				RejectCode:   createPointerFromInt(200),
+			},
+			msg: "invalid reject code",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateAPIKey(test.apiKey, field.NewPath("apiKey"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateAPIKey() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateBandwidthLimit_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	dryRun := true
+
+	tests := []struct {
+		bandwidthLimit *v1.BandwidthLimit
+		msg            string
+	}{
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: 10,
+				ZoneSize:    "10M",
+			},
+			msg: "only connections are set",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:      "1m",
+				RateAfter: "10m",
+			},
+			msg: "only rate is set",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: 2,
+				ZoneSize:    "10M",
+				DryRun:      &dryRun,
+				LogLevel:    "warn",
+				RejectCode:  createPointerFromInt(429),
+				Rate:        "500k",
+			},
+			msg: "bandwidthLimit all fields set",
+		},
+	}
+
+	isPlus := false
+
+	for _, test := range tests {
+		allErrs := validateBandwidthLimit(test.bandwidthLimit, field.NewPath("bandwidthLimit"), isPlus)
+		if len(allErrs) > 0 {
+			t.Errorf("validateBandwidthLimit() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateBandwidthLimit_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		bandwidthLimit *v1.BandwidthLimit
+		msg            string
+	}{
+		{
+			bandwidthLimit: &v1.BandwidthLimit{},
+			msg:            "neither connections nor rate are set",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: -1,
+				ZoneSize:    "10M",
+			},
+			msg: "invalid connections",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Connections: 10,
+				ZoneSize:    "10M",
+			},
+			msg: "missing key",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: 10,
+				ZoneSize:    "31k",
+			},
+			msg: "invalid zoneSize",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: 10,
+				ZoneSize:    "10M",
+				RejectCode:  createPointerFromInt(600),
+			},
+			msg: "invalid rejectCode",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:  "${binary_remote_addr}",
+				Rate: "1m",
+			},
+			msg: "key without connections",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate: "1 mb",
+			},
+			msg: "invalid rate",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Key:         "${binary_remote_addr}",
+				Connections: 10,
+				ZoneSize:    "10M",
+				RateAfter:   "10m",
+			},
+			msg: "rateAfter without rate",
+		},
+	}
+
+	isPlus := false
+
+	for _, test := range tests {
+		allErrs := validateBandwidthLimit(test.bandwidthLimit, field.NewPath("bandwidthLimit"), isPlus)
+		if len(allErrs) == 0 {
+			t.Errorf("validateBandwidthLimit() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateGeo_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		geo *v1.Geo
+		msg string
+	}{
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US", "CA"},
+			},
+			msg: "allowed countries",
+		},
+		{
+			geo: &v1.Geo{
+				ASNDatabase: "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+				DenyASNs:    []int{64496},
+			},
+			msg: "denied autonomous systems",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				ASNDatabase:     "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+				DenyCountries:   []string{"DE"},
+				AllowASNs:       []int{64496, 64497},
+				UnknownAction:   "deny",
+				RejectCode:      createPointerFromInt(451),
+				CountryHeader:   "X-Country",
+				ASNHeader:       "X-ASN",
+			},
+			msg: "countries, autonomous systems and headers",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateGeo(test.geo, field.NewPath("geo"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateGeo() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateGeo_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		geo *v1.Geo
+		msg string
+	}{
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+			},
+			msg: "no countries or autonomous systems",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US"},
+				DenyCountries:   []string{"DE"},
+			},
+			msg: "both allowed and denied countries",
+		},
+		{
+			geo: &v1.Geo{
+				ASNDatabase: "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+				AllowASNs:   []int{64496},
+				DenyASNs:    []int{64497},
+			},
+			msg: "both allowed and denied autonomous systems",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"usa"},
+			},
+			msg: "invalid country code",
+		},
+		{
+			geo: &v1.Geo{
+				ASNDatabase: "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+				AllowASNs:   []int{0},
+			},
+			msg: "invalid autonomous system number",
+		},
+		{
+			geo: &v1.Geo{
+				AllowCountries: []string{"US"},
+			},
+			msg: "missing country database",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US"},
+				ASNHeader:       "X-ASN",
+			},
+			msg: "missing asn database",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US"},
+			},
+			msg: "relative database path",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US"},
+				UnknownAction:   "reject",
+			},
+			msg: "invalid unknown action",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US"},
+				RejectCode:      createPointerFromInt(200),
+			},
+			msg: "invalid reject code",
+		},
+		{
+			geo: &v1.Geo{
+				CountryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+				AllowCountries:  []string{"US"},
+				CountryHeader:   "X Country",
+			},
+			msg: "invalid header",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateGeo(test.geo, field.NewPath("geo"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateGeo() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateJWTClaims_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		claims *v1.JWTClaims
+		msg    string
+	}{
+		{
+			claims: &v1.JWTClaims{
+				Issuer:   "https://idp.example.com",
+				Audience: "jobs-api",
+			},
+			msg: "issuer and audience",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Scopes:     []string{"jobs:read", "jobs:write"},
+				Roles:      []string{"admin"},
+				RolesClaim: "groups",
+				RejectBody: "Access denied",
+			},
+			msg: "scopes, roles and reject body",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Match: []v1.JWTClaimMatch{
+					{
+						Name:  "tenant_id",
+						Value: "cafe",
+					},
+					{
+						Name:  "email",
+						Regex: `@example\.com$`,
+					},
+				},
+			},
+			msg: "claim value and regex",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateJWTClaims(test.claims, field.NewPath("claims"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateJWTClaims() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateJWTClaims_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		claims *v1.JWTClaims
+		msg    string
+	}{
+		{
+			claims: &v1.JWTClaims{},
+			msg:    "no claims",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Issuer: `https://idp.example.com"`,
+			},
+			msg: "invalid issuer",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Scopes: []string{"jobs:read jobs:write"},
+			},
+			msg: "scope with whitespace",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Issuer:     "https://idp.example.com",
+				RolesClaim: "groups",
+			},
+			msg: "roles claim without roles",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Roles:      []string{"admin"},
+				RolesClaim: "realm-roles",
+			},
+			msg: "invalid roles claim",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Match: []v1.JWTClaimMatch{
+					{
+						Value: "cafe",
+					},
+				},
+			},
+			msg: "missing claim name",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Match: []v1.JWTClaimMatch{
+					{
+						Name: "tenant_id",
+					},
+				},
+			},
+			msg: "missing claim value and regex",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Match: []v1.JWTClaimMatch{
+					{
+						Name:  "tenant_id",
+						Value: "cafe",
+						Regex: "^cafe$",
+					},
+				},
+			},
+			msg: "both claim value and regex",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Match: []v1.JWTClaimMatch{
+					{
+						Name:  "email",
+						Regex: "(@example.com",
+					},
+				},
+			},
+			msg: "invalid regex",
+		},
+		{
+			claims: &v1.JWTClaims{
+				Issuer:     "https://idp.example.com",
+				RejectBody: "Access denied for $remote_user",
+			},
+			msg: "reject body with a variable",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateJWTClaims(test.claims, field.NewPath("claims"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateJWTClaims() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	v1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1"
 	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -30,27 +31,33 @@ func NewTransportServerValidator(tlsPassthrough bool, snippetsEnabled bool, isPl
 
 // ValidateTransportServer validates a TransportServer.
 func (tsv *TransportServerValidator) ValidateTransportServer(transportServer *v1alpha1.TransportServer) error {
-	allErrs := tsv.validateTransportServerSpec(&transportServer.Spec, field.NewPath("spec"))
+	allErrs := tsv.validateTransportServerSpec(&transportServer.Spec, field.NewPath("spec"), transportServer.Namespace)
 	return allErrs.ToAggregate()
 }
 
-func (tsv *TransportServerValidator) validateTransportServerSpec(spec *v1alpha1.TransportServerSpec, fieldPath *field.Path) field.ErrorList {
+func (tsv *TransportServerValidator) validateTransportServerSpec(spec *v1alpha1.TransportServerSpec, fieldPath *field.Path, namespace string) field.ErrorList {
 	allErrs := tsv.validateTransportListener(&spec.Listener, fieldPath.Child("listener"))
 
 	isTLSPassthroughListener := isPotentialTLSPassthroughListener(&spec.Listener)
-	allErrs = append(allErrs, validateTransportServerHost(spec.Host, fieldPath.Child("host"), isTLSPassthroughListener)...)
+	isTLSTermination := spec.TLS != nil && spec.Listener.Protocol == "TCP"
+	allErrs = append(allErrs, validateTransportServerHost(spec.Host, fieldPath.Child("host"), isTLSPassthroughListener, isTLSTermination)...)
 
 	upstreamErrs, upstreamNames := validateTransportServerUpstreams(spec.Upstreams, fieldPath.Child("upstreams"), tsv.isPlus)
 	allErrs = append(allErrs, upstreamErrs...)
 
+	allErrs = append(allErrs, validateTransportServerUpstreamsProxyProtocol(spec.Upstreams, fieldPath.Child("upstreams"), spec.Listener.Protocol)...)
+
 	allErrs = append(allErrs, validateTransportServerUpstreamParameters(spec.UpstreamParameters, fieldPath.Child("upstreamParameters"), spec.Listener.Protocol)...)
 
 	allErrs = append(allErrs, validateSessionParameters(spec.SessionParameters, fieldPath.Child("sessionParameters"))...)
 
-	if spec.Action == nil {
-		allErrs = append(allErrs, field.Required(fieldPath.Child("action"), "must specify action"))
-	} else {
-		allErrs = append(allErrs, validateTransportServerAction(spec.Action, fieldPath.Child("action"), upstreamNames)...)
+	sslPrereadAllowed := spec.TLS == nil && spec.Listener.Protocol != "UDP"
+	allErrs = append(allErrs, tsv.validateTransportServerActionOrSplits(spec.Action, spec.Splits, "", fieldPath, upstreamNames, sslPrereadAllowed)...)
+
+	allErrs = append(allErrs, tsv.validateTransportServerSpecSplitKey(spec, fieldPath.Child("splitKey"), sslPrereadAllowed)...)
+
+	for i, m := range spec.Matches {
+		allErrs = append(allErrs, tsv.validateTransportServerMatch(m, fieldPath.Child("matches").Index(i), upstreamNames, sslPrereadAllowed)...)
 	}
 
 	allErrs = append(allErrs, validateSnippets(spec.ServerSnippets, fieldPath.Child("serverSnippets"), tsv.snippetsEnabled)...)
@@ -59,9 +66,24 @@ func (tsv *TransportServerValidator) validateTransportServerSpec(spec *v1alpha1.
 
 	allErrs = append(allErrs, validateTLS(spec.TLS, isTLSPassthroughListener, fieldPath.Child("tls"))...)
 
+	allErrs = append(allErrs, validateTransportServerPolicies(spec.Policies, fieldPath.Child("policies"), namespace)...)
+
+	allErrs = append(allErrs, validateTransportServerLog(spec.Log, fieldPath.Child("log"))...)
+
 	return allErrs
 }
 
+func validateTransportServerPolicies(policies []v1alpha1.PolicyReference, fieldPath *field.Path, namespace string) field.ErrorList {
+	var refs []v1.PolicyReference
+	for _, p := range policies {
+		refs = append(refs, v1.PolicyReference{
+			Name:      p.Name,
+			Namespace: p.Namespace,
+		})
+	}
+	return validatePolicies(refs, fieldPath, namespace)
+}
+
 func validateTLS(tls *v1alpha1.TLS, isTLSPassthrough bool, fieldPath *field.Path) field.ErrorList {
 	if tls == nil {
 		return nil
@@ -82,13 +104,19 @@ func validateSnippets(serverSnippet string, fieldPath *field.Path, snippetsEnabl
 	return nil
 }
 
-func validateTransportServerHost(host string, fieldPath *field.Path, isTLSPassthroughListener bool) field.ErrorList {
-	if !isTLSPassthroughListener {
-		if host != "" {
-			return field.ErrorList{field.Forbidden(fieldPath, "host field is allowed only for TLS Passthrough TransportServers")}
-		}
+// validateTransportServerHost validates the host of a TransportServer. The host is required for TLS Passthrough
+// TransportServers. TransportServers that terminate TLS on a TCP listener can set the host to share the listener
+// with other TransportServers, so that NGINX routes the connections by SNI.
+func validateTransportServerHost(host string, fieldPath *field.Path, isTLSPassthroughListener bool, isTLSTermination bool) field.ErrorList {
+	if isTLSPassthroughListener {
+		return validateHost(host, fieldPath)
+	}
+	if host == "" {
 		return nil
 	}
+	if !isTLSTermination {
+		return field.ErrorList{field.Forbidden(fieldPath, "host field is allowed only for TLS Passthrough TransportServers and TransportServers with TLS termination on a TCP listener")}
+	}
 	return validateHost(host, fieldPath)
 }
 
@@ -162,6 +190,24 @@ func validateTransportServerUpstreams(upstreams []v1alpha1.Upstream, fieldPath *
 	return allErrs, upstreamNames
 }
 
+// validateTransportServerUpstreamsProxyProtocol validates the proxyProtocol field of the upstreams. NGINX enables
+// the PROXY protocol for the connections of a server to all its upstreams, so all upstreams must use the same value.
+func validateTransportServerUpstreamsProxyProtocol(upstreams []v1alpha1.Upstream, fieldPath *field.Path, protocol string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, u := range upstreams {
+		idxPath := fieldPath.Index(i).Child("proxyProtocol")
+
+		if u.ProxyProtocol && protocol == "UDP" {
+			allErrs = append(allErrs, field.Forbidden(idxPath, "is not supported for UDP TransportServers"))
+		} else if u.ProxyProtocol != upstreams[0].ProxyProtocol {
+			allErrs = append(allErrs, field.Invalid(idxPath, u.ProxyProtocol, "must be the same for all upstreams"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateLoadBalancingMethod(method string, fieldPath *field.Path, isPlus bool) field.ErrorList {
 	if method == "" {
 		return nil
@@ -356,3 +402,199 @@ func validateTransportServerAction(action *v1alpha1.Action, fieldPath *field.Pat
 	}
 	return validateReferencedUpstream(action.Pass, fieldPath.Child("pass"), upstreamNames)
 }
+
+// validateTransportServerActionOrSplits validates the action or the splits of a TransportServer or of its match.
+// sslPrereadAllowed tells if the $ssl_preread_server_name variable can be used, which is not the case
+// for TransportServers that terminate TLS or use the UDP protocol.
+func (tsv *TransportServerValidator) validateTransportServerActionOrSplits(action *v1alpha1.Action, splits []v1alpha1.Split, splitKey string,
+	fieldPath *field.Path, upstreamNames sets.Set[string], sslPrereadAllowed bool,
+) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldCount := 0
+
+	if action != nil {
+		allErrs = append(allErrs, validateTransportServerAction(action, fieldPath.Child("action"), upstreamNames)...)
+		fieldCount++
+	}
+
+	if len(splits) > 0 {
+		allErrs = append(allErrs, validateTransportServerSplits(splits, fieldPath.Child("splits"), upstreamNames)...)
+		fieldCount++
+	}
+
+	if fieldCount != 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of `action` or `splits`"))
+	}
+
+	if splitKey != "" {
+		if len(splits) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("splitKey"), "can only be used with `splits`"))
+		} else {
+			allErrs = append(allErrs, tsv.validateTransportServerSplitKey(splitKey, fieldPath.Child("splitKey"), sslPrereadAllowed)...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateTransportServerSpecSplitKey validates the split key of a TransportServer, which applies to the splits
+// of the TransportServer and to the splits of its matches that don't define their own key.
+func (tsv *TransportServerValidator) validateTransportServerSpecSplitKey(spec *v1alpha1.TransportServerSpec, fieldPath *field.Path, sslPrereadAllowed bool) field.ErrorList {
+	if spec.SplitKey == "" {
+		return nil
+	}
+
+	usedBySplits := len(spec.Splits) > 0
+	for _, m := range spec.Matches {
+		if len(m.Splits) > 0 && m.SplitKey == "" {
+			usedBySplits = true
+		}
+	}
+	if !usedBySplits {
+		return field.ErrorList{field.Forbidden(fieldPath, "can only be used with `splits` of the TransportServer or of matches without their own `splitKey`")}
+	}
+
+	return tsv.validateTransportServerSplitKey(spec.SplitKey, fieldPath, sslPrereadAllowed)
+}
+
+func validateTransportServerSplits(splits []v1alpha1.Split, fieldPath *field.Path, upstreamNames sets.Set[string]) field.ErrorList {
+	weights := make([]int, 0, len(splits))
+	for _, s := range splits {
+		weights = append(weights, s.Weight)
+	}
+
+	allErrs := validateSplitWeights(weights, fieldPath)
+	if len(splits) < 2 {
+		return allErrs
+	}
+
+	for i, s := range splits {
+		idxPath := fieldPath.Index(i)
+
+		if s.Action == nil {
+			allErrs = append(allErrs, field.Required(idxPath.Child("action"), ""))
+		} else {
+			allErrs = append(allErrs, validateTransportServerAction(s.Action, idxPath.Child("action"), upstreamNames)...)
+		}
+	}
+
+	return allErrs
+}
+
+// transportServerSplitKeyVariables includes NGINX stream variables allowed to be used in a TransportServer split key.
+var transportServerSplitKeyVariables = map[string]bool{
+	"binary_remote_addr":      true,
+	"remote_addr":             true,
+	"remote_port":             true,
+	"server_addr":             true,
+	"server_port":             true,
+	"ssl_preread_server_name": true,
+}
+
+func (tsv *TransportServerValidator) validateTransportServerSplitKey(key string, fieldPath *field.Path, sslPrereadAllowed bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := ValidateEscapedString(key, "${remote_addr}", "${remote_addr}${remote_port}"); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, key, err.Error()))
+	}
+	if !sslPrereadAllowed && strings.Contains(key, "${ssl_preread_server_name}") {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "ssl_preread_server_name variable is not supported for TransportServers with TLS termination or the UDP protocol"))
+	}
+	return append(allErrs, validateStringWithVariables(key, fieldPath, []string{}, transportServerSplitKeyVariables, tsv.isPlus)...)
+}
+
+func (tsv *TransportServerValidator) validateTransportServerMatch(match v1alpha1.TransportServerMatch, fieldPath *field.Path,
+	upstreamNames sets.Set[string], sslPrereadAllowed bool,
+) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(match.Conditions) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("conditions"), "must specify at least one condition"))
+	} else {
+		for i, c := range match.Conditions {
+			allErrs = append(allErrs, validateTransportServerCondition(c, fieldPath.Child("conditions").Index(i), sslPrereadAllowed)...)
+		}
+	}
+
+	return append(allErrs, tsv.validateTransportServerActionOrSplits(match.Action, match.Splits, match.SplitKey, fieldPath, upstreamNames, sslPrereadAllowed)...)
+}
+
+// transportServerConditionVariables includes NGINX stream variables allowed to be used in TransportServer conditions.
+var transportServerConditionVariables = map[string]bool{
+	"$remote_addr":             true,
+	"$ssl_preread_server_name": true,
+}
+
+func validateTransportServerCondition(condition v1alpha1.Condition, fieldPath *field.Path, sslPrereadAllowed bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case condition.Variable == "":
+		allErrs = append(allErrs, field.Required(fieldPath.Child("variable"), ""))
+	case !transportServerConditionVariables[condition.Variable]:
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("variable"), condition.Variable, sets.List(sets.KeySet(transportServerConditionVariables))))
+	case condition.Variable == "$ssl_preread_server_name" && !sslPrereadAllowed:
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("variable"), "is not supported for TransportServers with TLS termination or the UDP protocol"))
+	}
+
+	for _, msg := range isValidMatchValue(condition.Value) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("value"), condition.Value, msg))
+	}
+
+	return allErrs
+}
+
+const (
+	logFormatNameFmt    = `[a-zA-Z0-9_-]+`
+	logFormatNameErrMsg = "must contain only alphanumeric characters, '-' or '_'"
+)
+
+var logFormatNameRegexp = regexp.MustCompile("^" + logFormatNameFmt + "$")
+
+const (
+	syslogDestinationFmt    = `syslog:server=[^\s"'\\;{}$]+`
+	syslogDestinationErrMsg = "must be 'stdout', 'off' or 'syslog:server=<address>' optionally followed by comma-separated syslog parameters"
+)
+
+var syslogDestinationRegexp = regexp.MustCompile("^" + syslogDestinationFmt + "$")
+
+const (
+	logConditionFmt    = `\$[a-zA-Z_][a-zA-Z0-9_]*`
+	logConditionErrMsg = "must be an NGINX variable"
+)
+
+var logConditionRegexp = regexp.MustCompile("^" + logConditionFmt + "$")
+
+func validateTransportServerLog(log *v1alpha1.TransportServerLog, fieldPath *field.Path) field.ErrorList {
+	if log == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+
+	switch {
+	case log.Destination == "", log.Destination == "stdout":
+	case log.Destination == "off":
+		if log.Format != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("format"), "cannot be set when the access log is off"))
+		}
+		if log.Condition != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("condition"), "cannot be set when the access log is off"))
+		}
+	case !syslogDestinationRegexp.MatchString(log.Destination):
+		msg := validation.RegexError(syslogDestinationErrMsg, syslogDestinationFmt, "syslog:server=10.0.0.1:514", "syslog:server=logs.example.com,tag=nginx")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("destination"), log.Destination, msg))
+	}
+
+	if log.Format != "" && !logFormatNameRegexp.MatchString(log.Format) {
+		msg := validation.RegexError(logFormatNameErrMsg, logFormatNameFmt, "tcp-audit", "tcp_audit")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("format"), log.Format, msg))
+	}
+
+	if log.Condition != "" && !logConditionRegexp.MatchString(log.Condition) {
+		msg := validation.RegexError(logConditionErrMsg, logConditionFmt, "$loggable", "$is_audited")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("condition"), log.Condition, msg))
+	}
+
+	return allErrs
+}
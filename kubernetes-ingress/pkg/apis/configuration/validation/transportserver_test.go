@@ -69,6 +69,77 @@ func TestValidateTransportServer_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateTransportServerPolicies(t *testing.T) {
+	t.Parallel()
+	policies := []v1alpha1.PolicyReference{
+		{
+			Name: "allow-policy",
+		},
+		{
+			Name:      "conn-limit",
+			Namespace: "nginx-ingress",
+		},
+	}
+
+	allErrs := validateTransportServerPolicies(policies, field.NewPath("policies"), "default")
+	if len(allErrs) > 0 {
+		t.Errorf("validateTransportServerPolicies() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateTransportServerPolicies_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		policies []v1alpha1.PolicyReference
+		msg      string
+	}{
+		{
+			policies: []v1alpha1.PolicyReference{
+				{
+					Name: "",
+				},
+			},
+			msg: "missing name",
+		},
+		{
+			policies: []v1alpha1.PolicyReference{
+				{
+					Name: "-invalid",
+				},
+			},
+			msg: "invalid name",
+		},
+		{
+			policies: []v1alpha1.PolicyReference{
+				{
+					Name:      "valid",
+					Namespace: "-invalid",
+				},
+			},
+			msg: "invalid namespace",
+		},
+		{
+			policies: []v1alpha1.PolicyReference{
+				{
+					Name: "duplicate",
+				},
+				{
+					Name:      "duplicate",
+					Namespace: "default",
+				},
+			},
+			msg: "duplicated policies",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerPolicies(test.policies, field.NewPath("policies"), "default")
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerPolicies() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateTransportServerUpstreams(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -188,26 +259,195 @@ func TestValidateTransportServerUpstreams_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateTransportServerUpstreamsProxyProtocol(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		upstreams []v1alpha1.Upstream
+		protocol  string
+		msg       string
+	}{
+		{
+			upstreams: []v1alpha1.Upstream{{Name: "app"}, {Name: "app-v2"}},
+			protocol:  "UDP",
+			msg:       "proxy protocol disabled",
+		},
+		{
+			upstreams: []v1alpha1.Upstream{{Name: "app", ProxyProtocol: true}, {Name: "app-v2", ProxyProtocol: true}},
+			protocol:  "TCP",
+			msg:       "proxy protocol enabled for all upstreams",
+		},
+		{
+			upstreams: []v1alpha1.Upstream{{Name: "app", ProxyProtocol: true}},
+			protocol:  "TLS_PASSTHROUGH",
+			msg:       "proxy protocol enabled for TLS passthrough",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerUpstreamsProxyProtocol(test.upstreams, field.NewPath("upstreams"), test.protocol)
+		if len(allErrs) > 0 {
+			t.Errorf("validateTransportServerUpstreamsProxyProtocol() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerUpstreamsProxyProtocol_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		upstreams []v1alpha1.Upstream
+		protocol  string
+		msg       string
+	}{
+		{
+			upstreams: []v1alpha1.Upstream{{Name: "app", ProxyProtocol: true}, {Name: "app-v2"}},
+			protocol:  "TCP",
+			msg:       "proxy protocol enabled only for some upstreams",
+		},
+		{
+			upstreams: []v1alpha1.Upstream{{Name: "app", ProxyProtocol: true}},
+			protocol:  "UDP",
+			msg:       "proxy protocol enabled for UDP",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerUpstreamsProxyProtocol(test.upstreams, field.NewPath("upstreams"), test.protocol)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerUpstreamsProxyProtocol() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerLog(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		log *v1alpha1.TransportServerLog
+		msg string
+	}{
+		{
+			log: nil,
+			msg: "no log",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{},
+			msg: "empty log",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Format:      "tcp-audit",
+				Destination: "stdout",
+				Condition:   "$loggable",
+			},
+			msg: "stdout destination with format and condition",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Format:      "tcp_audit",
+				Destination: "syslog:server=10.0.0.1:514,facility=local7,tag=nginx",
+			},
+			msg: "syslog destination",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Destination: "off",
+			},
+			msg: "log off",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerLog(test.log, field.NewPath("log"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateTransportServerLog() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerLog_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		log *v1alpha1.TransportServerLog
+		msg string
+	}{
+		{
+			log: &v1alpha1.TransportServerLog{
+				Destination: "/var/log/nginx/tcp.log",
+			},
+			msg: "file destination",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Destination: "syslog:server=10.0.0.1:514; error_log",
+			},
+			msg: "syslog destination with an invalid character",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Format: "tcp audit",
+			},
+			msg: "invalid format name",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Condition: "loggable",
+			},
+			msg: "condition is not a variable",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Destination: "off",
+				Format:      "tcp-audit",
+			},
+			msg: "format with log off",
+		},
+		{
+			log: &v1alpha1.TransportServerLog{
+				Destination: "off",
+				Condition:   "$loggable",
+			},
+			msg: "condition with log off",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerLog(test.log, field.NewPath("log"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerLog() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateTransportServerHost(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		host                     string
 		isTLSPassthroughListener bool
+		isTLSTermination         bool
 	}{
 		{
 			host:                     "",
 			isTLSPassthroughListener: false,
 		},
+		{
+			host:                     "",
+			isTLSPassthroughListener: false,
+			isTLSTermination:         true,
+		},
 		{
 			host:                     "nginx.org",
 			isTLSPassthroughListener: true,
 		},
+		{
+			host:                     "nginx.org",
+			isTLSPassthroughListener: false,
+			isTLSTermination:         true,
+		},
 	}
 
 	for _, test := range tests {
-		allErrs := validateTransportServerHost(test.host, field.NewPath("host"), test.isTLSPassthroughListener)
+		allErrs := validateTransportServerHost(test.host, field.NewPath("host"), test.isTLSPassthroughListener, test.isTLSTermination)
 		if len(allErrs) > 0 {
-			t.Errorf("validateTransportServerHost(%q, %v) returned errors %v for valid input", test.host, test.isTLSPassthroughListener, allErrs)
+			t.Errorf("validateTransportServerHost(%q, %v, %v) returned errors %v for valid input", test.host, test.isTLSPassthroughListener, test.isTLSTermination, allErrs)
 		}
 	}
 }
@@ -371,6 +611,7 @@ func TestValidateTransportServerHost_FailsOnInvalidInput(t *testing.T) {
 	tests := []struct {
 		host                     string
 		isTLSPassthroughListener bool
+		isTLSTermination         bool
 	}{
 		{
 			host:                     "nginx.org",
@@ -380,12 +621,17 @@ func TestValidateTransportServerHost_FailsOnInvalidInput(t *testing.T) {
 			host:                     "",
 			isTLSPassthroughListener: true,
 		},
+		{
+			host:                     "nginx_org",
+			isTLSPassthroughListener: false,
+			isTLSTermination:         true,
+		},
 	}
 
 	for _, test := range tests {
-		allErrs := validateTransportServerHost(test.host, field.NewPath("host"), test.isTLSPassthroughListener)
+		allErrs := validateTransportServerHost(test.host, field.NewPath("host"), test.isTLSPassthroughListener, test.isTLSTermination)
 		if len(allErrs) == 0 {
-			t.Errorf("validateTransportServerHost(%q, %v) returned no errors for invalid input", test.host, test.isTLSPassthroughListener)
+			t.Errorf("validateTransportServerHost(%q, %v, %v) returned no errors for invalid input", test.host, test.isTLSPassthroughListener, test.isTLSTermination)
 		}
 	}
 }
@@ -839,6 +1085,343 @@ func TestValidateTransportServerAction_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateTransportServerActionOrSplits(t *testing.T) {
+	t.Parallel()
+	upstreamNames := sets.New("test", "test-2")
+
+	tests := []struct {
+		action   *v1alpha1.Action
+		splits   []v1alpha1.Split
+		splitKey string
+		msg      string
+	}{
+		{
+			action: &v1alpha1.Action{Pass: "test"},
+			msg:    "action",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 90, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 10, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			msg: "splits",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			splitKey: "${remote_addr}${remote_port}",
+			msg:      "splits with a split key",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			splitKey: "${ssl_preread_server_name}",
+			msg:      "splits with a split key with the ssl_preread_server_name variable",
+		},
+	}
+
+	tsv := &TransportServerValidator{}
+	for _, test := range tests {
+		allErrs := tsv.validateTransportServerActionOrSplits(test.action, test.splits, test.splitKey, field.NewPath("spec"), upstreamNames, true)
+		if len(allErrs) > 0 {
+			t.Errorf("validateTransportServerActionOrSplits() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerActionOrSplits_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	upstreamNames := sets.New("test", "test-2")
+
+	tests := []struct {
+		action            *v1alpha1.Action
+		splits            []v1alpha1.Split
+		splitKey          string
+		sslPrereadAllowed bool
+		msg               string
+	}{
+		{
+			sslPrereadAllowed: true,
+			msg:               "neither action nor splits",
+		},
+		{
+			action: &v1alpha1.Action{Pass: "test"},
+			splits: []v1alpha1.Split{
+				{Weight: 90, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 10, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			sslPrereadAllowed: true,
+			msg:               "both action and splits",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 100, Action: &v1alpha1.Action{Pass: "test"}},
+			},
+			sslPrereadAllowed: true,
+			msg:               "a single split",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 40, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			sslPrereadAllowed: true,
+			msg:               "weights don't sum up to 100",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "non-existing"}},
+			},
+			sslPrereadAllowed: true,
+			msg:               "split passes to a non-existing upstream",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 50},
+			},
+			sslPrereadAllowed: true,
+			msg:               "split without action",
+		},
+		{
+			action:            &v1alpha1.Action{Pass: "test"},
+			splitKey:          "${remote_addr}",
+			sslPrereadAllowed: true,
+			msg:               "split key without splits",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			splitKey:          "${request_uri}",
+			sslPrereadAllowed: true,
+			msg:               "split key with an unsupported variable",
+		},
+		{
+			splits: []v1alpha1.Split{
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+				{Weight: 50, Action: &v1alpha1.Action{Pass: "test-2"}},
+			},
+			splitKey:          "${ssl_preread_server_name}",
+			sslPrereadAllowed: false,
+			msg:               "split key with the ssl_preread_server_name variable when ssl_preread is not allowed",
+		},
+	}
+
+	tsv := &TransportServerValidator{}
+	for _, test := range tests {
+		allErrs := tsv.validateTransportServerActionOrSplits(test.action, test.splits, test.splitKey, field.NewPath("spec"), upstreamNames, test.sslPrereadAllowed)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerActionOrSplits() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerSpecSplitKey(t *testing.T) {
+	t.Parallel()
+	splits := []v1alpha1.Split{
+		{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+		{Weight: 50, Action: &v1alpha1.Action{Pass: "test-2"}},
+	}
+
+	tests := []struct {
+		spec *v1alpha1.TransportServerSpec
+		msg  string
+	}{
+		{
+			spec: &v1alpha1.TransportServerSpec{
+				Action: &v1alpha1.Action{Pass: "test"},
+			},
+			msg: "no split key",
+		},
+		{
+			spec: &v1alpha1.TransportServerSpec{
+				Splits:   splits,
+				SplitKey: "${remote_addr}${remote_port}",
+			},
+			msg: "split key with splits",
+		},
+		{
+			spec: &v1alpha1.TransportServerSpec{
+				Action: &v1alpha1.Action{Pass: "test"},
+				Matches: []v1alpha1.TransportServerMatch{
+					{
+						Conditions: []v1alpha1.Condition{{Variable: "$remote_addr", Value: "10.0.0.1"}},
+						Splits:     splits,
+					},
+				},
+				SplitKey: "${remote_addr}${remote_port}",
+			},
+			msg: "split key with splits of a match",
+		},
+	}
+
+	tsv := &TransportServerValidator{}
+	for _, test := range tests {
+		allErrs := tsv.validateTransportServerSpecSplitKey(test.spec, field.NewPath("splitKey"), true)
+		if len(allErrs) > 0 {
+			t.Errorf("validateTransportServerSpecSplitKey() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerSpecSplitKey_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	splits := []v1alpha1.Split{
+		{Weight: 50, Action: &v1alpha1.Action{Pass: "test"}},
+		{Weight: 50, Action: &v1alpha1.Action{Pass: "test-2"}},
+	}
+
+	tests := []struct {
+		spec *v1alpha1.TransportServerSpec
+		msg  string
+	}{
+		{
+			spec: &v1alpha1.TransportServerSpec{
+				Action:   &v1alpha1.Action{Pass: "test"},
+				SplitKey: "${remote_addr}",
+			},
+			msg: "split key without splits",
+		},
+		{
+			spec: &v1alpha1.TransportServerSpec{
+				Action: &v1alpha1.Action{Pass: "test"},
+				Matches: []v1alpha1.TransportServerMatch{
+					{
+						Conditions: []v1alpha1.Condition{{Variable: "$remote_addr", Value: "10.0.0.1"}},
+						Splits:     splits,
+						SplitKey:   "${remote_port}",
+					},
+				},
+				SplitKey: "${remote_addr}",
+			},
+			msg: "split key with splits of a match with its own split key",
+		},
+		{
+			spec: &v1alpha1.TransportServerSpec{
+				Action: &v1alpha1.Action{Pass: "test"},
+				Matches: []v1alpha1.TransportServerMatch{
+					{
+						Conditions: []v1alpha1.Condition{{Variable: "$remote_addr", Value: "10.0.0.1"}},
+						Splits:     splits,
+					},
+				},
+				SplitKey: "${request_uri}",
+			},
+			msg: "split key of matches with an unsupported variable",
+		},
+	}
+
+	tsv := &TransportServerValidator{}
+	for _, test := range tests {
+		allErrs := tsv.validateTransportServerSpecSplitKey(test.spec, field.NewPath("splitKey"), true)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerSpecSplitKey() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerMatch(t *testing.T) {
+	t.Parallel()
+	upstreamNames := sets.New("test", "test-2")
+
+	match := v1alpha1.TransportServerMatch{
+		Conditions: []v1alpha1.Condition{
+			{Variable: "$ssl_preread_server_name", Value: "app.example.com"},
+			{Variable: "$remote_addr", Value: "!10.0.0.1"},
+		},
+		Action: &v1alpha1.Action{Pass: "test"},
+	}
+
+	tsv := &TransportServerValidator{}
+	allErrs := tsv.validateTransportServerMatch(match, field.NewPath("match"), upstreamNames, true)
+	if len(allErrs) > 0 {
+		t.Errorf("validateTransportServerMatch() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateTransportServerMatch_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	upstreamNames := sets.New("test", "test-2")
+
+	tests := []struct {
+		match             v1alpha1.TransportServerMatch
+		sslPrereadAllowed bool
+		msg               string
+	}{
+		{
+			match: v1alpha1.TransportServerMatch{
+				Action: &v1alpha1.Action{Pass: "test"},
+			},
+			sslPrereadAllowed: true,
+			msg:               "no conditions",
+		},
+		{
+			match: v1alpha1.TransportServerMatch{
+				Conditions: []v1alpha1.Condition{
+					{Variable: "$remote_addr", Value: "10.0.0.1"},
+				},
+			},
+			sslPrereadAllowed: true,
+			msg:               "no action or splits",
+		},
+		{
+			match: v1alpha1.TransportServerMatch{
+				Conditions: []v1alpha1.Condition{
+					{Variable: "$server_port", Value: "5353"},
+				},
+				Action: &v1alpha1.Action{Pass: "test"},
+			},
+			sslPrereadAllowed: true,
+			msg:               "unsupported variable",
+		},
+		{
+			match: v1alpha1.TransportServerMatch{
+				Conditions: []v1alpha1.Condition{
+					{Value: "10.0.0.1"},
+				},
+				Action: &v1alpha1.Action{Pass: "test"},
+			},
+			sslPrereadAllowed: true,
+			msg:               "missing variable",
+		},
+		{
+			match: v1alpha1.TransportServerMatch{
+				Conditions: []v1alpha1.Condition{
+					{Variable: "$remote_addr", Value: `10.0.0.1"`},
+				},
+				Action: &v1alpha1.Action{Pass: "test"},
+			},
+			sslPrereadAllowed: true,
+			msg:               "invalid value",
+		},
+		{
+			match: v1alpha1.TransportServerMatch{
+				Conditions: []v1alpha1.Condition{
+					{Variable: "$ssl_preread_server_name", Value: "app.example.com"},
+				},
+				Action: &v1alpha1.Action{Pass: "test"},
+			},
+			sslPrereadAllowed: false,
+			msg:               "ssl_preread_server_name variable when ssl_preread is not allowed",
+		},
+	}
+
+	tsv := &TransportServerValidator{}
+	for _, test := range tests {
+		allErrs := tsv.validateTransportServerMatch(test.match, field.NewPath("match"), upstreamNames, test.sslPrereadAllowed)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerMatch() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateMatchSend(t *testing.T) {
 	t.Parallel()
 	validInput := []string{
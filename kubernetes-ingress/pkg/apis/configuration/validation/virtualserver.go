@@ -77,6 +77,7 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateHost(spec.Host, fieldPath.Child("host"))...)
+	allErrs = append(allErrs, validateVirtualServerListener(spec.Listener, fieldPath.Child("listener"))...)
 	allErrs = append(allErrs, vsv.validateTLS(spec.TLS, fieldPath.Child("tls"))...)
 	allErrs = append(allErrs, validatePolicies(spec.Policies, fieldPath.Child("policies"), namespace)...)
 
@@ -84,6 +85,7 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 	allErrs = append(allErrs, upstreamErrs...)
 
 	allErrs = append(allErrs, vsv.validateVirtualServerRoutes(spec.Routes, fieldPath.Child("routes"), upstreamNames, namespace)...)
+	allErrs = append(allErrs, validateMirrorUpstreamTypes(spec.Routes, spec.Upstreams, fieldPath.Child("routes"))...)
 
 	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, spec.Dos, fieldPath.Child("dos"))...)
 
@@ -113,6 +115,35 @@ func validateHost(host string, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateVirtualServerListener(listener *v1.Listener, fieldPath *field.Path) field.ErrorList {
+	if listener == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	listenerNames := sets.Set[string]{}
+
+	if listener.HTTP != "" {
+		allErrs = append(allErrs, validateListenerName(listener.HTTP, fieldPath.Child("http"))...)
+		listenerNames.Insert(listener.HTTP)
+	}
+	if listener.HTTPS != "" {
+		allErrs = append(allErrs, validateListenerName(listener.HTTPS, fieldPath.Child("https"))...)
+		listenerNames.Insert(listener.HTTPS)
+	}
+
+	for i, name := range listener.Additional {
+		idxPath := fieldPath.Child("additional").Index(i)
+		allErrs = append(allErrs, validateListenerName(name, idxPath)...)
+		if listenerNames.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+		}
+		listenerNames.Insert(name)
+	}
+
+	return allErrs
+}
+
 func validatePolicies(policies []v1.PolicyReference, fieldPath *field.Path, namespace string) field.ErrorList {
 	allErrs := field.ErrorList{}
 	policyKeys := sets.Set[string]{}
@@ -708,6 +739,14 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 		}
 	}
 
+	if route.SplitKey != "" {
+		if !routeHasSplits(route) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("splitKey"), "can only be used with `splits`"))
+		} else {
+			allErrs = append(allErrs, validateSplitKey(route.SplitKey, fieldPath.Child("splitKey"), vsv.isPlus)...)
+		}
+	}
+
 	for i, e := range route.ErrorPages {
 		allErrs = append(allErrs, vsv.validateErrorPage(e, fieldPath.Child("errorPages").Index(i))...)
 	}
@@ -1010,6 +1049,10 @@ func (vsv *VirtualServerValidator) validateActionProxy(p *v1.ActionProxy, fieldP
 	allErrs = append(allErrs, vsv.validateActionProxyRequestHeaders(p.RequestHeaders, fieldPath.Child("requestHeaders"))...)
 	allErrs = append(allErrs, vsv.validateActionProxyResponseHeaders(p.ResponseHeaders, fieldPath.Child("responseHeaders"))...)
 
+	if p.Mirror != nil {
+		allErrs = append(allErrs, validateProxyMirror(p.Mirror, p.Upstream, fieldPath.Child("mirror"), upstreamNames)...)
+	}
+
 	if strings.HasPrefix(path, "~") || internal {
 		allErrs = append(allErrs, validateActionProxyRewritePathForRegexp(p.RewritePath, fieldPath.Child("rewritePath"))...)
 	} else {
@@ -1019,6 +1062,80 @@ func (vsv *VirtualServerValidator) validateActionProxy(p *v1.ActionProxy, fieldP
 	return allErrs
 }
 
+func validateProxyMirror(m *v1.ProxyMirror, proxyUpstream string, fieldPath *field.Path, upstreamNames sets.Set[string]) field.ErrorList {
+	if m.Upstream == "" {
+		return field.ErrorList{field.Required(fieldPath.Child("upstream"), "")}
+	}
+
+	allErrs := validateReferencedUpstream(m.Upstream, fieldPath.Child("upstream"), upstreamNames)
+	if m.Upstream == proxyUpstream {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("upstream"), m.Upstream, "must be different from the upstream of the proxy"))
+	}
+
+	if m.Percentage != nil {
+		for _, msg := range validation.IsInRange(*m.Percentage, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("percentage"), *m.Percentage, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateMirrorUpstreamTypes checks that the routes don't mirror the requests to or from gRPC upstreams,
+// because NGINX proxies the mirrored requests over HTTP/1.1.
+func validateMirrorUpstreamTypes(routes []v1.Route, upstreams []v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	grpcUpstreams := sets.Set[string]{}
+	for _, u := range upstreams {
+		if u.Type == "grpc" {
+			grpcUpstreams.Insert(u.Name)
+		}
+	}
+
+	if grpcUpstreams.Len() == 0 {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+
+	for i, r := range routes {
+		idxPath := fieldPath.Index(i)
+
+		allErrs = append(allErrs, validateActionMirrorUpstreamTypes(r.Action, idxPath.Child("action"), grpcUpstreams)...)
+		for j, s := range r.Splits {
+			allErrs = append(allErrs, validateActionMirrorUpstreamTypes(s.Action, idxPath.Child("splits").Index(j).Child("action"), grpcUpstreams)...)
+		}
+
+		for j, m := range r.Matches {
+			matchPath := idxPath.Child("matches").Index(j)
+
+			allErrs = append(allErrs, validateActionMirrorUpstreamTypes(m.Action, matchPath.Child("action"), grpcUpstreams)...)
+			for k, s := range m.Splits {
+				allErrs = append(allErrs, validateActionMirrorUpstreamTypes(s.Action, matchPath.Child("splits").Index(k).Child("action"), grpcUpstreams)...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateActionMirrorUpstreamTypes(action *v1.Action, fieldPath *field.Path, grpcUpstreams sets.Set[string]) field.ErrorList {
+	if action == nil || action.Proxy == nil || action.Proxy.Mirror == nil {
+		return nil
+	}
+
+	proxyPath := fieldPath.Child("proxy")
+
+	if grpcUpstreams.Has(action.Proxy.Mirror.Upstream) {
+		return field.ErrorList{field.Invalid(proxyPath.Child("mirror", "upstream"), action.Proxy.Mirror.Upstream, "must not be a gRPC upstream")}
+	}
+
+	if grpcUpstreams.Has(action.Proxy.Upstream) {
+		return field.ErrorList{field.Forbidden(proxyPath.Child("mirror"), "the requests to a gRPC upstream can't be mirrored")}
+	}
+
+	return nil
+}
+
 func validateStringNoVariables(s string, fieldPath *field.Path) field.ErrorList {
 	for i, char := range s {
 		charLen := len(string(char))
@@ -1184,26 +1301,43 @@ func validateIgnoreHeaders(ignoreHeaders []string, fieldPath *field.Path) field.
 }
 
 func (vsv *VirtualServerValidator) validateSplits(splits []v1.Split, fieldPath *field.Path, upstreamNames sets.Set[string], path string) field.ErrorList {
+	weights := make([]int, 0, len(splits))
+	for _, s := range splits {
+		weights = append(weights, s.Weight)
+	}
+
+	allErrs := validateSplitWeights(weights, fieldPath)
 	if len(splits) < 2 {
-		return field.ErrorList{field.Invalid(fieldPath, "", "must include at least 2 splits")}
+		return allErrs
 	}
 
-	allErrs := field.ErrorList{}
-	totalWeight := 0
 	for i, s := range splits {
 		idxPath := fieldPath.Index(i)
 
-		for _, msg := range validation.IsInRange(s.Weight, 1, 99) {
-			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), s.Weight, msg))
-		}
-
 		if s.Action == nil {
 			allErrs = append(allErrs, field.Required(idxPath.Child("action"), ""))
 		} else {
 			allErrs = append(allErrs, vsv.validateAction(s.Action, idxPath.Child("action"), upstreamNames, path, true)...)
 		}
+	}
+
+	return allErrs
+}
+
+// validateSplitWeights validates the weights of splits. The same rules apply to the splits of VirtualServers,
+// VirtualServerRoutes and TransportServers.
+func validateSplitWeights(weights []int, fieldPath *field.Path) field.ErrorList {
+	if len(weights) < 2 {
+		return field.ErrorList{field.Invalid(fieldPath, "", "must include at least 2 splits")}
+	}
 
-		totalWeight += s.Weight
+	allErrs := field.ErrorList{}
+	totalWeight := 0
+	for i, w := range weights {
+		for _, msg := range validation.IsInRange(w, 1, 99) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("weight"), w, msg))
+		}
+		totalWeight += w
 	}
 
 	if totalWeight != 100 {
@@ -1306,6 +1440,14 @@ func (vsv *VirtualServerValidator) validateMatch(match v1.Match, fieldPath *fiel
 		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of `action` or `splits`"))
 	}
 
+	if match.SplitKey != "" {
+		if len(match.Splits) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("splitKey"), "can only be used with `splits`"))
+		} else {
+			allErrs = append(allErrs, validateSplitKey(match.SplitKey, fieldPath.Child("splitKey"), vsv.isPlus)...)
+		}
+	}
+
 	return allErrs
 }
 
@@ -1405,6 +1547,38 @@ func validateVariableName(name string, fieldPath *field.Path) field.ErrorList {
 	return nil
 }
 
+func routeHasSplits(route v1.Route) bool {
+	if len(route.Splits) > 0 {
+		return true
+	}
+	for _, m := range route.Matches {
+		if len(m.Splits) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var splitKeySpecialVariables = []string{"arg_", "http_", "cookie_", "jwt_claim_"}
+
+// splitKeyVariables includes NGINX variables allowed to be used in a split key.
+// Those are the same variables that are allowed in conditions.
+var splitKeyVariables = func() map[string]bool {
+	vars := make(map[string]bool, len(validVariableNames))
+	for name := range validVariableNames {
+		vars[strings.TrimPrefix(name, "$")] = true
+	}
+	return vars
+}()
+
+func validateSplitKey(key string, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := ValidateEscapedString(key, "${cookie_user}", "${http_x_tenant}"); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, key, err.Error()))
+	}
+	return append(allErrs, validateStringWithVariables(key, fieldPath, splitKeySpecialVariables, splitKeyVariables, isPlus)...)
+}
+
 func isValidMatchValue(value string) []string {
 	if err := ValidateEscapedString(value, "value-123"); err != nil {
 		return []string{err.Error()}
@@ -1434,6 +1608,7 @@ func (vsv *VirtualServerValidator) validateVirtualServerRouteSpec(spec *v1.Virtu
 	allErrs = append(allErrs, upstreamErrs...)
 
 	allErrs = append(allErrs, vsv.validateVirtualServerRouteSubroutes(spec.Subroutes, fieldPath.Child("subroutes"), upstreamNames, vsPath, namespace)...)
+	allErrs = append(allErrs, validateMirrorUpstreamTypes(spec.Subroutes, spec.Upstreams, fieldPath.Child("subroutes"))...)
 
 	return allErrs
 }
@@ -156,6 +156,63 @@ func TestValidateDosDisabled(t *testing.T) {
 	}
 }
 
+func TestValidateVirtualServerListener(t *testing.T) {
+	t.Parallel()
+	validInput := []*v1.Listener{
+		nil,
+		{
+			HTTP:  "http-8083",
+			HTTPS: "https-8443",
+		},
+		{
+			HTTPS:      "https-8443",
+			Additional: []string{"https-9443", "http-9080"},
+		},
+	}
+
+	for _, listener := range validInput {
+		allErrs := validateVirtualServerListener(listener, field.NewPath("listener"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateVirtualServerListener(%+v) returned errors %v for valid input", listener, allErrs)
+		}
+	}
+}
+
+func TestValidateVirtualServerListener_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		listener *v1.Listener
+		msg      string
+	}{
+		{
+			listener: &v1.Listener{
+				HTTP: "http_8083",
+			},
+			msg: "invalid http listener name",
+		},
+		{
+			listener: &v1.Listener{
+				Additional: []string{"https-9443", "@"},
+			},
+			msg: "invalid additional listener name",
+		},
+		{
+			listener: &v1.Listener{
+				HTTPS:      "https-8443",
+				Additional: []string{"https-8443"},
+			},
+			msg: "duplicated listener",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateVirtualServerListener(test.listener, field.NewPath("listener"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateVirtualServerListener() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidatePolicies(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -915,6 +972,32 @@ func TestValidateRoute(t *testing.T) {
 			isRouteFieldForbidden: false,
 			msg:                   "valid upstream with splits",
 		},
+		{
+			route: v1.Route{
+				Path: "/",
+				Splits: []v1.Split{
+					{
+						Weight: 90,
+						Action: &v1.Action{
+							Pass: "test-1",
+						},
+					},
+					{
+						Weight: 10,
+						Action: &v1.Action{
+							Pass: "test-2",
+						},
+					},
+				},
+				SplitKey: "${http_x_tenant}",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+				"test-2": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "valid splits with split key",
+		},
 		{
 			route: v1.Route{
 				Path: "/",
@@ -995,6 +1078,20 @@ func TestValidateRouteFails(t *testing.T) {
 			isRouteFieldForbidden: false,
 			msg:                   "invalid pass action",
 		},
+		{
+			route: v1.Route{
+				Path: "/",
+				Action: &v1.Action{
+					Pass: "test",
+				},
+				SplitKey: "${cookie_user}",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "split key without splits",
+		},
 		{
 			route: v1.Route{
 				Path: "/",
@@ -1963,6 +2060,36 @@ func TestValidateMatch(t *testing.T) {
 			},
 			msg: "valid match with splits",
 		},
+		{
+			match: v1.Match{
+				Conditions: []v1.Condition{
+					{
+						Cookie: "version",
+						Value:  "v1",
+					},
+				},
+				Splits: []v1.Split{
+					{
+						Weight: 90,
+						Action: &v1.Action{
+							Pass: "test-1",
+						},
+					},
+					{
+						Weight: 10,
+						Action: &v1.Action{
+							Pass: "test-2",
+						},
+					},
+				},
+				SplitKey: "${cookie_user}",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+				"test-2": {},
+			},
+			msg: "valid match with splits and split key",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -2056,6 +2183,54 @@ func TestValidateMatchFails(t *testing.T) {
 			},
 			msg: "both splits and action are set",
 		},
+		{
+			match: v1.Match{
+				Conditions: []v1.Condition{
+					{
+						Cookie: "version",
+						Value:  "v1",
+					},
+				},
+				Action: &v1.Action{
+					Pass: "test",
+				},
+				SplitKey: "${cookie_user}",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test": {},
+			},
+			msg: "split key without splits",
+		},
+		{
+			match: v1.Match{
+				Conditions: []v1.Condition{
+					{
+						Cookie: "version",
+						Value:  "v1",
+					},
+				},
+				Splits: []v1.Split{
+					{
+						Weight: 90,
+						Action: &v1.Action{
+							Pass: "test-1",
+						},
+					},
+					{
+						Weight: 10,
+						Action: &v1.Action{
+							Pass: "test-2",
+						},
+					},
+				},
+				SplitKey: "${request_id}",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+				"test-2": {},
+			},
+			msg: "split key with invalid variable",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -2068,6 +2243,46 @@ func TestValidateMatchFails(t *testing.T) {
 	}
 }
 
+func TestValidateSplitKey(t *testing.T) {
+	t.Parallel()
+	validKeys := []string{
+		"${cookie_user}",
+		"${http_x_tenant}",
+		"${arg_user}",
+		"${remote_addr}",
+		"${remote_addr}${http_user_agent}",
+		"${jwt_claim_sub}",
+	}
+
+	for _, key := range validKeys {
+		allErrs := validateSplitKey(key, field.NewPath("splitKey"), true)
+		if len(allErrs) > 0 {
+			t.Errorf("validateSplitKey(%q) returned errors %v for valid input", key, allErrs)
+		}
+	}
+
+	invalidKeys := []string{
+		"$cookie_user",
+		"${request_id}",
+		"${host}",
+		"${cookie_user",
+		`${cookie_user}"`,
+		"${http_x-tenant}",
+	}
+
+	for _, key := range invalidKeys {
+		allErrs := validateSplitKey(key, field.NewPath("splitKey"), true)
+		if len(allErrs) == 0 {
+			t.Errorf("validateSplitKey(%q) returned no errors for invalid input", key)
+		}
+	}
+
+	allErrs := validateSplitKey("${jwt_claim_sub}", field.NewPath("splitKey"), false)
+	if len(allErrs) > 0 {
+		t.Errorf("validateSplitKey() returned errors %v for a JWT claim in NGINX OSS", allErrs)
+	}
+}
+
 func TestIsValidMatchValue(t *testing.T) {
 	t.Parallel()
 	validValues := []string{
@@ -3350,6 +3565,236 @@ func TestValidateActionProxyFails(t *testing.T) {
 	}
 }
 
+func TestValidateProxyMirror(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"upstream1": {},
+		"upstream2": {},
+	}
+	tests := []struct {
+		mirror *v1.ProxyMirror
+		msg    string
+	}{
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream: "upstream2",
+			},
+			msg: "mirror without percentage",
+		},
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream:   "upstream2",
+				Percentage: createPointerFromInt(10),
+			},
+			msg: "mirror with percentage",
+		},
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream:   "upstream2",
+				Percentage: createPointerFromInt(100),
+			},
+			msg: "mirror with max percentage",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateProxyMirror(test.mirror, "upstream1", field.NewPath("mirror"), upstreamNames)
+		if len(allErrs) > 0 {
+			t.Errorf("validateProxyMirror() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateProxyMirrorFails(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"upstream1": {},
+		"upstream2": {},
+	}
+	tests := []struct {
+		mirror *v1.ProxyMirror
+		msg    string
+	}{
+		{
+			mirror: &v1.ProxyMirror{},
+			msg:    "missing upstream",
+		},
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream: "upstream3",
+			},
+			msg: "upstream does not exist",
+		},
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream: "upstream1",
+			},
+			msg: "same upstream as the proxy",
+		},
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream:   "upstream2",
+				Percentage: createPointerFromInt(0),
+			},
+			msg: "zero percentage",
+		},
+		{
+			mirror: &v1.ProxyMirror{
+				Upstream:   "upstream2",
+				Percentage: createPointerFromInt(101),
+			},
+			msg: "percentage above 100",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateProxyMirror(test.mirror, "upstream1", field.NewPath("mirror"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateProxyMirror() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateMirrorUpstreamTypes(t *testing.T) {
+	t.Parallel()
+	upstreams := []v1.Upstream{
+		{Name: "http1"},
+		{Name: "http2", Type: "http"},
+		{Name: "grpc", Type: "grpc"},
+	}
+	routes := []v1.Route{
+		{
+			Path: "/tea",
+			Action: &v1.Action{
+				Proxy: &v1.ActionProxy{
+					Upstream: "http1",
+					Mirror:   &v1.ProxyMirror{Upstream: "http2"},
+				},
+			},
+		},
+		{
+			Path:   "/coffee",
+			Action: &v1.Action{Pass: "grpc"},
+		},
+	}
+
+	allErrs := validateMirrorUpstreamTypes(routes, upstreams, field.NewPath("routes"))
+	if len(allErrs) > 0 {
+		t.Errorf("validateMirrorUpstreamTypes() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateMirrorUpstreamTypesFails(t *testing.T) {
+	t.Parallel()
+	upstreams := []v1.Upstream{
+		{Name: "http1"},
+		{Name: "http2", Type: "http"},
+		{Name: "grpc", Type: "grpc"},
+	}
+	tests := []struct {
+		route         v1.Route
+		expectedField string
+		msg           string
+	}{
+		{
+			route: v1.Route{
+				Path: "/tea",
+				Action: &v1.Action{
+					Proxy: &v1.ActionProxy{
+						Upstream: "http1",
+						Mirror:   &v1.ProxyMirror{Upstream: "grpc"},
+					},
+				},
+			},
+			expectedField: "routes[0].action.proxy.mirror.upstream",
+			msg:           "mirror to a gRPC upstream",
+		},
+		{
+			route: v1.Route{
+				Path: "/tea",
+				Action: &v1.Action{
+					Proxy: &v1.ActionProxy{
+						Upstream: "grpc",
+						Mirror:   &v1.ProxyMirror{Upstream: "http1"},
+					},
+				},
+			},
+			expectedField: "routes[0].action.proxy.mirror",
+			msg:           "mirror of the requests to a gRPC upstream",
+		},
+		{
+			route: v1.Route{
+				Path: "/tea",
+				Splits: []v1.Split{
+					{
+						Weight: 100,
+						Action: &v1.Action{
+							Proxy: &v1.ActionProxy{
+								Upstream: "http1",
+								Mirror:   &v1.ProxyMirror{Upstream: "grpc"},
+							},
+						},
+					},
+				},
+			},
+			expectedField: "routes[0].splits[0].action.proxy.mirror.upstream",
+			msg:           "mirror to a gRPC upstream in a split",
+		},
+		{
+			route: v1.Route{
+				Path: "/tea",
+				Matches: []v1.Match{
+					{
+						Action: &v1.Action{
+							Proxy: &v1.ActionProxy{
+								Upstream: "grpc",
+								Mirror:   &v1.ProxyMirror{Upstream: "http2"},
+							},
+						},
+					},
+				},
+				Action: &v1.Action{Pass: "http1"},
+			},
+			expectedField: "routes[0].matches[0].action.proxy.mirror",
+			msg:           "mirror of the requests to a gRPC upstream in a match",
+		},
+		{
+			route: v1.Route{
+				Path: "/tea",
+				Matches: []v1.Match{
+					{
+						Splits: []v1.Split{
+							{
+								Weight: 100,
+								Action: &v1.Action{
+									Proxy: &v1.ActionProxy{
+										Upstream: "http1",
+										Mirror:   &v1.ProxyMirror{Upstream: "grpc"},
+									},
+								},
+							},
+						},
+					},
+				},
+				Action: &v1.Action{Pass: "http1"},
+			},
+			expectedField: "routes[0].matches[0].splits[0].action.proxy.mirror.upstream",
+			msg:           "mirror to a gRPC upstream in a split of a match",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateMirrorUpstreamTypes([]v1.Route{test.route}, upstreams, field.NewPath("routes"))
+		if len(allErrs) != 1 {
+			t.Errorf("validateMirrorUpstreamTypes() returned errors %v but expected one error for the case of %s", allErrs, test.msg)
+			continue
+		}
+		if allErrs[0].Field != test.expectedField {
+			t.Errorf("validateMirrorUpstreamTypes() returned an error for the field %s but expected %s for the case of %s", allErrs[0].Field, test.expectedField, test.msg)
+		}
+	}
+}
+
 func TestValidateActionProxyRewritePath(t *testing.T) {
 	t.Parallel()
 	tests := []string{"/rewrite", "/rewrite", `/$2`}